@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/vultisig/verifier/internal/logging"
+	"github.com/vultisig/verifier/internal/relay"
+)
+
+// Local relay for offline development, point vault_service.relay.server of the verifier,
+// worker and plugins to it. Keeps everything in memory.
+func main() {
+	host := flag.String("host", "0.0.0.0", "Host to listen on")
+	port := flag.Int("port", 8090, "Port to listen on")
+	sessionTTL := flag.Duration("session-ttl", 30*time.Minute, "Drop sessions not updated for this long, 0 keeps them forever")
+	logFormat := flag.String("log-format", "text", "Log format: text or json")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	logger := logging.NewLogger(logging.LogFormat(*logFormat))
+
+	addr := fmt.Sprintf("%s:%d", *host, *port)
+	logger.Infof("relay server listening on %s", addr)
+	if err := relay.NewServer(logger, *sessionTTL).Start(ctx, addr); err != nil {
+		panic(fmt.Errorf("relay server failed: %w", err))
+	}
+}
@@ -7,11 +7,13 @@ import (
 	"github.com/vultisig/verifier/config"
 	"github.com/vultisig/verifier/internal/logging"
 	internalMetrics "github.com/vultisig/verifier/internal/metrics"
+	"github.com/vultisig/verifier/internal/pricing"
 	"github.com/vultisig/verifier/internal/storage/postgres"
 	fee_tx_indexer "github.com/vultisig/verifier/internal/tx_indexer"
 	"github.com/vultisig/verifier/plugin/metrics"
 	"github.com/vultisig/verifier/plugin/tx_indexer"
 	"github.com/vultisig/verifier/plugin/tx_indexer/pkg/storage"
+	"github.com/vultisig/vultisig-go/common"
 )
 
 func main() {
@@ -77,6 +79,26 @@ func main() {
 		worker,
 	)
 
+	feePrices := pricing.DefaultStaticPrices()
+	for _, p := range cfg.FeePrices {
+		chain, err := common.FromString(p.Chain)
+		if err != nil {
+			panic(fmt.Errorf("invalid fee price chain: %w", err))
+		}
+		feePrices = append(feePrices, pricing.StaticPrice{
+			Chain:    chain,
+			Token:    p.Token,
+			Decimals: p.Decimals,
+			USD:      p.USD,
+		})
+	}
+	priceSource, err := pricing.NewStaticPriceSource(feePrices)
+	if err != nil {
+		panic(fmt.Errorf("pricing.NewStaticPriceSource: %w", err))
+	}
+	feeIndexer.SetPriceSource(priceSource)
+	feeIndexer.SetRecheckWindow(cfg.RefundRecheckWindow)
+
 	err = feeIndexer.Run()
 	if err != nil {
 		panic(fmt.Errorf("failed to start feeIndexer: %w", err))
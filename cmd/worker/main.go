@@ -6,8 +6,10 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/hibiken/asynq"
+	"github.com/robfig/cron/v3"
 
 	"github.com/vultisig/verifier/config"
 	"github.com/vultisig/verifier/internal/fee_manager"
@@ -16,7 +18,9 @@ import (
 	internalMetrics "github.com/vultisig/verifier/internal/metrics"
 	"github.com/vultisig/verifier/internal/safety"
 	"github.com/vultisig/verifier/internal/service"
+	vstorage "github.com/vultisig/verifier/internal/storage"
 	"github.com/vultisig/verifier/internal/storage/postgres"
+	"github.com/vultisig/verifier/internal/syncer"
 	"github.com/vultisig/verifier/plugin/tasks"
 	"github.com/vultisig/verifier/plugin/tx_indexer"
 	"github.com/vultisig/verifier/plugin/tx_indexer/pkg/storage"
@@ -76,10 +80,7 @@ func main() {
 		panic(fmt.Sprintf("failed to initialize database: %v", err))
 	}
 
-	policyService, err := service.NewPolicyService(
-		backendDB,
-		nil, // No syncer needed for async operations
-	)
+	policyService, err := service.NewPolicyService(backendDB, client)
 	if err != nil {
 		panic(fmt.Sprintf("failed to initialize policy service: %v", err))
 	}
@@ -122,6 +123,17 @@ func main() {
 		txIndexerService,
 		safetyMgm,
 	)
+	if err != nil {
+		panic(fmt.Sprintf("failed to initialize vault management service: %v", err))
+	}
+	vaultMgmService.SetInspector(asynq.NewInspector(redisConnOpt))
+	vaultMgmService.SetPresignStore(backendDB)
+	vaultMgmService.SetKeysignResultStore(backendDB)
+	receiptKey, err := cfg.DeletionReceipt.SigningKey()
+	if err != nil {
+		panic(fmt.Sprintf("failed to load deletion receipt key: %v", err))
+	}
+	vaultMgmService.SetDeletionReceiptKey(receiptKey)
 
 	feeMgmService := fee_manager.NewFeeManagementService(
 		logger,
@@ -149,6 +161,117 @@ func main() {
 		workerMetrics = internalMetrics.NewNoOpWorkerMetrics()
 	}
 
+	redisStorage, err := vstorage.NewRedisStorage(cfg.Redis)
+	if err != nil {
+		panic(fmt.Sprintf("failed to initialize redis storage: %v", err))
+	}
+	sessionStatus, err := service.NewSessionStatusService(redisStorage, workerMetrics, logger)
+	if err != nil {
+		panic(fmt.Sprintf("failed to initialize session status service: %v", err))
+	}
+	vaultMgmService.SetSessionReporter(sessionStatus)
+
+	invoiceService, err := service.NewInvoiceService(backendDB, logger)
+	if err != nil {
+		panic(fmt.Sprintf("failed to initialize invoice service: %v", err))
+	}
+
+	refundService, err := service.NewRefundService(backendDB, logger)
+	if err != nil {
+		panic(fmt.Sprintf("failed to initialize refund service: %v", err))
+	}
+
+	dunningService, err := service.NewDunningService(backendDB, policyService, cfg.Fees.Dunning, logger)
+	if err != nil {
+		panic(fmt.Sprintf("failed to initialize dunning service: %v", err))
+	}
+
+	reconciliationService, err := service.NewReconciliationService(backendDB, logger)
+	if err != nil {
+		panic(fmt.Sprintf("failed to initialize reconciliation service: %v", err))
+	}
+
+	payoutService, err := service.NewPayoutService(backendDB, cfg.Fees.Payouts, logger)
+	if err != nil {
+		panic(fmt.Sprintf("failed to initialize payout service: %v", err))
+	}
+
+	signer, err := cfg.Signing.Signer()
+	if err != nil {
+		panic(fmt.Sprintf("failed to load signing key: %v", err))
+	}
+	pluginSyncer := syncer.NewPolicySyncer(backendDB, signer)
+	pluginSyncer.SetLegacyBearerTokens(cfg.Signing.LegacyBearerTokens)
+
+	controlFlagService, err := service.NewControlFlagService(backendDB, pluginSyncer, logger)
+	if err != nil {
+		panic(fmt.Sprintf("failed to initialize control flag service: %v", err))
+	}
+
+	go pluginSyncer.WatchServerInfo(ctx)
+
+	policySyncService, err := service.NewPolicySyncService(backendDB, pluginSyncer, logger)
+	if err != nil {
+		panic(fmt.Sprintf("failed to initialize policy sync service: %v", err))
+	}
+
+	pluginHealthService, err := service.NewPluginHealthService(backendDB, cfg.PluginHealth, logger)
+	if err != nil {
+		panic(fmt.Sprintf("failed to initialize plugin health service: %v", err))
+	}
+
+	keysignResultService, err := service.NewKeysignResultService(backendDB, logger)
+	if err != nil {
+		panic(fmt.Sprintf("failed to initialize keysign result service: %v", err))
+	}
+
+	anomalyService, err := service.NewAnomalyService(backendDB, controlFlagService, cfg.Safety.Anomaly, logger)
+	if err != nil {
+		panic(fmt.Sprintf("failed to initialize anomaly service: %v", err))
+	}
+
+	scheduler := asynq.NewScheduler(redisConnOpt, &asynq.SchedulerOpts{
+		Logger:   logger,
+		Location: time.UTC,
+	})
+	for _, entry := range []struct {
+		spec     string
+		taskType string
+	}{
+		{cfg.Fees.InvoiceSchedule, tasks.TypeMonthlyInvoices},
+		{cfg.Fees.RefundSchedule, tasks.TypeSubscriptionRefund},
+		{cfg.Fees.Dunning.Schedule, tasks.TypeFeeDunning},
+		{cfg.Fees.ReconciliationSchedule, tasks.TypeLedgerReconcile},
+		{cfg.Fees.Payouts.Schedule, tasks.TypeDeveloperPayouts},
+		{cfg.Safety.Anomaly.Schedule, tasks.TypeAnomalyDetection},
+		{cfg.Safety.OutboxSchedule, tasks.TypeControlFlagOutbox},
+		{cfg.PolicySyncSchedule, tasks.TypePolicySync},
+		{cfg.PluginHealth.Schedule, tasks.TypePluginHealthCheck},
+		{cfg.KeysignResultPurgeSchedule, tasks.TypeKeysignResultPurge},
+	} {
+		if entry.spec == "" {
+			continue
+		}
+		ttl, err := uniqueTTL(entry.spec, time.Now())
+		if err != nil {
+			panic(fmt.Sprintf("failed to parse schedule of %s: %v", entry.taskType, err))
+		}
+		// every replica runs the scheduler, the unique option keeps a single task per run
+		_, err = scheduler.Register(
+			entry.spec,
+			asynq.NewTask(entry.taskType, nil),
+			asynq.Queue(tasks.QUEUE_NAME),
+			asynq.Unique(ttl),
+		)
+		if err != nil {
+			panic(fmt.Sprintf("failed to schedule %s: %v", entry.taskType, err))
+		}
+	}
+	if err := scheduler.Start(); err != nil {
+		panic(fmt.Sprintf("failed to start scheduler: %v", err))
+	}
+	defer scheduler.Shutdown()
+
 	mux := asynq.NewServeMux()
 
 	// Wrap handlers with metrics collection
@@ -156,14 +279,49 @@ func main() {
 		workerMetrics.Handler("keygen", vaultMgmService.HandleKeyGenerationDKLS))
 	mux.HandleFunc(tasks.TypeKeySignDKLS,
 		workerMetrics.Handler("keysign", vaultMgmService.HandleKeySignDKLS))
+	mux.HandleFunc(tasks.TypePresignDKLS,
+		workerMetrics.Handler("presign", vaultMgmService.HandlePresignDKLS))
 	mux.HandleFunc(tasks.TypeReshareDKLS,
 		workerMetrics.Handler("reshare", feeMgmService.HandleReshareDKLS))
+	mux.HandleFunc(tasks.TypeVaultUninstall,
+		workerMetrics.Handler("uninstall", vaultMgmService.HandleVaultUninstall))
 	mux.HandleFunc(tasks.TypeRecurringFeeRecord,
 		workerMetrics.Handler("fees", policyService.HandleScheduledFees))
 	mux.HandleFunc(tasks.TypePolicyDeactivate,
 		workerMetrics.Handler("policy_deactivate", policyService.HandlePolicyDeactivate))
+	mux.HandleFunc(tasks.TypeMonthlyInvoices,
+		workerMetrics.Handler("invoices", invoiceService.HandleMonthlyInvoices))
+	mux.HandleFunc(tasks.TypeSubscriptionRefund,
+		workerMetrics.Handler("subscription_refund", refundService.HandleSubscriptionRefunds))
+	mux.HandleFunc(tasks.TypeFeeDunning,
+		workerMetrics.Handler("dunning", dunningService.HandleDunning))
+	mux.HandleFunc(tasks.TypeLedgerReconcile,
+		workerMetrics.Handler("reconciliation", reconciliationService.HandleReconciliation))
+	mux.HandleFunc(tasks.TypeDeveloperPayouts,
+		workerMetrics.Handler("developer_payouts", payoutService.HandleDeveloperPayouts))
+	mux.HandleFunc(tasks.TypeAnomalyDetection,
+		workerMetrics.Handler("anomaly_detection", anomalyService.HandleAnomalyDetection))
+	mux.HandleFunc(tasks.TypeControlFlagOutbox,
+		workerMetrics.Handler("control_flag_outbox", controlFlagService.HandleControlFlagOutbox))
+	mux.HandleFunc(tasks.TypePolicySync,
+		workerMetrics.Handler("policy_sync", policySyncService.HandlePolicySync))
+	mux.HandleFunc(tasks.TypePluginHealthCheck,
+		workerMetrics.Handler("plugin_health", pluginHealthService.HandlePluginHealthCheck))
+	mux.HandleFunc(tasks.TypeKeysignResultPurge,
+		workerMetrics.Handler("keysign_result_purge", keysignResultService.HandleKeysignResultPurge))
 
 	if err := srv.Run(mux); err != nil {
 		panic(fmt.Errorf("could not run server: %w", err))
 	}
 }
+
+// uniqueTTL is how long a scheduled task stays unique: the interval between its next two runs, at most an hour,
+// so that the lock of a run never outlives it and skips the next one
+func uniqueTTL(spec string, now time.Time) (time.Duration, error) {
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		return 0, err
+	}
+	next := schedule.Next(now.UTC())
+	return min(schedule.Next(next).Sub(next), time.Hour), nil
+}
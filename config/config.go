@@ -1,6 +1,8 @@
 package config
 
 import (
+	"crypto/ed25519"
+	"errors"
 	"fmt"
 	"os"
 	"reflect"
@@ -10,7 +12,9 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/vultisig/verifier/internal/logging"
+	"github.com/vultisig/verifier/internal/ratelimit"
 	"github.com/vultisig/verifier/plugin/config"
+	"github.com/vultisig/verifier/plugin/reqsign"
 	tx_indexer_config "github.com/vultisig/verifier/plugin/tx_indexer/pkg/config"
 	"github.com/vultisig/verifier/vault_config"
 )
@@ -22,8 +26,74 @@ type WorkerConfig struct {
 	BlockStorage vault_config.BlockStorage `mapstructure:"block_storage" json:"block_storage,omitempty"`
 	Database     config.Database           `mapstructure:"database" json:"database,omitempty"`
 	Fees         FeesConfig                `mapstructure:"fees" json:"fees"`
+	Safety       SafetyConfig              `mapstructure:"safety" json:"safety,omitempty"`
 	Metrics      MetricsConfig             `mapstructure:"metrics" json:"metrics,omitempty"`
 	HealthPort   int                       `mapstructure:"health_port" json:"health_port,omitempty"`
+	// PolicySyncSchedule is the cron spec (UTC) the worker retries delivering policy changes to plugin servers on
+	PolicySyncSchedule string                `mapstructure:"policy_sync_schedule" json:"policy_sync_schedule,omitempty"`
+	PluginHealth       PluginHealthConfig    `mapstructure:"plugin_health" json:"plugin_health,omitempty"`
+	Signing            SigningConfig         `mapstructure:"signing" json:"signing,omitempty"`
+	DeletionReceipt    DeletionReceiptConfig `mapstructure:"deletion_receipt" json:"deletion_receipt,omitempty"`
+	// KeysignResultPurgeSchedule is the cron spec (UTC) the worker deletes the expired keysign results on
+	KeysignResultPurgeSchedule string `mapstructure:"keysign_result_purge_schedule" json:"keysign_result_purge_schedule,omitempty"`
+}
+
+// SigningConfig holds the key the verifier signs its requests to the plugin servers with
+type SigningConfig struct {
+	// PrivateKey is the hex encoded ed25519 seed, required since the plugin servers only accept signed requests
+	PrivateKey string `mapstructure:"private_key" json:"private_key,omitempty"`
+	// LegacyBearerTokens are the bearer tokens of the plugin servers that don't verify the signature yet, by plugin ID.
+	// They are sent next to the signature while bearer auth is deprecated, drop a plugin once its server sets the verifier keys.
+	LegacyBearerTokens map[string]string `mapstructure:"legacy_bearer_tokens" json:"legacy_bearer_tokens,omitempty"`
+}
+
+func (c SigningConfig) Signer() (*reqsign.Signer, error) {
+	if c.PrivateKey == "" {
+		return nil, errors.New("signing.private_key is required, plugin servers only accept signed requests of the verifier")
+	}
+	key, err := reqsign.ParsePrivateKey(c.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signing key: %w", err)
+	}
+	return reqsign.NewSigner(key), nil
+}
+
+// DeletionReceiptConfig holds the key the vault deletion receipts are signed with.
+// The worker needs the private key to sign them, the api only the public key it publishes and checks receipts against.
+type DeletionReceiptConfig struct {
+	// PrivateKey is the hex encoded ed25519 seed
+	PrivateKey string `mapstructure:"private_key" json:"private_key,omitempty"`
+	// PublicKey is the hex encoded ed25519 public key
+	PublicKey string `mapstructure:"public_key" json:"public_key,omitempty"`
+}
+
+func (c DeletionReceiptConfig) SigningKey() (ed25519.PrivateKey, error) {
+	if c.PrivateKey == "" {
+		return nil, errors.New("deletion_receipt.private_key is required to sign the vault deletion receipts")
+	}
+	key, err := reqsign.ParsePrivateKey(c.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid deletion receipt key: %w", err)
+	}
+	return key, nil
+}
+
+func (c DeletionReceiptConfig) VerifyingKey() (ed25519.PublicKey, error) {
+	if c.PublicKey == "" {
+		return nil, errors.New("deletion_receipt.public_key is required to publish and check the vault deletion receipts")
+	}
+	key, err := reqsign.ParsePublicKey(c.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid deletion receipt public key: %w", err)
+	}
+	return key, nil
+}
+
+type PluginHealthConfig struct {
+	// Schedule is the cron spec (UTC) the worker probes the /healthz endpoint of the plugin servers on, empty disables it
+	Schedule string `mapstructure:"schedule" json:"schedule,omitempty"`
+	// Timeout is how long a plugin server has to answer a probe
+	Timeout time.Duration `mapstructure:"timeout" json:"timeout,omitempty"`
 }
 
 type VerifierConfig struct {
@@ -32,6 +102,9 @@ type VerifierConfig struct {
 		Host      string `mapstructure:"host" json:"host,omitempty"`
 		Port      int64  `mapstructure:"port" json:"port,omitempty"`
 		JWTSecret string `mapstructure:"jwt_secret" json:"jwt_secret,omitempty"`
+		// TrustedProxies are the CIDRs of the load balancers whose X-Forwarded-For is trusted,
+		// empty uses the address of the connection as the client IP
+		TrustedProxies []string `mapstructure:"trusted_proxies" json:"trusted_proxies,omitempty"`
 	} `mapstructure:"server" json:"server"`
 	Database         config.Database           `mapstructure:"database" json:"database,omitempty"`
 	Redis            config.Redis              `mapstructure:"redis" json:"redis,omitempty"`
@@ -43,13 +116,116 @@ type VerifierConfig struct {
 		// pointer so it must be explicitly set to false, no value considered as enabled
 		Enabled *bool `mapstructure:"enabled" json:"enabled,omitempty"`
 	} `mapstructure:"auth" json:"auth"`
-	Fees         FeesConfig         `mapstructure:"fees" json:"fees"`
-	Metrics      MetricsConfig      `mapstructure:"metrics" json:"metrics,omitempty"`
-	PluginAssets PluginAssetsConfig `mapstructure:"plugin_assets" json:"plugin_assets,omitempty"`
+	Fees            FeesConfig            `mapstructure:"fees" json:"fees"`
+	Metrics         MetricsConfig         `mapstructure:"metrics" json:"metrics,omitempty"`
+	PluginAssets    PluginAssetsConfig    `mapstructure:"plugin_assets" json:"plugin_assets,omitempty"`
+	Signing         SigningConfig         `mapstructure:"signing" json:"signing,omitempty"`
+	RateLimit       RateLimitConfig       `mapstructure:"rate_limit" json:"rate_limit,omitempty"`
+	DeletionReceipt DeletionReceiptConfig `mapstructure:"deletion_receipt" json:"deletion_receipt,omitempty"`
+}
+
+// RateLimitConfig drives the token buckets shared by the verifier instances in redis
+type RateLimitConfig struct {
+	// Global limits every request by client IP before it is authenticated, so that bad credentials are throttled too
+	Global ratelimit.Limit `mapstructure:"global" json:"global,omitempty"`
+	// IP limits the requests without credentials by client IP
+	IP ratelimit.Limit `mapstructure:"ip" json:"ip,omitempty"`
+	// Vault limits the requests with a vault token by vault public key
+	Vault ratelimit.Limit `mapstructure:"vault" json:"vault,omitempty"`
+	// Tiers are the limits of the plugin API keys and signing keys by tier name
+	Tiers map[string]ratelimit.Limit `mapstructure:"tiers" json:"tiers,omitempty"`
+	// PluginTiers assigns plugins by ID to a tier, the other plugins are in DefaultTier
+	PluginTiers map[string]string `mapstructure:"plugin_tiers" json:"plugin_tiers,omitempty"`
+	DefaultTier string            `mapstructure:"default_tier" json:"default_tier,omitempty"`
+}
+
+// LimitOf returns the limit of the plugin tier, falling back to the default tier for unknown tiers
+func (c RateLimitConfig) LimitOf(pluginID string) ratelimit.Limit {
+	if tier, ok := c.PluginTiers[pluginID]; ok {
+		if limit, ok := c.Tiers[tier]; ok {
+			return limit
+		}
+	}
+	return c.Tiers[c.DefaultTier]
 }
 
 type FeesConfig struct {
 	USDCAddress string `mapstructure:"usdc_address" json:"usdc_address,omitempty"`
+	// InvoiceSchedule is the cron spec (UTC) the worker issues the previous month invoices on, empty disables it
+	InvoiceSchedule string `mapstructure:"invoice_schedule" json:"invoice_schedule,omitempty"`
+	// RefundSchedule is the cron spec (UTC) the worker refunds idle subscription periods on, empty disables it
+	RefundSchedule string `mapstructure:"refund_schedule" json:"refund_schedule,omitempty"`
+	// ReconciliationSchedule is the cron spec (UTC) the worker reconciles the fee ledger on, empty disables it
+	ReconciliationSchedule string `mapstructure:"reconciliation_schedule" json:"reconciliation_schedule,omitempty"`
+	// Dunning reminds and suspends the vaults with unpaid fees
+	Dunning DunningConfig `mapstructure:"dunning" json:"dunning,omitempty"`
+	// Payouts pays developers the fees collected for their plugins
+	Payouts PayoutsConfig `mapstructure:"payouts" json:"payouts,omitempty"`
+}
+
+// PayoutsConfig drives the developer payouts, amounts are in units of the fee asset
+type PayoutsConfig struct {
+	// Schedule is the cron spec (UTC) the worker creates the payouts of the previous month on, empty (the default) disables it
+	Schedule string `mapstructure:"schedule" json:"schedule,omitempty"`
+	// RevenueShareBps is the platform share of the collected fees, in basis points
+	RevenueShareBps uint64 `mapstructure:"revenue_share_bps" json:"revenue_share_bps,omitempty"`
+	// MinAmount is the least amount paid out, smaller balances are carried over to the next period
+	MinAmount uint64 `mapstructure:"min_amount" json:"min_amount,omitempty"`
+	// VaultPublicKey is the ECDSA public key of the treasury vault payouts are sent from, empty disables signing
+	VaultPublicKey string `mapstructure:"vault_public_key" json:"vault_public_key,omitempty"`
+}
+
+// DunningConfig drives the handling of unpaid balances, amounts are summed over the fee assets
+// (USD stablecoins) in their smallest unit
+type DunningConfig struct {
+	// Schedule is the cron spec (UTC) the worker checks the unpaid balances on, empty (the default) disables it
+	Schedule string `mapstructure:"schedule" json:"schedule,omitempty"`
+	// GracePeriod is how long a fee stays uncollected before reminders and suspension
+	GracePeriod      time.Duration `mapstructure:"grace_period" json:"grace_period,omitempty"`
+	ReminderInterval time.Duration `mapstructure:"reminder_interval" json:"reminder_interval,omitempty"`
+	// SuspendThreshold is the unpaid amount over which paid plugin policies are deactivated
+	// once the grace period is over, 0 (the default) never suspends
+	SuspendThreshold uint64 `mapstructure:"suspend_threshold" json:"suspend_threshold,omitempty"`
+}
+
+// SafetyConfig drives the automatic safety triggers besides user reports
+type SafetyConfig struct {
+	// Anomaly pauses the keysign of plugins whose signing deviates sharply from their baseline
+	Anomaly AnomalyConfig `mapstructure:"anomaly" json:"anomaly,omitempty"`
+	// OutboxSchedule is the cron spec (UTC) the worker retries pushing control flags to plugin servers on
+	OutboxSchedule string `mapstructure:"outbox_schedule" json:"outbox_schedule,omitempty"`
+}
+
+type AnomalyConfig struct {
+	// Schedule is the cron spec (UTC) the worker checks the signing activity on, empty disables it
+	Schedule string `mapstructure:"schedule" json:"schedule,omitempty"`
+	// Window is the recent activity checked against the Baseline period before it
+	Window   time.Duration     `mapstructure:"window" json:"window,omitempty"`
+	Baseline time.Duration     `mapstructure:"baseline" json:"baseline,omitempty"`
+	Defaults AnomalyThresholds `mapstructure:"defaults" json:"defaults,omitempty"`
+	// Plugins replaces the default thresholds of the plugins by ID
+	Plugins map[string]AnomalyThresholds `mapstructure:"plugins" json:"plugins,omitempty"`
+}
+
+// AnomalyThresholds are the deviations that pause a plugin, a zero value disables the check
+type AnomalyThresholds struct {
+	// MinRequests is the least sign requests in the window for the plugin to be checked
+	MinRequests int `mapstructure:"min_requests" json:"min_requests,omitempty"`
+	// RateFactor is how many times its baseline hourly rate the sign request rate may reach
+	RateFactor float64 `mapstructure:"rate_factor" json:"rate_factor,omitempty"`
+	// MaxRejectionRate is the share of sign requests the policy engine may reject, or its baseline share if higher
+	MaxRejectionRate float64 `mapstructure:"max_rejection_rate" json:"max_rejection_rate,omitempty"`
+	// MaxNewRecipientRate is the share of txs that may go to recipients unseen in the baseline
+	MaxNewRecipientRate float64 `mapstructure:"max_new_recipient_rate" json:"max_new_recipient_rate,omitempty"`
+	// MaxFailureRate is the share of txs resolved on chain that may fail, or its baseline share if higher
+	MaxFailureRate float64 `mapstructure:"max_failure_rate" json:"max_failure_rate,omitempty"`
+}
+
+func (c AnomalyConfig) ThresholdsOf(pluginID string) AnomalyThresholds {
+	if thresholds, ok := c.Plugins[pluginID]; ok {
+		return thresholds
+	}
+	return c.Defaults
 }
 
 type MetricsConfig struct {
@@ -116,6 +292,11 @@ type PortalConfig struct {
 	PresignedURLExpiry      time.Duration      `mapstructure:"presigned_url_expiry" json:"presigned_url_expiry,omitempty"`
 	Email                   PortalEmailConfig  `mapstructure:"email" json:"email,omitempty"`
 	DeveloperServiceURL     string             `mapstructure:"developer_service_url" json:"developer_service_url,omitempty"`
+	Signing                 SigningConfig      `mapstructure:"signing" json:"signing,omitempty"`
+	// SigningKeyOverlap is how long the previous signing keys of a plugin stay valid after it registers a new one
+	SigningKeyOverlap time.Duration `mapstructure:"signing_key_overlap" json:"signing_key_overlap,omitempty"`
+	// ApiKeyRotationOverlap is how long a rotated API key stays valid when the rotation does not set it
+	ApiKeyRotationOverlap time.Duration `mapstructure:"api_key_rotation_overlap" json:"api_key_rotation_overlap,omitempty"`
 }
 
 type PortalEmailConfig struct {
@@ -147,6 +328,28 @@ func ReadConfig(configName string) (*WorkerConfig, error) {
 	viper.SetDefault("VaultService.VaultsFilePath", "vaults")
 	viper.SetDefault("log_format", "text")
 	viper.SetDefault("health_port", 80)
+	viper.SetDefault("fees.invoice_schedule", "0 1 1 * *")
+	viper.SetDefault("fees.refund_schedule", "0 2 * * *")
+	viper.SetDefault("fees.reconciliation_schedule", "30 3 * * *")
+	viper.SetDefault("fees.payouts.revenue_share_bps", 2000)
+	viper.SetDefault("fees.payouts.min_amount", 10_000_000)
+	viper.SetDefault("fees.dunning.grace_period", 72*time.Hour)
+	viper.SetDefault("fees.dunning.reminder_interval", 24*time.Hour)
+	viper.SetDefault("safety.anomaly.schedule", "*/10 * * * *")
+	viper.SetDefault("safety.outbox_schedule", "* * * * *")
+	viper.SetDefault("policy_sync_schedule", "* * * * *")
+	viper.SetDefault("plugin_health.schedule", "* * * * *")
+	viper.SetDefault("keysign_result_purge_schedule", "20 * * * *")
+	viper.SetDefault("signing.legacy_bearer_tokens", map[string]string{})
+	viper.SetDefault("plugin_health.timeout", 5*time.Second)
+	viper.SetDefault("safety.anomaly.window", time.Hour)
+	viper.SetDefault("safety.anomaly.baseline", 7*24*time.Hour)
+	viper.SetDefault("safety.anomaly.defaults.min_requests", 20)
+	viper.SetDefault("safety.anomaly.defaults.rate_factor", 5)
+	viper.SetDefault("safety.anomaly.defaults.max_rejection_rate", 0.5)
+	viper.SetDefault("safety.anomaly.defaults.max_new_recipient_rate", 0.9)
+	viper.SetDefault("safety.anomaly.defaults.max_failure_rate", 0.5)
+	viper.SetDefault("safety.anomaly.plugins", map[string]AnomalyThresholds{})
 
 	if err := viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
@@ -176,6 +379,19 @@ func ReadVerifierConfig() (*VerifierConfig, error) {
 	// Set default values
 	viper.SetDefault("auth.nonce_expiry_minutes", 15)
 	viper.SetDefault("log_format", "text")
+	viper.SetDefault("rate_limit.global.rate", 50)
+	viper.SetDefault("rate_limit.global.burst", 200)
+	viper.SetDefault("rate_limit.ip.rate", 5)
+	viper.SetDefault("rate_limit.ip.burst", 30)
+	viper.SetDefault("rate_limit.vault.rate", 10)
+	viper.SetDefault("rate_limit.vault.burst", 60)
+	viper.SetDefault("rate_limit.plugin_tiers", map[string]string{})
+	viper.SetDefault("signing.legacy_bearer_tokens", map[string]string{})
+	viper.SetDefault("rate_limit.default_tier", "standard")
+	viper.SetDefault("rate_limit.tiers.standard.rate", 20)
+	viper.SetDefault("rate_limit.tiers.standard.burst", 100)
+	viper.SetDefault("fees.dunning.grace_period", 72*time.Hour)
+	viper.SetDefault("fees.dunning.reminder_interval", 24*time.Hour)
 
 	if err := viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
@@ -204,9 +420,12 @@ func ReadPortalConfig() (*PortalConfig, error) {
 
 	viper.SetDefault("log_format", "text")
 	viper.SetDefault("max_api_keys_per_plugin", 5)
+	viper.SetDefault("api_key_rotation_overlap", 24*time.Hour)
 	viper.SetDefault("max_media_images_per_plugin", 10)
 	viper.SetDefault("max_image_size_bytes", 5*1024*1024)
 	viper.SetDefault("presigned_url_expiry", 15*time.Minute)
+	viper.SetDefault("signing_key_overlap", 24*time.Hour)
+	viper.SetDefault("signing.legacy_bearer_tokens", map[string]string{})
 
 	if err := viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
@@ -233,6 +452,7 @@ func ReadTxIndexerConfig() (*tx_indexer_config.Config, error) {
 	viper.AutomaticEnv()
 
 	viper.SetDefault("log_format", "text")
+	viper.SetDefault("refund_recheck_window", 24*time.Hour)
 
 	if err := viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
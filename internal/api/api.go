@@ -23,6 +23,7 @@ const (
 	msgInternalError       = "an internal error occurred"
 	msgAccessDenied        = "access denied: token not authorized for this vault"
 	msgAccessDeniedBilling = "access denied: install billing app first"
+	msgRateLimited         = "too many requests, retry later"
 
 	// Token
 	msgMissingTokenID              = "missing tokenId"
@@ -34,12 +35,22 @@ const (
 	msgTokenRevokeFailed           = "failed to revoke token"
 	msgTokenNotFound               = "token not found"
 	msgTokenGetFailed              = "failed to get token"
+	msgRefreshTokenReused          = "refresh token was already used, please log in again"
+	msgRefreshTokenRotating        = "refresh token was just rotated, use the token returned by the other refresh"
 
 	// API key
 	msgDisabledAPIKey = "API key is disabled"
 	msgExpiredAPIKey  = "API key has expired"
 	msgAPIKeyNotFound = "API key not found"
 
+	msgAPIKeyIPNotAllowed = "API key is not allowed from this IP address"
+	msgAPIKeyScopeMissing = "API key does not have the scope required by this endpoint"
+
+	msgInvalidRequestSignature = "invalid request signature"
+	msgExpiredRequest          = "request timestamp is outside the allowed window"
+	msgReplayedRequest         = "request already received"
+	msgRequestBodyTooLarge     = "request body too large"
+
 	// Plugin
 	msgRequiredPluginID              = "pluginId is required"
 	msgPluginInstallationCountFailed = "failed to get plugin installation count"
@@ -54,6 +65,7 @@ const (
 	msgReactivateMissingReason = "cannot reactivate policy without deactivation_reason"
 	msgReactivateExpiredPolicy = "cannot reactivate expired/completed policy"
 	msgReactivateInvalidReason = "cannot reactivate policy with this deactivation reason"
+	msgReactivateUnpaidFees    = "policy is suspended until the unpaid fees are collected"
 
 	// Plugin Report
 	msgReportNotEligible      = "not eligible to report: no installation found"
@@ -86,14 +98,30 @@ const (
 	msgGetRecipeFunctionsFailed = "failed to get recipe functions"
 
 	// Vault
-	msgVaultPublicKeyGetFailed = "failed to get vault_public_key"
-	msgVaultShareDeleteFailed  = "failed to delete vault share"
-	msgVaultNotFound           = "vault not found"
+	msgVaultPublicKeyGetFailed  = "failed to get vault_public_key"
+	msgVaultShareDeleteFailed   = "failed to delete vault share"
+	msgVaultNotFound            = "vault not found"
+	msgUninstallReceiptNotFound = "uninstall receipt not found"
 
 	// Fees
 	msgGetFeesFailed           = "failed to get fees"
 	msgMarkFeesCollectedFailed = "failed to mark fees as collected"
 
+	// Payouts
+	msgPayoutsDisabled     = "payouts signing is disabled"
+	msgInvalidPayoutID     = "invalid payoutId"
+	msgPayoutNotOpen       = "payout is not open for signing"
+	msgPayoutVaultMismatch = "payouts are only signed from the treasury vault"
+	msgGetPayoutsFailed    = "failed to get payouts"
+	msgClaimPayoutFailed   = "failed to claim payout"
+
+	// Invoices
+	msgInvalidInvoiceID     = "invalid invoiceId"
+	msgInvalidInvoiceFormat = "format must be json, csv or pdf"
+	msgInvoiceNotFound      = "invoice not found"
+	msgGetInvoicesFailed    = "failed to get invoices"
+	msgRenderInvoiceFailed  = "failed to render invoice"
+
 	// Policy
 	msgInvalidPluginPolicy    = "plugin policy is invalid"
 	msgInvalidPolicySignature = "invalid policy signature"
@@ -103,17 +131,34 @@ const (
 	msgPolicyDeleteFailed     = "failed to delete policy"
 	msgPoliciesDeleteFailed   = "failed to delete plugin policies"
 	msgPolicyEnded            = "policy has ended"
+	msgPolicySyncGetFailed    = "failed to get policy sync status"
 
 	// Signing
 	msgNoMessagesToSign = "no messages to sign"
 	msgTxNotAllowed     = "tx not allowed to execute"
 
+	// Keysign result
+	msgInvalidTxIndexerID        = "invalid txIndexerId"
+	msgKeysignResultNotFound     = "keysign result not found"
+	msgKeysignResultGetFailed    = "failed to get keysign result"
+	msgKeysignResultDecodeFailed = "failed to decode keysign result"
+	msgKeysignResultCreateFailed = "failed to create keysign result"
+
+	// Presign
+	msgInvalidPresignRequest = "invalid presign request"
+	msgPresignPoolFull       = "presignature pool is full"
+
 	msgGetTxsByPolicyIDFailed = "failed to get txs by policyID"
 	msgGetTxsByPluginIDFailed = "failed to get txs by pluginID"
 
 	// Reshare
 	msgReshareQueueFailed = "failed to queue reshare task"
 
+	// Session
+	msgRequiredSessionID      = "sessionId is required"
+	msgSessionStatusGetFailed = "failed to get session status"
+	msgSessionStatusNotFound  = "session status not found"
+
 	// Public key
 	msgRequiredPublicKey      = "publicKeyECDSA is required"
 	msgInvalidPublicKey       = "invalid publicKeyECDSA"
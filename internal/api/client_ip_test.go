@@ -0,0 +1,34 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewIPExtractor(t *testing.T) {
+	request := func(remoteAddr string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set(echo.HeaderXForwardedFor, "1.2.3.4")
+		req.Header.Set(echo.HeaderXRealIP, "1.2.3.4")
+		return req
+	}
+
+	direct, err := newIPExtractor(nil)
+	require.NoError(t, err)
+	require.Equal(t, "203.0.113.7", direct(request("203.0.113.7:5000")))
+
+	behindProxy, err := newIPExtractor([]string{"10.0.0.0/8"})
+	require.NoError(t, err)
+	require.Equal(t, "1.2.3.4", behindProxy(request("10.1.2.3:5000")))
+	// only the configured proxies are trusted, not every private network
+	require.Equal(t, "192.168.1.1", behindProxy(request("192.168.1.1:5000")))
+	require.Equal(t, "203.0.113.7", behindProxy(request("203.0.113.7:5000")))
+
+	_, err = newIPExtractor([]string{"10.0.0.1"})
+	require.Error(t, err)
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"fmt"
+	"math"
 	"net/http"
 	"strconv"
 	"strings"
@@ -76,9 +77,10 @@ func (s *Server) MarkCollected(c echo.Context) error {
 
 func (s *Server) IssueCredit(c echo.Context) error {
 	var req struct {
-		PublicKey string `json:"public_key" validate:"required"`
-		Amount    uint64 `json:"amount" validate:"required,gt=0"`
-		Reason    string `json:"reason" validate:"required"`
+		PublicKey string             `json:"public_key" validate:"required"`
+		Amount    uint64             `json:"amount" validate:"required,gt=0"`
+		Asset     types.PricingAsset `json:"asset,omitempty"` // defaults to usdc
+		Reason    string             `json:"reason" validate:"required"`
 	}
 
 	if err := c.Bind(&req); err != nil {
@@ -86,7 +88,7 @@ func (s *Server) IssueCredit(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, NewErrorResponseWithMessage(msgRequestParseFailed))
 	}
 
-	err := s.feeService.IssueCredit(c.Request().Context(), req.PublicKey, req.Amount, req.Reason)
+	err := s.feeService.IssueCredit(c.Request().Context(), req.PublicKey, req.Amount, req.Asset, req.Reason)
 	if err != nil {
 		s.logger.WithError(err).Error("Failed to issue credit")
 		return c.JSON(http.StatusInternalServerError, NewErrorResponseWithMessage(msgIssueCreditFailed))
@@ -193,10 +195,14 @@ func (s *Server) GetPluginBillingSummary(c echo.Context) error {
 		}))
 	}
 
-	// Collect plugin IDs for lookups
-	pluginIDs := make([]string, len(rows))
-	for i, row := range rows {
-		pluginIDs[i] = row.PluginID
+	// Rows are per plugin and asset, collect plugin IDs for lookups keeping the order of the rows
+	var pluginIDs []string
+	rowsByPlugin := make(map[string][]itypes.PluginBillingSummaryRow)
+	for _, row := range rows {
+		if _, ok := rowsByPlugin[row.PluginID]; !ok {
+			pluginIDs = append(pluginIDs, row.PluginID)
+		}
+		rowsByPlugin[row.PluginID] = append(rowsByPlugin[row.PluginID], row)
 	}
 
 	titleMap, err := s.pluginService.GetPluginTitlesByIDs(c.Request().Context(), pluginIDs)
@@ -213,16 +219,33 @@ func (s *Server) GetPluginBillingSummary(c echo.Context) error {
 	}
 
 	// Convert rows to response format
-	summaries := make([]itypes.PluginBillingSummary, len(rows))
-	for i, row := range rows {
-		pricings := pricingsMap[row.PluginID]
+	summaries := make([]itypes.PluginBillingSummary, len(pluginIDs))
+	for i, pluginID := range pluginIDs {
+		pricings := pricingsMap[pluginID]
+		var startDate time.Time
+		var defaultTotal uint64
+		totals := make([]itypes.AssetTotal, 0, len(rowsByPlugin[pluginID]))
+		for _, row := range rowsByPlugin[pluginID] {
+			if startDate.IsZero() || row.StartDate.Before(startDate) {
+				startDate = row.StartDate
+			}
+			if row.Asset == types.PricingAssetUSDC {
+				defaultTotal = row.TotalFees
+			}
+			totals = append(totals, itypes.AssetTotal{
+				Asset:    row.Asset,
+				FeeAsset: itypes.FeeAssetOf(row.Asset),
+				Amount:   strconv.FormatUint(row.TotalFees, 10),
+			})
+		}
 		summaries[i] = itypes.PluginBillingSummary{
-			PluginID:    types.PluginID(row.PluginID),
-			AppName:     titleMap[row.PluginID],
+			PluginID:    types.PluginID(pluginID),
+			AppName:     titleMap[pluginID],
 			Pricing:     formatPricings(pricings),
-			StartDate:   row.StartDate.UTC(),
-			NextPayment: calculateNextPaymentFromPricings(pricings, row.StartDate),
-			TotalFees:   strconv.FormatUint(row.TotalFees, 10),
+			StartDate:   startDate.UTC(),
+			NextPayment: calculateNextPaymentFromPricings(pricings, startDate),
+			TotalFees:   strconv.FormatUint(defaultTotal, 10),
+			Totals:      totals,
 		}
 	}
 
@@ -249,9 +272,30 @@ func formatPricings(pricings []itypes.PricingInfo) string {
 
 // formatSinglePricing formats a single pricing entry for display
 func formatSinglePricing(p itypes.PricingInfo) string {
-	// Convert from smallest unit (6 decimals for USDC)
-	amountFloat := float64(p.Amount) / 1_000_000
-	assetUpper := strings.ToUpper(p.Asset)
+	// Convert from smallest unit of the asset
+	asset, err := types.GetFeeAsset(types.PricingAsset(p.Asset))
+	if err != nil {
+		asset = types.DefaultFeeAsset()
+	}
+	amountFloat := float64(p.Amount) / math.Pow10(int(asset.Decimals))
+	assetUpper := asset.Symbol
+
+	if p.Metric == string(types.PricingMetricPercentage) {
+		// Example output: "0.3% of volume per transaction (min 0.50 USDC, max 25 USDC)"
+		percent := strconv.FormatFloat(float64(p.Amount)/100, 'f', -1, 64)
+		var caps []string
+		if p.MinAmount != nil {
+			caps = append(caps, "min "+formatAmount(float64(*p.MinAmount)/math.Pow10(int(asset.Decimals)))+" "+assetUpper)
+		}
+		if p.MaxAmount != nil {
+			caps = append(caps, "max "+formatAmount(float64(*p.MaxAmount)/math.Pow10(int(asset.Decimals)))+" "+assetUpper)
+		}
+		formatted := percent + "% of volume per transaction"
+		if len(caps) > 0 {
+			formatted += " (" + strings.Join(caps, ", ") + ")"
+		}
+		return formatted
+	}
 
 	switch p.Type {
 	case "per-tx":
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/vultisig/verifier/internal/conv"
+	"github.com/vultisig/verifier/internal/invoice"
+	itypes "github.com/vultisig/verifier/internal/types"
+)
+
+// GetInvoices returns the monthly invoices of the vault, most recent first
+func (s *Server) GetInvoices(c echo.Context) error {
+	skip, take, err := conv.PageParamsFromCtx(c, 0, 12)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, NewErrorResponseWithMessage(msgInvalidPagination))
+	}
+
+	publicKey, ok := c.Get("vault_public_key").(string)
+	if !ok || publicKey == "" {
+		return c.JSON(http.StatusInternalServerError, NewErrorResponseWithMessage(msgVaultPublicKeyGetFailed))
+	}
+
+	invoices, totalCount, err := s.db.GetInvoices(c.Request().Context(), publicKey, skip, take)
+	if err != nil {
+		return s.internal(c, msgGetInvoicesFailed, err)
+	}
+
+	return c.JSON(http.StatusOK, NewSuccessResponse(http.StatusOK, itypes.InvoicePaginatedList{
+		Invoices:   itypes.ToInvoiceSummaries(invoices),
+		TotalCount: totalCount,
+	}))
+}
+
+// GetInvoice returns an invoice of the vault, as JSON by default or rendered with ?format=csv|pdf
+func (s *Server) GetInvoice(c echo.Context) error {
+	invoiceID, err := uuid.Parse(c.Param("invoiceId"))
+	if err != nil {
+		return s.badRequest(c, msgInvalidInvoiceID, err)
+	}
+
+	publicKey, ok := c.Get("vault_public_key").(string)
+	if !ok || publicKey == "" {
+		return c.JSON(http.StatusInternalServerError, NewErrorResponseWithMessage(msgVaultPublicKeyGetFailed))
+	}
+
+	inv, err := s.db.GetInvoice(c.Request().Context(), publicKey, invoiceID)
+	if err != nil {
+		return s.internal(c, msgGetInvoicesFailed, err)
+	}
+	if inv == nil {
+		return c.JSON(http.StatusNotFound, NewErrorResponseWithMessage(msgInvoiceNotFound))
+	}
+
+	var buf bytes.Buffer
+	var contentType string
+	format := c.QueryParam("format")
+	switch format {
+	case "", "json":
+		return c.JSON(http.StatusOK, NewSuccessResponse(http.StatusOK, inv))
+	case "csv":
+		err = invoice.WriteCSV(&buf, inv)
+		contentType = "text/csv"
+	case "pdf":
+		err = invoice.WritePDF(&buf, inv)
+		contentType = "application/pdf"
+	default:
+		return c.JSON(http.StatusBadRequest, NewErrorResponseWithMessage(msgInvalidInvoiceFormat))
+	}
+	if err != nil {
+		return s.internal(c, msgRenderInvoiceFailed, err)
+	}
+
+	filename := fmt.Sprintf("vultisig-invoice-%s.%s", inv.PeriodStart.UTC().Format("2006-01"), format)
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+	return c.Blob(http.StatusOK, contentType, buf.Bytes())
+}
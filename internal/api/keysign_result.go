@@ -0,0 +1,74 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+
+	vtypes "github.com/vultisig/verifier/types"
+	"github.com/vultisig/verifier/vault"
+)
+
+// keysignResultTTL is how long plugins can fetch a keysign result, well past the asynq task retention.
+// The worker purges the expired results.
+const keysignResultTTL = 7 * 24 * time.Hour
+
+func (s *Server) createKeysignResult(
+	ctx context.Context,
+	req *vtypes.PluginKeysignRequest,
+	txIndexerID uuid.UUID,
+	taskID string,
+) error {
+	return s.db.CreateKeysignResult(ctx, vtypes.KeysignResult{
+		TxIndexerID: txIndexerID,
+		SessionID:   req.SessionID,
+		TaskID:      taskID,
+		PluginID:    req.PluginID,
+		PublicKey:   req.PublicKey,
+		Status:      vtypes.KeysignResultQueued,
+		ExpiresAt:   time.Now().Add(keysignResultTTL),
+	})
+}
+
+func (s *Server) failKeysignResult(ctx context.Context, txIndexerID uuid.UUID, reason string) {
+	err := s.db.UpdateKeysignResult(ctx, txIndexerID, vtypes.KeysignResultFailed, reason, nil)
+	if err != nil {
+		s.logger.WithError(err).WithFields(logrus.Fields{
+			"tx_indexer_id": txIndexerID,
+		}).Error("failed to mark keysign result as failed")
+	}
+}
+
+// GetKeysignResultByTxIndexerID returns the state of a plugin keysign, and its signatures once completed.
+// Unlike /sign/response/:taskId it doesn't depend on the asynq task still being retained.
+func (s *Server) GetKeysignResultByTxIndexerID(c echo.Context) error {
+	txIndexerID, err := uuid.Parse(c.Param("txIndexerId"))
+	if err != nil {
+		return s.badRequest(c, msgInvalidTxIndexerID, err)
+	}
+	authenticatedPluginID, ok := c.Get("plugin_id").(vtypes.PluginID)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, NewErrorResponseWithMessage(msgRequiredPluginID))
+	}
+
+	result, err := s.db.GetKeysignResult(c.Request().Context(), txIndexerID)
+	if err != nil {
+		return s.internal(c, msgKeysignResultGetFailed, err)
+	}
+	// results of other plugins are reported as missing, not forbidden, to not leak their existence
+	if result == nil || result.PluginID != authenticatedPluginID.String() {
+		return c.JSON(http.StatusNotFound, NewErrorResponseWithMessage(msgKeysignResultNotFound))
+	}
+
+	if result.Status == vtypes.KeysignResultCompleted && len(result.Data) > 0 {
+		result.Signatures, err = vault.DecryptKeysignResult(s.cfg.EncryptionSecret, result.Data)
+		if err != nil {
+			return s.internal(c, msgKeysignResultDecodeFailed, err)
+		}
+	}
+	return c.JSON(http.StatusOK, NewSuccessResponse(http.StatusOK, result))
+}
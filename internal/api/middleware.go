@@ -1,15 +1,21 @@
 package api
 
 import (
+	"context"
+	"crypto/ed25519"
 	"errors"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/labstack/echo/v4"
 
+	"github.com/vultisig/verifier/internal/apikey"
 	"github.com/vultisig/verifier/internal/service"
+	itypes "github.com/vultisig/verifier/internal/types"
+	"github.com/vultisig/verifier/plugin/reqsign"
 )
 
 func (s *Server) AuthMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
@@ -81,12 +87,19 @@ func (s *Server) VaultAuthMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 		// Store the public key in context for later use
 		c.Set("vault_public_key", claims.PublicKey)
 
-		return next(c)
+		return s.rateLimit(c, "vault:"+claims.PublicKey, s.cfg.RateLimit.Vault, next)
 	}
 }
 
+// PluginAuthMiddleware accepts the requests signed with a signing key registered by the plugin,
+// or the requests with an API key of the plugin as bearer token. The scopes of the key are checked by RequirePluginScope.
+// Every key has its own rate limit, set by the tier of the plugin.
 func (s *Server) PluginAuthMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
+		if reqsign.Signed(c.Request()) {
+			return s.verifyPluginSignature(c, next)
+		}
+
 		authHeader := c.Request().Header.Get(echo.HeaderAuthorization)
 		if authHeader == "" {
 			return c.JSON(http.StatusUnauthorized, NewErrorResponseWithMessage(msgMissingAuthHeader))
@@ -100,7 +113,7 @@ func (s *Server) PluginAuthMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 		apiKey, err := s.db.GetAPIKey(c.Request().Context(), tokenStr)
 		if err != nil {
 			if errors.Is(err, pgx.ErrNoRows) {
-				s.logger.Warnf("API key not found: %v", tokenStr)
+				s.logger.Warnf("API key not found, prefix: %s", apikey.Prefix(tokenStr))
 				return c.JSON(http.StatusUnauthorized, NewErrorResponseWithMessage(msgAPIKeyNotFound))
 			}
 			s.logger.WithError(err).Error("fail to get API key")
@@ -116,7 +129,79 @@ func (s *Server) PluginAuthMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 				return c.JSON(http.StatusUnauthorized, NewErrorResponseWithMessage(msgExpiredAPIKey))
 			}
 		}
+		ip := c.RealIP()
+		if !apikey.IPAllowed(apiKey.AllowedIPs, ip) {
+			s.logger.Warnf("API key used from an IP not allowed, id: %s, ip: %s", apiKey.ID, ip)
+			return c.JSON(http.StatusForbidden, NewErrorResponseWithMessage(msgAPIKeyIPNotAllowed))
+		}
+		err = s.db.TouchAPIKey(c.Request().Context(), apiKey.ID, ip)
+		if err != nil {
+			s.logger.WithError(err).Warn("fail to record API key use")
+		}
 		c.Set("plugin_id", apiKey.PluginID)
-		return next(c)
+		c.Set("api_key_scopes", apiKey.Scopes)
+		return s.rateLimit(c, "apikey:"+apiKey.ID, s.cfg.RateLimit.LimitOf(apiKey.PluginID.String()), next)
+	}
+}
+
+func (s *Server) verifyPluginSignature(c echo.Context, next echo.HandlerFunc) error {
+	body, err := reqsign.ReadBody(c.Request())
+	if errors.Is(err, reqsign.ErrBodyTooLarge) {
+		return c.JSON(http.StatusRequestEntityTooLarge, NewErrorResponseWithMessage(msgRequestBodyTooLarge))
+	}
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, NewErrorResponseWithMessage(msgRequestParseFailed))
+	}
+
+	var signingKey *itypes.PluginSigningKey
+	keys := func(ctx context.Context, keyID string) (ed25519.PublicKey, error) {
+		key, err := s.db.GetPluginSigningKey(ctx, keyID)
+		if err != nil {
+			return nil, err
+		}
+		if key == nil || !key.Active(time.Now()) {
+			return nil, nil
+		}
+		signingKey = key
+		return reqsign.ParsePublicKey(key.PublicKey)
+	}
+
+	_, err = reqsign.Verify(c.Request(), body, keys, time.Now(), reqsign.DefaultWindow)
+	if err == nil {
+		err = reqsign.CheckReplay(c.Request().Context(), s.replayCache, c.Request(), reqsign.DefaultWindow)
+	}
+	switch {
+	case err == nil:
+	case errors.Is(err, reqsign.ErrStaleRequest):
+		return c.JSON(http.StatusUnauthorized, NewErrorResponseWithMessage(msgExpiredRequest))
+	case errors.Is(err, reqsign.ErrReplayedRequest):
+		s.logger.Warnf("rejected replayed plugin request signed with key: %s", c.Request().Header.Get(reqsign.HeaderKeyID))
+		return c.JSON(http.StatusUnauthorized, NewErrorResponseWithMessage(msgReplayedRequest))
+	case errors.Is(err, reqsign.ErrMissingSignature),
+		errors.Is(err, reqsign.ErrUnknownKey),
+		errors.Is(err, reqsign.ErrInvalidSignature):
+		s.logger.WithError(err).Warnf("rejected plugin request signed with key: %s", c.Request().Header.Get(reqsign.HeaderKeyID))
+		return c.JSON(http.StatusUnauthorized, NewErrorResponseWithMessage(msgInvalidRequestSignature))
+	default:
+		s.logger.WithError(err).Error("fail to verify plugin request signature")
+		return c.JSON(http.StatusInternalServerError, NewErrorResponseWithMessage(msgInternalError))
+	}
+
+	// the plugin server signs with its own key, it has every scope
+	c.Set("plugin_id", signingKey.PluginID)
+	c.Set("api_key_scopes", apikey.Scopes)
+	return s.rateLimit(c, "signingkey:"+signingKey.KeyID, s.cfg.RateLimit.LimitOf(signingKey.PluginID.String()), next)
+}
+
+// RequirePluginScope rejects the requests authenticated by PluginAuthMiddleware without scope
+func (s *Server) RequirePluginScope(scope string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			scopes, _ := c.Get("api_key_scopes").([]string)
+			if !slices.Contains(scopes, scope) {
+				return c.JSON(http.StatusForbidden, NewErrorResponseWithMessage(msgAPIKeyScopeMissing))
+			}
+			return next(c)
+		}
 	}
 }
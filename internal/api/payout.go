@@ -0,0 +1,103 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/vultisig/verifier/internal/safety"
+	itypes "github.com/vultisig/verifier/internal/types"
+	psafety "github.com/vultisig/verifier/plugin/safety"
+	vtypes "github.com/vultisig/verifier/types"
+)
+
+// payoutSignTimeout is how long a payout waits for its keysign to produce a tx before it can be signed again
+const payoutSignTimeout = 10 * time.Minute
+
+type PayoutResponse struct {
+	itypes.DeveloperPayout
+	FeeAsset itypes.FeeAsset `json:"fee_asset"`
+}
+
+// GetOpenPayouts returns the developer payouts the fee plugin has to sign
+func (s *Server) GetOpenPayouts(c echo.Context) error {
+	pluginID, ok := c.Get("plugin_id").(vtypes.PluginID)
+	if !ok || pluginID != vtypes.PluginVultisigFees_feee {
+		return c.JSON(http.StatusUnauthorized, NewErrorResponseWithMessage("unauthorized"))
+	}
+
+	payouts, err := s.db.GetOpenPayouts(c.Request().Context(), time.Now().Add(-payoutSignTimeout))
+	if err != nil {
+		return s.internal(c, msgGetPayoutsFailed, err)
+	}
+
+	resp := make([]PayoutResponse, 0, len(payouts))
+	for _, payout := range payouts {
+		resp = append(resp, PayoutResponse{
+			DeveloperPayout: payout,
+			FeeAsset:        itypes.FeeAssetOf(payout.Asset),
+		})
+	}
+	return c.JSON(http.StatusOK, NewSuccessResponse(http.StatusOK, resp))
+}
+
+// SignPayout signs the tx of a developer payout from the treasury vault, through the fee plugin keysign path.
+// The tx must send exactly the payout amount to the payout address, the fee indexer then tracks it.
+func (s *Server) SignPayout(c echo.Context) error {
+	pluginID, ok := c.Get("plugin_id").(vtypes.PluginID)
+	if !ok || pluginID != vtypes.PluginVultisigFees_feee {
+		return c.JSON(http.StatusUnauthorized, NewErrorResponseWithMessage("unauthorized"))
+	}
+	vaultPublicKey := s.cfg.Fees.Payouts.VaultPublicKey
+	if vaultPublicKey == "" {
+		return c.JSON(http.StatusServiceUnavailable, NewErrorResponseWithMessage(msgPayoutsDisabled))
+	}
+
+	payoutID, err := strconv.ParseUint(c.Param("payoutId"), 10, 64)
+	if err != nil {
+		return s.badRequest(c, msgInvalidPayoutID, err)
+	}
+
+	var req vtypes.PluginKeysignRequest
+	if err := c.Bind(&req); err != nil {
+		return s.badRequest(c, msgRequestParseFailed, err)
+	}
+	if req.PluginID != pluginID.String() {
+		return c.JSON(http.StatusForbidden, NewErrorResponseWithMessage(msgPluginIDMismatch))
+	}
+	if req.PublicKey != vaultPublicKey {
+		return s.forbidden(c, msgPayoutVaultMismatch, nil)
+	}
+	if len(req.Messages) == 0 {
+		return s.badRequest(c, msgNoMessagesToSign, nil)
+	}
+
+	if err := s.safetyMgm.EnforceKeysignScope(c.Request().Context(), psafety.KeysignScope{
+		PluginID:  req.PluginID,
+		Chains:    req.Chains(),
+		PublicKey: req.PublicKey,
+	}); err != nil {
+		if safety.IsDisabledError(err) {
+			s.logger.WithError(err).WithField("plugin_id", req.PluginID).Warn("SignPayout: Plugin is paused")
+			return c.JSON(http.StatusLocked, NewErrorResponseWithMessage(msgPluginPaused))
+		}
+		return s.internal(c, msgRequestProcessFailed, err)
+	}
+
+	payout, err := s.db.ClaimPayout(c.Request().Context(), payoutID, time.Now().Add(-payoutSignTimeout))
+	if err != nil {
+		return s.internal(c, msgClaimPayoutFailed, err)
+	}
+	if payout == nil {
+		return c.JSON(http.StatusConflict, NewErrorResponseWithMessage(msgPayoutNotOpen))
+	}
+	asset, err := vtypes.GetFeeAsset(payout.Asset)
+	if err != nil {
+		return s.internal(c, msgClaimPayoutFailed, err)
+	}
+
+	// the tx indexer tracks the payout txs under its keysign policy ID, the fee indexer completes it with them
+	return s.validateAndSign(c, &req, itypes.NewPayoutPolicy(asset, payout.PayoutAddress, payout.Amount), payout.KeysignPolicyID)
+}
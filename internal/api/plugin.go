@@ -33,6 +33,7 @@ import (
 	"github.com/vultisig/verifier/internal/safety"
 	"github.com/vultisig/verifier/internal/service"
 	"github.com/vultisig/verifier/internal/types"
+	psafety "github.com/vultisig/verifier/plugin/safety"
 	"github.com/vultisig/verifier/plugin/scheduler"
 	"github.com/vultisig/verifier/plugin/tasks"
 	"github.com/vultisig/verifier/plugin/tx_indexer/pkg/storage"
@@ -60,7 +61,12 @@ func (s *Server) SignPluginMessages(c echo.Context) error {
 		return c.JSON(http.StatusForbidden, NewErrorResponseWithMessage(msgPluginIDMismatch))
 	}
 
-	if err := s.safetyMgm.EnforceKeysign(c.Request().Context(), req.PluginID); err != nil {
+	if err := s.safetyMgm.EnforceKeysignScope(c.Request().Context(), psafety.KeysignScope{
+		PluginID:  req.PluginID,
+		Chains:    req.Chains(),
+		PublicKey: req.PublicKey,
+		PolicyID:  req.PolicyID,
+	}); err != nil {
 		if safety.IsDisabledError(err) {
 			s.logger.WithError(err).WithField("plugin_id", req.PluginID).Warn("SignPluginMessages: Plugin is paused")
 			return c.JSON(http.StatusLocked, NewErrorResponseWithMessage(msgPluginPaused))
@@ -241,18 +247,23 @@ func (s *Server) validateAndSign(c echo.Context, req *vtypes.PluginKeysignReques
 		req.Messages[i].HashFunction = vtypes.HashFunction_SHA256
 	}
 
-	var matchedRule *rtypes.Rule
-	//TODO: fee plugin priority for testing purposes
-	if req.PluginID == vtypes.PluginVultisigFees_feee.String() {
-		matchedRule, err = ngn.Evaluate(types.FeeDefaultPolicy, firstKeysignMessage.Chain, txBytesEvaluate)
-		if err != nil {
-			return s.forbidden(c, msgTxNotAllowed, err)
-		}
-	} else {
-		matchedRule, err = ngn.Evaluate(recipe, firstKeysignMessage.Chain, txBytesEvaluate)
-		if err != nil {
-			return s.forbidden(c, msgTxNotAllowed, err)
+	// The signature scheme is derived from the transaction too, e.g. taproot inputs need Schnorr
+	schemes, err := deriveSignatureSchemes(firstKeysignMessage.Chain, req.Transaction, len(derivedHashes))
+	if err != nil {
+		return s.badRequest(c, "failed to derive signature scheme", err)
+	}
+	if err := applySignatureSchemes(req.Messages, schemes); err != nil {
+		return s.badRequest(c, "invalid signature scheme", err)
+	}
+
+	matchedRule, err := ngn.Evaluate(recipe, firstKeysignMessage.Chain, txBytesEvaluate)
+	if err != nil {
+		// rejections feed the signing anomaly detection
+		recErr := s.db.InsertKeysignRejection(c.Request().Context(), vtypes.PluginID(req.PluginID), policyID, err.Error())
+		if recErr != nil {
+			s.logger.WithError(recErr).Warn("failed to record keysign rejection")
 		}
+		return s.forbidden(c, msgTxNotAllowed, err)
 	}
 
 	// Extract transaction details from matched rule's parameter constraints
@@ -298,14 +309,23 @@ func (s *Server) validateAndSign(c echo.Context, req *vtypes.PluginKeysignReques
 		return s.badRequest(c, errMsg, err)
 	}
 
+	// the result is recorded before enqueueing, so the worker always finds it to update
+	taskID := uuid.NewString()
+	err = s.createKeysignResult(c.Request().Context(), req, txToTrack.ID, taskID)
+	if err != nil {
+		return s.internal(c, msgKeysignResultCreateFailed, err)
+	}
+
 	ti, err := s.asynqClient.EnqueueContext(c.Request().Context(),
 		asynq.NewTask(tasks.TypeKeySignDKLS, buf),
 		asynq.MaxRetry(0),
 		asynq.Timeout(2*time.Minute),
 		asynq.Retention(5*time.Minute),
-		asynq.Queue(tasks.QUEUE_NAME))
+		asynq.Queue(tasks.QUEUE_NAME),
+		asynq.TaskID(taskID))
 
 	if err != nil {
+		s.failKeysignResult(c.Request().Context(), txToTrack.ID, "failed to enqueue keysign task")
 		errMsg := "fail to enqueue keysign task"
 		return s.internal(c, errMsg, err)
 	}
@@ -334,6 +354,7 @@ func (s *Server) GetPlugins(c echo.Context) error {
 	}
 
 	s.enrichPluginsWithImages(c.Request().Context(), plugins.Plugins)
+	s.enrichPluginsWithAvailability(c.Request().Context(), plugins.Plugins)
 
 	return c.JSON(http.StatusOK, NewSuccessResponse(http.StatusOK, plugins))
 }
@@ -418,6 +439,30 @@ func (s *Server) enrichPluginsWithImages(ctx context.Context, plugins []types.Pl
 	}
 }
 
+// enrichPluginsWithAvailability sets the availability of the plugins from the last health probe of their servers
+func (s *Server) enrichPluginsWithAvailability(ctx context.Context, plugins []types.Plugin) {
+	if len(plugins) == 0 {
+		return
+	}
+
+	pluginIDs := make([]vtypes.PluginID, len(plugins))
+	for i, p := range plugins {
+		pluginIDs[i] = p.ID
+	}
+
+	health, err := s.db.GetPluginHealth(ctx, pluginIDs)
+	if err != nil {
+		s.logger.WithError(err).Warn("failed to fetch plugin health for enrichment")
+		return
+	}
+
+	for i := range plugins {
+		if h, ok := health[plugins[i].ID]; ok {
+			plugins[i].Availability = h.Availability()
+		}
+	}
+}
+
 func (s *Server) GetInstalledPlugins(c echo.Context) error {
 	publicKey, ok := c.Get("vault_public_key").(string)
 	if !ok || publicKey == "" {
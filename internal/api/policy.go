@@ -222,6 +222,10 @@ func (s *Server) UpdatePluginPolicyById(c echo.Context) error {
 			return c.JSON(http.StatusBadRequest, NewErrorResponseWithMessage(msgReactivateExpiredPolicy))
 		}
 
+		if *r == types.DeactivationReasonUnpaidFees {
+			return c.JSON(http.StatusPaymentRequired, NewErrorResponseWithMessage(msgReactivateUnpaidFees))
+		}
+
 		if *r != types.DeactivationReasonUser && *r != types.DeactivationReasonPluginPause {
 			return c.JSON(http.StatusBadRequest, NewErrorResponseWithMessage(msgReactivateInvalidReason))
 		}
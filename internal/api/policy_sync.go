@@ -0,0 +1,93 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	itypes "github.com/vultisig/verifier/internal/types"
+)
+
+// PolicySyncResponse is the delivery state of the changes of a policy to its plugin server. Status is the state
+// of the change being delivered, dead_lettered once the others are done when the plugin server gave up on some,
+// and synced once every change was delivered.
+type PolicySyncResponse struct {
+	PolicyID     uuid.UUID         `json:"policy_id"`
+	Status       string            `json:"status"`
+	DeadLettered int               `json:"dead_lettered"`
+	Syncs        []PolicySyncEntry `json:"syncs"`
+}
+
+type PolicySyncEntry struct {
+	ID            uuid.UUID  `json:"id"`
+	Type          string     `json:"type"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	Reason        string     `json:"reason,omitempty"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+	SyncedAt      *time.Time `json:"synced_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// GetPluginPolicySyncStatus returns the changes of a policy queued for its plugin server, in delivery order
+func (s *Server) GetPluginPolicySyncStatus(c echo.Context) error {
+	policyUUID, err := uuid.Parse(c.Param("policyId"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, NewErrorResponseWithMessage(msgInvalidPolicyID))
+	}
+
+	publicKey, ok := c.Get("vault_public_key").(string)
+	if !ok || publicKey == "" {
+		return c.JSON(http.StatusInternalServerError, NewErrorResponseWithMessage(msgVaultPublicKeyGetFailed))
+	}
+	policy, err := s.policyService.GetPluginPolicy(c.Request().Context(), policyUUID)
+	if err != nil {
+		s.logger.WithError(err).Errorf("failed to get plugin policy, id:%s", policyUUID)
+		return c.JSON(http.StatusInternalServerError, NewErrorResponseWithMessage(msgPolicyGetFailed))
+	}
+	if policy.PublicKey != publicKey {
+		return c.JSON(http.StatusForbidden, NewErrorResponseWithMessage(msgPublicKeyMismatch))
+	}
+
+	syncs, err := s.policyService.GetPolicySyncs(c.Request().Context(), policyUUID)
+	if err != nil {
+		return s.internal(c, msgPolicySyncGetFailed, err)
+	}
+
+	resp := PolicySyncResponse{
+		PolicyID: policyUUID,
+		Syncs:    make([]PolicySyncEntry, 0, len(syncs)),
+	}
+	for _, sync := range syncs {
+		entry := PolicySyncEntry{
+			ID:        sync.ID,
+			Type:      sync.SyncType.String(),
+			Status:    sync.Status.String(),
+			Attempts:  sync.Attempts,
+			Reason:    sync.FailReason,
+			SyncedAt:  sync.SyncedAt,
+			CreatedAt: sync.CreatedAt,
+		}
+		switch sync.Status {
+		case itypes.Synced:
+		case itypes.DeadLettered:
+			resp.DeadLettered++
+		default:
+			entry.NextAttemptAt = &sync.NextAttemptAt
+			// a failed change holds back the later ones, it is the state of the policy
+			if resp.Status == "" {
+				resp.Status = sync.Status.String()
+			}
+		}
+		resp.Syncs = append(resp.Syncs, entry)
+	}
+	if resp.Status == "" {
+		resp.Status = itypes.Synced.String()
+		if resp.DeadLettered > 0 {
+			resp.Status = itypes.DeadLettered.String()
+		}
+	}
+	return c.JSON(http.StatusOK, NewSuccessResponse(http.StatusOK, resp))
+}
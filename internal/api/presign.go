@@ -0,0 +1,85 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/labstack/echo/v4"
+
+	"github.com/vultisig/verifier/internal/safety"
+	psafety "github.com/vultisig/verifier/plugin/safety"
+	"github.com/vultisig/verifier/plugin/tasks"
+	vtypes "github.com/vultisig/verifier/types"
+	"github.com/vultisig/vultisig-go/common"
+)
+
+// PresignPluginVault queues a presign session for the verifier's share of a vault the plugin is installed on.
+// Presignatures don't authorize anything by themselves, every keysign consuming one is still validated
+// against the policy.
+func (s *Server) PresignPluginVault(c echo.Context) error {
+	var req vtypes.PresignRequest
+	if err := c.Bind(&req); err != nil {
+		return s.badRequest(c, "fail to parse request", err)
+	}
+
+	authenticatedPluginID, ok := c.Get("plugin_id").(vtypes.PluginID)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, NewErrorResponseWithMessage(msgRequiredPluginID))
+	}
+	if authenticatedPluginID.String() != req.PluginID {
+		s.logger.Warnf("Plugin ID mismatch: authenticated=%s, requested=%s", authenticatedPluginID, req.PluginID)
+		return c.JSON(http.StatusForbidden, NewErrorResponseWithMessage(msgPluginIDMismatch))
+	}
+	if err := req.IsValid(); err != nil {
+		return s.badRequest(c, msgInvalidPresignRequest, err)
+	}
+
+	ctx := c.Request().Context()
+	if err := s.safetyMgm.EnforceKeysignScope(ctx, psafety.KeysignScope{
+		PluginID:  req.PluginID,
+		Chains:    []common.Chain{req.Chain},
+		PublicKey: req.PublicKey,
+	}); err != nil {
+		if safety.IsDisabledError(err) {
+			return c.JSON(http.StatusLocked, NewErrorResponseWithMessage(msgPluginPaused))
+		}
+		return s.internal(c, "EnforceKeysign failed", err)
+	}
+
+	exist, err := s.vaultStorage.Exist(common.GetVaultBackupFilename(req.PublicKey, req.PluginID))
+	if err != nil {
+		return s.internal(c, "failed to check vault existence", err)
+	}
+	if !exist {
+		return c.JSON(http.StatusNotFound, NewErrorResponseWithMessage(msgVaultNotFound))
+	}
+
+	// concurrent requests may both pass this check, the store enforces the cap when the presignatures are saved
+	available, err := s.db.CountPresigns(ctx, req.PublicKey, req.PluginID, req.Chain.GetDerivePath())
+	if err != nil {
+		return s.internal(c, "failed to count presignatures", err)
+	}
+	if available+req.Count > vtypes.MaxPresignPoolSize {
+		return c.JSON(http.StatusTooManyRequests, NewErrorResponseWithMessage(msgPresignPoolFull))
+	}
+
+	buf, err := json.Marshal(req)
+	if err != nil {
+		return s.badRequest(c, "fail to marshal to json", err)
+	}
+	ti, err := s.asynqClient.EnqueueContext(ctx,
+		asynq.NewTask(tasks.TypePresignDKLS, buf),
+		asynq.MaxRetry(0),
+		asynq.Timeout(5*time.Minute),
+		asynq.Retention(10*time.Minute),
+		asynq.Queue(tasks.QUEUE_NAME))
+	if err != nil {
+		return s.internal(c, "fail to enqueue presign task", err)
+	}
+
+	return c.JSON(http.StatusOK, NewSuccessResponse(http.StatusOK, map[string][]string{
+		"task_ids": {ti.ID},
+	}))
+}
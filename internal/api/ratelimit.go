@@ -0,0 +1,51 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/vultisig/verifier/internal/ratelimit"
+)
+
+// RateLimitGlobal limits every request by client IP ahead of the auth middlewares
+func (s *Server) RateLimitGlobal(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if c.Path() == "/healthz" {
+			return next(c)
+		}
+		return s.rateLimit(c, "global:"+c.RealIP(), s.cfg.RateLimit.Global, next)
+	}
+}
+
+// RateLimitByIP limits the requests of the public routes by client IP,
+// the authenticated routes are limited by API key or vault in their auth middleware
+func (s *Server) RateLimitByIP(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return s.rateLimit(c, "ip:"+c.RealIP(), s.cfg.RateLimit.IP, next)
+	}
+}
+
+// rateLimit takes a token from the bucket of key before calling next. When the shared buckets can't be read
+// the instance falls back to its own, so that a redis outage neither takes the API down nor lifts the limits.
+func (s *Server) rateLimit(c echo.Context, key string, limit ratelimit.Limit, next echo.HandlerFunc) error {
+	if !limit.Enabled() {
+		return next(c)
+	}
+
+	res, err := s.limiter.TakeRateLimitToken(c.Request().Context(), key, limit)
+	if err != nil {
+		s.logger.WithError(err).Warnf("fail to check rate limit of %s, using the local limiter", key)
+		res, err = s.fallbackLimiter.TakeRateLimitToken(c.Request().Context(), key, limit)
+		if err != nil {
+			s.logger.WithError(err).Errorf("fail to check rate limit of %s", key)
+			return c.JSON(http.StatusInternalServerError, NewErrorResponseWithMessage(msgInternalError))
+		}
+	}
+
+	ratelimit.SetHeaders(c.Response().Header(), limit, res)
+	if !res.Allowed {
+		return c.JSON(http.StatusTooManyRequests, NewErrorResponseWithMessage(msgRateLimited))
+	}
+	return next(c)
+}
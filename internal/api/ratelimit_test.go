@@ -0,0 +1,134 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vultisig/verifier/config"
+	"github.com/vultisig/verifier/internal/ratelimit"
+)
+
+// memoryLimiter never refills its buckets
+type memoryLimiter struct {
+	taken map[string]int
+	err   error
+}
+
+func (m *memoryLimiter) TakeRateLimitToken(_ context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	if m.err != nil {
+		return ratelimit.Result{}, m.err
+	}
+	if m.taken[key] >= limit.Burst {
+		return ratelimit.Result{Allowed: false}, nil
+	}
+	m.taken[key]++
+	return ratelimit.Result{Allowed: true, Tokens: float64(limit.Burst - m.taken[key])}, nil
+}
+
+func rateLimitedRequest(t *testing.T, s *Server, ip string) *httptest.ResponseRecorder {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/plugins", nil)
+	req.Header.Set(echo.HeaderXRealIP, ip)
+	rec := httptest.NewRecorder()
+	handler := s.RateLimitByIP(func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+	require.NoError(t, handler(e.NewContext(req, rec)))
+	return rec
+}
+
+func TestRateLimitByIP(t *testing.T) {
+	limiter := &memoryLimiter{taken: map[string]int{}}
+	var cfg config.VerifierConfig
+	cfg.RateLimit.IP = ratelimit.Limit{Rate: 1, Burst: 2}
+	s := &Server{cfg: cfg, limiter: limiter, logger: logrus.New()}
+
+	rec := rateLimitedRequest(t, s, "10.0.0.1")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "2", rec.Header().Get(ratelimit.HeaderLimit))
+	require.Equal(t, "1", rec.Header().Get(ratelimit.HeaderRemaining))
+
+	require.Equal(t, http.StatusOK, rateLimitedRequest(t, s, "10.0.0.1").Code)
+	rec = rateLimitedRequest(t, s, "10.0.0.1")
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+	require.Equal(t, "0", rec.Header().Get(ratelimit.HeaderRemaining))
+	require.Equal(t, "1", rec.Header().Get("Retry-After"))
+
+	// buckets are per IP
+	require.Equal(t, http.StatusOK, rateLimitedRequest(t, s, "10.0.0.2").Code)
+	require.Equal(t, 2, limiter.taken["ip:10.0.0.1"])
+}
+
+func TestRateLimitByIP_Fallback(t *testing.T) {
+	var cfg config.VerifierConfig
+	cfg.RateLimit.IP = ratelimit.Limit{Rate: 1, Burst: 1}
+
+	// redis unavailable, the instance keeps limiting with its own buckets
+	s := &Server{
+		cfg:             cfg,
+		limiter:         &memoryLimiter{err: errors.New("connection refused")},
+		fallbackLimiter: ratelimit.NewMemoryStore(),
+		logger:          logrus.New(),
+	}
+	rec := rateLimitedRequest(t, s, "10.0.0.1")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "1", rec.Header().Get(ratelimit.HeaderLimit))
+	require.Equal(t, http.StatusTooManyRequests, rateLimitedRequest(t, s, "10.0.0.1").Code)
+
+	// limit disabled
+	cfg.RateLimit.IP = ratelimit.Limit{}
+	s = &Server{cfg: cfg, limiter: &memoryLimiter{taken: map[string]int{}}, logger: logrus.New()}
+	for range 3 {
+		require.Equal(t, http.StatusOK, rateLimitedRequest(t, s, "10.0.0.1").Code)
+	}
+}
+
+func TestRateLimitGlobal(t *testing.T) {
+	limiter := &memoryLimiter{taken: map[string]int{}}
+	var cfg config.VerifierConfig
+	cfg.RateLimit.Global = ratelimit.Limit{Rate: 1, Burst: 1}
+	s := &Server{cfg: cfg, limiter: limiter, logger: logrus.New()}
+
+	e := echo.New()
+	e.Use(s.RateLimitGlobal)
+	// the bucket is taken before the credentials are checked
+	e.GET("/auth/me", func(c echo.Context) error {
+		return c.NoContent(http.StatusUnauthorized)
+	})
+	e.GET("/healthz", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	serve := func(path string) int {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = "10.0.0.1:5000"
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	require.Equal(t, http.StatusUnauthorized, serve("/auth/me"))
+	require.Equal(t, http.StatusTooManyRequests, serve("/auth/me"))
+	require.Equal(t, http.StatusOK, serve("/healthz"))
+	require.Equal(t, 1, limiter.taken["global:10.0.0.1"])
+}
+
+func TestRateLimitConfig_LimitOf(t *testing.T) {
+	cfg := config.RateLimitConfig{
+		Tiers: map[string]ratelimit.Limit{
+			"standard": {Rate: 20, Burst: 100},
+			"partner":  {Rate: 100, Burst: 500},
+		},
+		PluginTiers: map[string]string{"vultisig-dca-0000": "partner", "vultisig-fees-feee": "unknown"},
+		DefaultTier: "standard",
+	}
+	require.Equal(t, ratelimit.Limit{Rate: 100, Burst: 500}, cfg.LimitOf("vultisig-dca-0000"))
+	require.Equal(t, ratelimit.Limit{Rate: 20, Burst: 100}, cfg.LimitOf("vultisig-fees-feee"))
+	require.Equal(t, ratelimit.Limit{Rate: 20, Burst: 100}, cfg.LimitOf("vultisig-payroll-0000"))
+}
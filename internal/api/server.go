@@ -3,6 +3,7 @@ package api
 import (
 	"bytes"
 	"context"
+	"crypto/ed25519"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -24,15 +25,18 @@ import (
 
 	vv "github.com/vultisig/verifier/common/vultisig_validator"
 	"github.com/vultisig/verifier/config"
+	"github.com/vultisig/verifier/internal/apikey"
 	"github.com/vultisig/verifier/internal/clientutil"
 	"github.com/vultisig/verifier/internal/logging"
 	internalMetrics "github.com/vultisig/verifier/internal/metrics"
+	"github.com/vultisig/verifier/internal/ratelimit"
 	"github.com/vultisig/verifier/internal/safety"
 	"github.com/vultisig/verifier/internal/service"
 	"github.com/vultisig/verifier/internal/sigutil"
 	"github.com/vultisig/verifier/internal/storage"
 	"github.com/vultisig/verifier/internal/storage/postgres"
 	"github.com/vultisig/verifier/internal/syncer"
+	"github.com/vultisig/verifier/plugin/reqsign"
 	"github.com/vultisig/verifier/plugin/tasks"
 	"github.com/vultisig/verifier/plugin/tx_indexer"
 	vtypes "github.com/vultisig/verifier/types"
@@ -55,10 +59,18 @@ type Server struct {
 	feeService       service.Fees
 	authService      *service.AuthService
 	reportService    *service.ReportService
+	sessionStatus    *service.SessionStatusService
 	txIndexerService *tx_indexer.Service
 	httpMetrics      *internalMetrics.HTTPMetrics
 	safetyMgm        *safety.Manager
-	logger           *logrus.Logger
+	pluginSyncer     *syncer.Syncer
+	signer           *reqsign.Signer
+	receiptKey       ed25519.PublicKey
+	// replayCache records the plugin request signatures received within the replay window
+	replayCache     reqsign.ReplayCache
+	limiter         ratelimit.Store
+	fallbackLimiter ratelimit.Store
+	logger          *logrus.Logger
 }
 
 // NewServer returns a new server.
@@ -79,18 +91,28 @@ func NewServer(
 
 	logger := logrus.WithField("service", "verifier-server").Logger
 
-	// Create syncer for synchronous policy sync
-	syncer := syncer.NewPolicySyncer(db)
+	signer, err := cfg.Signing.Signer()
+	if err != nil {
+		logrus.Fatalf("Failed to load signing key: %v", err)
+	}
+	syncer := syncer.NewPolicySyncer(db, signer)
+	syncer.SetLegacyBearerTokens(cfg.Signing.LegacyBearerTokens)
+
+	receiptKey, err := cfg.DeletionReceipt.VerifyingKey()
+	if err != nil {
+		logrus.Fatalf("Failed to load deletion receipt key: %v", err)
+	}
 
-	policyService, err := service.NewPolicyService(db, syncer)
+	policyService, err := service.NewPolicyService(db, asynqClient)
 	if err != nil {
 		logrus.Fatalf("Failed to initialize policy service: %v", err)
 	}
 
-	pluginService, err := service.NewPluginService(db, redis, logger)
+	pluginService, err := service.NewPluginService(db, redis, signer, logger)
 	if err != nil {
 		logrus.Fatalf("Failed to initialize plugin service: %v", err)
 	}
+	pluginService.SetLegacyBearerTokens(cfg.Signing.LegacyBearerTokens)
 
 	feeService, err := service.NewFeeService(db, asynqClient, logger, cfg.Fees)
 	if err != nil {
@@ -99,13 +121,32 @@ func NewServer(
 
 	authService := service.NewAuthService(jwtSecret, db, logrus.WithField("service", "auth-service").Logger)
 
-	reportService, err := service.NewReportService(db, syncer, logrus.WithField("service", "report-service").Logger)
+	controlFlagService, err := service.NewControlFlagService(db, syncer, logger)
+	if err != nil {
+		logrus.Fatalf("Failed to initialize control flag service: %v", err)
+	}
+
+	reportService, err := service.NewReportService(db, controlFlagService, logrus.WithField("service", "report-service").Logger)
 	if err != nil {
 		logrus.Fatalf("Failed to initialize report service: %v", err)
 	}
 
+	sessionStatus, err := service.NewSessionStatusService(redis, nil, logrus.WithField("service", "session-status").Logger)
+	if err != nil {
+		logrus.Fatalf("Failed to initialize session status service: %v", err)
+	}
+
 	safetyMgm := safety.NewManager(db, logger)
 
+	// the buckets are per instance while redis is unavailable
+	fallbackLimiter := ratelimit.NewMemoryStore()
+	var limiter ratelimit.Store = fallbackLimiter
+	var replayCache reqsign.ReplayCache = reqsign.NewMemoryCache()
+	if redis != nil {
+		limiter = redis
+		replayCache = redis
+	}
+
 	return &Server{
 		cfg:              cfg,
 		redis:            redis,
@@ -118,16 +159,31 @@ func NewServer(
 		policyService:    policyService,
 		authService:      authService,
 		reportService:    reportService,
+		sessionStatus:    sessionStatus,
 		pluginService:    pluginService,
 		feeService:       feeService,
 		txIndexerService: txIndexerService,
 		httpMetrics:      httpMetrics,
 		safetyMgm:        safetyMgm,
+		pluginSyncer:     syncer,
+		signer:           signer,
+		receiptKey:       receiptKey,
+		limiter:          limiter,
+		fallbackLimiter:  fallbackLimiter,
+		replayCache:      replayCache,
 	}
 }
 
 func (s *Server) StartServer() error {
+	go s.pluginSyncer.WatchServerInfo(context.Background())
+
 	e := echo.New()
+	ipExtractor, err := newIPExtractor(s.cfg.Server.TrustedProxies)
+	if err != nil {
+		return fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+	// the client IP keys the rate limits and the API key allowlists, it must not come from a spoofable header
+	e.IPExtractor = ipExtractor
 
 	// Add HTTP metrics middleware if enabled
 	if s.httpMetrics != nil {
@@ -138,18 +194,16 @@ func (s *Server) StartServer() error {
 	e.Use(middleware.Recover())
 	e.Use(middleware.BodyLimit("2M")) // set maximum allowed size for a request body to 2M
 	e.Use(middleware.CORS())
-	limiterStore := middleware.NewRateLimiterMemoryStoreWithConfig(
-		middleware.RateLimiterMemoryStoreConfig{Rate: 5, Burst: 30, ExpiresIn: 5 * time.Minute},
-	)
-	e.Use(middleware.RateLimiter(limiterStore))
+	e.Use(s.RateLimitGlobal)
 
 	e.Validator = &vv.VultisigValidator{Validator: validator.New()}
 
 	e.GET("/healthz", s.Ping)
+	e.GET("/uninstall/receipt-key", s.GetDeletionReceiptKey)
 
 	// Auth endpoints - not requiring authentication
-	e.POST("/auth", s.Auth)
-	e.POST("/auth/refresh", s.RefreshToken)
+	e.POST("/auth", s.Auth, s.RateLimitByIP)
+	e.POST("/auth/refresh", s.RefreshToken, s.RateLimitByIP)
 
 	e.GET("/auth/me", s.GetMe, s.VaultAuthMiddleware)
 
@@ -163,20 +217,25 @@ func (s *Server) StartServer() error {
 	vaultGroup := e.Group("/vault", s.VaultAuthMiddleware)
 	// Reshare vault endpoint, only user who already log in can request resharing
 	vaultGroup.POST("/reshare", s.ReshareVault)
+	vaultGroup.GET("/session/:sessionId/status", s.GetSessionStatus)
 	vaultGroup.GET("/get/:pluginId/:publicKeyECDSA", s.GetVault)     // Get Vault Data
 	vaultGroup.GET("/exist/:pluginId/:publicKeyECDSA", s.ExistVault) // Check if Vault exists
 
 	// Sign endpoint, plugin should authenticate themselves using the API Key issued by the Verifier
-	pluginSigner := e.Group("/plugin-signer", s.PluginAuthMiddleware)
+	pluginSigner := e.Group("/plugin-signer", s.PluginAuthMiddleware, s.RequirePluginScope(apikey.ScopeSign))
 	pluginSigner.POST("/sign", s.SignPluginMessages)               // Sign messages
+	pluginSigner.POST("/presign", s.PresignPluginVault)            // Generate presignatures, result via /sign/response
 	pluginSigner.GET("/sign/response/:taskId", s.GetKeysignResult) // Get keysign result
+	pluginSigner.GET("/sign/result/:txIndexerId", s.GetKeysignResultByTxIndexerID)
 
 	pluginGroup := e.Group("/plugin", s.VaultAuthMiddleware)
 	pluginGroup.DELETE("/:pluginId", s.DeletePlugin) // Delete plugin
+	pluginGroup.GET("/uninstall/:taskId", s.GetUninstallReceipt)
 	pluginGroup.POST("/policy", s.CreatePluginPolicy)
 	pluginGroup.PUT("/policy", s.UpdatePluginPolicyById)
 	pluginGroup.GET("/policies/:pluginId", s.GetAllPluginPolicies)
 	pluginGroup.GET("/policy/:policyId", s.GetPluginPolicyById)
+	pluginGroup.GET("/policy/:policyId/sync", s.GetPluginPolicySyncStatus)
 	pluginGroup.GET("/policy/:pluginId/total-count", s.GetPluginInstallationsCountByID)
 	pluginGroup.DELETE("/policy/:policyId", s.DeletePluginPolicyById)
 	pluginGroup.GET("/policies/:policyId/history", s.GetPluginPolicyTransactionHistory)
@@ -184,38 +243,43 @@ func (s *Server) StartServer() error {
 
 	// fee group. These should only be accessible by the plugin server
 	feeGroup := e.Group("/fees", s.PluginAuthMiddleware)
-	feeGroup.GET("/publickey/:publicKey", s.GetPublicKeyFees)
-	feeGroup.POST("/collected", s.MarkCollected)
+	feeGroup.GET("/publickey/:publicKey", s.GetPublicKeyFees, s.RequirePluginScope(apikey.ScopeFeesRead))
+	feeGroup.POST("/collected", s.MarkCollected, s.RequirePluginScope(apikey.ScopeFeesCollect))
+	feeGroup.GET("/payouts", s.GetOpenPayouts, s.RequirePluginScope(apikey.ScopeFeesRead))
+	feeGroup.POST("/payouts/:payoutId/sign", s.SignPayout, s.RequirePluginScope(apikey.ScopeFeesCollect))
 
 	// user fee group. These should only be accessible by the plugin server
 	userFeeGroup := e.Group("/fee", s.VaultAuthMiddleware)
 	userFeeGroup.GET("/status", s.GetUserFees)
 	userFeeGroup.GET("/plugins", s.GetPluginBillingSummary)
 	userFeeGroup.GET("/plugins/:pluginId/transactions", s.GetPluginFeeHistory)
+	userFeeGroup.GET("/invoices", s.GetInvoices)
+	userFeeGroup.GET("/invoices/:invoiceId", s.GetInvoice)
 
+	// the routes behind the vault auth are limited by vault, the others by client IP
 	pluginsGroup := e.Group("/plugins")
-	pluginsGroup.GET("", s.GetPlugins)
-	pluginsGroup.GET("/available", s.GetAvailablePlugins)
-	pluginsGroup.GET("/:pluginId", s.GetPlugin)
+	pluginsGroup.GET("", s.GetPlugins, s.RateLimitByIP)
+	pluginsGroup.GET("/available", s.GetAvailablePlugins, s.RateLimitByIP)
+	pluginsGroup.GET("/:pluginId", s.GetPlugin, s.RateLimitByIP)
 	pluginsGroup.GET("/installed", s.GetInstalledPlugins, s.VaultAuthMiddleware)
 
-	pluginsGroup.GET("/:pluginId/reviews", s.GetReviews)
-	pluginsGroup.POST("/:pluginId/reviews", s.CreateReview, s.AuthMiddleware)
-	pluginsGroup.GET("/:pluginId/recipe-specification", s.GetPluginRecipeSpecification)
-	pluginsGroup.GET("/:pluginId/recipe-functions", s.GetPluginRecipeFunctions)
-	pluginsGroup.POST("/:pluginId/recipe-specification/suggest", s.GetPluginRecipeSpecificationSuggest)
-	pluginsGroup.GET("/:pluginId/skills", s.GetPluginSkills)
-	pluginsGroup.GET("/:pluginId/average-rating", s.GetPluginAvgRating)
+	pluginsGroup.GET("/:pluginId/reviews", s.GetReviews, s.RateLimitByIP)
+	pluginsGroup.POST("/:pluginId/reviews", s.CreateReview, s.RateLimitByIP, s.AuthMiddleware)
+	pluginsGroup.GET("/:pluginId/recipe-specification", s.GetPluginRecipeSpecification, s.RateLimitByIP)
+	pluginsGroup.GET("/:pluginId/recipe-functions", s.GetPluginRecipeFunctions, s.RateLimitByIP)
+	pluginsGroup.POST("/:pluginId/recipe-specification/suggest", s.GetPluginRecipeSpecificationSuggest, s.RateLimitByIP)
+	pluginsGroup.GET("/:pluginId/skills", s.GetPluginSkills, s.RateLimitByIP)
+	pluginsGroup.GET("/:pluginId/average-rating", s.GetPluginAvgRating, s.RateLimitByIP)
 	pluginsGroup.POST("/:pluginId/report", s.ReportPlugin, s.VaultAuthMiddleware)
 	pluginsGroup.GET("/proposed/validate/:pluginId", s.ValidateProposedPlugin, s.VaultAuthMiddleware)
 
-	categoriesGroup := e.Group("/categories")
+	categoriesGroup := e.Group("/categories", s.RateLimitByIP)
 	categoriesGroup.GET("", s.GetCategories)
 
-	tagsGroup := e.Group("/tags")
+	tagsGroup := e.Group("/tags", s.RateLimitByIP)
 	tagsGroup.GET("", s.GetTags)
 
-	pricingsGroup := e.Group("/pricing")
+	pricingsGroup := e.Group("/pricing", s.RateLimitByIP)
 	pricingsGroup.GET("/:pricingId", s.GetPricing)
 
 	return e.Start(fmt.Sprintf(":%d", s.cfg.Server.Port))
@@ -274,6 +338,12 @@ func (s *Server) ReshareVault(c echo.Context) error {
 		s.logger.WithError(err).Error("ReshareVault: Failed to store session in Redis")
 		return c.JSON(http.StatusInternalServerError, NewErrorResponseWithMessage(msgStoreSessionFailed))
 	}
+	// the requesting vault follows the session before the worker binds it to a vault
+	if publicKey, ok := c.Get("vault_public_key").(string); ok && publicKey != "" {
+		if err := s.sessionStatus.SetSessionRequester(c.Request().Context(), req.SessionID, publicKey); err != nil {
+			s.logger.WithError(err).WithField("session_id", req.SessionID).Warn("ReshareVault: Failed to store session requester")
+		}
+	}
 
 	// Enqueue background task
 	buf, err := json.Marshal(req)
@@ -331,11 +401,6 @@ func (s *Server) notifyPluginServerReshare(ctx context.Context, req vtypes.Resha
 		}
 	}
 
-	keyInfo, err := s.db.GetAPIKeyByPluginId(ctx, req.PluginID)
-	if err != nil {
-		return fmt.Errorf("failed to get api key by id: %w", err)
-	}
-
 	// Prepare and send request to plugin server
 	pluginURL := fmt.Sprintf("%s/vault/reshare", plugin.ServerEndpoint)
 	payload, err := json.Marshal(req)
@@ -348,7 +413,7 @@ func (s *Server) notifyPluginServerReshare(ctx context.Context, req vtypes.Resha
 		return fmt.Errorf("failed to create HTTP request: %w", err)
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+keyInfo.ApiKey)
+	s.signPluginRequest(httpReq, payload)
 	client := &http.Client{Timeout: 30 * time.Second}
 
 	resp, err := client.Do(httpReq)
@@ -465,6 +530,7 @@ func (s *Server) Auth(c echo.Context) error {
 		Signature    string `json:"signature"`      // hex encoded signature
 		ChainCodeHex string `json:"chain_code_hex"` // hex encoded chain code
 		PublicKey    string `json:"public_key"`     // hex encoded public key
+		DeviceName   string `json:"device_name"`    // optional, shown in the token list
 	}
 
 	if err := c.Bind(&req); err != nil {
@@ -541,7 +607,7 @@ func (s *Server) Auth(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, NewErrorResponseWithMessage(msgNonceStoreFailed))
 	}
 
-	tokenPair, err := s.authService.GenerateTokenPair(c.Request().Context(), req.PublicKey)
+	tokenPair, err := s.authService.GenerateTokenPair(c.Request().Context(), req.PublicKey, clientDevice(c, req.DeviceName))
 	if err != nil {
 		s.logger.Error("failed to generate token pair:", err)
 		return c.JSON(http.StatusInternalServerError, NewErrorResponseWithMessage(msgTokenGenerateFailed))
@@ -566,6 +632,28 @@ func (s *Server) Auth(c echo.Context) error {
 	return c.JSON(status, NewSuccessResponse(status, tokenPair))
 }
 
+const (
+	maxDeviceNameLength = 100
+	maxUserAgentLength  = 512
+)
+
+// clientDevice describes the client of the request for the token list, the user agent is truncated
+func clientDevice(c echo.Context, deviceName string) types.VaultTokenDevice {
+	return types.VaultTokenDevice{
+		DeviceName: truncateString(strings.TrimSpace(deviceName), maxDeviceNameLength),
+		UserAgent:  truncateString(c.Request().UserAgent(), maxUserAgentLength),
+		IPAddress:  c.RealIP(),
+	}
+}
+
+func truncateString(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
+
 // parseAuthMessage extracts nonce and expiry time from the auth message
 func parseAuthMessage(message string) (string, time.Time, error) {
 	var authData struct {
@@ -605,9 +693,15 @@ func (s *Server) RefreshToken(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, NewErrorResponseWithMessage("missing refresh token"))
 	}
 
-	tokenPair, err := s.authService.RefreshToken(c.Request().Context(), req.RefreshToken)
+	tokenPair, err := s.authService.RefreshToken(c.Request().Context(), req.RefreshToken, clientDevice(c, ""))
 	if err != nil {
 		s.logger.WithError(err).Error("fail to refresh token")
+		if errors.Is(err, service.ErrRefreshTokenReused) {
+			return c.JSON(http.StatusUnauthorized, NewErrorResponseWithMessage(msgRefreshTokenReused))
+		}
+		if errors.Is(err, service.ErrRefreshTokenRotating) {
+			return c.JSON(http.StatusConflict, NewErrorResponseWithMessage(msgRefreshTokenRotating))
+		}
 		return c.JSON(http.StatusUnauthorized, NewErrorResponseWithMessage(msgInvalidOrExpiredToken))
 	}
 
@@ -699,6 +793,11 @@ func (s *Server) GetMe(c echo.Context) error {
 	return c.JSON(http.StatusOK, NewSuccessResponse(http.StatusOK, resp))
 }
 
+// signPluginRequest authenticates a request to a plugin server, body is the body req is sent with
+func (s *Server) signPluginRequest(req *http.Request, body []byte) {
+	s.signer.Sign(req, body)
+}
+
 // notifyPluginServerDeletePlugin user would like to delete a plugin, we need to notify the plugin server
 func (s *Server) notifyPluginServerDeletePlugin(ctx context.Context, id vtypes.PluginID, publicKeyEcdsa string) error {
 	// Look up plugin server endpoint
@@ -706,10 +805,6 @@ func (s *Server) notifyPluginServerDeletePlugin(ctx context.Context, id vtypes.P
 	if err != nil {
 		return fmt.Errorf("failed to find plugin: %w", err)
 	}
-	keyInfo, err := s.db.GetAPIKeyByPluginId(ctx, id.String())
-	if err != nil {
-		return fmt.Errorf("failed to get api key by id: %w", err)
-	}
 
 	// Prepare and send request to plugin server
 	pluginURL := fmt.Sprintf("%s/vault/%s/%s", strings.TrimRight(plugin.ServerEndpoint, "/"), id, publicKeyEcdsa)
@@ -718,7 +813,7 @@ func (s *Server) notifyPluginServerDeletePlugin(ctx context.Context, id vtypes.P
 		return fmt.Errorf("failed to create HTTP request: %w", err)
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+keyInfo.ApiKey)
+	s.signPluginRequest(httpReq, nil)
 	client := &http.Client{Timeout: 30 * time.Second}
 
 	resp, err := client.Do(httpReq)
@@ -758,7 +853,7 @@ func (s *Server) DeletePlugin(c echo.Context) error {
 			s.logger.WithError(err).Error("Failed to get user fees")
 			return c.JSON(http.StatusInternalServerError, NewErrorResponseWithMessage(msgGetUserFeesFailed))
 		}
-		if status.UnpaidAmount > 0 {
+		if status.HasUnpaid() {
 			return c.JSON(http.StatusForbidden, NewErrorResponseWithMessage("Unable to uninstall due to outstanding fees"))
 		}
 	}
@@ -772,13 +867,115 @@ func (s *Server) DeletePlugin(c echo.Context) error {
 		s.logger.Errorf("Failed to delete plugin policies: %v", err)
 		return c.JSON(http.StatusInternalServerError, NewErrorResponseWithMessage(msgPoliciesDeleteFailed))
 	}
-	fileName := common.GetVaultBackupFilename(publicKey, pluginID)
-	// delete the vault
-	if err := s.vaultStorage.DeleteFile(fileName); err != nil {
-		s.logger.WithError(err).Error("Failed to delete vault file")
+	// destroy the verifier's key share in the worker, the receipt is available through GetUninstallReceipt
+	buf, err := json.Marshal(vtypes.VaultUninstallRequest{
+		PublicKey:   publicKey,
+		PluginID:    pluginID,
+		RequestedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		s.logger.WithError(err).Error("DeletePlugin: Failed to marshal uninstall request")
+		return c.JSON(http.StatusInternalServerError, NewErrorResponseWithMessage(msgRequestProcessFailed))
+	}
+	ti, err := s.asynqClient.EnqueueContext(c.Request().Context(),
+		asynq.NewTask(tasks.TypeVaultUninstall, buf),
+		asynq.MaxRetry(5),
+		asynq.Timeout(2*time.Minute),
+		asynq.Retention(7*24*time.Hour),
+		asynq.Queue(tasks.QUEUE_NAME))
+	if err != nil {
+		s.logger.WithError(err).Error("DeletePlugin: Failed to enqueue uninstall task")
 		return c.JSON(http.StatusInternalServerError, NewErrorResponseWithMessage(msgVaultShareDeleteFailed))
 	}
 
 	status := http.StatusOK
-	return c.JSON(status, NewSuccessResponse(status, "deleted"))
+	return c.JSON(status, NewSuccessResponse(status, map[string]string{
+		"status":  "deleting",
+		"task_id": ti.ID,
+	}))
+}
+
+// GetUninstallReceipt returns the signed deletion receipt produced by the uninstall task
+func (s *Server) GetUninstallReceipt(c echo.Context) error {
+	taskID := c.Param("taskId")
+	if taskID == "" {
+		return c.JSON(http.StatusBadRequest, NewErrorResponseWithMessage(msgRequiredTaskID))
+	}
+	publicKey, ok := c.Get("vault_public_key").(string)
+	if !ok || publicKey == "" {
+		return c.JSON(http.StatusInternalServerError, NewErrorResponseWithMessage(msgVaultPublicKeyGetFailed))
+	}
+
+	result, err := tasks.GetTaskResult(s.inspector, taskID)
+	if err != nil {
+		if err.Error() == "task is still in progress" {
+			return c.JSON(http.StatusOK, NewSuccessResponse(http.StatusOK, map[string]string{"status": "deleting"}))
+		}
+		s.logger.WithError(err).Error("failed to get uninstall task result")
+		return c.JSON(http.StatusNotFound, NewErrorResponseWithMessage(msgUninstallReceiptNotFound))
+	}
+
+	var receipt vtypes.VaultDeletionReceipt
+	if err := json.Unmarshal(result, &receipt); err != nil {
+		s.logger.WithError(err).Error("failed to unmarshal deletion receipt")
+		return c.JSON(http.StatusInternalServerError, NewErrorResponseWithMessage(msgInternalError))
+	}
+	if receipt.PublicKey != publicKey {
+		return c.JSON(http.StatusForbidden, NewErrorResponseWithMessage(msgPublicKeyMismatch))
+	}
+	if err := vault.VerifyDeletionReceipt(receipt, s.receiptKey); err != nil {
+		s.logger.WithError(err).WithField("task_id", taskID).Error("deletion receipt doesn't verify against the receipt key")
+		return c.JSON(http.StatusInternalServerError, NewErrorResponseWithMessage(msgInternalError))
+	}
+
+	return c.JSON(http.StatusOK, NewSuccessResponse(http.StatusOK, receipt))
+}
+
+// GetDeletionReceiptKey publishes the public key the deletion receipts are signed with
+func (s *Server) GetDeletionReceiptKey(c echo.Context) error {
+	return c.JSON(http.StatusOK, NewSuccessResponse(http.StatusOK, map[string]string{
+		"public_key": hex.EncodeToString(s.receiptKey),
+	}))
+}
+
+// GetSessionStatus returns the current phase of a keygen or reshare session run by the worker
+func (s *Server) GetSessionStatus(c echo.Context) error {
+	sessionID := c.Param("sessionId")
+	if sessionID == "" {
+		return c.JSON(http.StatusBadRequest, NewErrorResponseWithMessage(msgRequiredSessionID))
+	}
+	publicKey, ok := c.Get("vault_public_key").(string)
+	if !ok || publicKey == "" {
+		return c.JSON(http.StatusInternalServerError, NewErrorResponseWithMessage(msgVaultPublicKeyGetFailed))
+	}
+
+	ctx := c.Request().Context()
+	status, err := s.sessionStatus.GetSessionStatus(ctx, sessionID)
+	if err != nil {
+		s.logger.WithError(err).WithField("session_id", sessionID).Error("failed to get session status")
+		return c.JSON(http.StatusInternalServerError, NewErrorResponseWithMessage(msgSessionStatusGetFailed))
+	}
+	requester, err := s.sessionStatus.GetSessionRequester(ctx, sessionID)
+	if err != nil {
+		s.logger.WithError(err).WithField("session_id", sessionID).Error("failed to get session requester")
+		return c.JSON(http.StatusInternalServerError, NewErrorResponseWithMessage(msgSessionStatusGetFailed))
+	}
+
+	// the vault that requested the session sees it from the start, the vault it is bound to once its key exists
+	if requester != publicKey {
+		if status == nil {
+			return c.JSON(http.StatusNotFound, NewErrorResponseWithMessage(msgSessionStatusNotFound))
+		}
+		if status.PublicKey != publicKey {
+			return c.JSON(http.StatusForbidden, NewErrorResponseWithMessage(msgPublicKeyMismatch))
+		}
+	}
+	if status == nil {
+		status = &vtypes.SessionStatus{
+			SessionID: sessionID,
+			Phase:     vtypes.SessionPhaseQueued,
+		}
+	}
+
+	return c.JSON(http.StatusOK, NewSuccessResponse(http.StatusOK, status))
 }
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/vultisig/vultisig-go/common"
+
+	vtypes "github.com/vultisig/verifier/types"
+	"github.com/vultisig/verifier/vault"
+)
+
+// deriveSignatureSchemes returns the scheme each derived signing hash has to be signed with,
+// in the same order as deriveSigningHashes. Bitcoin P2TR key-path inputs need BIP-340 Schnorr,
+// which the signer doesn't support, so applySignatureSchemes rejects them. Every other input uses the chain default.
+func deriveSignatureSchemes(chain common.Chain, originalTx string, count int) ([]vtypes.SignatureScheme, error) {
+	schemes := make([]vtypes.SignatureScheme, count)
+	if chain != common.Bitcoin {
+		for i := range schemes {
+			schemes[i] = vtypes.DefaultSignatureScheme(chain)
+		}
+		return schemes, nil
+	}
+
+	psbtBytes, err := base64.StdEncoding.DecodeString(originalTx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode PSBT base64: %w", err)
+	}
+	pkt, err := psbt.NewFromRawBytes(bytes.NewReader(psbtBytes), false)
+	if err != nil {
+		return nil, fmt.Errorf("parse psbt: %w", err)
+	}
+	if len(pkt.Inputs) != count {
+		return nil, fmt.Errorf("expected %d PSBT inputs, got %d", count, len(pkt.Inputs))
+	}
+
+	for i, input := range pkt.Inputs {
+		schemes[i] = vtypes.SignatureSchemeECDSA
+		if input.WitnessUtxo == nil || !txscript.IsPayToTaproot(input.WitnessUtxo.PkScript) {
+			continue
+		}
+		if len(input.TaprootLeafScript) > 0 || len(input.TaprootScriptSpendSig) > 0 {
+			return nil, fmt.Errorf("input %d: taproot script-path spends are not supported", i)
+		}
+		schemes[i] = vtypes.SignatureSchemeSchnorr
+	}
+	return schemes, nil
+}
+
+// applySignatureSchemes sets the derived scheme on every message. A scheme set by the plugin
+// must match the derived one, and the signer must be able to produce it.
+func applySignatureSchemes(messages []vtypes.KeysignMessage, schemes []vtypes.SignatureScheme) error {
+	for i := range messages {
+		scheme := schemes[i]
+		if err := scheme.ValidateForChain(messages[i].Chain); err != nil {
+			return fmt.Errorf("message %d: %w", i, err)
+		}
+		if messages[i].SignatureScheme != "" && messages[i].SignatureScheme != scheme {
+			return fmt.Errorf("message %d: signature scheme %s doesn't match the transaction, expected %s",
+				i, messages[i].SignatureScheme, scheme)
+		}
+		if !vault.SupportsSignatureScheme(scheme) {
+			return fmt.Errorf("message %d: %s: %w", i, scheme, vault.ErrUnsupportedSignatureScheme)
+		}
+		messages[i].SignatureScheme = scheme
+	}
+	return nil
+}
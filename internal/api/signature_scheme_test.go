@@ -0,0 +1,61 @@
+package api
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+	"github.com/vultisig/vultisig-go/common"
+
+	vtypes "github.com/vultisig/verifier/types"
+)
+
+func testPsbt(t *testing.T, pkScripts ...[]byte) (*psbt.Packet, func() string) {
+	tx := wire.NewMsgTx(2)
+	for i := range pkScripts {
+		tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{byte(i + 1)}, 0), nil, nil))
+	}
+	tx.AddTxOut(wire.NewTxOut(1000, append([]byte{0x00, 0x14}, make([]byte, 20)...)))
+	pkt, err := psbt.NewFromUnsignedTx(tx)
+	require.NoError(t, err)
+	for i, pkScript := range pkScripts {
+		pkt.Inputs[i].WitnessUtxo = wire.NewTxOut(5000, pkScript)
+	}
+	return pkt, func() string {
+		var buf bytes.Buffer
+		require.NoError(t, pkt.Serialize(&buf))
+		return base64.StdEncoding.EncodeToString(buf.Bytes())
+	}
+}
+
+func TestDeriveSignatureSchemes(t *testing.T) {
+	p2wpkh := append([]byte{0x00, 0x14}, make([]byte, 20)...)
+	p2tr := append([]byte{0x51, 0x20}, bytes.Repeat([]byte{0x02}, 32)...)
+
+	pkt, encode := testPsbt(t, p2wpkh, p2tr)
+	schemes, err := deriveSignatureSchemes(common.Bitcoin, encode(), 2)
+	require.NoError(t, err)
+	require.Equal(t, []vtypes.SignatureScheme{vtypes.SignatureSchemeECDSA, vtypes.SignatureSchemeSchnorr}, schemes)
+
+	messages := []vtypes.KeysignMessage{{Chain: common.Bitcoin}, {Chain: common.Bitcoin}}
+	require.ErrorContains(t, applySignatureSchemes(messages, schemes), "not supported")
+
+	messages = []vtypes.KeysignMessage{{Chain: common.Bitcoin, SignatureScheme: vtypes.SignatureSchemeSchnorr}}
+	require.ErrorContains(t, applySignatureSchemes(messages, schemes[:1]), "doesn't match")
+
+	pkt.Inputs[1].TaprootScriptSpendSig = []*psbt.TaprootScriptSpendSig{{
+		XOnlyPubKey: bytes.Repeat([]byte{0x02}, 32),
+		LeafHash:    bytes.Repeat([]byte{0x03}, 32),
+		Signature:   bytes.Repeat([]byte{0x04}, 64),
+	}}
+	_, err = deriveSignatureSchemes(common.Bitcoin, encode(), 2)
+	require.ErrorContains(t, err, "script-path")
+
+	schemes, err = deriveSignatureSchemes(common.Solana, "", 1)
+	require.NoError(t, err)
+	require.Equal(t, []vtypes.SignatureScheme{vtypes.SignatureSchemeEdDSA}, schemes)
+}
@@ -0,0 +1,135 @@
+// Package apikey issues the plugin API keys and checks them against their stored salted hash.
+// The first characters of a key are stored in clear to find it, the rest is only known to the plugin.
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+)
+
+const (
+	keyPrefix = "vbt_"
+	// lookupLength is how many characters of a key are stored in clear, the migrated keys use the same length
+	lookupLength = 12
+
+	ScopeSign        = "sign"
+	ScopeFeesRead    = "fees:read"
+	ScopeFeesCollect = "fees:collect"
+)
+
+// Scopes are all the scopes a key can be granted
+var Scopes = []string{ScopeSign, ScopeFeesRead, ScopeFeesCollect}
+
+// Issued is a new key, Key is returned once to the developer and never stored
+type Issued struct {
+	Key    string
+	Prefix string
+	Salt   string
+	Hash   string
+}
+
+func Generate() (*Issued, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	key := keyPrefix + hex.EncodeToString(secret)
+	issued := &Issued{
+		Key:    key,
+		Prefix: Prefix(key),
+		Salt:   hex.EncodeToString(salt),
+	}
+	issued.Hash = Hash(key, issued.Salt)
+	return issued, nil
+}
+
+// Prefix is the part of the key stored in clear and used to find it
+func Prefix(key string) string {
+	if len(key) <= lookupLength {
+		return key
+	}
+	return key[:lookupLength]
+}
+
+// Hash is the hex encoded SHA-256 of the salt bytes followed by the key
+func Hash(key, salt string) string {
+	saltBytes, err := hex.DecodeString(salt)
+	if err != nil {
+		saltBytes = []byte(salt)
+	}
+	sum := sha256.Sum256(append(saltBytes, key...))
+	return hex.EncodeToString(sum[:])
+}
+
+// Matches compares the key with a stored hash in constant time
+func Matches(key, salt, hash string) bool {
+	return subtle.ConstantTimeCompare([]byte(Hash(key, salt)), []byte(hash)) == 1
+}
+
+// Mask shows the prefix of a key, the only part the verifier knows after issuing it
+func Mask(prefix string) string {
+	return prefix + "..."
+}
+
+func ValidateScopes(scopes []string) error {
+	if len(scopes) == 0 {
+		return fmt.Errorf("at least one scope is required")
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(Scopes, scope) {
+			return fmt.Errorf("unknown scope: %s", scope)
+		}
+	}
+	return nil
+}
+
+// ValidateAllowedIPs checks that every entry is an IP address or a CIDR range
+func ValidateAllowedIPs(entries []string) error {
+	for _, entry := range entries {
+		if strings.Contains(entry, "/") {
+			if _, _, err := net.ParseCIDR(entry); err != nil {
+				return fmt.Errorf("invalid CIDR: %s", entry)
+			}
+			continue
+		}
+		if net.ParseIP(entry) == nil {
+			return fmt.Errorf("invalid IP address: %s", entry)
+		}
+	}
+	return nil
+}
+
+// IPAllowed reports whether ip is in the allowlist, an empty allowlist allows every IP
+func IPAllowed(allowed []string, ip string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, entry := range allowed {
+		if strings.Contains(entry, "/") {
+			_, network, err := net.ParseCIDR(entry)
+			if err == nil && network.Contains(parsed) {
+				return true
+			}
+			continue
+		}
+		if allowedIP := net.ParseIP(entry); allowedIP != nil && allowedIP.Equal(parsed) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+package apikey
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIssuedKeyMatches(t *testing.T) {
+	issued, err := Generate()
+	require.NoError(t, err)
+	require.Len(t, issued.Key, len(keyPrefix)+64)
+	require.Equal(t, issued.Key[:lookupLength], issued.Prefix)
+	require.True(t, Matches(issued.Key, issued.Salt, issued.Hash))
+	require.False(t, Matches(issued.Key+"0", issued.Salt, issued.Hash))
+
+	other, err := Generate()
+	require.NoError(t, err)
+	require.NotEqual(t, issued.Salt, other.Salt)
+	require.False(t, Matches(issued.Key, other.Salt, other.Hash))
+
+	// the migration hashes the existing keys with the same scheme
+	require.Equal(t, "localhost-ap", Prefix("localhost-apikey"))
+	require.Equal(t, "short", Prefix("short"))
+}
+
+func TestScopesAndAllowedIPs(t *testing.T) {
+	require.NoError(t, ValidateScopes([]string{ScopeSign, ScopeFeesRead}))
+	require.Error(t, ValidateScopes(nil))
+	require.Error(t, ValidateScopes([]string{"fees:*"}))
+
+	require.NoError(t, ValidateAllowedIPs([]string{"10.0.0.0/8", "2001:db8::1"}))
+	require.Error(t, ValidateAllowedIPs([]string{"10.0.0.0/33"}))
+	require.Error(t, ValidateAllowedIPs([]string{"plugin.example"}))
+
+	allowed := []string{"10.0.0.0/8", "192.168.1.7"}
+	require.True(t, IPAllowed(nil, "203.0.113.9"))
+	require.True(t, IPAllowed(allowed, "10.1.2.3"))
+	require.True(t, IPAllowed(allowed, "192.168.1.7"))
+	require.False(t, IPAllowed(allowed, "192.168.1.8"))
+	require.False(t, IPAllowed(allowed, ""))
+}
@@ -65,9 +65,11 @@ func (s *FeeManagementService) RegisterInstallation(ctx context.Context, pluginI
 		}
 
 		var installationFee uint64
+		var asset vtypes.PricingAsset
 		for _, pricing := range pluginInfo.Pricing {
 			if pricing.Type == vtypes.PricingTypeOnce {
 				installationFee = pricing.Amount
+				asset = pricing.Asset
 				break
 			}
 		}
@@ -83,6 +85,7 @@ func (s *FeeManagementService) RegisterInstallation(ctx context.Context, pluginI
 			PublicKey:      publicKey,
 			TxType:         vtypes.TxTypeDebit,
 			Amount:         installationFee,
+			Asset:          asset,
 			FeeType:        vtypes.FeeTypeInstallationFee,
 			UnderlyingType: "plugin",
 			UnderlyingID:   pluginID.String(),
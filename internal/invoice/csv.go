@@ -0,0 +1,56 @@
+package invoice
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	itypes "github.com/vultisig/verifier/internal/types"
+)
+
+var csvHeader = []string{"section", "date", "plugin_id", "app_name", "type", "reference", "count", "asset", "amount"}
+
+// WriteCSV writes the invoice as one row per line item, credit, collection and total
+func WriteCSV(w io.Writer, inv *itypes.Invoice) error {
+	cw := csv.NewWriter(w)
+	rows := [][]string{csvHeader}
+
+	for _, item := range inv.LineItems {
+		rows = append(rows, []string{
+			"charge", "", item.PluginID, item.AppName, item.FeeType, "",
+			strconv.FormatUint(item.Count, 10), symbol(item.Asset), FormatAmount(item.Amount, item.Asset),
+		})
+	}
+	for _, credit := range inv.Credits {
+		rows = append(rows, []string{
+			"credit", credit.CreatedAt.UTC().Format(dateLayout), "", "", credit.FeeType, credit.Reason,
+			"1", symbol(credit.Asset), FormatAmount(credit.Amount, credit.Asset),
+		})
+	}
+	for _, batch := range inv.Batches {
+		rows = append(rows, []string{
+			"collection", batch.CreatedAt.UTC().Format(dateLayout), "", "", batch.Status, batch.TxHash,
+			"1", symbol(batch.Asset), FormatAmount(batch.Amount, batch.Asset),
+		})
+	}
+	for _, total := range inv.Totals {
+		for _, t := range []struct {
+			kind   string
+			amount uint64
+		}{
+			{"charged", total.Charged},
+			{"credited", total.Credited},
+			{"collected", total.Collected},
+		} {
+			rows = append(rows, []string{
+				"total", "", "", "", t.kind, "", "", symbol(total.Asset), FormatAmount(t.amount, total.Asset),
+			})
+		}
+	}
+
+	if err := cw.WriteAll(rows); err != nil {
+		return fmt.Errorf("failed to write invoice csv: %w", err)
+	}
+	return nil
+}
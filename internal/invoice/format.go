@@ -0,0 +1,40 @@
+package invoice
+
+import (
+	"math/big"
+	"time"
+
+	itypes "github.com/vultisig/verifier/internal/types"
+	vtypes "github.com/vultisig/verifier/types"
+)
+
+const dateLayout = "2006-01-02"
+
+// FormatAmount formats an amount in the smallest unit of asset with its decimals, e.g. 1500000 usdc is "1.500000"
+func FormatAmount(amount uint64, asset vtypes.PricingAsset) string {
+	decimals := itypes.FeeAssetOf(asset).Decimals
+	if decimals == 0 {
+		return new(big.Int).SetUint64(amount).String()
+	}
+	denom := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	return new(big.Rat).SetFrac(new(big.Int).SetUint64(amount), denom).FloatString(int(decimals))
+}
+
+func symbol(asset vtypes.PricingAsset) string {
+	return itypes.FeeAssetOf(asset).Symbol
+}
+
+// periodLabel is the first and last day of the invoice period
+func periodLabel(inv *itypes.Invoice) string {
+	return inv.PeriodStart.UTC().Format(dateLayout) + " - " + inv.PeriodEnd.UTC().Add(-time.Nanosecond).Format(dateLayout)
+}
+
+func appName(item itypes.InvoiceLineItem) string {
+	if item.AppName != "" {
+		return item.AppName
+	}
+	if item.PluginID != "" {
+		return item.PluginID
+	}
+	return "Vultisig"
+}
@@ -0,0 +1,93 @@
+package invoice
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	itypes "github.com/vultisig/verifier/internal/types"
+	vtypes "github.com/vultisig/verifier/types"
+)
+
+func testInvoice() *itypes.Invoice {
+	start := time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC)
+	inv := &itypes.Invoice{
+		ID:          uuid.MustParse("7b0c8e52-3a8d-4a5e-9d1f-0e6c2f1b7a11"),
+		PublicKey:   "02abc",
+		PeriodStart: start,
+		PeriodEnd:   start.AddDate(0, 1, 0),
+		InvoiceData: itypes.InvoiceData{
+			LineItems: []itypes.InvoiceLineItem{{
+				PluginID: "vultisig-dca-0000",
+				AppName:  "Recurring Swaps (DCA)",
+				FeeType:  vtypes.FeeTxExecFee,
+				Asset:    vtypes.PricingAssetUSDC,
+				Count:    3,
+				Amount:   1_500_000,
+			}},
+			Credits: []itypes.InvoiceCredit{{
+				FeeID:     7,
+				FeeType:   "free_credit",
+				Reason:    "support",
+				Asset:     vtypes.PricingAssetUSDC,
+				Amount:    250_000,
+				CreatedAt: start.Add(48 * time.Hour),
+			}},
+			Batches: []itypes.InvoiceBatch{{
+				BatchID:   4,
+				Asset:     vtypes.PricingAssetUSDC,
+				Amount:    1_000_000,
+				Status:    "COMPLETED",
+				TxHash:    "0xdeadbeef",
+				CreatedAt: start.Add(72 * time.Hour),
+			}},
+		},
+		CreatedAt: start.AddDate(0, 1, 0).Add(time.Hour),
+	}
+	inv.ComputeTotals()
+	return inv
+}
+
+func TestFormatAmount(t *testing.T) {
+	require.Equal(t, "1.500000", FormatAmount(1_500_000, vtypes.PricingAssetUSDC))
+	require.Equal(t, "0.000001", FormatAmount(1, vtypes.PricingAssetUSDT))
+	require.Equal(t, "0.000000", FormatAmount(0, vtypes.PricingAssetUSDC))
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, testInvoice()))
+	require.Equal(t, strings.Join([]string{
+		"section,date,plugin_id,app_name,type,reference,count,asset,amount",
+		"charge,,vultisig-dca-0000,Recurring Swaps (DCA),transaction_execution_fee,,3,USDC,1.500000",
+		"credit,2026-09-03,,,free_credit,support,1,USDC,0.250000",
+		"collection,2026-09-04,,,COMPLETED,0xdeadbeef,1,USDC,1.000000",
+		"total,,,,charged,,,USDC,1.500000",
+		"total,,,,credited,,,USDC,0.250000",
+		"total,,,,collected,,,USDC,1.000000",
+		"",
+	}, "\n"), buf.String())
+}
+
+func TestWritePDF(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WritePDF(&buf, testInvoice()))
+
+	out := buf.String()
+	require.True(t, strings.HasPrefix(out, "%PDF-1.4\n"))
+	require.True(t, strings.HasSuffix(out, "%%EOF\n"))
+	require.Contains(t, out, "/Count 1")
+	require.Contains(t, out, "(Period:   2026-09-01 - 2026-09-30) '")
+	require.Contains(t, out, "Recurring Swaps \\(DCA\\)")
+	require.Contains(t, out, "tx 0xdeadbeef")
+
+	// long statements are split on several pages
+	lines := make([]string, linesPerPage*2+1)
+	buf.Reset()
+	require.NoError(t, writePDF(&buf, lines))
+	require.Contains(t, buf.String(), "/Count 3")
+}
@@ -0,0 +1,155 @@
+package invoice
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	itypes "github.com/vultisig/verifier/internal/types"
+)
+
+// A4 in points, text is set in Courier so columns align with fmt padding
+const (
+	pageWidth     = 595
+	pageHeight    = 842
+	pageMargin    = 50
+	fontSize      = 9
+	lineHeight    = 12
+	linesPerPage  = (pageHeight - 2*pageMargin) / lineHeight
+	maxLineLength = 90
+)
+
+// WritePDF writes the invoice as a plain text statement
+func WritePDF(w io.Writer, inv *itypes.Invoice) error {
+	return writePDF(w, statementLines(inv))
+}
+
+func statementLines(inv *itypes.Invoice) []string {
+	lines := []string{
+		"VULTISIG FEE STATEMENT",
+		"",
+		"Invoice:  " + inv.ID.String(),
+		"Vault:    " + inv.PublicKey,
+		"Period:   " + periodLabel(inv),
+		"Issued:   " + inv.CreatedAt.UTC().Format(dateLayout),
+		"",
+		"CHARGES",
+	}
+	if len(inv.LineItems) == 0 {
+		lines = append(lines, "  none")
+	}
+	for _, item := range inv.LineItems {
+		lines = append(lines, fmt.Sprintf("  %-30.30s %-28.28s %6d %14s %s",
+			appName(item), item.FeeType, item.Count, FormatAmount(item.Amount, item.Asset), symbol(item.Asset)))
+	}
+
+	lines = append(lines, "", "CREDITS")
+	if len(inv.Credits) == 0 {
+		lines = append(lines, "  none")
+	}
+	for _, credit := range inv.Credits {
+		lines = append(lines, fmt.Sprintf("  %-10s %-54.54s %14s %s",
+			credit.CreatedAt.UTC().Format(dateLayout), credit.Reason,
+			FormatAmount(credit.Amount, credit.Asset), symbol(credit.Asset)))
+	}
+
+	lines = append(lines, "", "COLLECTIONS")
+	if len(inv.Batches) == 0 {
+		lines = append(lines, "  none")
+	}
+	for _, batch := range inv.Batches {
+		lines = append(lines,
+			fmt.Sprintf("  %-10s batch #%-10d %-35s %14s %s",
+				batch.CreatedAt.UTC().Format(dateLayout), batch.BatchID, batch.Status,
+				FormatAmount(batch.Amount, batch.Asset), symbol(batch.Asset)),
+			"    tx "+batch.TxHash,
+		)
+	}
+
+	lines = append(lines, "", "TOTALS")
+	for _, total := range inv.Totals {
+		s := symbol(total.Asset)
+		lines = append(lines,
+			fmt.Sprintf("  %-66s %14s %s", "Charged", FormatAmount(total.Charged, total.Asset), s),
+			fmt.Sprintf("  %-66s %14s %s", "Credited", FormatAmount(total.Credited, total.Asset), s),
+			fmt.Sprintf("  %-66s %14s %s", "Collected", FormatAmount(total.Collected, total.Asset), s),
+		)
+	}
+	return lines
+}
+
+// writePDF lays out lines on as many pages as needed, with the standard Courier font so no font is embedded
+func writePDF(w io.Writer, lines []string) error {
+	var pages [][]string
+	for len(lines) > linesPerPage {
+		pages = append(pages, lines[:linesPerPage])
+		lines = lines[linesPerPage:]
+	}
+	pages = append(pages, lines)
+
+	// objects: 1 catalog, 2 page tree, 3 font, then a page and its content stream per page
+	var objects []string
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 4+2*i)
+	}
+	objects = append(objects,
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>",
+	)
+	for i, page := range pages {
+		var content bytes.Buffer
+		fmt.Fprintf(&content, "BT\n/F1 %d Tf\n%d TL\n%d %d Td\n", fontSize, lineHeight, pageMargin, pageHeight-pageMargin)
+		for _, line := range page {
+			fmt.Fprintf(&content, "(%s) '\n", escapePDFText(line))
+		}
+		content.WriteString("ET")
+
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+				pageWidth, pageHeight, 5+2*i),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.String()),
+		)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write invoice pdf: %w", err)
+	}
+	return nil
+}
+
+// escapePDFText keeps printable ASCII, which the standard fonts can show, and escapes string delimiters
+func escapePDFText(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if i >= maxLineLength {
+			break
+		}
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20 || r > 0x7e:
+			b.WriteByte('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
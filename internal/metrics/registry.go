@@ -72,6 +72,8 @@ func registerWorkerMetrics(registry *prometheus.Registry, logger *logrus.Logger)
 	registerIfNotExists(workerVaultOperationDuration, "worker_vault_operation_duration", registry, logger)
 	registerIfNotExists(workerSignaturesGenerated, "worker_signatures_generated", registry, logger)
 	registerIfNotExists(workerErrorsTotal, "worker_errors_total", registry, logger)
+	registerIfNotExists(workerSessionPhasesTotal, "worker_session_phases_total", registry, logger)
+	registerIfNotExists(workerSessionPhaseElapsed, "worker_session_phase_elapsed", registry, logger)
 	registerIfNotExists(workerLastTaskTimestamp, "worker_last_task_timestamp", registry, logger)
 }
 
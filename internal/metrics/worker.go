@@ -73,6 +73,28 @@ var (
 		},
 	)
 
+	// Keygen / reshare session phases
+	workerSessionPhasesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "verifier",
+			Subsystem: "worker",
+			Name:      "session_phases_total",
+			Help:      "Total number of keygen and reshare sessions reaching a phase",
+		},
+		[]string{"operation", "phase"}, // phase: registered, waiting_for_parties, round, finalizing, saved, failed
+	)
+
+	workerSessionPhaseElapsed = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "verifier",
+			Subsystem: "worker",
+			Name:      "session_phase_elapsed_seconds",
+			Help:      "Time from session start until a phase is reached, by operation and phase",
+			Buckets:   []float64{0.5, 1, 5, 15, 30, 60, 120, 180, 300, 420},
+		},
+		[]string{"operation", "phase"},
+	)
+
 	// Error tracking
 	workerErrorsTotal = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
@@ -103,6 +125,7 @@ type WorkerMetricsInterface interface {
 	RecordTaskFinished(taskType string)
 	RecordVaultOperation(operation, status string, duration float64)
 	RecordError(taskType, errorType string)
+	RecordSessionPhase(operation, phase string, elapsed float64)
 	Handler(taskType string, handler asynq.HandlerFunc) asynq.HandlerFunc
 }
 
@@ -133,6 +156,7 @@ func (n *NoOpWorkerMetrics) RecordTaskStarted(taskType string)
 func (n *NoOpWorkerMetrics) RecordTaskFinished(taskType string)                              {}
 func (n *NoOpWorkerMetrics) RecordVaultOperation(operation, status string, duration float64) {}
 func (n *NoOpWorkerMetrics) RecordError(taskType, errorType string)                          {}
+func (n *NoOpWorkerMetrics) RecordSessionPhase(operation, phase string, elapsed float64)     {}
 
 // Handler returns the original handler without any metrics wrapping
 func (n *NoOpWorkerMetrics) Handler(taskType string, handler asynq.HandlerFunc) asynq.HandlerFunc {
@@ -179,6 +203,13 @@ func (wm *WorkerMetrics) RecordError(taskType, errorType string) {
 	workerErrorsTotal.WithLabelValues(taskType, errorType).Inc()
 }
 
+// RecordSessionPhase records a keygen or reshare session reaching a phase,
+// elapsed is the time since the session started
+func (wm *WorkerMetrics) RecordSessionPhase(operation, phase string, elapsed float64) {
+	workerSessionPhasesTotal.WithLabelValues(operation, phase).Inc()
+	workerSessionPhaseElapsed.WithLabelValues(operation, phase).Observe(elapsed)
+}
+
 // Handler wraps a task handler with worker metrics collection
 func (wm *WorkerMetrics) Handler(taskType string, handler asynq.HandlerFunc) asynq.HandlerFunc {
 	return asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
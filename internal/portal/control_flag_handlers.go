@@ -0,0 +1,121 @@
+package portal
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	psafety "github.com/vultisig/verifier/plugin/safety"
+)
+
+// ControlFlagResponse is the API response for a control flag
+type ControlFlagResponse struct {
+	Key       string `json:"key"`
+	Scope     string `json:"scope"`
+	ID        string `json:"id,omitempty"`
+	Action    string `json:"action"`
+	Enabled   bool   `json:"enabled"`
+	UpdatedAt string `json:"updatedAt"`
+}
+
+type ControlFlagsResponse struct {
+	Data []ControlFlagResponse `json:"data"`
+}
+
+// SetControlFlagRequest halts or resumes an action for a scope, id is the chain name, plugin ID,
+// vault public key or policy ID and is empty for the global scope
+type SetControlFlagRequest struct {
+	Scope   psafety.FlagScope `json:"scope"`
+	ID      string            `json:"id"`
+	Action  string            `json:"action"`
+	Enabled *bool             `json:"enabled"`
+	Reason  string            `json:"reason"`
+}
+
+// GetControlFlags lists the control flags of every scope (approvers only)
+func (s *Server) GetControlFlags(c echo.Context) error {
+	if _, err := s.requireApprover(c); err != nil {
+		return s.handleApproverError(c, err)
+	}
+
+	flags, err := s.db.ListControlFlags(c.Request().Context())
+	if err != nil {
+		s.logger.WithError(err).Error("failed to list control flags")
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+	}
+
+	data := make([]ControlFlagResponse, len(flags))
+	for i, f := range flags {
+		scope, id, action := psafety.ParseFlagKey(f.Key)
+		data[i] = ControlFlagResponse{
+			Key:       f.Key,
+			Scope:     string(scope),
+			ID:        id,
+			Action:    action,
+			Enabled:   f.Enabled,
+			UpdatedAt: f.UpdatedAt.Format(time.RFC3339),
+		}
+	}
+	return c.JSON(http.StatusOK, ControlFlagsResponse{Data: data})
+}
+
+// SetControlFlag halts or resumes the keysign of a chain, plugin, vault or policy, or everything globally.
+// Keygen can only be halted globally or per plugin. The verifier applies it within its flag cache TTL,
+// global and plugin flags are pushed to the plugin servers (approvers only)
+func (s *Server) SetControlFlag(c echo.Context) error {
+	address, err := s.requireApprover(c)
+	if err != nil {
+		return s.handleApproverError(c, err)
+	}
+
+	var req SetControlFlagRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	if req.Enabled == nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "enabled is required"})
+	}
+	req.ID = strings.TrimSpace(req.ID)
+	if req.Scope == psafety.ScopeGlobal {
+		req.ID = ""
+	}
+
+	var key string
+	switch req.Action {
+	case "", "keysign":
+		req.Action = "keysign"
+		key, err = psafety.ScopedKeysignKey(req.Scope, req.ID)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+	case "keygen":
+		switch {
+		case req.Scope == psafety.ScopeGlobal:
+			key = psafety.GlobalKeygenKey()
+		case req.Scope == psafety.ScopePlugin && req.ID != "":
+			key = psafety.KeygenFlagKey(req.ID)
+		default:
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "keygen can only be halted globally or per plugin"})
+		}
+	default:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "action must be keysign or keygen"})
+	}
+
+	_, err = s.controlFlags.SetFlag(c.Request().Context(), key, *req.Enabled, address, strings.TrimSpace(req.Reason))
+	if err != nil {
+		s.logger.WithError(err).Errorf("failed to set control flag %s", key)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+	}
+
+	s.logger.WithField("key", key).WithField("set_by", address).Warnf("control flag set, enabled=%t", *req.Enabled)
+	return c.JSON(http.StatusOK, ControlFlagResponse{
+		Key:       key,
+		Scope:     string(req.Scope),
+		ID:        req.ID,
+		Action:    req.Action,
+		Enabled:   *req.Enabled,
+		UpdatedAt: time.Now().UTC().Format(time.RFC3339),
+	})
+}
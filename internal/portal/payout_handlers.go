@@ -0,0 +1,76 @@
+package portal
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/vultisig/verifier/internal/conv"
+	itypes "github.com/vultisig/verifier/internal/types"
+)
+
+// PayoutResponse is the API response for a developer payout
+type PayoutResponse struct {
+	ID            string          `json:"id"`
+	PluginID      string          `json:"pluginId"`
+	PayoutAddress string          `json:"payoutAddress"`
+	FeeAsset      itypes.FeeAsset `json:"fee_asset"`
+	PeriodEnd     string          `json:"periodEnd"`
+	GrossAmount   string          `json:"grossAmount"`
+	PlatformShare string          `json:"platformShare"`
+	Amount        string          `json:"amount"`
+	Status        string          `json:"status"`
+	TxHash        string          `json:"txHash"`
+	CreatedAt     string          `json:"createdAt"`
+}
+
+type PayoutsResponse struct {
+	Data       []PayoutResponse `json:"data"`
+	TotalCount uint32           `json:"totalCount"`
+}
+
+// GetPayouts lists the payouts of the plugins owned by the requester, most recent first
+func (s *Server) GetPayouts(c echo.Context) error {
+	address, ok := c.Get("address").(string)
+	if !ok || address == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "authentication required"})
+	}
+
+	skip, take, err := conv.PageParamsFromCtx(c, 0, 20)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid pagination parameters"})
+	}
+
+	payouts, totalCount, err := s.db.GetDeveloperPayouts(c.Request().Context(), address, c.QueryParam("pluginId"), skip, take)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get payouts")
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+	}
+
+	data := make([]PayoutResponse, len(payouts))
+	for i, p := range payouts {
+		txHash := ""
+		if p.TxHash != nil {
+			txHash = *p.TxHash
+		}
+		data[i] = PayoutResponse{
+			ID:            strconv.FormatUint(p.ID, 10),
+			PluginID:      p.PluginID.String(),
+			PayoutAddress: maskPayoutAddress(p.PayoutAddress),
+			FeeAsset:      itypes.FeeAssetOf(p.Asset),
+			PeriodEnd:     p.PeriodEnd.Format(time.RFC3339),
+			GrossAmount:   strconv.FormatUint(p.GrossAmount, 10),
+			PlatformShare: strconv.FormatUint(p.PlatformShare, 10),
+			Amount:        strconv.FormatUint(p.Amount, 10),
+			Status:        string(p.Status),
+			TxHash:        txHash,
+			CreatedAt:     p.CreatedAt.Format(time.RFC3339),
+		}
+	}
+	return c.JSON(http.StatusOK, PayoutsResponse{
+		Data:       data,
+		TotalCount: totalCount,
+	})
+}
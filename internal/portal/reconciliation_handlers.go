@@ -0,0 +1,37 @@
+package portal
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/vultisig/verifier/internal/conv"
+	itypes "github.com/vultisig/verifier/internal/types"
+)
+
+type ReconciliationReportsResponse struct {
+	Reports    []itypes.ReconciliationReport `json:"reports"`
+	TotalCount uint32                        `json:"totalCount"`
+}
+
+// GetReconciliationReports lists the fee ledger reconciliation reports, most recent first (approvers only)
+func (s *Server) GetReconciliationReports(c echo.Context) error {
+	if _, err := s.requireApprover(c); err != nil {
+		return s.handleApproverError(c, err)
+	}
+
+	skip, take, err := conv.PageParamsFromCtx(c, 0, 20)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid pagination parameters"})
+	}
+
+	reports, totalCount, err := s.db.GetReconciliationReports(c.Request().Context(), skip, take)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get reconciliation reports")
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+	}
+	return c.JSON(http.StatusOK, ReconciliationReportsResponse{
+		Reports:    reports,
+		TotalCount: totalCount,
+	})
+}
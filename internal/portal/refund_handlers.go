@@ -0,0 +1,77 @@
+package portal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+
+	vtypes "github.com/vultisig/verifier/types"
+)
+
+type RefundFeeRequest struct {
+	FeeID  uint64              `json:"feeId"`
+	Amount uint64              `json:"amount"` // 0 refunds the whole fee
+	Reason vtypes.RefundReason `json:"reason"`
+	Note   string              `json:"note"`
+}
+
+type RefundFeeResponse struct {
+	FeeID    uint64 `json:"feeId"`
+	CreditID uint64 `json:"creditId"`
+}
+
+// RefundFee credits back a fee charged to a vault (approvers only)
+func (s *Server) RefundFee(c echo.Context) error {
+	address, err := s.requireApprover(c)
+	if err != nil {
+		return s.handleApproverError(c, err)
+	}
+
+	var req RefundFeeRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	if req.FeeID == 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "feeId is required"})
+	}
+	if !req.Reason.IsValid() {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid reason"})
+	}
+	req.Note = strings.TrimSpace(req.Note)
+	if req.Note == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "note is required"})
+	}
+
+	var creditID uint64
+	err = s.db.WithTransaction(c.Request().Context(), func(ctx context.Context, tx pgx.Tx) error {
+		var err error
+		creditID, err = s.db.RefundFee(ctx, tx, vtypes.Refund{
+			DebitFeeID: req.FeeID,
+			Amount:     req.Amount,
+			Reason:     req.Reason,
+			Note:       req.Note,
+			IssuedBy:   address,
+		})
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, vtypes.ErrFeeNotRefundable) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		s.logger.WithError(err).Errorf("failed to refund fee %d", req.FeeID)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+	}
+	if creditID == 0 {
+		return c.JSON(http.StatusConflict, map[string]string{"error": "fee already refunded"})
+	}
+
+	s.logger.WithField("fee_id", req.FeeID).WithField("issued_by", address).Infof("fee refunded: %s", req.Reason)
+	return c.JSON(http.StatusOK, RefundFeeResponse{
+		FeeID:    req.FeeID,
+		CreditID: creditID,
+	})
+}
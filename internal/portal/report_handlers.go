@@ -0,0 +1,229 @@
+package portal
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/vultisig/verifier/internal/conv"
+	"github.com/vultisig/verifier/internal/service"
+	itypes "github.com/vultisig/verifier/internal/types"
+	vtypes "github.com/vultisig/verifier/types"
+)
+
+// ReportResponse is the API response for a plugin report
+type ReportResponse struct {
+	PluginID          string `json:"pluginId"`
+	ReporterPublicKey string `json:"reporterPublicKey"`
+	Reason            string `json:"reason"`
+	Details           string `json:"details"`
+	ReportCount       int    `json:"reportCount"`
+	Status            string `json:"status"`
+	ReviewedBy        string `json:"reviewedBy,omitempty"`
+	ReviewedAt        string `json:"reviewedAt,omitempty"`
+	ReviewNote        string `json:"reviewNote,omitempty"`
+	CreatedAt         string `json:"createdAt"`
+	LastReportedAt    string `json:"lastReportedAt"`
+}
+
+type ReportsResponse struct {
+	Data       []ReportResponse `json:"data"`
+	TotalCount uint32           `json:"totalCount"`
+}
+
+// ReviewReportRequest is the admin decision on a report
+type ReviewReportRequest struct {
+	Status itypes.ReportStatus `json:"status"`
+	Note   string              `json:"note"`
+}
+
+// PauseHistoryResponse is the API response for a pause or unpause of a plugin
+type PauseHistoryResponse struct {
+	ID                string   `json:"id"`
+	Action            string   `json:"action"`
+	ReportCountWindow *int     `json:"reportCountWindow,omitempty"`
+	ActiveUsers       *int     `json:"activeUsers,omitempty"`
+	ThresholdRate     *float64 `json:"thresholdRate,omitempty"`
+	Reason            *string  `json:"reason,omitempty"`
+	TriggeredBy       *string  `json:"triggeredBy,omitempty"`
+	CreatedAt         string   `json:"createdAt"`
+}
+
+type PauseHistoriesResponse struct {
+	Data       []PauseHistoryResponse `json:"data"`
+	TotalCount uint32                 `json:"totalCount"`
+}
+
+// UnpausePluginRequest is the admin decision to resume a paused plugin
+type UnpausePluginRequest struct {
+	Reason string `json:"reason"`
+}
+
+func toReportResponse(r itypes.PluginReport) ReportResponse {
+	resp := ReportResponse{
+		PluginID:          r.PluginID.String(),
+		ReporterPublicKey: r.ReporterPubKey,
+		Reason:            r.Reason,
+		Details:           r.Details,
+		ReportCount:       r.ReportCount,
+		Status:            string(r.Status),
+		CreatedAt:         r.CreatedAt.Format(time.RFC3339),
+		LastReportedAt:    r.LastReportedAt.Format(time.RFC3339),
+	}
+	if r.ReviewedBy != nil {
+		resp.ReviewedBy = *r.ReviewedBy
+	}
+	if r.ReviewedAt != nil {
+		resp.ReviewedAt = r.ReviewedAt.Format(time.RFC3339)
+	}
+	if r.ReviewNote != nil {
+		resp.ReviewNote = *r.ReviewNote
+	}
+	return resp
+}
+
+// GetReports lists the plugin reports to triage, optionally filtered by pluginId and status (approvers only)
+func (s *Server) GetReports(c echo.Context) error {
+	if _, err := s.requireApprover(c); err != nil {
+		return s.handleApproverError(c, err)
+	}
+
+	status := itypes.ReportStatus(c.QueryParam("status"))
+	if status != "" && !status.IsValid() {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid status"})
+	}
+
+	skip, take, err := conv.PageParamsFromCtx(c, 0, 20)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid pagination parameters"})
+	}
+
+	reports, totalCount, err := s.db.ListReports(c.Request().Context(), vtypes.PluginID(c.QueryParam("pluginId")), status, skip, take)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to list reports")
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+	}
+
+	data := make([]ReportResponse, len(reports))
+	for i, r := range reports {
+		data[i] = toReportResponse(r)
+	}
+	return c.JSON(http.StatusOK, ReportsResponse{
+		Data:       data,
+		TotalCount: totalCount,
+	})
+}
+
+// ReviewReport marks a report as valid or invalid, invalid reports no longer count towards auto-pause (approvers only)
+func (s *Server) ReviewReport(c echo.Context) error {
+	address, err := s.requireApprover(c)
+	if err != nil {
+		return s.handleApproverError(c, err)
+	}
+
+	pluginID := c.Param("id")
+	publicKey := c.Param("publicKey")
+	if pluginID == "" || publicKey == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "id and publicKey are required"})
+	}
+
+	var req ReviewReportRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	if req.Status != itypes.ReportValid && req.Status != itypes.ReportInvalid {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "status must be VALID or INVALID"})
+	}
+
+	report, err := s.reportService.ReviewReport(c.Request().Context(), vtypes.PluginID(pluginID), publicKey, req.Status, address, strings.TrimSpace(req.Note))
+	if err != nil {
+		if errors.Is(err, service.ErrReportNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "report not found"})
+		}
+		s.logger.WithError(err).Error("failed to review report")
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+	}
+	return c.JSON(http.StatusOK, toReportResponse(*report))
+}
+
+// GetPauseHistory lists the pauses and unpauses of a plugin with their rationale, most recent first (approvers only)
+func (s *Server) GetPauseHistory(c echo.Context) error {
+	if _, err := s.requireApprover(c); err != nil {
+		return s.handleApproverError(c, err)
+	}
+
+	pluginID := c.Param("id")
+	if pluginID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "id is required"})
+	}
+
+	skip, take, err := conv.PageParamsFromCtx(c, 0, 20)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid pagination parameters"})
+	}
+
+	records, totalCount, err := s.db.GetPauseHistory(c.Request().Context(), vtypes.PluginID(pluginID), skip, take)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get pause history")
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+	}
+
+	data := make([]PauseHistoryResponse, len(records))
+	for i, r := range records {
+		data[i] = PauseHistoryResponse{
+			ID:                r.ID.String(),
+			Action:            r.Action,
+			ReportCountWindow: r.ReportCountWindow,
+			ActiveUsers:       r.ActiveUsers,
+			ThresholdRate:     r.ThresholdRate,
+			Reason:            r.Reason,
+			TriggeredBy:       r.TriggeredBy,
+			CreatedAt:         r.CreatedAt.Format(time.RFC3339),
+		}
+	}
+	return c.JSON(http.StatusOK, PauseHistoriesResponse{
+		Data:       data,
+		TotalCount: totalCount,
+	})
+}
+
+// UnpausePlugin resumes a paused plugin, records the decision and syncs the flags to the plugin (approvers only)
+func (s *Server) UnpausePlugin(c echo.Context) error {
+	address, err := s.requireApprover(c)
+	if err != nil {
+		return s.handleApproverError(c, err)
+	}
+
+	pluginID := c.Param("id")
+	if pluginID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "id is required"})
+	}
+
+	var req UnpausePluginRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.Reason == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "reason is required"})
+	}
+
+	err = s.reportService.UnpausePlugin(c.Request().Context(), vtypes.PluginID(pluginID), address, req.Reason)
+	if err != nil {
+		if errors.Is(err, service.ErrPluginNotPaused) {
+			return c.JSON(http.StatusConflict, map[string]string{"error": "plugin is not paused"})
+		}
+		s.logger.WithError(err).Errorf("failed to unpause plugin %s", pluginID)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+	}
+
+	s.logger.WithField("plugin_id", pluginID).WithField("unpaused_by", address).Infof("plugin unpaused: %s", req.Reason)
+	return c.JSON(http.StatusOK, KillSwitchResponse{
+		PluginID:       pluginID,
+		KeygenEnabled:  true,
+		KeysignEnabled: true,
+	})
+}
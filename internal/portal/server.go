@@ -2,7 +2,6 @@ package portal
 
 import (
 	"context"
-	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -22,11 +21,16 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/vultisig/verifier/config"
+	"github.com/vultisig/verifier/internal/apikey"
+	"github.com/vultisig/verifier/internal/service"
 	"github.com/vultisig/verifier/internal/sigutil"
 	"github.com/vultisig/verifier/internal/storage"
 	"github.com/vultisig/verifier/internal/storage/postgres"
 	"github.com/vultisig/verifier/internal/storage/postgres/queries"
+	"github.com/vultisig/verifier/internal/syncer"
 	itypes "github.com/vultisig/verifier/internal/types"
+	psafety "github.com/vultisig/verifier/plugin/safety"
+	vtypes "github.com/vultisig/verifier/types"
 	"github.com/vultisig/vultisig-go/address"
 	vcommon "github.com/vultisig/vultisig-go/common"
 )
@@ -42,6 +46,9 @@ type Server struct {
 	assetStorage     storage.PluginAssetStorage
 	emailService     EmailSender
 	listingFeeClient *ListingFeeClient
+	reportService    *service.ReportService
+	controlFlags     *service.ControlFlagService
+	pluginSyncer     *syncer.Syncer
 }
 
 func NewServer(cfg config.PortalConfig, pool *pgxpool.Pool, db *postgres.PostgresBackend, assetStorage storage.PluginAssetStorage) *Server {
@@ -50,6 +57,20 @@ func NewServer(cfg config.PortalConfig, pool *pgxpool.Pool, db *postgres.Postgre
 	if cfg.DeveloperServiceURL != "" {
 		listingFeeClient = NewListingFeeClient(cfg.DeveloperServiceURL)
 	}
+	signer, err := cfg.Signing.Signer()
+	if err != nil {
+		logrus.Fatalf("Failed to load signing key: %v", err)
+	}
+	pluginSyncer := syncer.NewPolicySyncer(db, signer)
+	pluginSyncer.SetLegacyBearerTokens(cfg.Signing.LegacyBearerTokens)
+	controlFlags, err := service.NewControlFlagService(db, pluginSyncer, logger)
+	if err != nil {
+		logrus.Fatalf("Failed to initialize control flag service: %v", err)
+	}
+	reportService, err := service.NewReportService(db, controlFlags, logrus.WithField("service", "report-service").Logger)
+	if err != nil {
+		logrus.Fatalf("Failed to initialize report service: %v", err)
+	}
 	return &Server{
 		cfg:              cfg,
 		pool:             pool,
@@ -61,10 +82,15 @@ func NewServer(cfg config.PortalConfig, pool *pgxpool.Pool, db *postgres.Postgre
 		assetStorage:     assetStorage,
 		emailService:     NewEmailService(cfg.Email, cfg.Server.BaseURL, logger),
 		listingFeeClient: listingFeeClient,
+		reportService:    reportService,
+		controlFlags:     controlFlags,
+		pluginSyncer:     pluginSyncer,
 	}
 }
 
 func (s *Server) Start() error {
+	go s.pluginSyncer.WatchServerInfo(context.Background())
+
 	e := echo.New()
 	e.HideBanner = true
 
@@ -110,11 +136,24 @@ func (s *Server) registerRoutes(e *echo.Echo) {
 	protected.GET("/admin/plugin-proposals/:id", s.GetPluginProposal)
 	protected.POST("/admin/plugin-proposals/:id/approve", s.ApprovePluginProposal)
 	protected.POST("/admin/plugin-proposals/:id/publish", s.PublishPluginProposal)
+	protected.POST("/admin/refunds", s.RefundFee)
+	protected.GET("/admin/reconciliations", s.GetReconciliationReports)
+	protected.GET("/admin/reports", s.GetReports)
+	protected.PUT("/admin/plugins/:id/reports/:publicKey", s.ReviewReport)
+	protected.GET("/admin/plugins/:id/pause-history", s.GetPauseHistory)
+	protected.POST("/admin/plugins/:id/unpause", s.UnpausePlugin)
+	protected.GET("/admin/control-flags", s.GetControlFlags)
+	protected.PUT("/admin/control-flags", s.SetControlFlag)
 	// API key management
 	protected.GET("/plugins/:id/api-keys", s.GetPluginApiKeys)
 	protected.POST("/plugins/:id/api-keys", s.CreatePluginApiKey)
 	protected.PUT("/plugins/:id/api-keys/:keyId", s.UpdatePluginApiKey)
 	protected.DELETE("/plugins/:id/api-keys/:keyId", s.DeletePluginApiKey)
+	protected.POST("/plugins/:id/api-keys/:keyId/rotate", s.RotatePluginApiKey)
+	// Request signing keys
+	protected.GET("/plugins/:id/signing-keys", s.GetPluginSigningKeys)
+	protected.POST("/plugins/:id/signing-keys", s.CreatePluginSigningKey)
+	protected.DELETE("/plugins/:id/signing-keys/:keyId", s.RevokePluginSigningKey)
 	// Team management
 	protected.GET("/plugins/:id/team", s.ListTeamMembers)
 	protected.POST("/plugins/:id/team/invite", s.CreateInvite)
@@ -126,6 +165,7 @@ func (s *Server) registerRoutes(e *echo.Echo) {
 	// Earnings
 	protected.GET("/earnings", s.GetEarnings)
 	protected.GET("/earnings/summary", s.GetEarningsSummary)
+	protected.GET("/payouts", s.GetPayouts)
 	// Image management
 	protected.GET("/plugins/:id/images", s.ListPluginImages)
 	protected.POST("/plugins/:id/images/upload-url", s.GetImageUploadURL)
@@ -347,9 +387,11 @@ type PluginPricingResponse struct {
 	PluginID  string          `json:"pluginId"`
 	Type      string          `json:"type"`
 	Frequency *string         `json:"frequency"`
-	Amount    string          `json:"amount"`
+	Amount    string          `json:"amount"` // basis points for the percentage metric
 	FeeAsset  itypes.FeeAsset `json:"fee_asset"`
 	Metric    string          `json:"metric"`
+	MinAmount *string         `json:"min_amount,omitempty"`
+	MaxAmount *string         `json:"max_amount,omitempty"`
 }
 
 func (s *Server) GetPluginPricings(c echo.Context) error {
@@ -378,22 +420,64 @@ func (s *Server) GetPluginPricings(c echo.Context) error {
 			Type:      string(p.Type),
 			Frequency: freq,
 			Amount:    strconv.FormatInt(p.Amount, 10),
-			FeeAsset:  itypes.DefaultFeeAsset,
+			FeeAsset:  itypes.FeeAssetOf(vtypes.PricingAsset(p.Asset)),
 			Metric:    string(p.Metric),
+			MinAmount: formatOptionalAmount(p.MinAmount),
+			MaxAmount: formatOptionalAmount(p.MaxAmount),
 		}
 	}
 
 	return c.JSON(http.StatusOK, response)
 }
 
+func formatOptionalAmount(amount pgtype.Int8) *string {
+	if !amount.Valid {
+		return nil
+	}
+	formatted := strconv.FormatInt(amount.Int64, 10)
+	return &formatted
+}
+
 // PluginApiKeyResponse is the API response for plugin API keys
 type PluginApiKeyResponse struct {
-	ID        string  `json:"id"`
-	PluginID  string  `json:"pluginId"`
-	ApiKey    string  `json:"apikey"`
-	CreatedAt string  `json:"createdAt"`
-	ExpiresAt *string `json:"expiresAt"`
-	Status    int32   `json:"status"`
+	ID         string   `json:"id"`
+	PluginID   string   `json:"pluginId"`
+	ApiKey     string   `json:"apikey"`
+	Scopes     []string `json:"scopes"`
+	AllowedIPs []string `json:"allowedIps"`
+	CreatedAt  string   `json:"createdAt"`
+	ExpiresAt  *string  `json:"expiresAt"`
+	LastUsedAt *string  `json:"lastUsedAt"`
+	LastUsedIP *string  `json:"lastUsedIp"`
+	RotatedAt  *string  `json:"rotatedAt"`
+	Status     int32    `json:"status"`
+}
+
+func newPluginApiKeyResponse(k *queries.PluginApikey) PluginApiKeyResponse {
+	format := func(t pgtype.Timestamptz) *string {
+		if !t.Valid {
+			return nil
+		}
+		s := t.Time.Format(time.RFC3339)
+		return &s
+	}
+	var lastUsedIP *string
+	if k.LastUsedIp.Valid {
+		lastUsedIP = &k.LastUsedIp.String
+	}
+	return PluginApiKeyResponse{
+		ID:         k.ID.String(),
+		PluginID:   k.PluginID,
+		ApiKey:     apikey.Mask(k.KeyPrefix),
+		Scopes:     k.Scopes,
+		AllowedIPs: k.AllowedIps,
+		CreatedAt:  k.CreatedAt.Time.Format(time.RFC3339),
+		ExpiresAt:  format(k.ExpiresAt),
+		LastUsedAt: format(k.LastUsedAt),
+		LastUsedIP: lastUsedIP,
+		RotatedAt:  format(k.RotatedAt),
+		Status:     k.Status,
+	}
 }
 
 func (s *Server) GetPluginApiKeys(c echo.Context) error {
@@ -435,42 +519,12 @@ func (s *Server) GetPluginApiKeys(c echo.Context) error {
 	// Convert to API response format
 	response := make([]PluginApiKeyResponse, len(apiKeys))
 	for i, k := range apiKeys {
-		var expiresAt *string
-		if k.ExpiresAt.Valid {
-			t := k.ExpiresAt.Time.Format(time.RFC3339)
-			expiresAt = &t
-		}
-		response[i] = PluginApiKeyResponse{
-			ID:        k.ID.String(),
-			PluginID:  string(k.PluginID),
-			ApiKey:    maskApiKey(k.Apikey),
-			CreatedAt: k.CreatedAt.Time.Format(time.RFC3339),
-			ExpiresAt: expiresAt,
-			Status:    k.Status,
-		}
+		response[i] = newPluginApiKeyResponse(k)
 	}
 
 	return c.JSON(http.StatusOK, response)
 }
 
-// maskApiKey masks an API key showing only the prefix and first/last 4 hex chars
-// e.g., "vbt_abc123...def456"
-func maskApiKey(key string) string {
-	// Key format: vbt_<64 hex chars>
-	if len(key) < 12 {
-		return key
-	}
-	// Get the prefix (vbt_) and the hex part
-	if strings.HasPrefix(key, "vbt_") {
-		hexPart := key[4:]
-		if len(hexPart) >= 8 {
-			return "vbt_" + hexPart[:4] + "..." + hexPart[len(hexPart)-4:]
-		}
-	}
-	// Fallback for keys without prefix
-	return key[:4] + "..." + key[len(key)-4:]
-}
-
 // maskPayoutAddress masks a payout address showing first 6 and last 4 chars
 func maskPayoutAddress(address string) string {
 	if address == "" {
@@ -482,28 +536,17 @@ func maskPayoutAddress(address string) string {
 	return address
 }
 
-// generateApiKey generates a random API key with vbt_ prefix and 32 bytes hex
-func generateApiKey() (string, error) {
-	bytes := make([]byte, 32)
-	if _, err := rand.Read(bytes); err != nil {
-		return "", err
-	}
-	return "vbt_" + hex.EncodeToString(bytes), nil
-}
-
 // CreateApiKeyRequest represents the request to create a new API key
 type CreateApiKeyRequest struct {
-	ExpiresAt *string `json:"expiresAt"` // RFC3339 format, optional
+	ExpiresAt  *string  `json:"expiresAt"`  // RFC3339 format, optional
+	Scopes     []string `json:"scopes"`     // optional, every scope when empty
+	AllowedIPs []string `json:"allowedIps"` // IPs or CIDR ranges, optional, every IP when empty
 }
 
 // CreateApiKeyResponse represents the response with the full API key (shown only once)
 type CreateApiKeyResponse struct {
-	ID        string  `json:"id"`
-	PluginID  string  `json:"pluginId"`
-	ApiKey    string  `json:"apikey"` // Full key, shown only on creation
-	CreatedAt string  `json:"createdAt"`
-	ExpiresAt *string `json:"expiresAt"`
-	Status    int32   `json:"status"`
+	PluginApiKeyResponse
+	ApiKey string `json:"apikey"` // Full key, shown only on creation
 }
 
 // CreatePluginApiKey creates a new API key for a plugin
@@ -542,8 +585,21 @@ func (s *Server) CreatePluginApiKey(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
 	}
 
-	// Generate the API key
-	apiKey, err := generateApiKey()
+	if len(req.Scopes) == 0 {
+		req.Scopes = apikey.Scopes
+	}
+	if err := apikey.ValidateScopes(req.Scopes); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if req.AllowedIPs == nil {
+		req.AllowedIPs = []string{}
+	}
+	if err := apikey.ValidateAllowedIPs(req.AllowedIPs); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	// Generate the API key, only its hash is stored
+	issued, err := apikey.Generate()
 	if err != nil {
 		s.logger.WithError(err).Error("failed to generate API key")
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to generate API key"})
@@ -587,9 +643,13 @@ func (s *Server) CreatePluginApiKey(c echo.Context) error {
 	}
 
 	created, err := q.CreatePluginApiKey(ctx, &queries.CreatePluginApiKeyParams{
-		PluginID:  id,
-		Apikey:    apiKey,
-		ExpiresAt: expiresAt,
+		PluginID:   id,
+		KeyPrefix:  issued.Prefix,
+		KeySalt:    issued.Salt,
+		KeyHash:    issued.Hash,
+		Scopes:     req.Scopes,
+		AllowedIps: req.AllowedIPs,
+		ExpiresAt:  expiresAt,
 	})
 	if err != nil {
 		s.logger.WithError(err).Error("failed to create API key")
@@ -602,12 +662,6 @@ func (s *Server) CreatePluginApiKey(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to create API key"})
 	}
 
-	var expiresAtStr *string
-	if created.ExpiresAt.Valid {
-		t := created.ExpiresAt.Time.Format(time.RFC3339)
-		expiresAtStr = &t
-	}
-
 	s.logger.WithFields(logrus.Fields{
 		"plugin_id": id,
 		"key_id":    created.ID.String(),
@@ -615,12 +669,8 @@ func (s *Server) CreatePluginApiKey(c echo.Context) error {
 
 	// Return the full API key (only shown once)
 	return c.JSON(http.StatusCreated, CreateApiKeyResponse{
-		ID:        created.ID.String(),
-		PluginID:  string(created.PluginID),
-		ApiKey:    apiKey, // Full key returned only on creation
-		CreatedAt: created.CreatedAt.Time.Format(time.RFC3339),
-		ExpiresAt: expiresAtStr,
-		Status:    created.Status,
+		PluginApiKeyResponse: newPluginApiKeyResponse(created),
+		ApiKey:               issued.Key,
 	})
 }
 
@@ -745,25 +795,142 @@ func (s *Server) UpdatePluginApiKey(c echo.Context) error {
 		}
 	}
 
-	var expiresAt *string
-	if updated.ExpiresAt.Valid {
-		t := updated.ExpiresAt.Time.Format(time.RFC3339)
-		expiresAt = &t
-	}
-
 	s.logger.WithFields(logrus.Fields{
 		"plugin_id": pluginID,
 		"key_id":    keyID,
 		"status":    req.Status,
 	}).Info("API key status updated")
 
-	return c.JSON(http.StatusOK, PluginApiKeyResponse{
-		ID:        updated.ID.String(),
-		PluginID:  string(updated.PluginID),
-		ApiKey:    maskApiKey(updated.Apikey),
-		CreatedAt: updated.CreatedAt.Time.Format(time.RFC3339),
-		ExpiresAt: expiresAt,
-		Status:    updated.Status,
+	return c.JSON(http.StatusOK, newPluginApiKeyResponse(updated))
+}
+
+// maxApiKeyRotationOverlap bounds how long a rotated API key can stay valid
+const maxApiKeyRotationOverlap = 7 * 24 * time.Hour
+
+// RotateApiKeyRequest represents the request to rotate an API key
+type RotateApiKeyRequest struct {
+	OverlapSeconds *int64 `json:"overlapSeconds"` // how long the rotated key stays valid, optional
+}
+
+// RotateApiKeyResponse is the new key, shown only once, and the rotated key with its new expiry
+type RotateApiKeyResponse struct {
+	CreateApiKeyResponse
+	Previous PluginApiKeyResponse `json:"previous"`
+}
+
+// RotatePluginApiKey issues a new key with the scopes and the allowed IPs of an active key,
+// the rotated key stays valid for the overlap period so the plugin servers can switch to the new one
+func (s *Server) RotatePluginApiKey(c echo.Context) error {
+	pluginID := c.Param("id")
+	keyID := c.Param("keyId")
+	if pluginID == "" || keyID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "plugin id and key id are required"})
+	}
+	if status, msg := s.checkPluginAdmin(c, pluginID, "API keys"); status != 0 {
+		return c.JSON(status, map[string]string{"error": msg})
+	}
+
+	var req RotateApiKeyRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	overlap := s.cfg.ApiKeyRotationOverlap
+	if req.OverlapSeconds != nil {
+		overlap = time.Duration(*req.OverlapSeconds) * time.Second
+		if overlap < 0 || overlap > maxApiKeyRotationOverlap {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("overlapSeconds must be between 0 and %d", int64(maxApiKeyRotationOverlap.Seconds()))})
+		}
+	}
+
+	keyUUID, err := uuid.Parse(keyID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid key id format"})
+	}
+
+	ctx := c.Request().Context()
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to begin transaction")
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to rotate API key"})
+	}
+	defer tx.Rollback(ctx)
+
+	q := queries.New(tx)
+
+	err = q.AcquireApiKeyLock(ctx, pluginID)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to acquire lock")
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to rotate API key"})
+	}
+
+	existingKey, err := q.GetPluginApiKeyByID(ctx, pgtype.UUID{Bytes: keyUUID, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "API key not found"})
+		}
+		s.logger.WithError(err).Error("failed to get API key")
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+	}
+	if existingKey.PluginID != pluginID {
+		return c.JSON(http.StatusForbidden, map[string]string{"error": "API key does not belong to this plugin"})
+	}
+	now := time.Now()
+	if existingKey.Status != 1 || (existingKey.ExpiresAt.Valid && !existingKey.ExpiresAt.Time.After(now)) {
+		return c.JSON(http.StatusConflict, map[string]string{"error": "only active API keys can be rotated"})
+	}
+	// rotating again during the overlap would keep the old key alive next to two replacements
+	if existingKey.RotatedAt.Valid {
+		return c.JSON(http.StatusConflict, map[string]string{"error": "API key is already rotated, rotate its replacement instead"})
+	}
+
+	issued, err := apikey.Generate()
+	if err != nil {
+		s.logger.WithError(err).Error("failed to generate API key")
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to generate API key"})
+	}
+
+	// the rotated key is replaced, so the limit of active keys is not checked
+	created, err := q.CreatePluginApiKey(ctx, &queries.CreatePluginApiKeyParams{
+		PluginID:   pluginID,
+		KeyPrefix:  issued.Prefix,
+		KeySalt:    issued.Salt,
+		KeyHash:    issued.Hash,
+		Scopes:     existingKey.Scopes,
+		AllowedIps: existingKey.AllowedIps,
+	})
+	if err != nil {
+		s.logger.WithError(err).Error("failed to create API key")
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to rotate API key"})
+	}
+
+	previous, err := q.ShortenPluginApiKeyExpiry(ctx, &queries.ShortenPluginApiKeyExpiryParams{
+		ExpiresAt: pgtype.Timestamptz{Time: now.Add(overlap), Valid: true},
+		ID:        existingKey.ID,
+	})
+	if err != nil {
+		s.logger.WithError(err).Error("failed to expire rotated API key")
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to rotate API key"})
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to commit transaction")
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to rotate API key"})
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"plugin_id":  pluginID,
+		"key_id":     created.ID.String(),
+		"rotated_id": keyID,
+		"overlap":    overlap.String(),
+	}).Info("API key rotated")
+
+	return c.JSON(http.StatusCreated, RotateApiKeyResponse{
+		CreateApiKeyResponse: CreateApiKeyResponse{
+			PluginApiKeyResponse: newPluginApiKeyResponse(created),
+			ApiKey:               issued.Key,
+		},
+		Previous: newPluginApiKeyResponse(previous),
 	})
 }
 
@@ -825,25 +992,12 @@ func (s *Server) DeletePluginApiKey(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to delete API key"})
 	}
 
-	var expiresAt *string
-	if expired.ExpiresAt.Valid {
-		t := expired.ExpiresAt.Time.Format(time.RFC3339)
-		expiresAt = &t
-	}
-
 	s.logger.WithFields(logrus.Fields{
 		"plugin_id": pluginID,
 		"key_id":    keyID,
 	}).Info("API key expired (deleted)")
 
-	return c.JSON(http.StatusOK, PluginApiKeyResponse{
-		ID:        expired.ID.String(),
-		PluginID:  string(expired.PluginID),
-		ApiKey:    maskApiKey(expired.Apikey),
-		CreatedAt: expired.CreatedAt.Time.Format(time.RFC3339),
-		ExpiresAt: expiresAt,
-		Status:    expired.Status,
-	})
+	return c.JSON(http.StatusOK, newPluginApiKeyResponse(expired))
 }
 
 // EarningTransactionResponse is the API response for earning transactions
@@ -954,7 +1108,7 @@ func (s *Server) GetEarnings(c echo.Context) error {
 			PluginID:    pid,
 			PluginName:  e.PluginName,
 			Amount:      strconv.FormatInt(e.Amount, 10),
-			FeeAsset:    itypes.DefaultFeeAsset,
+			FeeAsset:    itypes.FeeAssetOf(vtypes.PricingAsset(e.Asset)),
 			Type:        pricingType,
 			CreatedAt:   e.CreatedAt.Time.Format(time.RFC3339),
 			FromAddress: e.FromAddress,
@@ -1746,6 +1900,8 @@ func (s *Server) GetKillSwitch(c echo.Context) error {
 type SetKillSwitchRequest struct {
 	KeygenEnabled  *bool `json:"keygenEnabled"`
 	KeysignEnabled *bool `json:"keysignEnabled"`
+	// Reason is recorded in the pause history of the plugin
+	Reason string `json:"reason"`
 }
 
 // SetKillSwitch sets the kill switch status for a plugin (staff only)
@@ -1790,28 +1946,42 @@ func (s *Server) SetKillSwitch(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "at least one of keygenEnabled or keysignEnabled must be provided"})
 	}
 
-	// Update control flags
+	// Update control flags, the change is recorded and pushed to the plugin server
 	keygenKey := pluginID + "-keygen"
 	keysignKey := pluginID + "-keysign"
 
+	var changes []psafety.ControlFlag
+	paused := false
 	if req.KeygenEnabled != nil {
-		if err := s.queries.UpsertControlFlag(c.Request().Context(), &queries.UpsertControlFlagParams{
-			Key:     keygenKey,
-			Enabled: *req.KeygenEnabled,
-		}); err != nil {
-			s.logger.WithError(err).Error("failed to update keygen flag")
-			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to update kill switch"})
-		}
+		changes = append(changes, psafety.ControlFlag{Key: keygenKey, Enabled: *req.KeygenEnabled})
+		paused = paused || !*req.KeygenEnabled
 	}
-
 	if req.KeysignEnabled != nil {
-		if err := s.queries.UpsertControlFlag(c.Request().Context(), &queries.UpsertControlFlagParams{
-			Key:     keysignKey,
-			Enabled: *req.KeysignEnabled,
-		}); err != nil {
-			s.logger.WithError(err).Error("failed to update keysign flag")
-			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to update kill switch"})
-		}
+		changes = append(changes, psafety.ControlFlag{Key: keysignKey, Enabled: *req.KeysignEnabled})
+		paused = paused || !*req.KeysignEnabled
+	}
+
+	action := itypes.PauseActionUnpaused
+	if paused {
+		action = itypes.PauseActionManualPaused
+	}
+	record := &itypes.PauseHistoryRecord{
+		PluginID:    vtypes.PluginID(pluginID),
+		Action:      action,
+		TriggeredBy: &address,
+	}
+	if reason := strings.TrimSpace(req.Reason); reason != "" {
+		record.Reason = &reason
+	}
+
+	_, err = s.controlFlags.Change(c.Request().Context(), itypes.ControlFlagChange{
+		Flags:  changes,
+		Record: record,
+		SyncTo: []vtypes.PluginID{vtypes.PluginID(pluginID)},
+	})
+	if err != nil {
+		s.logger.WithError(err).Error("failed to update kill switch")
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to update kill switch"})
 	}
 
 	// Get updated state
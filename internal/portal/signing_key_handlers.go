@@ -0,0 +1,177 @@
+package portal
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+
+	"github.com/vultisig/verifier/internal/storage/postgres/queries"
+	itypes "github.com/vultisig/verifier/internal/types"
+	"github.com/vultisig/verifier/plugin/reqsign"
+	"github.com/vultisig/verifier/types"
+)
+
+// SigningKeyResponse is the API response for a plugin signing key
+type SigningKeyResponse struct {
+	KeyID     string  `json:"keyId"`
+	PluginID  string  `json:"pluginId"`
+	PublicKey string  `json:"publicKey"`
+	CreatedAt string  `json:"createdAt"`
+	ExpiresAt *string `json:"expiresAt"`
+	RevokedAt *string `json:"revokedAt"`
+	Active    bool    `json:"active"`
+}
+
+// CreateSigningKeyRequest registers an ed25519 public key, hex encoded
+type CreateSigningKeyRequest struct {
+	PublicKey string `json:"publicKey"`
+}
+
+func newSigningKeyResponse(k itypes.PluginSigningKey, now time.Time) SigningKeyResponse {
+	format := func(t *time.Time) *string {
+		if t == nil {
+			return nil
+		}
+		s := t.Format(time.RFC3339)
+		return &s
+	}
+	return SigningKeyResponse{
+		KeyID:     k.KeyID,
+		PluginID:  k.PluginID.String(),
+		PublicKey: k.PublicKey,
+		CreatedAt: k.CreatedAt.Format(time.RFC3339),
+		ExpiresAt: format(k.ExpiresAt),
+		RevokedAt: format(k.RevokedAt),
+		Active:    k.Active(now),
+	}
+}
+
+// checkPluginAdmin returns the status and the error to answer with when the requester is not an admin of the plugin,
+// resource names what the requester manages in the errors
+func (s *Server) checkPluginAdmin(c echo.Context, pluginID, resource string) (int, string) {
+	address, ok := c.Get("address").(string)
+	if !ok || address == "" {
+		return http.StatusUnauthorized, "authentication required"
+	}
+
+	owner, err := s.queries.GetPluginOwnerWithRole(c.Request().Context(), &queries.GetPluginOwnerWithRoleParams{
+		PluginID:  pluginID,
+		PublicKey: address,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return http.StatusForbidden, fmt.Sprintf("not authorized to manage %s for this plugin", resource)
+		}
+		s.logger.WithError(err).Error("failed to check plugin ownership")
+		return http.StatusInternalServerError, "internal server error"
+	}
+	if owner.Role != queries.PluginOwnerRoleAdmin {
+		return http.StatusForbidden, fmt.Sprintf("only admins can manage %s", resource)
+	}
+	return 0, ""
+}
+
+// GetPluginSigningKeys lists the signing keys of a plugin, most recent first
+func (s *Server) GetPluginSigningKeys(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "id is required"})
+	}
+	if status, msg := s.checkPluginAdmin(c, id, "signing keys"); status != 0 {
+		return c.JSON(status, map[string]string{"error": msg})
+	}
+
+	keys, err := s.db.GetPluginSigningKeys(c.Request().Context(), types.PluginID(id))
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get plugin signing keys")
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+	}
+
+	now := time.Now()
+	response := make([]SigningKeyResponse, len(keys))
+	for i, k := range keys {
+		response[i] = newSigningKeyResponse(k, now)
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// CreatePluginSigningKey registers a signing key, the keys the plugin already has stay valid for the overlap period
+// so its servers can switch to the new key
+func (s *Server) CreatePluginSigningKey(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "id is required"})
+	}
+	if status, msg := s.checkPluginAdmin(c, id, "signing keys"); status != 0 {
+		return c.JSON(status, map[string]string{"error": msg})
+	}
+
+	var req CreateSigningKeyRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	publicKey, err := reqsign.ParsePublicKey(req.PublicKey)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "publicKey must be a hex encoded ed25519 public key"})
+	}
+
+	ctx := c.Request().Context()
+	keyID := reqsign.KeyID(publicKey)
+	existing, err := s.db.GetPluginSigningKey(ctx, keyID)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get signing key")
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+	}
+	if existing != nil {
+		return c.JSON(http.StatusConflict, map[string]string{"error": "signing key is already registered"})
+	}
+
+	created, err := s.db.CreatePluginSigningKey(ctx, itypes.PluginSigningKey{
+		KeyID:     keyID,
+		PluginID:  types.PluginID(id),
+		PublicKey: hex.EncodeToString(publicKey),
+	}, s.cfg.SigningKeyOverlap)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to create signing key")
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to create signing key"})
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"plugin_id": id,
+		"key_id":    keyID,
+	}).Info("signing key registered")
+	return c.JSON(http.StatusCreated, newSigningKeyResponse(*created, time.Now()))
+}
+
+// RevokePluginSigningKey rejects the requests signed with the key right away
+func (s *Server) RevokePluginSigningKey(c echo.Context) error {
+	id := c.Param("id")
+	keyID := c.Param("keyId")
+	if id == "" || keyID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "plugin id and key id are required"})
+	}
+	if status, msg := s.checkPluginAdmin(c, id, "signing keys"); status != 0 {
+		return c.JSON(status, map[string]string{"error": msg})
+	}
+
+	revoked, err := s.db.RevokePluginSigningKey(c.Request().Context(), types.PluginID(id), keyID)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to revoke signing key")
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to revoke signing key"})
+	}
+	if !revoked {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "signing key not found"})
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"plugin_id": id,
+		"key_id":    keyID,
+	}).Info("signing key revoked")
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,108 @@
+package pricing
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/vultisig/vultisig-go/common"
+
+	"github.com/vultisig/verifier/types"
+)
+
+// Calculation is a per-tx fee with the inputs it was computed from, stored as the fee metadata
+type Calculation struct {
+	Metric types.PricingMetric `json:"metric"`
+	Asset  types.PricingAsset  `json:"asset"`
+	// Amount is the fee charged, in the smallest unit of Asset
+	Amount uint64 `json:"amount"`
+
+	// percentage pricing only
+	BasisPoints    uint64  `json:"basis_points,omitempty"`
+	Chain          string  `json:"chain,omitempty"`
+	TokenID        string  `json:"token_id,omitempty"`
+	TxAmount       string  `json:"tx_amount,omitempty"`
+	TokenDecimals  uint8   `json:"token_decimals,omitempty"`
+	Price          string  `json:"price,omitempty"`
+	PriceSource    string  `json:"price_source,omitempty"`
+	UncappedAmount uint64  `json:"uncapped_amount,omitempty"`
+	MinAmount      *uint64 `json:"min_amount,omitempty"`
+	MaxAmount      *uint64 `json:"max_amount,omitempty"`
+}
+
+// TxFee computes the fee of a transaction of amount tokenID (in the token smallest unit) on chain.
+// When the token can't be priced an error is returned, so that the tx is billed once the price is available.
+func TxFee(
+	ctx context.Context,
+	src PriceSource,
+	pricing types.Pricing,
+	chain common.Chain,
+	tokenID string,
+	amount string,
+) (Calculation, error) {
+	calc := Calculation{
+		Metric: pricing.Metric,
+		Asset:  pricing.Asset,
+	}
+	switch pricing.Metric {
+	case types.PricingMetricFixed, "":
+		calc.Amount = pricing.Amount
+		return calc, nil
+	case types.PricingMetricPercentage:
+	default:
+		return Calculation{}, fmt.Errorf("unsupported pricing metric: %s", pricing.Metric)
+	}
+
+	if pricing.Amount > types.MaxBasisPoints {
+		return Calculation{}, fmt.Errorf("invalid basis points: %d", pricing.Amount)
+	}
+	asset, err := types.GetFeeAsset(pricing.Asset)
+	if err != nil {
+		return Calculation{}, err
+	}
+	txAmount, ok := new(big.Int).SetString(amount, 10)
+	if !ok || txAmount.Sign() < 0 {
+		return Calculation{}, fmt.Errorf("invalid tx amount: %q", amount)
+	}
+
+	calc.BasisPoints = pricing.Amount
+	calc.Chain = chain.String()
+	calc.TokenID = tokenID
+	calc.TxAmount = txAmount.String()
+	calc.MinAmount = pricing.MinAmount
+	calc.MaxAmount = pricing.MaxAmount
+
+	quote, err := src.Quote(ctx, chain, tokenID, pricing.Asset)
+	if err != nil {
+		return Calculation{}, fmt.Errorf("failed to price %q on %s: %w", tokenID, chain.String(), err)
+	}
+	calc.TokenDecimals = quote.TokenDecimals
+	calc.Price = quote.Price.FloatString(int(asset.Decimals))
+	calc.PriceSource = quote.Source
+
+	// fee = amount / 10^tokenDecimals * price * bps / 10000, in the smallest unit of the fee asset
+	fee := new(big.Rat).SetInt(txAmount)
+	fee.Mul(fee, quote.Price)
+	fee.Mul(fee, new(big.Rat).SetFrac(
+		new(big.Int).Mul(big.NewInt(int64(pricing.Amount)), pow10(asset.Decimals)),
+		new(big.Int).Mul(big.NewInt(types.MaxBasisPoints), pow10(quote.TokenDecimals)),
+	))
+	feeInt := new(big.Int).Quo(fee.Num(), fee.Denom())
+	if !feeInt.IsUint64() {
+		return Calculation{}, fmt.Errorf("fee overflows: %s", feeInt.String())
+	}
+
+	calc.UncappedAmount = feeInt.Uint64()
+	calc.Amount = calc.UncappedAmount
+	if pricing.MinAmount != nil && calc.Amount < *pricing.MinAmount {
+		calc.Amount = *pricing.MinAmount
+	}
+	if pricing.MaxAmount != nil && calc.Amount > *pricing.MaxAmount {
+		calc.Amount = *pricing.MaxAmount
+	}
+	return calc, nil
+}
+
+func pow10(n uint8) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
@@ -0,0 +1,112 @@
+package pricing
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vultisig/vultisig-go/common"
+
+	"github.com/vultisig/verifier/types"
+)
+
+func ptr(v uint64) *uint64 {
+	return &v
+}
+
+func testPrices(t *testing.T) PriceSource {
+	src, err := NewStaticPriceSource(append(DefaultStaticPrices(), StaticPrice{
+		Chain:    common.Ethereum,
+		Decimals: 18,
+		USD:      "3000",
+	}))
+	require.NoError(t, err)
+	return src
+}
+
+func TestTxFee(t *testing.T) {
+	ctx := context.Background()
+	src := testPrices(t)
+	usdc, err := types.GetFeeAsset(types.PricingAssetUSDC)
+	require.NoError(t, err)
+
+	calc, err := TxFee(ctx, src, types.Pricing{
+		Metric: types.PricingMetricFixed,
+		Asset:  types.PricingAssetUSDC,
+		Amount: 10_000,
+	}, common.Ethereum, "", "1")
+	require.NoError(t, err)
+	require.Equal(t, uint64(10_000), calc.Amount)
+
+	percentage := types.Pricing{
+		Metric: types.PricingMetricPercentage,
+		Asset:  types.PricingAssetUSDC,
+		Amount: 30, // 0.3%
+	}
+
+	// 0.5 ETH at 3000 USD is 1500 USD, 0.3% of it is 4.5 USDC
+	calc, err = TxFee(ctx, src, percentage, common.Ethereum, "", "500000000000000000")
+	require.NoError(t, err)
+	require.Equal(t, uint64(4_500_000), calc.Amount)
+	require.Equal(t, uint64(4_500_000), calc.UncappedAmount)
+	require.Equal(t, uint8(18), calc.TokenDecimals)
+	require.Equal(t, "3000.000000", calc.Price)
+	require.Equal(t, "static", calc.PriceSource)
+
+	// 100 USDC volume, 0.3 USDC fee
+	calc, err = TxFee(ctx, src, percentage, common.Ethereum, usdc.Token, "100000000")
+	require.NoError(t, err)
+	require.Equal(t, uint64(300_000), calc.Amount)
+
+	capped := percentage
+	capped.MinAmount = ptr(500_000)
+	capped.MaxAmount = ptr(2_000_000)
+	calc, err = TxFee(ctx, src, capped, common.Ethereum, usdc.Token, "100000000")
+	require.NoError(t, err)
+	require.Equal(t, uint64(500_000), calc.Amount)
+	require.Equal(t, uint64(300_000), calc.UncappedAmount)
+
+	calc, err = TxFee(ctx, src, capped, common.Ethereum, "", "500000000000000000")
+	require.NoError(t, err)
+	require.Equal(t, uint64(2_000_000), calc.Amount)
+
+	// the inputs are kept with the fee
+	raw, err := json.Marshal(calc)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"metric": "percentage",
+		"asset": "usdc",
+		"amount": 2000000,
+		"basis_points": 30,
+		"chain": "Ethereum",
+		"tx_amount": "500000000000000000",
+		"token_decimals": 18,
+		"price": "3000.000000",
+		"price_source": "static",
+		"uncapped_amount": 4500000,
+		"min_amount": 500000,
+		"max_amount": 2000000
+	}`, string(raw))
+}
+
+func TestTxFeeWithoutPrice(t *testing.T) {
+	ctx := context.Background()
+	src := testPrices(t)
+
+	pricing := types.Pricing{
+		Metric: types.PricingMetricPercentage,
+		Asset:  types.PricingAssetUSDC,
+		Amount: 30,
+	}
+	_, err := TxFee(ctx, src, pricing, common.Solana, "", "1000000000")
+	require.ErrorIs(t, err, ErrPriceNotFound)
+
+	// the min amount isn't charged instead, the tx is billed once it can be priced
+	pricing.MinAmount = ptr(100_000)
+	_, err = TxFee(ctx, src, pricing, common.Solana, "", "1000000000")
+	require.ErrorIs(t, err, ErrPriceNotFound)
+
+	_, err = TxFee(ctx, src, pricing, common.Ethereum, "", "not a number")
+	require.Error(t, err)
+}
@@ -0,0 +1,100 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/vultisig/vultisig-go/common"
+
+	"github.com/vultisig/verifier/types"
+)
+
+var ErrPriceNotFound = errors.New("price not found")
+
+// Quote is the price of a token in a fee asset
+type Quote struct {
+	TokenDecimals uint8
+	// Price of one whole token in whole units of the fee asset
+	Price  *big.Rat
+	Source string
+}
+
+// PriceSource prices transaction tokens in fee assets, to compute percentage fees
+type PriceSource interface {
+	Quote(ctx context.Context, chain common.Chain, tokenID string, asset types.PricingAsset) (Quote, error)
+}
+
+// StaticPrice is a fixed USD price of a token, Token is empty for the chain native token
+type StaticPrice struct {
+	Chain    common.Chain
+	Token    string
+	Decimals uint8
+	USD      string
+}
+
+type staticKey struct {
+	chain common.Chain
+	token string
+}
+
+type staticEntry struct {
+	decimals uint8
+	usd      *big.Rat
+}
+
+// StaticPriceSource is an offline price table. Fee assets are USD stablecoins,
+// so a USD price is used as the price in any of them.
+type StaticPriceSource struct {
+	prices map[staticKey]staticEntry
+}
+
+var _ PriceSource = (*StaticPriceSource)(nil)
+
+func NewStaticPriceSource(prices []StaticPrice) (*StaticPriceSource, error) {
+	src := &StaticPriceSource{
+		prices: make(map[staticKey]staticEntry, len(prices)),
+	}
+	for _, p := range prices {
+		usd, ok := new(big.Rat).SetString(p.USD)
+		if !ok || usd.Sign() < 0 {
+			return nil, fmt.Errorf("invalid USD price %q of %s on %s", p.USD, p.Token, p.Chain.String())
+		}
+		src.prices[staticKey{chain: p.Chain, token: strings.ToLower(p.Token)}] = staticEntry{
+			decimals: p.Decimals,
+			usd:      usd,
+		}
+	}
+	return src, nil
+}
+
+// DefaultStaticPrices prices the fee assets at 1 USD
+func DefaultStaticPrices() []StaticPrice {
+	var prices []StaticPrice
+	for _, asset := range types.FeeAssets() {
+		prices = append(prices, StaticPrice{
+			Chain:    asset.Chain,
+			Token:    asset.Token,
+			Decimals: asset.Decimals,
+			USD:      "1",
+		})
+	}
+	return prices
+}
+
+func (s *StaticPriceSource) Quote(_ context.Context, chain common.Chain, tokenID string, asset types.PricingAsset) (Quote, error) {
+	if !asset.IsValid() {
+		return Quote{}, fmt.Errorf("unsupported fee asset: %s", asset)
+	}
+	entry, ok := s.prices[staticKey{chain: chain, token: strings.ToLower(tokenID)}]
+	if !ok {
+		return Quote{}, fmt.Errorf("%w: %s on %s", ErrPriceNotFound, tokenID, chain.String())
+	}
+	return Quote{
+		TokenDecimals: entry.decimals,
+		Price:         new(big.Rat).Set(entry.usd),
+		Source:        "static",
+	}, nil
+}
@@ -0,0 +1,138 @@
+package pricing
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	itypes "github.com/vultisig/verifier/internal/types"
+	"github.com/vultisig/verifier/types"
+)
+
+// Proration is a subscription fee with the inputs it was computed from, stored as the fee metadata.
+// Amount is FullAmount * ActiveSeconds / PeriodSeconds, rounded down.
+type Proration struct {
+	PeriodStart   time.Time              `json:"period_start"`
+	PeriodEnd     time.Time              `json:"period_end"`
+	Frequency     types.PricingFrequency `json:"frequency"`
+	FullAmount    uint64                 `json:"full_amount"`
+	PeriodSeconds int64                  `json:"period_seconds"`
+	ActiveSeconds int64                  `json:"active_seconds"`
+	Amount        uint64                 `json:"amount"`
+}
+
+// PeriodEnd returns the end of the billing period starting at start
+func PeriodEnd(start time.Time, frequency types.PricingFrequency) (time.Time, error) {
+	switch frequency {
+	case types.PricingFrequencyDaily:
+		return start.AddDate(0, 0, 1), nil
+	case types.PricingFrequencyWeekly:
+		return start.AddDate(0, 0, 7), nil
+	case types.PricingFrequencyBiweekly:
+		return start.AddDate(0, 0, 14), nil
+	case types.PricingFrequencyMonthly:
+		return start.AddDate(0, 1, 0), nil
+	default:
+		return time.Time{}, fmt.Errorf("unsupported pricing frequency: %q", frequency)
+	}
+}
+
+// SubscriptionFees returns the fees of the subscription periods ended by now, prorated on the time the
+// policy was active in each of them. Periods follow each other from the billing start date, or from the
+// end of the last billed period. Periods the policy wasn't active in aren't billed.
+func SubscriptionFees(sub itypes.Subscription, now time.Time) ([]Proration, error) {
+	if len(sub.Activations) == 0 {
+		return nil, nil
+	}
+
+	var start time.Time
+	if sub.BilledUntil != nil {
+		start = sub.BilledUntil.UTC()
+	} else {
+		var err error
+		start, err = firstPeriodStart(sub.StartDate.UTC(), sub.Activations[0].ActivatedAt, sub.Frequency)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var fees []Proration
+	for {
+		end, err := PeriodEnd(start, sub.Frequency)
+		if err != nil {
+			return nil, err
+		}
+		if end.After(now) {
+			return fees, nil
+		}
+
+		active := activeDuration(sub.Activations, start, end, now)
+		if active > 0 {
+			fee := Proration{
+				PeriodStart:   start,
+				PeriodEnd:     end,
+				Frequency:     sub.Frequency,
+				FullAmount:    sub.Amount,
+				PeriodSeconds: int64(end.Sub(start) / time.Second),
+				ActiveSeconds: int64(active / time.Second),
+			}
+			fee.Amount = prorate(fee.FullAmount, fee.ActiveSeconds, fee.PeriodSeconds)
+			if fee.Amount > 0 {
+				fees = append(fees, fee)
+			}
+		}
+		start = end
+	}
+}
+
+// firstPeriodStart returns the start of the period the policy was first activated in,
+// billing starts at anchor for policies activated before it
+func firstPeriodStart(anchor, activatedAt time.Time, frequency types.PricingFrequency) (time.Time, error) {
+	if anchor.IsZero() {
+		return activatedAt.UTC(), nil
+	}
+	start := anchor
+	for {
+		end, err := PeriodEnd(start, frequency)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if end.After(activatedAt) {
+			return start, nil
+		}
+		start = end
+	}
+}
+
+// activeDuration returns how long the policy was active between start and end,
+// open activations last until now
+func activeDuration(activations []types.PolicyActivation, start, end, now time.Time) time.Duration {
+	var total time.Duration
+	for _, a := range activations {
+		from := a.ActivatedAt
+		to := now
+		if a.DeactivatedAt != nil {
+			to = *a.DeactivatedAt
+		}
+		if from.Before(start) {
+			from = start
+		}
+		if to.After(end) {
+			to = end
+		}
+		if to.After(from) {
+			total += to.Sub(from)
+		}
+	}
+	return total
+}
+
+func prorate(amount uint64, active, period int64) uint64 {
+	if active >= period {
+		return amount
+	}
+	prorated := new(big.Int).SetUint64(amount)
+	prorated.Mul(prorated, big.NewInt(active))
+	prorated.Quo(prorated, big.NewInt(period))
+	return prorated.Uint64()
+}
@@ -0,0 +1,98 @@
+package pricing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	itypes "github.com/vultisig/verifier/internal/types"
+	"github.com/vultisig/verifier/types"
+)
+
+func day(d int) time.Time {
+	return time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, d-1)
+}
+
+func dayPtr(d int) *time.Time {
+	t := day(d)
+	return &t
+}
+
+func TestSubscriptionFees(t *testing.T) {
+	sub := itypes.Subscription{
+		Amount:    7_000_000,
+		Frequency: types.PricingFrequencyWeekly,
+		StartDate: day(1),
+		Activations: []types.PolicyActivation{
+			{ActivatedAt: day(3), DeactivatedAt: dayPtr(9)},   // installed mid-period, paused
+			{ActivatedAt: day(11), DeactivatedAt: dayPtr(16)}, // resumed, uninstalled
+		},
+	}
+
+	fees, err := SubscriptionFees(sub, day(32))
+	require.NoError(t, err)
+	require.Equal(t, []Proration{
+		{
+			PeriodStart:   day(1),
+			PeriodEnd:     day(8),
+			Frequency:     types.PricingFrequencyWeekly,
+			FullAmount:    7_000_000,
+			PeriodSeconds: 7 * 86400,
+			ActiveSeconds: 5 * 86400,
+			Amount:        5_000_000,
+		},
+		{
+			PeriodStart:   day(8),
+			PeriodEnd:     day(15),
+			Frequency:     types.PricingFrequencyWeekly,
+			FullAmount:    7_000_000,
+			PeriodSeconds: 7 * 86400,
+			ActiveSeconds: 5 * 86400,
+			Amount:        5_000_000,
+		},
+		{
+			PeriodStart:   day(15),
+			PeriodEnd:     day(22),
+			Frequency:     types.PricingFrequencyWeekly,
+			FullAmount:    7_000_000,
+			PeriodSeconds: 7 * 86400,
+			ActiveSeconds: 86400,
+			Amount:        1_000_000,
+		},
+	}, fees)
+
+	// reinstalled, billing continues from the last billed period
+	sub.BilledUntil = dayPtr(22)
+	sub.Activations = append(sub.Activations, types.PolicyActivation{ActivatedAt: day(30)})
+	fees, err = SubscriptionFees(sub, day(44))
+	require.NoError(t, err)
+	require.Len(t, fees, 2)
+	require.Equal(t, day(29), fees[0].PeriodStart)
+	require.Equal(t, uint64(6_000_000), fees[0].Amount)
+	require.Equal(t, day(36), fees[1].PeriodStart)
+	require.Equal(t, uint64(7_000_000), fees[1].Amount)
+
+	// the current period isn't billed before it ends
+	fees, err = SubscriptionFees(sub, day(42))
+	require.NoError(t, err)
+	require.Len(t, fees, 1)
+
+	sub.Frequency = "yearly"
+	_, err = SubscriptionFees(sub, day(44))
+	require.Error(t, err)
+}
+
+func TestSubscriptionFeesMonthly(t *testing.T) {
+	// activated before the billing start date, billed from it
+	fees, err := SubscriptionFees(itypes.Subscription{
+		Amount:      30_000,
+		Frequency:   types.PricingFrequencyMonthly,
+		StartDate:   time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC),
+		Activations: []types.PolicyActivation{{ActivatedAt: time.Date(2026, time.August, 20, 0, 0, 0, 0, time.UTC)}},
+	}, day(1))
+	require.NoError(t, err)
+	require.Len(t, fees, 1)
+	require.Equal(t, int64(30*86400), fees[0].PeriodSeconds)
+	require.Equal(t, uint64(30_000), fees[0].Amount)
+}
@@ -0,0 +1,70 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+const memorySweepInterval = time.Minute
+
+// MemoryStore keeps the buckets in the process, the verifier falls back to it when redis is unavailable
+// so that the limits still hold per instance
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*memoryBucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type memoryBucket struct {
+	tokens  float64
+	ts      time.Time
+	expires time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*memoryBucket),
+		now:     time.Now,
+	}
+}
+
+// TakeRateLimitToken takes a token from the bucket of key, the same way the redis store does
+func (m *MemoryStore) TakeRateLimitToken(_ context.Context, key string, limit Limit) (Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.sweep(now)
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &memoryBucket{tokens: float64(limit.Burst), ts: now}
+		m.buckets[key] = b
+	}
+	elapsed := math.Max(0, now.Sub(b.ts).Seconds())
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+elapsed*limit.Rate)
+	b.ts = now
+	b.expires = now.Add(limit.Window())
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+	return Result{Allowed: allowed, Tokens: b.tokens}, nil
+}
+
+// sweep drops the buckets that are full again, they behave like missing ones
+func (m *MemoryStore) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < memorySweepInterval {
+		return
+	}
+	m.lastSweep = now
+	for key, b := range m.buckets {
+		if now.After(b.expires) {
+			delete(m.buckets, key)
+		}
+	}
+}
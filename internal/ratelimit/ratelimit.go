@@ -0,0 +1,83 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	HeaderLimit     = "RateLimit-Limit"
+	HeaderRemaining = "RateLimit-Remaining"
+	HeaderReset     = "RateLimit-Reset"
+	HeaderPolicy    = "RateLimit-Policy"
+)
+
+// Limit is a token bucket refilled with Rate tokens per second up to Burst, a zero Rate disables it
+type Limit struct {
+	Rate  float64 `mapstructure:"rate" json:"rate,omitempty"`
+	Burst int     `mapstructure:"burst" json:"burst,omitempty"`
+}
+
+func (l Limit) Enabled() bool {
+	return l.Rate > 0 && l.Burst > 0
+}
+
+// Window is how long an empty bucket takes to refill
+func (l Limit) Window() time.Duration {
+	return seconds(float64(l.Burst) / l.Rate)
+}
+
+// Result is the state of a bucket after a token was taken from it
+type Result struct {
+	Allowed bool
+	// Tokens left in the bucket, fractional while it refills
+	Tokens float64
+}
+
+// Remaining is the number of requests allowed right away
+func (r Result) Remaining() int {
+	return int(math.Floor(r.Tokens))
+}
+
+// Reset is how long the bucket takes to refill
+func (r Result) Reset(limit Limit) time.Duration {
+	return seconds((float64(limit.Burst) - r.Tokens) / limit.Rate)
+}
+
+// RetryAfter is how long until the next token, zero when the request was allowed
+func (r Result) RetryAfter(limit Limit) time.Duration {
+	if r.Allowed {
+		return 0
+	}
+	return seconds((1 - r.Tokens) / limit.Rate)
+}
+
+// Store takes tokens from buckets shared between the verifier instances
+type Store interface {
+	TakeRateLimitToken(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// SetHeaders writes the RateLimit-* headers of the IETF draft, and Retry-After when the request was rejected
+func SetHeaders(h http.Header, limit Limit, res Result) {
+	h.Set(HeaderLimit, strconv.Itoa(limit.Burst))
+	h.Set(HeaderRemaining, strconv.Itoa(res.Remaining()))
+	h.Set(HeaderReset, strconv.Itoa(ceilSeconds(res.Reset(limit))))
+	h.Set(HeaderPolicy, strconv.Itoa(limit.Burst)+";w="+strconv.Itoa(ceilSeconds(limit.Window())))
+	if !res.Allowed {
+		h.Set("Retry-After", strconv.Itoa(ceilSeconds(res.RetryAfter(limit))))
+	}
+}
+
+func seconds(s float64) time.Duration {
+	if s <= 0 {
+		return 0
+	}
+	return time.Duration(s * float64(time.Second))
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,76 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimit_Enabled(t *testing.T) {
+	require.True(t, Limit{Rate: 1, Burst: 1}.Enabled())
+	require.False(t, Limit{Rate: 0, Burst: 10}.Enabled())
+	require.False(t, Limit{Rate: 5, Burst: 0}.Enabled())
+}
+
+func TestResult(t *testing.T) {
+	limit := Limit{Rate: 2, Burst: 10}
+
+	allowed := Result{Allowed: true, Tokens: 4.5}
+	require.Equal(t, 4, allowed.Remaining())
+	require.Equal(t, 2750*time.Millisecond, allowed.Reset(limit))
+	require.Zero(t, allowed.RetryAfter(limit))
+
+	rejected := Result{Allowed: false, Tokens: 0.5}
+	require.Equal(t, 0, rejected.Remaining())
+	require.Equal(t, 250*time.Millisecond, rejected.RetryAfter(limit))
+	require.Equal(t, 5*time.Second, limit.Window())
+}
+
+func TestSetHeaders(t *testing.T) {
+	limit := Limit{Rate: 2, Burst: 10}
+
+	h := http.Header{}
+	SetHeaders(h, limit, Result{Allowed: true, Tokens: 9})
+	require.Equal(t, "10", h.Get(HeaderLimit))
+	require.Equal(t, "9", h.Get(HeaderRemaining))
+	require.Equal(t, "1", h.Get(HeaderReset))
+	require.Equal(t, "10;w=5", h.Get(HeaderPolicy))
+	require.Empty(t, h.Get("Retry-After"))
+
+	h = http.Header{}
+	SetHeaders(h, limit, Result{Allowed: false, Tokens: 0.2})
+	require.Equal(t, "0", h.Get(HeaderRemaining))
+	require.Equal(t, "5", h.Get(HeaderReset))
+	require.Equal(t, "1", h.Get("Retry-After"))
+}
+
+func TestMemoryStore(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	limit := Limit{Rate: 1, Burst: 2}
+
+	for _, allowed := range []bool{true, true, false} {
+		res, err := store.TakeRateLimitToken(context.Background(), "ip:10.0.0.1", limit)
+		require.NoError(t, err)
+		require.Equal(t, allowed, res.Allowed)
+	}
+	res, err := store.TakeRateLimitToken(context.Background(), "ip:10.0.0.2", limit)
+	require.NoError(t, err)
+	require.True(t, res.Allowed)
+
+	now = now.Add(1500 * time.Millisecond)
+	res, err = store.TakeRateLimitToken(context.Background(), "ip:10.0.0.1", limit)
+	require.NoError(t, err)
+	require.True(t, res.Allowed)
+	require.InDelta(t, 0.5, res.Tokens, 1e-9)
+
+	// full buckets are dropped
+	now = now.Add(time.Hour)
+	_, err = store.TakeRateLimitToken(context.Background(), "ip:10.0.0.3", limit)
+	require.NoError(t, err)
+	require.Len(t, store.buckets, 1)
+}
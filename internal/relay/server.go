@@ -0,0 +1,437 @@
+package relay
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/sirupsen/logrus"
+	vgrelay "github.com/vultisig/vultisig-go/relay"
+
+	vtypes "github.com/vultisig/verifier/types"
+)
+
+const messageIDHeader = "message_id"
+
+const eventsKeepAlive = 15 * time.Second
+
+type session struct {
+	parties         []string
+	started         []string
+	completed       []string
+	setupMessages   map[string]string
+	keysignComplete map[string]json.RawMessage
+	// messages are keyed by message_id header, then by receiver
+	messages  map[string]map[string][]vgrelay.Message
+	updatedAt time.Time
+	// changed is closed and replaced whenever parties join or complete
+	changed chan struct{}
+}
+
+func newSession() *session {
+	return &session{
+		setupMessages:   make(map[string]string),
+		keysignComplete: make(map[string]json.RawMessage),
+		messages:        make(map[string]map[string][]vgrelay.Message),
+		updatedAt:       time.Now(),
+		changed:         make(chan struct{}),
+	}
+}
+
+func (sess *session) notify() {
+	close(sess.changed)
+	sess.changed = make(chan struct{})
+}
+
+func (sess *session) event() vtypes.RelaySessionEvent {
+	event := vtypes.RelaySessionEvent{
+		Parties:   slices.Clone(sess.parties),
+		Completed: slices.Clone(sess.completed),
+	}
+	if event.Parties == nil {
+		event.Parties = []string{}
+	}
+	if event.Completed == nil {
+		event.Completed = []string{}
+	}
+	return event
+}
+
+// Server is an in-memory implementation of the Vultisig relay API, the one used by
+// vultisig-go/relay.Client and vultiserver/relay.Messenger. It's meant for tests and
+// offline development, all state is lost on restart.
+type Server struct {
+	mu         sync.Mutex
+	sessions   map[string]*session
+	sessionTTL time.Duration
+	logger     *logrus.Logger
+	e          *echo.Echo
+}
+
+// NewServer creates a relay server, sessions not updated for sessionTTL are dropped.
+// Zero sessionTTL keeps sessions forever.
+func NewServer(logger *logrus.Logger, sessionTTL time.Duration) *Server {
+	s := &Server{
+		sessions:   make(map[string]*session),
+		sessionTTL: sessionTTL,
+		logger:     logger,
+	}
+
+	e := echo.New()
+	e.HideBanner = true
+	e.HidePort = true
+	e.Use(middleware.Recover())
+
+	e.POST("/start/:sessionID", s.startSession)
+	e.GET("/start/:sessionID", s.getStartedParties)
+	e.POST("/complete/:sessionID/keysign", s.markKeysignComplete)
+	e.GET("/complete/:sessionID/keysign", s.getKeysignComplete)
+	e.POST("/complete/:sessionID", s.completeSession)
+	e.GET("/complete/:sessionID", s.getCompletedParties)
+	e.POST("/setup-message/:sessionID", s.uploadSetupMessage)
+	e.GET("/setup-message/:sessionID", s.getSetupMessage)
+	e.GET("/events/:sessionID", s.sessionEvents)
+	e.POST("/message/:sessionID", s.postMessage)
+	e.GET("/message/:sessionID/:participantID", s.getMessages)
+	e.DELETE("/message/:sessionID/:participantID/:hash", s.deleteMessage)
+	e.POST("/:sessionID", s.registerSession)
+	e.GET("/:sessionID", s.getSession)
+	e.DELETE("/:sessionID", s.endSession)
+	s.e = e
+	return s
+}
+
+func (s *Server) Handler() http.Handler {
+	return s.e
+}
+
+// Start serves the relay on addr and removes expired sessions until ctx is done
+func (s *Server) Start(ctx context.Context, addr string) error {
+	if s.sessionTTL > 0 {
+		go s.cleanup(ctx)
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := s.e.Shutdown(shutdownCtx); err != nil {
+			s.logger.WithError(err).Error("failed to shutdown relay server")
+		}
+	}()
+	err := s.e.Start(addr)
+	if err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+func (s *Server) cleanup(ctx context.Context) {
+	ticker := time.NewTicker(s.sessionTTL / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.removeExpired()
+		}
+	}
+}
+
+func (s *Server) removeExpired() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, sess := range s.sessions {
+		if time.Since(sess.updatedAt) > s.sessionTTL {
+			sess.notify()
+			delete(s.sessions, id)
+		}
+	}
+}
+
+// withSession runs fn under the lock on the session, creating it if needed
+func (s *Server) withSession(sessionID string, fn func(sess *session)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess, ok := s.sessions[sessionID]
+	if !ok {
+		sess = newSession()
+		s.sessions[sessionID] = sess
+	}
+	sess.updatedAt = time.Now()
+	fn(sess)
+}
+
+// readSession runs fn under the lock if the session exists
+func (s *Server) readSession(sessionID string, fn func(sess *session)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if sess, ok := s.sessions[sessionID]; ok {
+		fn(sess)
+	}
+}
+
+func appendUnique(list []string, items ...string) []string {
+	for _, item := range items {
+		if !slices.Contains(list, item) {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+func bindParties(c echo.Context) ([]string, error) {
+	var parties []string
+	if err := json.NewDecoder(c.Request().Body).Decode(&parties); err != nil {
+		return nil, err
+	}
+	return parties, nil
+}
+
+func (s *Server) registerSession(c echo.Context) error {
+	parties, err := bindParties(c)
+	if err != nil {
+		return c.NoContent(http.StatusBadRequest)
+	}
+	s.withSession(c.Param("sessionID"), func(sess *session) {
+		sess.parties = appendUnique(sess.parties, parties...)
+		sess.notify()
+	})
+	return c.NoContent(http.StatusCreated)
+}
+
+func (s *Server) getSession(c echo.Context) error {
+	var parties []string
+	s.readSession(c.Param("sessionID"), func(sess *session) {
+		parties = slices.Clone(sess.parties)
+	})
+	if parties == nil {
+		parties = []string{}
+	}
+	return c.JSON(http.StatusOK, parties)
+}
+
+func (s *Server) endSession(c echo.Context) error {
+	s.mu.Lock()
+	if sess, ok := s.sessions[c.Param("sessionID")]; ok {
+		sess.notify()
+		delete(s.sessions, c.Param("sessionID"))
+	}
+	s.mu.Unlock()
+	return c.NoContent(http.StatusOK)
+}
+
+func (s *Server) startSession(c echo.Context) error {
+	parties, err := bindParties(c)
+	if err != nil {
+		return c.NoContent(http.StatusBadRequest)
+	}
+	s.withSession(c.Param("sessionID"), func(sess *session) {
+		sess.started = parties
+	})
+	return c.NoContent(http.StatusOK)
+}
+
+func (s *Server) getStartedParties(c echo.Context) error {
+	var parties []string
+	s.readSession(c.Param("sessionID"), func(sess *session) {
+		parties = slices.Clone(sess.started)
+	})
+	if parties == nil {
+		parties = []string{}
+	}
+	return c.JSON(http.StatusOK, parties)
+}
+
+func (s *Server) completeSession(c echo.Context) error {
+	parties, err := bindParties(c)
+	if err != nil {
+		return c.NoContent(http.StatusBadRequest)
+	}
+	s.withSession(c.Param("sessionID"), func(sess *session) {
+		sess.completed = appendUnique(sess.completed, parties...)
+		sess.notify()
+	})
+	return c.NoContent(http.StatusOK)
+}
+
+func (s *Server) getCompletedParties(c echo.Context) error {
+	var parties []string
+	s.readSession(c.Param("sessionID"), func(sess *session) {
+		parties = slices.Clone(sess.completed)
+	})
+	if parties == nil {
+		parties = []string{}
+	}
+	return c.JSON(http.StatusOK, parties)
+}
+
+// sessionEvents streams the session state as server-sent events, once on connect and then on every change.
+// The stream ends when the session is removed.
+func (s *Server) sessionEvents(c echo.Context) error {
+	sessionID := c.Param("sessionID")
+	var (
+		event   vtypes.RelaySessionEvent
+		changed chan struct{}
+	)
+	// subscribing may come before any party registers
+	s.withSession(sessionID, func(sess *session) {
+		event = sess.event()
+		changed = sess.changed
+	})
+
+	w := c.Response()
+	w.Header().Set(echo.HeaderContentType, "text/event-stream")
+	w.Header().Set(echo.HeaderCacheControl, "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	keepAlive := time.NewTicker(eventsKeepAlive)
+	defer keepAlive.Stop()
+	var last []byte
+	for {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(data, last) {
+			if _, err := fmt.Fprintf(w, "event: session\ndata: %s\n\n", data); err != nil {
+				return nil
+			}
+			w.Flush()
+			last = data
+		}
+
+		select {
+		case <-c.Request().Context().Done():
+			return nil
+		case <-changed:
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return nil
+			}
+			w.Flush()
+		}
+
+		found := false
+		s.readSession(sessionID, func(sess *session) {
+			found = true
+			event = sess.event()
+			changed = sess.changed
+		})
+		if !found {
+			return nil
+		}
+	}
+}
+
+func (s *Server) markKeysignComplete(c echo.Context) error {
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil || !json.Valid(body) {
+		return c.NoContent(http.StatusBadRequest)
+	}
+	messageID := c.Request().Header.Get(messageIDHeader)
+	s.withSession(c.Param("sessionID"), func(sess *session) {
+		sess.keysignComplete[messageID] = body
+	})
+	return c.NoContent(http.StatusOK)
+}
+
+func (s *Server) getKeysignComplete(c echo.Context) error {
+	var (
+		sig json.RawMessage
+		ok  bool
+	)
+	messageID := c.Request().Header.Get(messageIDHeader)
+	s.readSession(c.Param("sessionID"), func(sess *session) {
+		sig, ok = sess.keysignComplete[messageID]
+	})
+	if !ok {
+		return c.NoContent(http.StatusNotFound)
+	}
+	return c.JSONBlob(http.StatusOK, sig)
+}
+
+func (s *Server) uploadSetupMessage(c echo.Context) error {
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return c.NoContent(http.StatusBadRequest)
+	}
+	messageID := c.Request().Header.Get(messageIDHeader)
+	s.withSession(c.Param("sessionID"), func(sess *session) {
+		sess.setupMessages[messageID] = string(body)
+	})
+	return c.NoContent(http.StatusCreated)
+}
+
+func (s *Server) getSetupMessage(c echo.Context) error {
+	var (
+		payload string
+		ok      bool
+	)
+	messageID := c.Request().Header.Get(messageIDHeader)
+	s.readSession(c.Param("sessionID"), func(sess *session) {
+		payload, ok = sess.setupMessages[messageID]
+	})
+	if !ok {
+		return c.NoContent(http.StatusNotFound)
+	}
+	return c.String(http.StatusOK, payload)
+}
+
+func (s *Server) postMessage(c echo.Context) error {
+	var msg vgrelay.Message
+	if err := json.NewDecoder(c.Request().Body).Decode(&msg); err != nil {
+		return c.NoContent(http.StatusBadRequest)
+	}
+	if msg.Hash == "" || len(msg.To) == 0 {
+		return c.NoContent(http.StatusBadRequest)
+	}
+	messageID := c.Request().Header.Get(messageIDHeader)
+	s.withSession(c.Param("sessionID"), func(sess *session) {
+		inbox, ok := sess.messages[messageID]
+		if !ok {
+			inbox = make(map[string][]vgrelay.Message)
+			sess.messages[messageID] = inbox
+		}
+		for _, to := range msg.To {
+			inbox[to] = append(inbox[to], msg)
+		}
+	})
+	return c.NoContent(http.StatusAccepted)
+}
+
+func (s *Server) getMessages(c echo.Context) error {
+	var messages []vgrelay.Message
+	messageID := c.Request().Header.Get(messageIDHeader)
+	s.readSession(c.Param("sessionID"), func(sess *session) {
+		messages = slices.Clone(sess.messages[messageID][c.Param("participantID")])
+	})
+	if messages == nil {
+		messages = []vgrelay.Message{}
+	}
+	return c.JSON(http.StatusOK, messages)
+}
+
+func (s *Server) deleteMessage(c echo.Context) error {
+	messageID := c.Request().Header.Get(messageIDHeader)
+	participantID := c.Param("participantID")
+	hash := c.Param("hash")
+	s.readSession(c.Param("sessionID"), func(sess *session) {
+		inbox, ok := sess.messages[messageID]
+		if !ok {
+			return
+		}
+		inbox[participantID] = slices.DeleteFunc(inbox[participantID], func(msg vgrelay.Message) bool {
+			return msg.Hash == hash
+		})
+	})
+	return c.NoContent(http.StatusOK)
+}
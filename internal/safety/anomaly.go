@@ -0,0 +1,80 @@
+package safety
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/vultisig/verifier/config"
+	itypes "github.com/vultisig/verifier/internal/types"
+)
+
+// Anomaly is a deviation of the signing activity of a plugin that trips its keysign circuit breaker
+type Anomaly struct {
+	Reason string
+	// ThresholdRate is the share exceeded, nil for the sign request rate
+	ThresholdRate *float64
+}
+
+// DetectAnomaly checks the signing activity of a plugin in the window against its baseline, nil when it is normal
+func DetectAnomaly(stats itypes.SigningStats, window, baseline time.Duration, t config.AnomalyThresholds) *Anomaly {
+	w, b := stats.Window, stats.Baseline
+	if w.Requests == 0 || w.Requests < t.MinRequests {
+		return nil
+	}
+
+	if t.RateFactor > 0 && b.Requests > 0 && window > 0 && baseline > 0 {
+		rate := float64(w.Requests) / window.Hours()
+		baselineRate := float64(b.Requests) / baseline.Hours()
+		if rate > baselineRate*t.RateFactor {
+			return &Anomaly{
+				Reason: fmt.Sprintf("%.1f sign requests per hour exceeded %.1f times the baseline (%.2f)", rate, t.RateFactor, baselineRate),
+			}
+		}
+	}
+
+	if t.MaxRejectionRate > 0 {
+		if anomaly := exceedsShare("rejected sign requests", w.Rejections, w.Requests, b.Rejections, b.Requests, t.MaxRejectionRate); anomaly != nil {
+			return anomaly
+		}
+	}
+
+	accepted, baselineAccepted := w.Requests-w.Rejections, b.Requests-b.Rejections
+	// all recipients are new to a plugin without history
+	if t.MaxNewRecipientRate > 0 && accepted > 0 && baselineAccepted > 0 {
+		share := float64(stats.NewRecipients) / float64(accepted)
+		if share > t.MaxNewRecipientRate {
+			threshold := t.MaxNewRecipientRate
+			return &Anomaly{
+				Reason:        fmt.Sprintf("%d of %d txs (%.1f%%) sent to new recipients exceeded threshold (%.1f%%)", stats.NewRecipients, accepted, share*100, threshold*100),
+				ThresholdRate: &threshold,
+			}
+		}
+	}
+
+	if t.MaxFailureRate > 0 {
+		if anomaly := exceedsShare("txs failed on chain", w.Failed, w.Resolved, b.Failed, b.Resolved, t.MaxFailureRate); anomaly != nil {
+			return anomaly
+		}
+	}
+
+	return nil
+}
+
+// exceedsShare checks a share of the window against the larger of the max share and its baseline share
+func exceedsShare(what string, count, total, baselineCount, baselineTotal int, maxShare float64) *Anomaly {
+	if total == 0 {
+		return nil
+	}
+	threshold := maxShare
+	if baselineTotal > 0 {
+		threshold = max(threshold, float64(baselineCount)/float64(baselineTotal))
+	}
+	share := float64(count) / float64(total)
+	if share <= threshold {
+		return nil
+	}
+	return &Anomaly{
+		Reason:        fmt.Sprintf("%d of %d %s (%.1f%%) exceeded threshold (%.1f%%)", count, total, what, share*100, threshold*100),
+		ThresholdRate: &threshold,
+	}
+}
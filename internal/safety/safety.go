@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/vultisig/verifier/internal/storage"
@@ -12,7 +14,10 @@ import (
 
 var (
 	ErrGloballyDisabled = errors.New("action disabled globally")
+	ErrChainDisabled    = errors.New("action disabled for chain")
 	ErrPluginDisabled   = errors.New("action disabled for plugin")
+	ErrVaultDisabled    = errors.New("action disabled for vault")
+	ErrPolicyDisabled   = errors.New("action disabled for policy")
 	ErrUnknownAction    = errors.New("unknown action")
 )
 
@@ -21,37 +26,74 @@ const (
 	actionKeysign = "keysign"
 )
 
+// defaultFlagCacheTTL is how long a control flag is cached, flags changed elsewhere apply after at most that long
+const defaultFlagCacheTTL = 5 * time.Second
+
+var scopeErrors = map[psafety.FlagScope]error{
+	psafety.ScopeGlobal: ErrGloballyDisabled,
+	psafety.ScopeChain:  ErrChainDisabled,
+	psafety.ScopePlugin: ErrPluginDisabled,
+	psafety.ScopeVault:  ErrVaultDisabled,
+	psafety.ScopePolicy: ErrPolicyDisabled,
+}
+
+type cachedFlag struct {
+	enabled bool
+	// found is false for keys without a flag, they are cached too
+	found     bool
+	expiresAt time.Time
+}
+
 type Manager struct {
 	db     storage.ControlFlagsRepository
 	logger *logrus.Logger
+
+	cacheTTL time.Duration
+	cacheMu  sync.Mutex
+	cache    map[string]cachedFlag
+	now      func() time.Time
 }
 
 func NewManager(db storage.ControlFlagsRepository, logger *logrus.Logger) *Manager {
-	return &Manager{db: db, logger: logger}
+	return &Manager{
+		db:       db,
+		logger:   logger,
+		cacheTTL: defaultFlagCacheTTL,
+		cache:    make(map[string]cachedFlag),
+		now:      time.Now,
+	}
+}
+
+// SetCacheTTL sets how long the control flags are cached, 0 disables the cache
+func (m *Manager) SetCacheTTL(ttl time.Duration) {
+	m.cacheMu.Lock()
+	defer m.cacheMu.Unlock()
+	m.cacheTTL = ttl
+	m.cache = make(map[string]cachedFlag)
 }
 
 func (m *Manager) EnforceKeygen(ctx context.Context, pluginID string) error {
-	return m.enforce(ctx, pluginID, actionKeygen)
+	return m.enforce(ctx, pluginID, actionKeygen, []psafety.ScopedKey{
+		{Scope: psafety.ScopeGlobal, Key: psafety.GlobalKeygenKey()},
+		{Scope: psafety.ScopePlugin, Key: psafety.KeygenFlagKey(pluginID)},
+	})
 }
 
 func (m *Manager) EnforceKeysign(ctx context.Context, pluginID string) error {
-	return m.enforce(ctx, pluginID, actionKeysign)
+	return m.EnforceKeysignScope(ctx, psafety.KeysignScope{PluginID: pluginID})
+}
+
+// EnforceKeysignScope checks the keysign flags of every level of the scope, from global to policy
+func (m *Manager) EnforceKeysignScope(ctx context.Context, scope psafety.KeysignScope) error {
+	return m.enforce(ctx, scope.PluginID, actionKeysign, scope.Keys())
 }
 
-func (m *Manager) enforce(ctx context.Context, pluginID, action string) error {
-	var globalKey, pluginKey string
-	switch action {
-	case actionKeysign:
-		globalKey = psafety.GlobalKeysignKey()
-		pluginKey = psafety.KeysignFlagKey(pluginID)
-	case actionKeygen:
-		globalKey = psafety.GlobalKeygenKey()
-		pluginKey = psafety.KeygenFlagKey(pluginID)
-	default:
+func (m *Manager) enforce(ctx context.Context, pluginID, action string, keys []psafety.ScopedKey) error {
+	if action != actionKeysign && action != actionKeygen {
 		return fmt.Errorf("%s: %w", action, ErrUnknownAction)
 	}
 
-	flags, err := m.db.GetControlFlags(ctx, globalKey, pluginKey)
+	flags, err := m.getFlags(ctx, keys)
 	if err != nil {
 		m.logger.WithFields(logrus.Fields{
 			"plugin": pluginID,
@@ -62,36 +104,73 @@ func (m *Manager) enforce(ctx context.Context, pluginID, action string) error {
 	}
 
 	// default: missing key => allowed = true
-	globalEnabled, ok := flags[globalKey]
-	if !ok {
-		globalEnabled = true
-	}
-	pluginEnabled, ok := flags[pluginKey]
-	if !ok {
-		pluginEnabled = true
-	}
-
-	if !globalEnabled {
+	for _, k := range keys {
+		enabled, ok := flags[k.Key]
+		if !ok || enabled {
+			continue
+		}
 		m.logger.WithFields(logrus.Fields{
-			"key":    globalKey,
+			"key":    k.Key,
 			"plugin": pluginID,
 			"action": action,
-		}).Warn("blocked by global control flag")
-		return fmt.Errorf("%s: %w", action, ErrGloballyDisabled)
+		}).Warnf("blocked by %s control flag", k.Scope)
+		if k.Scope == psafety.ScopeGlobal {
+			return fmt.Errorf("%s: %w", action, ErrGloballyDisabled)
+		}
+		return fmt.Errorf("%s %s: %w", action, k.Key, scopeErrors[k.Scope])
 	}
 
-	if !pluginEnabled {
-		m.logger.WithFields(logrus.Fields{
-			"key":    pluginKey,
-			"plugin": pluginID,
-			"action": action,
-		}).Warn("blocked by plugin control flag")
-		return fmt.Errorf("%s %s: %w", action, pluginID, ErrPluginDisabled)
+	return nil
+}
+
+// getFlags returns the flags of the keys that have one, from the cache or the database
+func (m *Manager) getFlags(ctx context.Context, keys []psafety.ScopedKey) (map[string]bool, error) {
+	flags := make(map[string]bool, len(keys))
+	now := m.now()
+
+	m.cacheMu.Lock()
+	var missing []string
+	for _, k := range keys {
+		cached, ok := m.cache[k.Key]
+		if !ok || !now.Before(cached.expiresAt) {
+			missing = append(missing, k.Key)
+			continue
+		}
+		if cached.found {
+			flags[k.Key] = cached.enabled
+		}
 	}
+	ttl := m.cacheTTL
+	m.cacheMu.Unlock()
 
-	return nil
+	if len(missing) == 0 {
+		return flags, nil
+	}
+
+	fetched, err := m.db.GetControlFlagsByKeys(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+
+	m.cacheMu.Lock()
+	defer m.cacheMu.Unlock()
+	for _, key := range missing {
+		enabled, found := fetched[key]
+		if found {
+			flags[key] = enabled
+		}
+		if ttl > 0 {
+			m.cache[key] = cachedFlag{enabled: enabled, found: found, expiresAt: now.Add(ttl)}
+		}
+	}
+	return flags, nil
 }
 
 func IsDisabledError(err error) bool {
-	return errors.Is(err, ErrGloballyDisabled) || errors.Is(err, ErrPluginDisabled)
+	for _, scopeErr := range scopeErrors {
+		if errors.Is(err, scopeErr) {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,108 @@
+package safety
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"github.com/vultisig/vultisig-go/common"
+
+	itypes "github.com/vultisig/verifier/internal/types"
+	psafety "github.com/vultisig/verifier/plugin/safety"
+)
+
+type fakeFlagStorage struct {
+	flags   map[string]bool
+	queries int
+}
+
+func (f *fakeFlagStorage) GetControlFlags(ctx context.Context, k1, k2 string) (map[string]bool, error) {
+	return f.GetControlFlagsByKeys(ctx, []string{k1, k2})
+}
+
+func (f *fakeFlagStorage) GetControlFlagsByKeys(_ context.Context, keys []string) (map[string]bool, error) {
+	f.queries++
+	flags := make(map[string]bool)
+	for _, key := range keys {
+		if enabled, ok := f.flags[key]; ok {
+			flags[key] = enabled
+		}
+	}
+	return flags, nil
+}
+
+func (f *fakeFlagStorage) ListControlFlags(context.Context) ([]itypes.ControlFlag, error) {
+	return nil, nil
+}
+
+func (f *fakeFlagStorage) SetControlFlag(_ context.Context, key string, enabled bool) error {
+	f.flags[key] = enabled
+	return nil
+}
+
+func TestEnforceKeysignScope(t *testing.T) {
+	ctx := context.Background()
+	policyID := uuid.New()
+	scope := psafety.KeysignScope{
+		PluginID:  "dca",
+		Chains:    []common.Chain{common.Ethereum, common.Solana, common.Ethereum},
+		PublicKey: "vault",
+		PolicyID:  policyID,
+	}
+	db := &fakeFlagStorage{flags: map[string]bool{psafety.KeysignFlagKey("dca"): true}}
+	mgr := NewManager(db, logrus.New())
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	mgr.now = func() time.Time { return now }
+
+	require.NoError(t, mgr.EnforceKeysignScope(ctx, scope))
+	require.Equal(t, 1, db.queries)
+
+	var prev error
+	for _, tc := range []struct {
+		key string
+		err error
+	}{
+		{psafety.PolicyKeysignKey(policyID), ErrPolicyDisabled},
+		{psafety.VaultKeysignKey("vault"), ErrVaultDisabled},
+		{psafety.KeysignFlagKey("dca"), ErrPluginDisabled},
+		{psafety.ChainKeysignKey(common.Solana), ErrChainDisabled},
+		{psafety.GlobalKeysignKey(), ErrGloballyDisabled},
+	} {
+		require.NoError(t, db.SetControlFlag(ctx, tc.key, false))
+		// the flags are cached until their TTL is over
+		err := mgr.EnforceKeysignScope(ctx, scope)
+		if prev == nil {
+			require.NoError(t, err)
+		} else {
+			require.ErrorIs(t, err, prev)
+		}
+		now = now.Add(defaultFlagCacheTTL)
+
+		// the broadest level disabled is reported
+		err = mgr.EnforceKeysignScope(ctx, scope)
+		require.ErrorIs(t, err, tc.err)
+		require.True(t, IsDisabledError(err))
+		prev = tc.err
+	}
+
+	// other chains and vaults are unaffected by their flags
+	require.NoError(t, db.SetControlFlag(ctx, psafety.GlobalKeysignKey(), true))
+	now = now.Add(defaultFlagCacheTTL)
+	require.ErrorIs(t, mgr.EnforceKeysignScope(ctx, scope), ErrChainDisabled)
+	require.NoError(t, mgr.EnforceKeysignScope(ctx, psafety.KeysignScope{
+		PluginID:  "payroll",
+		Chains:    []common.Chain{common.Ethereum},
+		PublicKey: "other",
+	}))
+	require.NoError(t, mgr.EnforceKeysign(ctx, "payroll"))
+	require.NoError(t, mgr.EnforceKeygen(ctx, "dca"))
+
+	mgr.SetCacheTTL(0)
+	queries := db.queries
+	require.NoError(t, mgr.EnforceKeygen(ctx, "dca"))
+	require.NoError(t, mgr.EnforceKeygen(ctx, "dca"))
+	require.Equal(t, queries+2, db.queries)
+}
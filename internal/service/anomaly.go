@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/sirupsen/logrus"
+
+	"github.com/vultisig/verifier/config"
+	"github.com/vultisig/verifier/internal/safety"
+	itypes "github.com/vultisig/verifier/internal/types"
+	psafety "github.com/vultisig/verifier/plugin/safety"
+	"github.com/vultisig/verifier/types"
+)
+
+const anomalyTriggeredBy = "anomaly_detector"
+
+type AnomalyServiceStorage interface {
+	GetSigningStats(ctx context.Context, window, baseline time.Duration) ([]itypes.SigningStats, error)
+	DeleteKeysignRejectionsBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// AnomalyService is the circuit breaker pausing the keysign of plugins whose signing deviates from their baseline
+type AnomalyService struct {
+	db     AnomalyServiceStorage
+	flags  ControlFlagChanger
+	cfg    config.AnomalyConfig
+	logger *logrus.Logger
+	now    func() time.Time
+}
+
+func NewAnomalyService(db AnomalyServiceStorage, flags ControlFlagChanger, cfg config.AnomalyConfig, logger *logrus.Logger) (*AnomalyService, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database storage cannot be nil")
+	}
+	if flags == nil {
+		return nil, fmt.Errorf("control flag changer cannot be nil")
+	}
+	if cfg.Window <= 0 || cfg.Baseline <= 0 {
+		return nil, fmt.Errorf("anomaly window and baseline must be positive")
+	}
+	return &AnomalyService{
+		db:     db,
+		flags:  flags,
+		cfg:    cfg,
+		logger: logger.WithField("service", "anomaly").Logger,
+		now:    time.Now,
+	}, nil
+}
+
+// HandleAnomalyDetection checks the signing activity of the plugins and pauses the anomalous ones
+func (s *AnomalyService) HandleAnomalyDetection(ctx context.Context, _ *asynq.Task) error {
+	paused, err := s.Detect(ctx)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to detect signing anomalies")
+		return err
+	}
+	if len(paused) > 0 {
+		s.logger.WithField("plugins", paused).Warn("Plugins paused on signing anomalies")
+	}
+
+	// rejections are only kept as long as the detection looks back
+	deleted, err := s.db.DeleteKeysignRejectionsBefore(ctx, s.now().Add(-s.cfg.Window-s.cfg.Baseline))
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to delete old keysign rejections")
+		return nil
+	}
+	if deleted > 0 {
+		s.logger.WithField("deleted", deleted).Debug("Old keysign rejections deleted")
+	}
+	return nil
+}
+
+// Detect pauses the keysign of the plugins whose signing activity is anomalous, it returns the plugins paused
+func (s *AnomalyService) Detect(ctx context.Context) ([]types.PluginID, error) {
+	stats, err := s.db.GetSigningStats(ctx, s.cfg.Window, s.cfg.Baseline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get signing stats: %w", err)
+	}
+
+	var paused []types.PluginID
+	for _, st := range stats {
+		thresholds := s.cfg.ThresholdsOf(st.PluginID.String())
+		anomaly := safety.DetectAnomaly(st, s.cfg.Window, s.cfg.Baseline, thresholds)
+		if anomaly == nil {
+			continue
+		}
+
+		triggeredBy := anomalyTriggeredBy
+		ok, err := s.flags.Change(ctx, itypes.ControlFlagChange{
+			Flags: []psafety.ControlFlag{
+				{Key: psafety.KeysignFlagKey(string(st.PluginID)), Enabled: false},
+			},
+			Record: &itypes.PauseHistoryRecord{
+				PluginID:      st.PluginID,
+				Action:        itypes.PauseActionAnomalyPaused,
+				ThresholdRate: anomaly.ThresholdRate,
+				Reason:        &anomaly.Reason,
+				TriggeredBy:   &triggeredBy,
+			},
+			SyncTo: []types.PluginID{st.PluginID},
+		})
+		if err != nil {
+			return paused, fmt.Errorf("failed to pause plugin %s: %w", st.PluginID, err)
+		}
+		if !ok {
+			// keysign is already disabled
+			continue
+		}
+
+		s.logger.WithFields(logrus.Fields{
+			"plugin_id": st.PluginID,
+			"requests":  st.Window.Requests,
+			"reason":    anomaly.Reason,
+		}).Warn("pausing plugin keysign on signing anomaly")
+		paused = append(paused, st.PluginID)
+	}
+	return paused, nil
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vultisig/verifier/config"
+	itypes "github.com/vultisig/verifier/internal/types"
+	psafety "github.com/vultisig/verifier/plugin/safety"
+	"github.com/vultisig/verifier/types"
+)
+
+type fakeAnomalyStorage struct {
+	stats  []itypes.SigningStats
+	pruned []time.Time
+}
+
+func (f *fakeAnomalyStorage) GetSigningStats(_ context.Context, _, _ time.Duration) ([]itypes.SigningStats, error) {
+	return f.stats, nil
+}
+
+func (f *fakeAnomalyStorage) DeleteKeysignRejectionsBefore(_ context.Context, before time.Time) (int64, error) {
+	f.pruned = append(f.pruned, before)
+	return 0, nil
+}
+
+func TestAnomalyDetection(t *testing.T) {
+	// a week of baseline at 10 requests per hour, 5% rejected and 10% failed
+	baseline := itypes.SigningActivity{Requests: 1680, Rejections: 84, Resolved: 1000, Failed: 100}
+	db := &fakeAnomalyStorage{
+		stats: []itypes.SigningStats{
+			{PluginID: "normal", Window: itypes.SigningActivity{Requests: 30, Rejections: 3, Resolved: 20, Failed: 2}, Baseline: baseline, NewRecipients: 3},
+			{PluginID: "burst", Window: itypes.SigningActivity{Requests: 60}, Baseline: baseline},
+			{PluginID: "rejected", Window: itypes.SigningActivity{Requests: 30, Rejections: 20}, Baseline: baseline},
+			{PluginID: "drained", Window: itypes.SigningActivity{Requests: 30, Resolved: 10}, Baseline: baseline, NewRecipients: 29},
+			{PluginID: "failing", Window: itypes.SigningActivity{Requests: 30, Resolved: 20, Failed: 15}, Baseline: baseline},
+			// its failure rate is usually that high
+			{PluginID: "flaky", Window: itypes.SigningActivity{Requests: 30, Resolved: 20, Failed: 14}, Baseline: itypes.SigningActivity{Requests: 1680, Resolved: 1000, Failed: 700}},
+			// no history, all of its recipients are new
+			{PluginID: "new", Window: itypes.SigningActivity{Requests: 30}, NewRecipients: 30},
+			{PluginID: "quiet", Window: itypes.SigningActivity{Requests: 5, Rejections: 5}, Baseline: baseline},
+			{PluginID: "paused", Window: itypes.SigningActivity{Requests: 500}, Baseline: baseline},
+			// thresholds overridden for the plugin
+			{PluginID: "payroll", Window: itypes.SigningActivity{Requests: 30}, Baseline: baseline, NewRecipients: 30},
+		},
+	}
+	cfg := config.AnomalyConfig{
+		Window:   time.Hour,
+		Baseline: 7 * 24 * time.Hour,
+		Defaults: config.AnomalyThresholds{
+			MinRequests:         20,
+			RateFactor:          5,
+			MaxRejectionRate:    0.5,
+			MaxNewRecipientRate: 0.9,
+			MaxFailureRate:      0.5,
+		},
+		Plugins: map[string]config.AnomalyThresholds{
+			"payroll": {MinRequests: 20, RateFactor: 5},
+		},
+	}
+
+	_, err := NewAnomalyService(db, nil, config.AnomalyConfig{}, logrus.New())
+	require.Error(t, err)
+
+	flagDB := &fakeControlFlagStorage{flags: map[string]bool{psafety.KeysignFlagKey("paused"): false}}
+	syncer := &fakeSafetySyncer{}
+	svc, err := NewAnomalyService(db, newTestControlFlagService(t, flagDB, syncer), cfg, logrus.New())
+	require.NoError(t, err)
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	paused, err := svc.Detect(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []types.PluginID{"burst", "rejected", "drained", "failing"}, paused)
+
+	require.Len(t, flagDB.history, 4)
+	for _, record := range flagDB.history {
+		require.Equal(t, itypes.PauseActionAnomalyPaused, record.Action)
+		require.Equal(t, anomalyTriggeredBy, *record.TriggeredBy)
+		require.NotEmpty(t, *record.Reason)
+	}
+	// the request rate has no share threshold
+	require.Nil(t, flagDB.history[0].ThresholdRate)
+	require.Equal(t, 0.5, *flagDB.history[1].ThresholdRate)
+	require.Equal(t, 0.9, *flagDB.history[2].ThresholdRate)
+	require.Equal(t, []psafety.ControlFlag{
+		{Key: psafety.KeysignFlagKey("burst"), Enabled: false},
+		{Key: psafety.KeysignFlagKey("rejected"), Enabled: false},
+		{Key: psafety.KeysignFlagKey("drained"), Enabled: false},
+		{Key: psafety.KeysignFlagKey("failing"), Enabled: false},
+	}, syncer.flags)
+
+	require.NoError(t, svc.HandleAnomalyDetection(context.Background(), nil))
+	require.Len(t, flagDB.history, 4)
+	require.Equal(t, []time.Time{now.Add(-time.Hour - 7*24*time.Hour)}, db.pruned)
+}
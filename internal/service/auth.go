@@ -5,6 +5,7 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -23,6 +24,11 @@ var (
 	ErrGetToken      = errors.New("failed to get token")
 	ErrRevokeToken   = errors.New("failed to revoke token")
 	ErrCommitTx      = errors.New("failed to commit transaction")
+	// ErrRefreshTokenReused is returned when a rotated refresh token is presented again, its family is revoked
+	ErrRefreshTokenReused = errors.New("refresh token reused")
+	// ErrRefreshTokenRotating is returned when a refresh token is presented again right after its rotation,
+	// most likely by a concurrent refresh of the same device, so its family is kept
+	ErrRefreshTokenRotating = errors.New("refresh token was just rotated")
 )
 
 type Claims struct {
@@ -30,6 +36,9 @@ type Claims struct {
 	PublicKey string `json:"public_key"`
 	TokenID   string `json:"token_id"`
 	TokenType string `json:"token_type"`
+	// FamilyID is the family of the refresh token an access token was issued with,
+	// the access token is rejected once the family is revoked
+	FamilyID string `json:"family_id,omitempty"`
 }
 
 type TokenPair struct {
@@ -45,6 +54,9 @@ const (
 	accessTokenDuration  = 60 * time.Minute
 	refreshTokenDuration = 7 * 24 * time.Hour
 	tokenIDLength        = 32
+	// refreshReuseGrace is how long a rotated refresh token is answered with ErrRefreshTokenRotating
+	// instead of revoking its family, so that concurrent refreshes of a device don't sign it out
+	refreshReuseGrace = 30 * time.Second
 )
 
 type AuthService struct {
@@ -111,21 +123,21 @@ func (a *AuthService) GenerateToken(ctx context.Context, publicKey string) (stri
 	return tokenString, nil
 }
 
-// GenerateTokenPair creates both access and refresh tokens
-func (a *AuthService) GenerateTokenPair(ctx context.Context, publicKey string) (*TokenPair, error) {
-	refreshToken, refreshTokenID, err := a.generateRefreshToken(ctx, publicKey)
+// GenerateTokenPair creates both access and refresh tokens, the refresh token starts a new family for the device
+func (a *AuthService) GenerateTokenPair(ctx context.Context, publicKey string, device types.VaultTokenDevice) (*TokenPair, error) {
+	refreshToken, created, err := a.generateRefreshToken(ctx, publicKey, device)
 	if err != nil {
 		return nil, err
 	}
 
-	accessToken, err := a.generateAccessToken(publicKey)
+	accessToken, err := a.generateAccessToken(publicKey, created.FamilyID)
 	if err != nil {
 		return nil, err
 	}
 
 	a.logger.WithFields(logrus.Fields{
 		"public_key":       publicKey,
-		"refresh_token_id": refreshTokenID,
+		"refresh_token_id": created.TokenID,
 	}).Info("Generated token pair")
 
 	return &TokenPair{
@@ -135,8 +147,8 @@ func (a *AuthService) GenerateTokenPair(ctx context.Context, publicKey string) (
 	}, nil
 }
 
-// generateAccessToken creates a stateless access token (no DB storage)
-func (a *AuthService) generateAccessToken(publicKey string) (string, error) {
+// generateAccessToken creates a stateless access token (no DB storage) bound to the refresh token family
+func (a *AuthService) generateAccessToken(publicKey, familyID string) (string, error) {
 	tokenID, err := generateTokenID()
 	if err != nil {
 		return "", err
@@ -151,17 +163,18 @@ func (a *AuthService) generateAccessToken(publicKey string) (string, error) {
 		PublicKey: publicKey,
 		TokenID:   tokenID,
 		TokenType: TokenTypeAccess,
+		FamilyID:  familyID,
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString(a.JWTSecret)
 }
 
-// generateRefreshToken creates a DB-stored refresh token
-func (a *AuthService) generateRefreshToken(ctx context.Context, publicKey string) (string, string, error) {
+// signRefreshToken creates a refresh token, the caller stores it with the returned record
+func (a *AuthService) signRefreshToken(publicKey string, device types.VaultTokenDevice) (string, types.VaultTokenCreate, error) {
 	tokenID, err := generateTokenID()
 	if err != nil {
-		return "", "", err
+		return "", types.VaultTokenCreate{}, err
 	}
 
 	expirationTime := time.Now().Add(refreshTokenDuration)
@@ -178,23 +191,34 @@ func (a *AuthService) generateRefreshToken(ctx context.Context, publicKey string
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	tokenString, err := token.SignedString(a.JWTSecret)
 	if err != nil {
-		return "", "", err
+		return "", types.VaultTokenCreate{}, err
 	}
 
-	_, err = a.db.CreateVaultToken(ctx, types.VaultTokenCreate{
-		PublicKey: publicKey,
-		TokenID:   tokenID,
-		ExpiresAt: expirationTime,
-	})
+	return tokenString, types.VaultTokenCreate{
+		PublicKey:        publicKey,
+		TokenID:          tokenID,
+		ExpiresAt:        expirationTime,
+		VaultTokenDevice: device,
+	}, nil
+}
+
+// generateRefreshToken creates a DB-stored refresh token
+func (a *AuthService) generateRefreshToken(ctx context.Context, publicKey string, device types.VaultTokenDevice) (string, *types.VaultToken, error) {
+	tokenString, record, err := a.signRefreshToken(publicKey, device)
 	if err != nil {
-		return "", "", err
+		return "", nil, err
 	}
 
-	return tokenString, tokenID, nil
+	created, err := a.db.CreateVaultToken(ctx, record)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return tokenString, created, nil
 }
 
-// ValidateToken validates a JWT token and checks its revocation status
-func (a *AuthService) ValidateToken(ctx context.Context, tokenStr string) (*Claims, error) {
+// parseToken checks the signature, expiry and fields of a JWT token
+func (a *AuthService) parseToken(tokenStr string) (*Claims, error) {
 	claims := &Claims{}
 	token, err := jwt.ParseWithClaims(tokenStr, claims, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
@@ -220,6 +244,16 @@ func (a *AuthService) ValidateToken(ctx context.Context, tokenStr string) (*Clai
 		return nil, errors.New("token missing token type")
 	}
 
+	return claims, nil
+}
+
+// ValidateToken validates a JWT token and checks its revocation status
+func (a *AuthService) ValidateToken(ctx context.Context, tokenStr string) (*Claims, error) {
+	claims, err := a.parseToken(tokenStr)
+	if err != nil {
+		return nil, err
+	}
+
 	if claims.TokenType == TokenTypeRefresh {
 		dbToken, err := a.db.GetVaultToken(ctx, claims.TokenID)
 		if err != nil {
@@ -234,6 +268,10 @@ func (a *AuthService) ValidateToken(ctx context.Context, tokenStr string) (*Clai
 			return nil, errors.New("token has been revoked")
 		}
 
+		if dbToken.IsRotated() {
+			return nil, errors.New("token has been rotated")
+		}
+
 		// Update last used timestamp
 		err = a.db.UpdateVaultTokenLastUsed(ctx, claims.TokenID)
 		if err != nil {
@@ -241,12 +279,27 @@ func (a *AuthService) ValidateToken(ctx context.Context, tokenStr string) (*Clai
 		}
 	}
 
+	// access tokens issued before they carried a family stay valid until they expire
+	if claims.TokenType == TokenTypeAccess && claims.FamilyID != "" {
+		revoked, err := a.db.IsVaultTokenFamilyRevoked(ctx, claims.FamilyID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check token family: %w", err)
+		}
+		if revoked {
+			return nil, errors.New("token has been revoked")
+		}
+	}
+
 	return claims, nil
 }
 
-// RefreshToken validates refresh token and issues new access token
-func (a *AuthService) RefreshToken(ctx context.Context, refreshTokenStr string) (*TokenPair, error) {
-	claims, err := a.ValidateToken(ctx, refreshTokenStr)
+// RefreshToken exchanges a refresh token for a new token pair, the refresh token can't be used again.
+// A refresh token presented after it was exchanged means that a copy of it leaked,
+// so every token of its family is revoked, including the access tokens, and the device has to log in again.
+// Within refreshReuseGrace of the exchange it is rather a concurrent refresh and ErrRefreshTokenRotating is returned.
+// The device name carries over from the refresh token when device has none.
+func (a *AuthService) RefreshToken(ctx context.Context, refreshTokenStr string, device types.VaultTokenDevice) (*TokenPair, error) {
+	claims, err := a.parseToken(refreshTokenStr)
 	if err != nil {
 		return nil, err
 	}
@@ -255,24 +308,69 @@ func (a *AuthService) RefreshToken(ctx context.Context, refreshTokenStr string)
 		return nil, errors.New("invalid token type: expected refresh token")
 	}
 
-	accessToken, err := a.generateAccessToken(claims.PublicKey)
+	dbToken, err := a.db.GetVaultToken(ctx, claims.TokenID)
+	if err != nil || dbToken == nil {
+		return nil, errors.New("token not found in database")
+	}
+	if dbToken.IsRevoked() {
+		return nil, errors.New("token has been revoked")
+	}
+	if dbToken.IsRotated() {
+		if time.Since(*dbToken.RotatedAt) < refreshReuseGrace {
+			return nil, ErrRefreshTokenRotating
+		}
+		return nil, a.revokeReusedToken(ctx, claims)
+	}
+
+	if device.DeviceName == "" {
+		device.DeviceName = dbToken.DeviceName
+	}
+	refreshToken, record, err := a.signRefreshToken(claims.PublicKey, device)
+	if err != nil {
+		return nil, err
+	}
+	rotated, err := a.db.RotateVaultToken(ctx, claims.TokenID, record)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+	if rotated == nil {
+		// another request exchanged the token since it was read
+		return nil, ErrRefreshTokenRotating
+	}
+
+	accessToken, err := a.generateAccessToken(claims.PublicKey, rotated.FamilyID)
 	if err != nil {
 		return nil, err
 	}
 
 	a.logger.WithFields(logrus.Fields{
-		"public_key": claims.PublicKey,
-		"token_id":   claims.TokenID,
-	}).Info("Refreshed access token")
+		"public_key":       claims.PublicKey,
+		"token_id":         claims.TokenID,
+		"refresh_token_id": record.TokenID,
+	}).Info("Rotated refresh token")
 
 	return &TokenPair{
 		AccessToken:  accessToken,
-		RefreshToken: refreshTokenStr,
+		RefreshToken: refreshToken,
 		ExpiresIn:    int(accessTokenDuration.Seconds()),
 	}, nil
 }
 
-// RevokeToken revokes a specific token
+// revokeReusedToken revokes the family of a refresh token presented again after its rotation
+func (a *AuthService) revokeReusedToken(ctx context.Context, claims *Claims) error {
+	logger := a.logger.WithFields(logrus.Fields{
+		"public_key": claims.PublicKey,
+		"token_id":   claims.TokenID,
+	})
+	logger.Warn("Rotated refresh token reused, revoking its family")
+	if err := a.db.RevokeVaultToken(ctx, claims.TokenID); err != nil {
+		logger.WithError(err).Error("Failed to revoke reused token family")
+		return ErrRevokeToken
+	}
+	return ErrRefreshTokenReused
+}
+
+// RevokeToken revokes a specific token and the tokens rotated from the same login, signing its device out
 func (a *AuthService) RevokeToken(ctx context.Context, vaultKey, tokenID string) error {
 	tok, err := a.db.GetVaultToken(ctx, tokenID)
 	if err != nil {
@@ -48,6 +48,14 @@ func (m *MockDatabaseStorage) CreateVaultToken(ctx context.Context, token itypes
 	return args.Get(0).(*itypes.VaultToken), args.Error(1)
 }
 
+func (m *MockDatabaseStorage) RotateVaultToken(ctx context.Context, tokenID string, next itypes.VaultTokenCreate) (*itypes.VaultToken, error) {
+	args := m.Called(ctx, tokenID, next)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*itypes.VaultToken), args.Error(1)
+}
+
 func (m *MockDatabaseStorage) GetVaultToken(ctx context.Context, tokenID string) (*itypes.VaultToken, error) {
 	args := m.Called(ctx, tokenID)
 	if args.Get(0) == nil {
@@ -61,6 +69,11 @@ func (m *MockDatabaseStorage) RevokeVaultToken(ctx context.Context, tokenID stri
 	return args.Error(0)
 }
 
+func (m *MockDatabaseStorage) IsVaultTokenFamilyRevoked(ctx context.Context, familyID string) (bool, error) {
+	args := m.Called(ctx, familyID)
+	return args.Bool(0), args.Error(1)
+}
+
 func (m *MockDatabaseStorage) RevokeAllVaultTokens(ctx context.Context, publicKey string) error {
 	args := m.Called(ctx, publicKey)
 	return args.Error(0)
@@ -277,6 +290,37 @@ func (m *MockDatabaseStorage) UpdatePluginPolicySync(ctx context.Context, dbTx p
 	return args.Error(0)
 }
 
+func (m *MockDatabaseStorage) ClaimPolicySyncs(ctx context.Context, lease time.Duration, limit int) ([]itypes.PluginPolicySync, error) {
+	args := m.Called(ctx, lease, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]itypes.PluginPolicySync), args.Error(1)
+}
+
+func (m *MockDatabaseStorage) MarkPolicySyncDelivered(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockDatabaseStorage) MarkPolicySyncFailed(ctx context.Context, id uuid.UUID, reason string, nextAttemptAt time.Time) error {
+	args := m.Called(ctx, id, reason, nextAttemptAt)
+	return args.Error(0)
+}
+
+func (m *MockDatabaseStorage) MarkPolicySyncDeadLettered(ctx context.Context, id uuid.UUID, reason string) error {
+	args := m.Called(ctx, id, reason)
+	return args.Error(0)
+}
+
+func (m *MockDatabaseStorage) GetPolicySyncs(ctx context.Context, policyID uuid.UUID) ([]itypes.PluginPolicySync, error) {
+	args := m.Called(ctx, policyID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]itypes.PluginPolicySync), args.Error(1)
+}
+
 func (m *MockDatabaseStorage) AttachTagToPlugin(ctx context.Context, pluginID types.PluginID, tagID string) (*itypes.Plugin, error) {
 	args := m.Called(ctx, pluginID, tagID)
 	if args.Get(0) == nil {
@@ -371,12 +415,9 @@ func (m *MockDatabaseStorage) GetAPIKey(ctx context.Context, apiKey string) (*it
 	return args.Get(0).(*itypes.APIKey), args.Error(1)
 }
 
-func (m *MockDatabaseStorage) GetAPIKeyByPluginId(ctx context.Context, pluginId string) (*itypes.APIKey, error) {
-	args := m.Called(ctx, pluginId)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
-	}
-	return args.Get(0).(*itypes.APIKey), args.Error(1)
+func (m *MockDatabaseStorage) TouchAPIKey(ctx context.Context, id string, ip string) error {
+	args := m.Called(ctx, id, ip)
+	return args.Error(0)
 }
 
 func (m *MockDatabaseStorage) GetPluginTitlesByIDs(ctx context.Context, ids []string) (map[string]string, error) {
@@ -387,6 +428,69 @@ func (m *MockDatabaseStorage) GetPluginTitlesByIDs(ctx context.Context, ids []st
 	return args.Get(0).(map[string]string), args.Error(1)
 }
 
+func (m *MockDatabaseStorage) GetPluginIDs(ctx context.Context) ([]types.PluginID, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]types.PluginID), args.Error(1)
+}
+
+func (m *MockDatabaseStorage) ListenPluginServerInfo(ctx context.Context, fn func(types.PluginID)) error {
+	args := m.Called(ctx, fn)
+	return args.Error(0)
+}
+
+func (m *MockDatabaseStorage) GetPluginEndpoints(ctx context.Context) (map[types.PluginID]string, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[types.PluginID]string), args.Error(1)
+}
+
+func (m *MockDatabaseStorage) UpsertPluginHealth(ctx context.Context, health itypes.PluginHealth) error {
+	args := m.Called(ctx, health)
+	return args.Error(0)
+}
+
+func (m *MockDatabaseStorage) GetPluginHealth(ctx context.Context, pluginIDs []types.PluginID) (map[types.PluginID]itypes.PluginHealth, error) {
+	args := m.Called(ctx, pluginIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[types.PluginID]itypes.PluginHealth), args.Error(1)
+}
+
+func (m *MockDatabaseStorage) CreatePluginSigningKey(ctx context.Context, key itypes.PluginSigningKey, overlap time.Duration) (*itypes.PluginSigningKey, error) {
+	args := m.Called(ctx, key, overlap)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*itypes.PluginSigningKey), args.Error(1)
+}
+
+func (m *MockDatabaseStorage) GetPluginSigningKeys(ctx context.Context, pluginID types.PluginID) ([]itypes.PluginSigningKey, error) {
+	args := m.Called(ctx, pluginID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]itypes.PluginSigningKey), args.Error(1)
+}
+
+func (m *MockDatabaseStorage) GetPluginSigningKey(ctx context.Context, keyID string) (*itypes.PluginSigningKey, error) {
+	args := m.Called(ctx, keyID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*itypes.PluginSigningKey), args.Error(1)
+}
+
+func (m *MockDatabaseStorage) RevokePluginSigningKey(ctx context.Context, pluginID types.PluginID, keyID string) (bool, error) {
+	args := m.Called(ctx, pluginID, keyID)
+	return args.Bool(0), args.Error(1)
+}
+
 func (m *MockDatabaseStorage) GetPricingsByPluginIDs(ctx context.Context, pluginIDs []string) (map[string][]itypes.PricingInfo, error) {
 	args := m.Called(ctx, pluginIDs)
 	if args.Get(0) == nil {
@@ -428,11 +532,48 @@ func (m *MockDatabaseStorage) IsPluginPaused(ctx context.Context, pluginID types
 	return args.Bool(0), args.Error(1)
 }
 
-func (m *MockDatabaseStorage) PausePlugin(ctx context.Context, pluginID types.PluginID, record itypes.PauseHistoryRecord) error {
-	args := m.Called(ctx, pluginID, record)
+func (m *MockDatabaseStorage) GetPauseHistory(ctx context.Context, pluginID types.PluginID, skip, take uint32) ([]itypes.PauseHistoryRecord, uint32, error) {
+	args := m.Called(ctx, pluginID, skip, take)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]itypes.PauseHistoryRecord), args.Get(1).(uint32), args.Error(2)
+}
+
+func (m *MockDatabaseStorage) ListReports(ctx context.Context, pluginID types.PluginID, status itypes.ReportStatus, skip, take uint32) ([]itypes.PluginReport, uint32, error) {
+	args := m.Called(ctx, pluginID, status, skip, take)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]itypes.PluginReport), args.Get(1).(uint32), args.Error(2)
+}
+
+func (m *MockDatabaseStorage) ReviewReport(ctx context.Context, pluginID types.PluginID, publicKey string, status itypes.ReportStatus, reviewedBy, note string) (*itypes.PluginReport, error) {
+	args := m.Called(ctx, pluginID, publicKey, status, reviewedBy, note)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*itypes.PluginReport), args.Error(1)
+}
+
+func (m *MockDatabaseStorage) InsertKeysignRejection(ctx context.Context, pluginID types.PluginID, policyID uuid.UUID, reason string) error {
+	args := m.Called(ctx, pluginID, policyID, reason)
 	return args.Error(0)
 }
 
+func (m *MockDatabaseStorage) DeleteKeysignRejectionsBefore(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockDatabaseStorage) GetSigningStats(ctx context.Context, window, baseline time.Duration) ([]itypes.SigningStats, error) {
+	args := m.Called(ctx, window, baseline)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]itypes.SigningStats), args.Error(1)
+}
+
 func (m *MockDatabaseStorage) GetControlFlags(ctx context.Context, k1, k2 string) (map[string]bool, error) {
 	args := m.Called(ctx, k1, k2)
 	if args.Get(0) == nil {
@@ -441,6 +582,53 @@ func (m *MockDatabaseStorage) GetControlFlags(ctx context.Context, k1, k2 string
 	return args.Get(0).(map[string]bool), args.Error(1)
 }
 
+func (m *MockDatabaseStorage) GetControlFlagsByKeys(ctx context.Context, keys []string) (map[string]bool, error) {
+	args := m.Called(ctx, keys)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]bool), args.Error(1)
+}
+
+func (m *MockDatabaseStorage) ListControlFlags(ctx context.Context) ([]itypes.ControlFlag, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]itypes.ControlFlag), args.Error(1)
+}
+
+func (m *MockDatabaseStorage) ApplyControlFlagChange(ctx context.Context, change itypes.ControlFlagChange) ([]itypes.ControlFlagOutboxEntry, bool, error) {
+	args := m.Called(ctx, change)
+	if args.Get(0) == nil {
+		return nil, args.Bool(1), args.Error(2)
+	}
+	return args.Get(0).([]itypes.ControlFlagOutboxEntry), args.Bool(1), args.Error(2)
+}
+
+func (m *MockDatabaseStorage) GetPendingControlFlagOutbox(ctx context.Context, limit int) ([]itypes.ControlFlagOutboxEntry, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]itypes.ControlFlagOutboxEntry), args.Error(1)
+}
+
+func (m *MockDatabaseStorage) MarkControlFlagOutboxDelivered(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockDatabaseStorage) MarkControlFlagOutboxFailed(ctx context.Context, id int64, lastError string, nextAttemptAt time.Time) error {
+	args := m.Called(ctx, id, lastError, nextAttemptAt)
+	return args.Error(0)
+}
+
+func (m *MockDatabaseStorage) DeleteDeliveredControlFlagOutboxBefore(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *MockDatabaseStorage) IsOwner(ctx context.Context, pluginID types.PluginID, publicKey string) (bool, error) {
 	args := m.Called(ctx, pluginID, publicKey)
 	return args.Bool(0), args.Error(1)
@@ -514,7 +702,7 @@ func TestGenerateTokenPair(t *testing.T) {
 			}, nil)
 
 			auth := service.NewAuthService(tt.secret, mockDB, testLogger)
-			tokenPair, err := auth.GenerateTokenPair(context.Background(), tt.publicKey)
+			tokenPair, err := auth.GenerateTokenPair(context.Background(), tt.publicKey, itypes.VaultTokenDevice{})
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -572,7 +760,7 @@ func TestValidateToken(t *testing.T) {
 				mockDB.On("UpdateVaultTokenLastUsed", mock.Anything, mock.Anything).Return(nil)
 
 				auth := service.NewAuthService(secret, mockDB, testLogger)
-				tokenPair, _ := auth.GenerateTokenPair(context.Background(), "test-public-key")
+				tokenPair, _ := auth.GenerateTokenPair(context.Background(), "test-public-key", itypes.VaultTokenDevice{})
 				return tokenPair.RefreshToken
 			},
 			secret:      secret,
@@ -619,12 +807,12 @@ func TestValidateToken(t *testing.T) {
 			name: "Wrong secret",
 			setupToken: func() string {
 				mockDB := new(MockDatabaseStorage)
-				mockDB.On("CreateVaultToken", mock.Anything, mock.Anything).Return(nil, nil)
+				mockDB.On("CreateVaultToken", mock.Anything, mock.Anything).Return(&itypes.VaultToken{}, nil)
 				mockDB.On("GetVaultToken", mock.Anything, mock.Anything).Return(nil, nil)
 				mockDB.On("UpdateVaultTokenLastUsed", mock.Anything, mock.Anything).Return(nil)
 
 				auth := service.NewAuthService(secret, mockDB, testLogger)
-				tokenPair, _ := auth.GenerateTokenPair(context.Background(), "test-public-key")
+				tokenPair, _ := auth.GenerateTokenPair(context.Background(), "test-public-key", itypes.VaultTokenDevice{})
 				return tokenPair.RefreshToken
 			},
 			secret:      wrongSecret,
@@ -691,7 +879,7 @@ func TestRefreshToken(t *testing.T) {
 				mockDB.On("UpdateVaultTokenLastUsed", mock.Anything, tokenID).Return(nil)
 
 				auth := service.NewAuthService(secret, mockDB, testLogger)
-				tokenPair, _ := auth.GenerateTokenPair(context.Background(), testPublicKey)
+				tokenPair, _ := auth.GenerateTokenPair(context.Background(), testPublicKey, itypes.VaultTokenDevice{})
 				return tokenPair.RefreshToken
 			},
 			shouldError: false,
@@ -751,11 +939,15 @@ func TestRefreshToken(t *testing.T) {
 						TokenID:   uuid.New().String(),
 						PublicKey: testPublicKey,
 					}, nil)
-				mockDB.On("UpdateVaultTokenLastUsed", mock.Anything, mock.Anything).Return(nil)
+				mockDB.On("RotateVaultToken", mock.Anything, mock.Anything, mock.Anything).
+					Return(&itypes.VaultToken{
+						TokenID:   uuid.New().String(),
+						PublicKey: testPublicKey,
+					}, nil)
 			}
 
 			authService := service.NewAuthService(secret, mockDB, testLogger)
-			tokenPair, err := authService.RefreshToken(context.Background(), tokenString)
+			tokenPair, err := authService.RefreshToken(context.Background(), tokenString, itypes.VaultTokenDevice{})
 
 			if tc.shouldError {
 				assert.Error(t, err)
@@ -765,7 +957,7 @@ func TestRefreshToken(t *testing.T) {
 				assert.NotNil(t, tokenPair)
 				assert.NotEmpty(t, tokenPair.AccessToken)
 				assert.NotEmpty(t, tokenPair.RefreshToken)
-				assert.Equal(t, tokenString, tokenPair.RefreshToken, "Refresh token should remain the same")
+				assert.NotEqual(t, tokenString, tokenPair.RefreshToken, "Refresh token should be rotated")
 				assert.NotEqual(t, tokenString, tokenPair.AccessToken, "Access token should be different from refresh token")
 				assert.Equal(t, 3600, tokenPair.ExpiresIn, "Access token should expire in 3600 seconds (60 minutes)")
 
@@ -777,3 +969,385 @@ func TestRefreshToken(t *testing.T) {
 		})
 	}
 }
+
+func TestRefreshTokenRotation(t *testing.T) {
+	secret := "rotation-test-secret"
+	device := itypes.VaultTokenDevice{UserAgent: "vultisig-ios/1.2", IPAddress: "10.0.0.1"}
+
+	login := func(t *testing.T) (string, string) {
+		mockDB := new(MockDatabaseStorage)
+		var tokenID string
+		mockDB.On("CreateVaultToken", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				tokenID = args.Get(1).(itypes.VaultTokenCreate).TokenID
+			}).
+			Return(&itypes.VaultToken{}, nil)
+		auth := service.NewAuthService(secret, mockDB, testLogger)
+		tokenPair, err := auth.GenerateTokenPair(context.Background(), testPublicKey, itypes.VaultTokenDevice{DeviceName: "iPhone"})
+		assert.NoError(t, err)
+		return tokenPair.RefreshToken, tokenID
+	}
+
+	t.Run("Rotates and keeps the device name", func(t *testing.T) {
+		refreshToken, tokenID := login(t)
+		mockDB := new(MockDatabaseStorage)
+		mockDB.On("GetVaultToken", mock.Anything, tokenID).Return(&itypes.VaultToken{
+			TokenID:          tokenID,
+			PublicKey:        testPublicKey,
+			VaultTokenDevice: itypes.VaultTokenDevice{DeviceName: "iPhone"},
+		}, nil)
+		mockDB.On("RotateVaultToken", mock.Anything, tokenID, mock.MatchedBy(func(next itypes.VaultTokenCreate) bool {
+			return next.TokenID != tokenID &&
+				next.PublicKey == testPublicKey &&
+				next.DeviceName == "iPhone" &&
+				next.UserAgent == device.UserAgent &&
+				next.IPAddress == device.IPAddress
+		})).Return(&itypes.VaultToken{}, nil)
+
+		auth := service.NewAuthService(secret, mockDB, testLogger)
+		tokenPair, err := auth.RefreshToken(context.Background(), refreshToken, device)
+		assert.NoError(t, err)
+		assert.NotEqual(t, refreshToken, tokenPair.RefreshToken)
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("Reuse revokes the family", func(t *testing.T) {
+		refreshToken, tokenID := login(t)
+		rotatedAt := time.Now().Add(-time.Minute)
+		mockDB := new(MockDatabaseStorage)
+		mockDB.On("GetVaultToken", mock.Anything, tokenID).Return(&itypes.VaultToken{
+			TokenID:   tokenID,
+			PublicKey: testPublicKey,
+			RotatedAt: &rotatedAt,
+		}, nil)
+		mockDB.On("RevokeVaultToken", mock.Anything, tokenID).Return(nil)
+
+		auth := service.NewAuthService(secret, mockDB, testLogger)
+		tokenPair, err := auth.RefreshToken(context.Background(), refreshToken, device)
+		assert.ErrorIs(t, err, service.ErrRefreshTokenReused)
+		assert.Nil(t, tokenPair)
+		mockDB.AssertExpectations(t)
+		mockDB.AssertNotCalled(t, "RotateVaultToken", mock.Anything, mock.Anything, mock.Anything)
+
+		_, err = auth.ValidateToken(context.Background(), refreshToken)
+		assert.Error(t, err)
+	})
+
+	t.Run("Reuse right after the rotation keeps the family", func(t *testing.T) {
+		refreshToken, tokenID := login(t)
+		rotatedAt := time.Now().Add(-time.Second)
+		mockDB := new(MockDatabaseStorage)
+		mockDB.On("GetVaultToken", mock.Anything, tokenID).Return(&itypes.VaultToken{
+			TokenID:   tokenID,
+			PublicKey: testPublicKey,
+			RotatedAt: &rotatedAt,
+		}, nil)
+
+		auth := service.NewAuthService(secret, mockDB, testLogger)
+		tokenPair, err := auth.RefreshToken(context.Background(), refreshToken, device)
+		assert.ErrorIs(t, err, service.ErrRefreshTokenRotating)
+		assert.Nil(t, tokenPair)
+		mockDB.AssertNotCalled(t, "RotateVaultToken", mock.Anything, mock.Anything, mock.Anything)
+		mockDB.AssertNotCalled(t, "RevokeVaultToken", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Concurrent rotation keeps the family", func(t *testing.T) {
+		refreshToken, tokenID := login(t)
+		mockDB := new(MockDatabaseStorage)
+		mockDB.On("GetVaultToken", mock.Anything, tokenID).Return(&itypes.VaultToken{
+			TokenID:   tokenID,
+			PublicKey: testPublicKey,
+		}, nil)
+		mockDB.On("RotateVaultToken", mock.Anything, tokenID, mock.Anything).Return(nil, nil)
+
+		auth := service.NewAuthService(secret, mockDB, testLogger)
+		_, err := auth.RefreshToken(context.Background(), refreshToken, device)
+		assert.ErrorIs(t, err, service.ErrRefreshTokenRotating)
+		mockDB.AssertExpectations(t)
+		mockDB.AssertNotCalled(t, "RevokeVaultToken", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Access token is rejected once its family is revoked", func(t *testing.T) {
+		familyID := uuid.New().String()
+		mockDB := new(MockDatabaseStorage)
+		mockDB.On("CreateVaultToken", mock.Anything, mock.Anything).Return(&itypes.VaultToken{
+			TokenID:  uuid.New().String(),
+			FamilyID: familyID,
+		}, nil)
+		auth := service.NewAuthService(secret, mockDB, testLogger)
+		tokenPair, err := auth.GenerateTokenPair(context.Background(), testPublicKey, itypes.VaultTokenDevice{})
+		assert.NoError(t, err)
+
+		mockDB.On("IsVaultTokenFamilyRevoked", mock.Anything, familyID).Return(false, nil).Once()
+		claims, err := auth.ValidateToken(context.Background(), tokenPair.AccessToken)
+		assert.NoError(t, err)
+		assert.Equal(t, familyID, claims.FamilyID)
+
+		mockDB.On("IsVaultTokenFamilyRevoked", mock.Anything, familyID).Return(true, nil).Once()
+		_, err = auth.ValidateToken(context.Background(), tokenPair.AccessToken)
+		assert.Error(t, err)
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("Revoked token is not rotated", func(t *testing.T) {
+		refreshToken, tokenID := login(t)
+		revokedAt := time.Now().Add(-time.Minute)
+		mockDB := new(MockDatabaseStorage)
+		mockDB.On("GetVaultToken", mock.Anything, tokenID).Return(&itypes.VaultToken{
+			TokenID:   tokenID,
+			PublicKey: testPublicKey,
+			RevokedAt: &revokedAt,
+		}, nil)
+
+		auth := service.NewAuthService(secret, mockDB, testLogger)
+		_, err := auth.RefreshToken(context.Background(), refreshToken, device)
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, service.ErrRefreshTokenReused)
+		mockDB.AssertNotCalled(t, "RotateVaultToken", mock.Anything, mock.Anything, mock.Anything)
+		mockDB.AssertNotCalled(t, "RevokeVaultToken", mock.Anything, mock.Anything)
+	})
+}
+
+func (m *MockDatabaseStorage) SavePresign(ctx context.Context, presign types.Presign) error {
+	args := m.Called(ctx, presign)
+	return args.Error(0)
+}
+
+func (m *MockDatabaseStorage) ConsumePresign(ctx context.Context, publicKey, pluginID, id string) (*types.Presign, error) {
+	args := m.Called(ctx, publicKey, pluginID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*types.Presign), args.Error(1)
+}
+
+func (m *MockDatabaseStorage) ListPresignIDs(ctx context.Context, publicKey, pluginID, derivePath string, limit int) ([]string, error) {
+	args := m.Called(ctx, publicKey, pluginID, derivePath, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockDatabaseStorage) CountPresigns(ctx context.Context, publicKey, pluginID, derivePath string) (int, error) {
+	args := m.Called(ctx, publicKey, pluginID, derivePath)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockDatabaseStorage) DeletePresigns(ctx context.Context, publicKey, pluginID string) (int64, error) {
+	args := m.Called(ctx, publicKey, pluginID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockDatabaseStorage) CreateKeysignResult(ctx context.Context, result types.KeysignResult) error {
+	args := m.Called(ctx, result)
+	return args.Error(0)
+}
+
+func (m *MockDatabaseStorage) UpdateKeysignResult(ctx context.Context, txIndexerID uuid.UUID, status types.KeysignResultStatus, failureReason string, data []byte) error {
+	args := m.Called(ctx, txIndexerID, status, failureReason, data)
+	return args.Error(0)
+}
+
+func (m *MockDatabaseStorage) GetKeysignResult(ctx context.Context, txIndexerID uuid.UUID) (*types.KeysignResult, error) {
+	args := m.Called(ctx, txIndexerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*types.KeysignResult), args.Error(1)
+}
+
+func (m *MockDatabaseStorage) DeleteExpiredKeysignResults(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockDatabaseStorage) GetInvoicePublicKeys(ctx context.Context, start, end time.Time) ([]string, error) {
+	args := m.Called(ctx, start, end)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockDatabaseStorage) GetInvoiceData(ctx context.Context, publicKey string, start, end time.Time) (itypes.InvoiceData, error) {
+	args := m.Called(ctx, publicKey, start, end)
+	return args.Get(0).(itypes.InvoiceData), args.Error(1)
+}
+
+func (m *MockDatabaseStorage) InsertInvoice(ctx context.Context, invoice *itypes.Invoice) (bool, error) {
+	args := m.Called(ctx, invoice)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockDatabaseStorage) GetInvoices(ctx context.Context, publicKey string, skip, take uint32) ([]itypes.Invoice, uint32, error) {
+	args := m.Called(ctx, publicKey, skip, take)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]itypes.Invoice), args.Get(1).(uint32), args.Error(2)
+}
+
+func (m *MockDatabaseStorage) GetInvoice(ctx context.Context, publicKey string, id uuid.UUID) (*itypes.Invoice, error) {
+	args := m.Called(ctx, publicKey, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*itypes.Invoice), args.Error(1)
+}
+
+func (m *MockDatabaseStorage) GetUnpaidAccounts(ctx context.Context, publicKey string, dueBefore time.Time) ([]itypes.UnpaidAccount, error) {
+	args := m.Called(ctx, publicKey, dueBefore)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]itypes.UnpaidAccount), args.Error(1)
+}
+
+func (m *MockDatabaseStorage) GetDunningStates(ctx context.Context, publicKey string) ([]itypes.DunningState, error) {
+	args := m.Called(ctx, publicKey)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]itypes.DunningState), args.Error(1)
+}
+
+func (m *MockDatabaseStorage) UpsertDunningState(ctx context.Context, dbTx pgx.Tx, state itypes.DunningState) error {
+	args := m.Called(ctx, dbTx, state)
+	return args.Error(0)
+}
+
+func (m *MockDatabaseStorage) DeleteDunningState(ctx context.Context, dbTx pgx.Tx, publicKey string) error {
+	args := m.Called(ctx, dbTx, publicKey)
+	return args.Error(0)
+}
+
+func (m *MockDatabaseStorage) InsertDunningEvent(ctx context.Context, dbTx pgx.Tx, event itypes.DunningEvent) error {
+	args := m.Called(ctx, dbTx, event)
+	return args.Error(0)
+}
+
+func (m *MockDatabaseStorage) GetLedgerBalances(ctx context.Context, accountType itypes.LedgerAccountType, accountID string) ([]itypes.LedgerBalance, error) {
+	args := m.Called(ctx, accountType, accountID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]itypes.LedgerBalance), args.Error(1)
+}
+
+func (m *MockDatabaseStorage) GetBatchCollections(ctx context.Context) ([]itypes.BatchCollection, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]itypes.BatchCollection), args.Error(1)
+}
+
+func (m *MockDatabaseStorage) GetLedgerIntegrityIssues(ctx context.Context) ([]uint64, []uint64, error) {
+	args := m.Called(ctx)
+	unbalanced, _ := args.Get(0).([]uint64)
+	unposted, _ := args.Get(1).([]uint64)
+	return unbalanced, unposted, args.Error(2)
+}
+
+func (m *MockDatabaseStorage) InsertReconciliationReport(ctx context.Context, report *itypes.ReconciliationReport) error {
+	args := m.Called(ctx, report)
+	return args.Error(0)
+}
+
+func (m *MockDatabaseStorage) GetReconciliationReports(ctx context.Context, skip, take uint32) ([]itypes.ReconciliationReport, uint32, error) {
+	args := m.Called(ctx, skip, take)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]itypes.ReconciliationReport), args.Get(1).(uint32), args.Error(2)
+}
+
+func (m *MockDatabaseStorage) GetPayableFees(ctx context.Context, periodEnd time.Time) ([]itypes.PayableFee, error) {
+	args := m.Called(ctx, periodEnd)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]itypes.PayableFee), args.Error(1)
+}
+
+func (m *MockDatabaseStorage) InsertDeveloperPayouts(ctx context.Context, dbTx pgx.Tx, payouts []*itypes.DeveloperPayout) error {
+	args := m.Called(ctx, dbTx, payouts)
+	return args.Error(0)
+}
+
+func (m *MockDatabaseStorage) GetOpenPayouts(ctx context.Context, staleBefore time.Time) ([]itypes.DeveloperPayout, error) {
+	args := m.Called(ctx, staleBefore)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]itypes.DeveloperPayout), args.Error(1)
+}
+
+func (m *MockDatabaseStorage) ClaimPayout(ctx context.Context, id uint64, staleBefore time.Time) (*itypes.DeveloperPayout, error) {
+	args := m.Called(ctx, id, staleBefore)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*itypes.DeveloperPayout), args.Error(1)
+}
+
+func (m *MockDatabaseStorage) UpdatePayoutStatus(ctx context.Context, dbTx pgx.Tx, keysignPolicyID uuid.UUID, txHash string, status *rpc.TxOnChainStatus) (bool, error) {
+	args := m.Called(ctx, dbTx, keysignPolicyID, txHash, status)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockDatabaseStorage) GetDeveloperPayouts(ctx context.Context, ownerPublicKey string, pluginID string, skip, take uint32) ([]itypes.DeveloperPayout, uint32, error) {
+	args := m.Called(ctx, ownerPublicKey, pluginID, skip, take)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]itypes.DeveloperPayout), args.Get(1).(uint32), args.Error(2)
+}
+
+func (m *MockDatabaseStorage) RefundFee(ctx context.Context, dbTx pgx.Tx, refund types.Refund) (uint64, error) {
+	args := m.Called(ctx, dbTx, refund)
+	return args.Get(0).(uint64), args.Error(1)
+}
+
+func (m *MockDatabaseStorage) GetUnrefundedTxFees(ctx context.Context, since time.Time) ([]itypes.BilledTxFee, error) {
+	args := m.Called(ctx, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]itypes.BilledTxFee), args.Error(1)
+}
+
+func (m *MockDatabaseStorage) UpsertUnbilledTx(ctx context.Context, tx itypes.UnbilledTx) error {
+	args := m.Called(ctx, tx)
+	return args.Error(0)
+}
+
+func (m *MockDatabaseStorage) GetDueUnbilledTxs(ctx context.Context, now time.Time, limit int) ([]itypes.UnbilledTx, error) {
+	args := m.Called(ctx, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]itypes.UnbilledTx), args.Error(1)
+}
+
+func (m *MockDatabaseStorage) DeleteUnbilledTx(ctx context.Context, dbTx pgx.Tx, txID uuid.UUID) error {
+	args := m.Called(ctx, dbTx, txID)
+	return args.Error(0)
+}
+
+func (m *MockDatabaseStorage) GetIdleSubscriptionFees(ctx context.Context, since, now time.Time) ([]*types.Fee, error) {
+	args := m.Called(ctx, since, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*types.Fee), args.Error(1)
+}
+
+func (m *MockDatabaseStorage) GetSubscriptionsToBill(ctx context.Context, now time.Time) ([]itypes.Subscription, error) {
+	args := m.Called(ctx, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]itypes.Subscription), args.Error(1)
+}
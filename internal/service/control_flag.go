@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/sirupsen/logrus"
+
+	itypes "github.com/vultisig/verifier/internal/types"
+	psafety "github.com/vultisig/verifier/plugin/safety"
+	"github.com/vultisig/verifier/types"
+)
+
+const (
+	outboxBatchSize = 100
+	outboxBaseDelay = 30 * time.Second
+	outboxMaxDelay  = time.Hour
+	// outboxRetention is how long delivered entries are kept
+	outboxRetention = 7 * 24 * time.Hour
+)
+
+type ControlFlagServiceStorage interface {
+	ApplyControlFlagChange(ctx context.Context, change itypes.ControlFlagChange) ([]itypes.ControlFlagOutboxEntry, bool, error)
+	GetControlFlagsByKeys(ctx context.Context, keys []string) (map[string]bool, error)
+	GetPendingControlFlagOutbox(ctx context.Context, limit int) ([]itypes.ControlFlagOutboxEntry, error)
+	MarkControlFlagOutboxDelivered(ctx context.Context, id int64) error
+	MarkControlFlagOutboxFailed(ctx context.Context, id int64, lastError string, nextAttemptAt time.Time) error
+	DeleteDeliveredControlFlagOutboxBefore(ctx context.Context, before time.Time) (int64, error)
+	GetPluginIDs(ctx context.Context) ([]types.PluginID, error)
+}
+
+type SafetySyncer interface {
+	SyncSafetyToPlugin(ctx context.Context, pluginID types.PluginID, flags []psafety.ControlFlag) error
+}
+
+// ControlFlagChanger applies control flag changes, it reports whether any flag changed
+type ControlFlagChanger interface {
+	Change(ctx context.Context, change itypes.ControlFlagChange) (bool, error)
+}
+
+// ControlFlagService is the single writer of control flags. Changes are recorded in the pause history and
+// pushed to the plugin servers through an outbox, so a plugin server that is down gets them once it is back.
+type ControlFlagService struct {
+	db     ControlFlagServiceStorage
+	syncer SafetySyncer
+	logger *logrus.Logger
+	now    func() time.Time
+}
+
+func NewControlFlagService(db ControlFlagServiceStorage, syncer SafetySyncer, logger *logrus.Logger) (*ControlFlagService, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database storage cannot be nil")
+	}
+	if syncer == nil {
+		return nil, fmt.Errorf("safety syncer cannot be nil")
+	}
+	return &ControlFlagService{
+		db:     db,
+		syncer: syncer,
+		logger: logger.WithField("service", "control-flag").Logger,
+		now:    time.Now,
+	}, nil
+}
+
+// Change applies the change and pushes it to the plugin servers right away, failed pushes are retried by
+// HandleControlFlagOutbox
+func (s *ControlFlagService) Change(ctx context.Context, change itypes.ControlFlagChange) (bool, error) {
+	entries, changed, err := s.db.ApplyControlFlagChange(ctx, change)
+	if err != nil {
+		return false, fmt.Errorf("failed to apply control flag change: %w", err)
+	}
+	for _, entry := range entries {
+		err = s.deliver(ctx, entry)
+		if err != nil {
+			s.logger.WithError(err).WithField("plugin_id", entry.PluginID).Warn("failed to sync safety to plugin, will retry")
+		}
+	}
+	return changed, nil
+}
+
+// SetFlag sets a flag of any scope. Plugin flags are recorded in the pause history of the plugin and
+// pushed to its server, global flags are pushed to every plugin server, the other scopes only apply in the verifier.
+func (s *ControlFlagService) SetFlag(ctx context.Context, key string, enabled bool, setBy, reason string) (bool, error) {
+	change := itypes.ControlFlagChange{
+		Flags: []psafety.ControlFlag{{Key: key, Enabled: enabled}},
+	}
+
+	scope, id, _ := psafety.ParseFlagKey(key)
+	switch scope {
+	case psafety.ScopeGlobal:
+		pluginIDs, err := s.db.GetPluginIDs(ctx)
+		if err != nil {
+			return false, fmt.Errorf("failed to get plugins: %w", err)
+		}
+		change.SyncTo = pluginIDs
+	case psafety.ScopePlugin:
+		pluginID := types.PluginID(id)
+		action := itypes.PauseActionManualPaused
+		if enabled {
+			action = itypes.PauseActionUnpaused
+		}
+		change.Record = &itypes.PauseHistoryRecord{
+			PluginID:    pluginID,
+			Action:      action,
+			TriggeredBy: &setBy,
+		}
+		if reason != "" {
+			change.Record.Reason = &reason
+		}
+		change.SyncTo = []types.PluginID{pluginID}
+	}
+
+	return s.Change(ctx, change)
+}
+
+// HandleControlFlagOutbox retries the pushes of control flags that failed and prunes the delivered ones
+func (s *ControlFlagService) HandleControlFlagOutbox(ctx context.Context, _ *asynq.Task) error {
+	delivered, err := s.DeliverPending(ctx)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to deliver control flag outbox")
+		return err
+	}
+	if delivered > 0 {
+		s.logger.WithField("delivered", delivered).Info("Control flags synced to plugins")
+	}
+
+	deleted, err := s.db.DeleteDeliveredControlFlagOutboxBefore(ctx, s.now().Add(-outboxRetention))
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to delete delivered control flag outbox entries")
+		return nil
+	}
+	if deleted > 0 {
+		s.logger.WithField("deleted", deleted).Debug("Delivered control flag outbox entries deleted")
+	}
+	return nil
+}
+
+// DeliverPending pushes the outbox entries due for a retry, it returns how many were delivered
+func (s *ControlFlagService) DeliverPending(ctx context.Context) (int, error) {
+	entries, err := s.db.GetPendingControlFlagOutbox(ctx, outboxBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get pending control flag outbox: %w", err)
+	}
+
+	delivered := 0
+	for _, entry := range entries {
+		err = s.deliver(ctx, entry)
+		if err != nil {
+			s.logger.WithError(err).WithFields(logrus.Fields{
+				"plugin_id": entry.PluginID,
+				"attempts":  entry.Attempts + 1,
+			}).Warn("failed to sync safety to plugin, will retry")
+			continue
+		}
+		delivered++
+	}
+	return delivered, nil
+}
+
+// deliver pushes the current value of the entry keys, so an entry delivered late never reverts a newer change
+func (s *ControlFlagService) deliver(ctx context.Context, entry itypes.ControlFlagOutboxEntry) error {
+	current, err := s.db.GetControlFlagsByKeys(ctx, entry.Keys)
+	if err != nil {
+		return fmt.Errorf("failed to get control flags: %w", err)
+	}
+	flags := make([]psafety.ControlFlag, 0, len(entry.Keys))
+	for _, key := range entry.Keys {
+		enabled, ok := current[key]
+		flags = append(flags, psafety.ControlFlag{Key: key, Enabled: !ok || enabled})
+	}
+
+	syncErr := s.syncer.SyncSafetyToPlugin(ctx, entry.PluginID, flags)
+	if syncErr != nil {
+		err = s.db.MarkControlFlagOutboxFailed(ctx, entry.ID, syncErr.Error(), s.now().Add(outboxBackoff(entry.Attempts)))
+		if err != nil {
+			return fmt.Errorf("failed to mark outbox entry failed: %w (sync: %v)", err, syncErr)
+		}
+		return syncErr
+	}
+
+	err = s.db.MarkControlFlagOutboxDelivered(ctx, entry.ID)
+	if err != nil {
+		return fmt.Errorf("failed to mark outbox entry delivered: %w", err)
+	}
+	return nil
+}
+
+// outboxBackoff doubles the delay after each failed attempt, up to outboxMaxDelay
+func outboxBackoff(attempts int) time.Duration {
+	delay := outboxBaseDelay
+	for i := 0; i < attempts && delay < outboxMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, outboxMaxDelay)
+}
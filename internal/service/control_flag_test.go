@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	itypes "github.com/vultisig/verifier/internal/types"
+	psafety "github.com/vultisig/verifier/plugin/safety"
+	"github.com/vultisig/verifier/types"
+)
+
+type fakeControlFlagStorage struct {
+	flags     map[string]bool
+	history   []itypes.PauseHistoryRecord
+	outbox    []*itypes.ControlFlagOutboxEntry
+	delivered map[int64]bool
+	plugins   []types.PluginID
+	now       func() time.Time
+}
+
+func (f *fakeControlFlagStorage) ApplyControlFlagChange(_ context.Context, change itypes.ControlFlagChange) ([]itypes.ControlFlagOutboxEntry, bool, error) {
+	if f.flags == nil {
+		f.flags = make(map[string]bool)
+	}
+	changed := false
+	keys := make([]string, 0, len(change.Flags))
+	for _, flag := range change.Flags {
+		keys = append(keys, flag.Key)
+		enabled, ok := f.flags[flag.Key]
+		if (!ok || enabled) != flag.Enabled {
+			f.flags[flag.Key] = flag.Enabled
+			changed = true
+		}
+	}
+	if !changed {
+		return nil, false, nil
+	}
+	if change.Record != nil {
+		f.history = append(f.history, *change.Record)
+	}
+	var entries []itypes.ControlFlagOutboxEntry
+	for _, pluginID := range change.SyncTo {
+		entry := &itypes.ControlFlagOutboxEntry{
+			ID:            int64(len(f.outbox) + 1),
+			PluginID:      pluginID,
+			Keys:          keys,
+			NextAttemptAt: f.now(),
+		}
+		f.outbox = append(f.outbox, entry)
+		entries = append(entries, *entry)
+	}
+	return entries, true, nil
+}
+
+func (f *fakeControlFlagStorage) GetControlFlagsByKeys(_ context.Context, keys []string) (map[string]bool, error) {
+	flags := make(map[string]bool)
+	for _, key := range keys {
+		if enabled, ok := f.flags[key]; ok {
+			flags[key] = enabled
+		}
+	}
+	return flags, nil
+}
+
+func (f *fakeControlFlagStorage) GetPendingControlFlagOutbox(_ context.Context, limit int) ([]itypes.ControlFlagOutboxEntry, error) {
+	var entries []itypes.ControlFlagOutboxEntry
+	for _, entry := range f.outbox {
+		if f.delivered[entry.ID] || entry.NextAttemptAt.After(f.now()) {
+			continue
+		}
+		entries = append(entries, *entry)
+		if len(entries) == limit {
+			break
+		}
+	}
+	return entries, nil
+}
+
+func (f *fakeControlFlagStorage) MarkControlFlagOutboxDelivered(_ context.Context, id int64) error {
+	if f.delivered == nil {
+		f.delivered = make(map[int64]bool)
+	}
+	f.delivered[id] = true
+	f.outbox[id-1].Attempts++
+	return nil
+}
+
+func (f *fakeControlFlagStorage) MarkControlFlagOutboxFailed(_ context.Context, id int64, lastError string, nextAttemptAt time.Time) error {
+	entry := f.outbox[id-1]
+	entry.Attempts++
+	entry.LastError = &lastError
+	entry.NextAttemptAt = nextAttemptAt
+	return nil
+}
+
+func (f *fakeControlFlagStorage) DeleteDeliveredControlFlagOutboxBefore(_ context.Context, _ time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (f *fakeControlFlagStorage) GetPluginIDs(_ context.Context) ([]types.PluginID, error) {
+	return f.plugins, nil
+}
+
+type fakeSafetySyncer struct {
+	err     error
+	plugins []types.PluginID
+	flags   []psafety.ControlFlag
+}
+
+func (f *fakeSafetySyncer) SyncSafetyToPlugin(_ context.Context, pluginID types.PluginID, flags []psafety.ControlFlag) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.plugins = append(f.plugins, pluginID)
+	f.flags = append(f.flags, flags...)
+	return nil
+}
+
+func newTestControlFlagService(t *testing.T, db *fakeControlFlagStorage, syncer SafetySyncer) *ControlFlagService {
+	svc, err := NewControlFlagService(db, syncer, logrus.New())
+	require.NoError(t, err)
+	if db.now == nil {
+		db.now = time.Now
+	}
+	svc.now = db.now
+	return svc
+}
+
+func TestControlFlagOutbox(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	db := &fakeControlFlagStorage{
+		plugins: []types.PluginID{"dca", "payroll"},
+		now:     func() time.Time { return now },
+	}
+	syncer := &fakeSafetySyncer{err: errors.New("plugin server down")}
+	svc := newTestControlFlagService(t, db, syncer)
+	key := psafety.KeysignFlagKey("dca")
+
+	_, err := NewControlFlagService(db, nil, logrus.New())
+	require.Error(t, err)
+
+	changed, err := svc.SetFlag(ctx, key, false, "admin", "drained a vault")
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Len(t, db.history, 1)
+	require.Equal(t, itypes.PauseActionManualPaused, db.history[0].Action)
+	require.Equal(t, "admin", *db.history[0].TriggeredBy)
+	require.Equal(t, "drained a vault", *db.history[0].Reason)
+	// the push failed and is retried later
+	require.Len(t, db.outbox, 1)
+	require.Equal(t, 1, db.outbox[0].Attempts)
+	require.Equal(t, now.Add(outboxBaseDelay), db.outbox[0].NextAttemptAt)
+
+	// nothing changes, nothing is recorded or pushed
+	changed, err = svc.SetFlag(ctx, key, false, "admin", "")
+	require.NoError(t, err)
+	require.False(t, changed)
+	require.Len(t, db.history, 1)
+	require.Len(t, db.outbox, 1)
+
+	changed, err = svc.SetFlag(ctx, key, true, "admin", "")
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Equal(t, itypes.PauseActionUnpaused, db.history[1].Action)
+	require.Nil(t, db.history[1].Reason)
+	require.Len(t, db.outbox, 2)
+
+	delivered, err := svc.DeliverPending(ctx)
+	require.NoError(t, err)
+	require.Zero(t, delivered)
+
+	// the late push of the pause sends the current value, it does not pause the plugin again
+	now = now.Add(outboxBaseDelay)
+	syncer.err = nil
+	delivered, err = svc.DeliverPending(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, delivered)
+	require.Equal(t, []psafety.ControlFlag{{Key: key, Enabled: true}, {Key: key, Enabled: true}}, syncer.flags)
+	delivered, err = svc.DeliverPending(ctx)
+	require.NoError(t, err)
+	require.Zero(t, delivered)
+
+	// global flags go to every plugin server, without a plugin pause history
+	syncer.flags = nil
+	_, err = svc.SetFlag(ctx, psafety.GlobalKeysignKey(), false, "admin", "")
+	require.NoError(t, err)
+	require.Equal(t, []types.PluginID{"dca", "dca", "dca", "payroll"}, syncer.plugins)
+	require.Len(t, db.history, 2)
+
+	// the other scopes are only enforced by the verifier
+	chainKey, err := psafety.ScopedKeysignKey(psafety.ScopeChain, "Ethereum")
+	require.NoError(t, err)
+	changed, err = svc.SetFlag(ctx, chainKey, false, "admin", "")
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Len(t, db.outbox, 4)
+
+	require.Equal(t, outboxBaseDelay, outboxBackoff(0))
+	require.Equal(t, 4*outboxBaseDelay, outboxBackoff(2))
+	require.Equal(t, outboxMaxDelay, outboxBackoff(20))
+}
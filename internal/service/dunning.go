@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5"
+	"github.com/sirupsen/logrus"
+
+	"github.com/vultisig/verifier/config"
+	itypes "github.com/vultisig/verifier/internal/types"
+	"github.com/vultisig/verifier/types"
+)
+
+type DunningServiceStorage interface {
+	WithTransaction(ctx context.Context, fn func(ctx context.Context, tx pgx.Tx) error) error
+	GetUnpaidAccounts(ctx context.Context, publicKey string, dueBefore time.Time) ([]itypes.UnpaidAccount, error)
+	GetDunningStates(ctx context.Context, publicKey string) ([]itypes.DunningState, error)
+	UpsertDunningState(ctx context.Context, dbTx pgx.Tx, state itypes.DunningState) error
+	DeleteDunningState(ctx context.Context, dbTx pgx.Tx, publicKey string) error
+	InsertDunningEvent(ctx context.Context, dbTx pgx.Tx, event itypes.DunningEvent) error
+	GetPluginPolicies(ctx context.Context, publicKey string, pluginIds []types.PluginID, includeInactive bool) ([]types.PluginPolicy, error)
+	GetPluginPolicy(ctx context.Context, id uuid.UUID) (*types.PluginPolicy, error)
+}
+
+// PolicyUpdater updates a policy and syncs it with the plugin server
+type PolicyUpdater interface {
+	UpdatePolicy(ctx context.Context, policy types.PluginPolicy) (*types.PluginPolicy, error)
+}
+
+// DunningService follows up on fees still unpaid after the grace period: it records reminder events,
+// and deactivates the paid plugin policies of the vault when the debt is over the suspend threshold.
+// The policies are reactivated once the balance is paid back.
+type DunningService struct {
+	db       DunningServiceStorage
+	policies PolicyUpdater
+	cfg      config.DunningConfig
+	logger   *logrus.Logger
+	now      func() time.Time
+}
+
+func NewDunningService(db DunningServiceStorage, policies PolicyUpdater, cfg config.DunningConfig, logger *logrus.Logger) (*DunningService, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database storage cannot be nil")
+	}
+	if policies == nil {
+		return nil, fmt.Errorf("policy updater cannot be nil")
+	}
+	return &DunningService{
+		db:       db,
+		policies: policies,
+		cfg:      cfg,
+		logger:   logger.WithField("service", "dunning").Logger,
+		now:      time.Now,
+	}, nil
+}
+
+// HandleDunning runs the dunning of every vault, or of a single one when the task payload is its public key
+func (s *DunningService) HandleDunning(ctx context.Context, task *asynq.Task) error {
+	return s.Run(ctx, string(task.Payload()))
+}
+
+// Run runs the dunning of every vault, of the one of publicKey when it isn't empty
+func (s *DunningService) Run(ctx context.Context, publicKey string) error {
+	// fees billed within the grace period may just not be collected yet
+	accounts, err := s.db.GetUnpaidAccounts(ctx, publicKey, s.now().Add(-s.cfg.GracePeriod))
+	if err != nil {
+		return fmt.Errorf("failed to get unpaid accounts: %w", err)
+	}
+	list, err := s.db.GetDunningStates(ctx, publicKey)
+	if err != nil {
+		return fmt.Errorf("failed to get dunning states: %w", err)
+	}
+	states := make(map[string]itypes.DunningState, len(list))
+	for _, state := range list {
+		states[state.PublicKey] = state
+	}
+
+	for _, account := range accounts {
+		state, ok := states[account.PublicKey]
+		delete(states, account.PublicKey)
+		if !ok {
+			state = itypes.DunningState{
+				PublicKey:    account.PublicKey,
+				Stage:        itypes.DunningStageGrace,
+				OverdueSince: s.now(),
+			}
+		}
+		if err := s.dun(ctx, account, state); err != nil {
+			return err
+		}
+	}
+
+	// the remaining vaults paid their balance back
+	for _, state := range states {
+		if err := s.settle(ctx, state); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *DunningService) dun(ctx context.Context, account itypes.UnpaidAccount, state itypes.DunningState) error {
+	now := s.now()
+	event := itypes.DunningEvent{
+		PublicKey:    account.PublicKey,
+		UnpaidAmount: account.Unpaid,
+	}
+	switch {
+	case state.Stage != itypes.DunningStageSuspended &&
+		s.cfg.SuspendThreshold > 0 && account.Unpaid > s.cfg.SuspendThreshold:
+		suspended, err := s.suspend(ctx, account.PublicKey)
+		if err != nil {
+			return err
+		}
+		s.logger.WithFields(logrus.Fields{
+			"public_key": account.PublicKey,
+			"unpaid":     account.Unpaid,
+			"policies":   suspended,
+		}).Info("Suspended paid plugin policies of unpaid balance")
+		event.EventType = itypes.DunningEventSuspended
+		state.Stage = itypes.DunningStageSuspended
+		state.SuspendedAt = &now
+		state.LastReminderAt = &now
+	case state.LastReminderAt == nil || now.Sub(*state.LastReminderAt) >= s.cfg.ReminderInterval:
+		event.EventType = itypes.DunningEventReminder
+		if state.Stage == itypes.DunningStageGrace {
+			state.Stage = itypes.DunningStageReminded
+		}
+		state.LastReminderAt = &now
+	default:
+		return nil
+	}
+
+	err := s.db.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		if err := s.db.UpsertDunningState(ctx, tx, state); err != nil {
+			return err
+		}
+		return s.db.InsertDunningEvent(ctx, tx, event)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record dunning %s of %s: %w", event.EventType, account.PublicKey, err)
+	}
+	return nil
+}
+
+// suspend deactivates the active policies of the vault billed by their plugin, the fee policy is kept
+func (s *DunningService) suspend(ctx context.Context, publicKey string) (int, error) {
+	policies, err := s.db.GetPluginPolicies(ctx, publicKey, nil, false)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get policies of %s: %w", publicKey, err)
+	}
+
+	var suspended int
+	for _, p := range policies {
+		if p.PluginID == types.PluginVultisigFees_feee {
+			continue
+		}
+		policy, err := s.db.GetPluginPolicy(ctx, p.ID)
+		if err != nil {
+			return suspended, fmt.Errorf("failed to get policy %s: %w", p.ID, err)
+		}
+		if !isPaid(policy) {
+			continue
+		}
+		policy.Deactivate(types.DeactivationReasonUnpaidFees)
+		if _, err := s.policies.UpdatePolicy(ctx, *policy); err != nil {
+			return suspended, fmt.Errorf("failed to deactivate policy %s: %w", policy.ID, err)
+		}
+		suspended++
+	}
+	return suspended, nil
+}
+
+func (s *DunningService) settle(ctx context.Context, state itypes.DunningState) error {
+	if state.Stage == itypes.DunningStageSuspended {
+		policies, err := s.db.GetPluginPolicies(ctx, state.PublicKey, nil, true)
+		if err != nil {
+			return fmt.Errorf("failed to get policies of %s: %w", state.PublicKey, err)
+		}
+		for _, policy := range policies {
+			if policy.Active || policy.DeactivationReason == nil ||
+				*policy.DeactivationReason != types.DeactivationReasonUnpaidFees {
+				continue
+			}
+			policy.Activate()
+			if _, err := s.policies.UpdatePolicy(ctx, policy); err != nil {
+				return fmt.Errorf("failed to reactivate policy %s: %w", policy.ID, err)
+			}
+		}
+		s.logger.WithField("public_key", state.PublicKey).Info("Reactivated policies of paid balance")
+	}
+
+	err := s.db.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		if err := s.db.DeleteDunningState(ctx, tx, state.PublicKey); err != nil {
+			return err
+		}
+		if state.Stage != itypes.DunningStageSuspended {
+			return nil
+		}
+		return s.db.InsertDunningEvent(ctx, tx, itypes.DunningEvent{
+			PublicKey: state.PublicKey,
+			EventType: itypes.DunningEventReactivated,
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to settle dunning of %s: %w", state.PublicKey, err)
+	}
+	return nil
+}
+
+func isPaid(policy *types.PluginPolicy) bool {
+	for _, billing := range policy.Billing {
+		if billing.Amount > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vultisig/verifier/config"
+	itypes "github.com/vultisig/verifier/internal/types"
+	"github.com/vultisig/verifier/types"
+)
+
+// fakeDebt is the unpaid amount of a vault billed at once
+type fakeDebt struct {
+	amount   uint64
+	billedAt time.Time
+}
+
+type fakeDunningStorage struct {
+	unpaid   map[string]fakeDebt
+	states   map[string]itypes.DunningState
+	events   []itypes.DunningEvent
+	policies map[uuid.UUID]*types.PluginPolicy
+}
+
+func (f *fakeDunningStorage) WithTransaction(ctx context.Context, fn func(ctx context.Context, tx pgx.Tx) error) error {
+	return fn(ctx, nil)
+}
+
+func (f *fakeDunningStorage) GetUnpaidAccounts(_ context.Context, publicKey string, dueBefore time.Time) ([]itypes.UnpaidAccount, error) {
+	var accounts []itypes.UnpaidAccount
+	for key, debt := range f.unpaid {
+		if (publicKey == "" || key == publicKey) && debt.billedAt.Before(dueBefore) {
+			accounts = append(accounts, itypes.UnpaidAccount{PublicKey: key, Unpaid: debt.amount})
+		}
+	}
+	return accounts, nil
+}
+
+func (f *fakeDunningStorage) GetDunningStates(_ context.Context, publicKey string) ([]itypes.DunningState, error) {
+	var states []itypes.DunningState
+	for key, state := range f.states {
+		if publicKey == "" || key == publicKey {
+			states = append(states, state)
+		}
+	}
+	return states, nil
+}
+
+func (f *fakeDunningStorage) UpsertDunningState(_ context.Context, _ pgx.Tx, state itypes.DunningState) error {
+	f.states[state.PublicKey] = state
+	return nil
+}
+
+func (f *fakeDunningStorage) DeleteDunningState(_ context.Context, _ pgx.Tx, publicKey string) error {
+	delete(f.states, publicKey)
+	return nil
+}
+
+func (f *fakeDunningStorage) InsertDunningEvent(_ context.Context, _ pgx.Tx, event itypes.DunningEvent) error {
+	f.events = append(f.events, event)
+	return nil
+}
+
+func (f *fakeDunningStorage) GetPluginPolicies(_ context.Context, publicKey string, _ []types.PluginID, includeInactive bool) ([]types.PluginPolicy, error) {
+	var policies []types.PluginPolicy
+	for _, policy := range f.policies {
+		if policy.PublicKey == publicKey && (includeInactive || policy.Active) {
+			policies = append(policies, *policy)
+		}
+	}
+	return policies, nil
+}
+
+func (f *fakeDunningStorage) GetPluginPolicy(_ context.Context, id uuid.UUID) (*types.PluginPolicy, error) {
+	policy := *f.policies[id]
+	return &policy, nil
+}
+
+func (f *fakeDunningStorage) UpdatePolicy(_ context.Context, policy types.PluginPolicy) (*types.PluginPolicy, error) {
+	f.policies[policy.ID] = &policy
+	return &policy, nil
+}
+
+func (f *fakeDunningStorage) addPolicy(publicKey string, pluginID types.PluginID, amount uint64) uuid.UUID {
+	policy := &types.PluginPolicy{
+		ID:        uuid.New(),
+		PublicKey: publicKey,
+		PluginID:  pluginID,
+		Active:    true,
+		Billing:   []types.BillingPolicy{{Type: types.PricingTypeRecurring, Amount: amount}},
+	}
+	f.policies[policy.ID] = policy
+	return policy.ID
+}
+
+func TestDunning(t *testing.T) {
+	start := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+	db := &fakeDunningStorage{
+		unpaid: map[string]fakeDebt{
+			"indebted": {amount: 20_000_000, billedAt: start},
+			"late":     {amount: 1_000_000, billedAt: start},
+			// billed but not collected yet
+			"active": {amount: 20_000_000, billedAt: start.Add(72 * time.Hour)},
+		},
+		states:   make(map[string]itypes.DunningState),
+		policies: make(map[uuid.UUID]*types.PluginPolicy),
+	}
+	feePolicy := db.addPolicy("indebted", types.PluginVultisigFees_feee, 0)
+	paidPolicy := db.addPolicy("indebted", "dca", 1_000_000)
+	freePolicy := db.addPolicy("indebted", "free", 0)
+	activePolicy := db.addPolicy("active", "dca", 1_000_000)
+
+	now := start
+	svc, err := NewDunningService(db, db, config.DunningConfig{
+		GracePeriod:      72 * time.Hour,
+		ReminderInterval: 24 * time.Hour,
+		SuspendThreshold: 10_000_000,
+	}, logrus.New())
+	require.NoError(t, err)
+	svc.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	require.NoError(t, svc.Run(ctx, ""))
+	require.Empty(t, db.states)
+	require.Empty(t, db.events)
+
+	now = start.Add(73 * time.Hour)
+	require.NoError(t, svc.Run(ctx, ""))
+	require.Equal(t, itypes.DunningStageSuspended, db.states["indebted"].Stage)
+	require.Equal(t, itypes.DunningStageReminded, db.states["late"].Stage)
+	require.Len(t, db.events, 2)
+	require.False(t, db.policies[paidPolicy].Active)
+	require.Equal(t, types.DeactivationReasonUnpaidFees, *db.policies[paidPolicy].DeactivationReason)
+	require.True(t, db.policies[feePolicy].Active)
+	require.True(t, db.policies[freePolicy].Active)
+	require.NotContains(t, db.states, "active")
+	require.True(t, db.policies[activePolicy].Active)
+
+	// reminders are spaced by the reminder interval
+	now = start.Add(80 * time.Hour)
+	require.NoError(t, svc.Run(ctx, ""))
+	require.Len(t, db.events, 2)
+	now = start.Add(98 * time.Hour)
+	require.NoError(t, svc.Run(ctx, ""))
+	require.Len(t, db.events, 4)
+
+	// paid back, the suspended policies are reactivated
+	delete(db.unpaid, "indebted")
+	require.NoError(t, svc.Run(ctx, "indebted"))
+	require.NotContains(t, db.states, "indebted")
+	require.Contains(t, db.states, "late")
+	require.True(t, db.policies[paidPolicy].Active)
+	require.Nil(t, db.policies[paidPolicy].DeactivationReason)
+	require.Equal(t, itypes.DunningEventReactivated, db.events[len(db.events)-1].EventType)
+}
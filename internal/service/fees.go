@@ -11,6 +11,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/vultisig/verifier/config"
 	"github.com/vultisig/verifier/internal/storage"
+	"github.com/vultisig/verifier/plugin/tasks"
 	vtypes "github.com/vultisig/verifier/types"
 	"github.com/vultisig/vultisig-go/common"
 )
@@ -18,7 +19,7 @@ import (
 type Fees interface {
 	PublicKeyGetFeeInfo(ctx context.Context, publicKey string) ([]*vtypes.Fee, error)
 	MarkFeesCollected(ctx context.Context, feeIDs []uint64, network, txHash string, amount uint64) error
-	IssueCredit(ctx context.Context, publicKey string, amount uint64, reason string) error
+	IssueCredit(ctx context.Context, publicKey string, amount uint64, asset vtypes.PricingAsset, reason string) error
 	GetUserFees(ctx context.Context, publicKey string) (*vtypes.UserFeeStatus, error)
 }
 
@@ -49,11 +50,26 @@ func (s *FeeService) PublicKeyGetFeeInfo(ctx context.Context, publicKey string)
 }
 
 func (s *FeeService) MarkFeesCollected(ctx context.Context, feeIDs []uint64, network, txHash string, amount uint64) error {
-	//TODO: add handling for fees in different networks
-	_, err := common.FromString(network)
+	chain, err := common.FromString(network)
 	if err != nil {
 		return fmt.Errorf("invalid network: %w", err)
 	}
+	if len(feeIDs) == 0 {
+		return fmt.Errorf("no fees to collect")
+	}
+
+	// storage checks every fee is billed in the same asset, its chain must be the collection network
+	fee, err := s.db.GetFeeById(ctx, feeIDs[0])
+	if err != nil {
+		return fmt.Errorf("failed to get fee %d: %w", feeIDs[0], err)
+	}
+	asset, err := vtypes.GetFeeAsset(fee.Asset)
+	if err != nil {
+		return err
+	}
+	if asset.Chain != chain {
+		return fmt.Errorf("fees in %s can't be collected on %s", asset.ID, chain.String())
+	}
 
 	err = s.db.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
 		return s.db.MarkFeesCollected(ctx, tx, feeIDs, txHash, amount)
@@ -61,11 +77,30 @@ func (s *FeeService) MarkFeesCollected(ctx context.Context, feeIDs []uint64, net
 	if err != nil {
 		return err
 	}
+
+	// reactivates the policies suspended by dunning once the balance is paid back
+	if s.client != nil {
+		_, err = s.client.EnqueueContext(ctx,
+			asynq.NewTask(tasks.TypeFeeDunning, []byte(fee.PublicKey)),
+			asynq.MaxRetry(3),
+			asynq.Queue(tasks.QUEUE_NAME))
+		if err != nil {
+			s.logger.WithError(err).WithField("public_key", fee.PublicKey).Warn("Failed to enqueue dunning task")
+		}
+	}
 	return nil
 }
 
 // IssueCredit for promo, bonuses, etc.
-func (s *FeeService) IssueCredit(ctx context.Context, publicKey string, amount uint64, reason string) error {
+// The asset defaults to usdc.
+func (s *FeeService) IssueCredit(ctx context.Context, publicKey string, amount uint64, asset vtypes.PricingAsset, reason string) error {
+	if asset == "" {
+		asset = vtypes.PricingAssetUSDC
+	}
+	if !asset.IsValid() {
+		return fmt.Errorf("unsupported asset: %s", asset)
+	}
+
 	metadata := map[string]interface{}{
 		"reason":    reason,
 		"issued_at": time.Now().UTC(),
@@ -79,6 +114,7 @@ func (s *FeeService) IssueCredit(ctx context.Context, publicKey string, amount u
 		PublicKey:      publicKey,
 		TxType:         vtypes.TxTypeCredit,
 		Amount:         amount,
+		Asset:          asset,
 		FeeType:        "free_credit",
 		Metadata:       metadataJSON,
 		UnderlyingType: reason,
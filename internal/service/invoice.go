@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/sirupsen/logrus"
+
+	itypes "github.com/vultisig/verifier/internal/types"
+)
+
+// InvoicePeriodLayout is the format of the optional monthly invoices task payload, e.g. "2026-09"
+const InvoicePeriodLayout = "2006-01"
+
+type InvoiceServiceStorage interface {
+	GetInvoicePublicKeys(ctx context.Context, start, end time.Time) ([]string, error)
+	GetInvoiceData(ctx context.Context, publicKey string, start, end time.Time) (itypes.InvoiceData, error)
+	InsertInvoice(ctx context.Context, invoice *itypes.Invoice) (bool, error)
+	GetPluginTitlesByIDs(ctx context.Context, ids []string) (map[string]string, error)
+}
+
+type InvoiceService struct {
+	db     InvoiceServiceStorage
+	logger *logrus.Logger
+	now    func() time.Time
+}
+
+func NewInvoiceService(db InvoiceServiceStorage, logger *logrus.Logger) (*InvoiceService, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database storage cannot be nil")
+	}
+	return &InvoiceService{
+		db:     db,
+		logger: logger.WithField("service", "invoice").Logger,
+		now:    time.Now,
+	}, nil
+}
+
+// HandleMonthlyInvoices issues the invoices of the previous month, or of the month in the payload.
+// Invoices already issued for the period are kept as they are, so the task can be retried.
+func (s *InvoiceService) HandleMonthlyInvoices(ctx context.Context, task *asynq.Task) error {
+	start, _ := itypes.InvoicePeriod(s.now())
+	start = start.AddDate(0, -1, 0)
+	if payload := string(task.Payload()); payload != "" {
+		var err error
+		start, err = time.Parse(InvoicePeriodLayout, payload)
+		if err != nil {
+			s.logger.WithError(err).WithField("payload", payload).Error("Invalid period in invoices task")
+			return fmt.Errorf("invalid invoice period %q: %v: %w", payload, err, asynq.SkipRetry)
+		}
+	}
+
+	issued, err := s.GenerateInvoices(ctx, start)
+	if err != nil {
+		return err
+	}
+	s.logger.WithFields(logrus.Fields{
+		"period": start.Format(InvoicePeriodLayout),
+		"issued": issued,
+	}).Info("Monthly invoices generated")
+	return nil
+}
+
+// GenerateInvoices issues an invoice for every vault billed in the month containing at, it returns the number issued
+func (s *InvoiceService) GenerateInvoices(ctx context.Context, at time.Time) (int, error) {
+	start, end := itypes.InvoicePeriod(at)
+	if !end.Before(s.now()) {
+		return 0, fmt.Errorf("invoice period %s is not over", start.Format(InvoicePeriodLayout))
+	}
+
+	publicKeys, err := s.db.GetInvoicePublicKeys(ctx, start, end)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get invoice public keys: %w", err)
+	}
+
+	issued := 0
+	for _, publicKey := range publicKeys {
+		ok, err := s.generateInvoice(ctx, publicKey, start, end)
+		if err != nil {
+			return issued, fmt.Errorf("failed to generate invoice of %s: %w", publicKey, err)
+		}
+		if ok {
+			issued++
+		}
+	}
+	return issued, nil
+}
+
+func (s *InvoiceService) generateInvoice(ctx context.Context, publicKey string, start, end time.Time) (bool, error) {
+	data, err := s.db.GetInvoiceData(ctx, publicKey, start, end)
+	if err != nil {
+		return false, err
+	}
+	if data.IsEmpty() {
+		return false, nil
+	}
+
+	// plugin titles are kept with the invoice, it reads the same after a plugin is renamed
+	var pluginIDs []string
+	for _, item := range data.LineItems {
+		if item.PluginID != "" {
+			pluginIDs = append(pluginIDs, item.PluginID)
+		}
+	}
+	if len(pluginIDs) > 0 {
+		titles, err := s.db.GetPluginTitlesByIDs(ctx, pluginIDs)
+		if err != nil {
+			return false, fmt.Errorf("failed to get plugin titles: %w", err)
+		}
+		for i := range data.LineItems {
+			data.LineItems[i].AppName = titles[data.LineItems[i].PluginID]
+		}
+	}
+
+	invoice := &itypes.Invoice{
+		PublicKey:   publicKey,
+		PeriodStart: start,
+		PeriodEnd:   end,
+		InvoiceData: data,
+	}
+	return s.db.InsertInvoice(ctx, invoice)
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	itypes "github.com/vultisig/verifier/internal/types"
+	"github.com/vultisig/verifier/plugin/tasks"
+	vtypes "github.com/vultisig/verifier/types"
+)
+
+type fakeInvoiceStorage struct {
+	data     map[string]itypes.InvoiceData
+	invoices map[string]*itypes.Invoice
+	start    time.Time
+}
+
+func (f *fakeInvoiceStorage) GetInvoicePublicKeys(_ context.Context, start, _ time.Time) ([]string, error) {
+	f.start = start
+	var keys []string
+	for key := range f.data {
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func (f *fakeInvoiceStorage) GetInvoiceData(_ context.Context, publicKey string, _, _ time.Time) (itypes.InvoiceData, error) {
+	data := f.data[publicKey]
+	data.ComputeTotals()
+	return data, nil
+}
+
+func (f *fakeInvoiceStorage) InsertInvoice(_ context.Context, invoice *itypes.Invoice) (bool, error) {
+	key := invoice.PublicKey + invoice.PeriodStart.String()
+	if _, ok := f.invoices[key]; ok {
+		return false, nil
+	}
+	f.invoices[key] = invoice
+	return true, nil
+}
+
+func (f *fakeInvoiceStorage) GetPluginTitlesByIDs(_ context.Context, ids []string) (map[string]string, error) {
+	titles := make(map[string]string)
+	for _, id := range ids {
+		titles[id] = "Title of " + id
+	}
+	return titles, nil
+}
+
+func TestHandleMonthlyInvoices(t *testing.T) {
+	db := &fakeInvoiceStorage{
+		data: map[string]itypes.InvoiceData{
+			"02billed": {
+				LineItems: []itypes.InvoiceLineItem{
+					{PluginID: "dca", FeeType: vtypes.FeeTxExecFee, Asset: vtypes.PricingAssetUSDC, Count: 2, Amount: 200},
+					{PluginID: "dca", FeeType: vtypes.FeeTxExecFee, Asset: vtypes.PricingAssetUSDT, Count: 1, Amount: 50},
+				},
+				Batches: []itypes.InvoiceBatch{
+					{BatchID: 1, Asset: vtypes.PricingAssetUSDC, Amount: 150, Status: "COMPLETED"},
+					{BatchID: 2, Asset: vtypes.PricingAssetUSDC, Amount: 50, Status: "FAILED"},
+				},
+			},
+			"02empty": {},
+		},
+		invoices: make(map[string]*itypes.Invoice),
+	}
+	svc, err := NewInvoiceService(db, logrus.New())
+	require.NoError(t, err)
+	svc.now = func() time.Time {
+		return time.Date(2026, time.October, 1, 1, 0, 0, 0, time.UTC)
+	}
+
+	err = svc.HandleMonthlyInvoices(context.Background(), asynq.NewTask(tasks.TypeMonthlyInvoices, nil))
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC), db.start)
+	require.Len(t, db.invoices, 1)
+
+	var invoice *itypes.Invoice
+	for _, inv := range db.invoices {
+		invoice = inv
+	}
+	require.Equal(t, "02billed", invoice.PublicKey)
+	require.Equal(t, time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC), invoice.PeriodEnd)
+	require.Equal(t, "Title of dca", invoice.LineItems[0].AppName)
+	require.Equal(t, []itypes.InvoiceTotal{
+		{
+			Asset:     vtypes.PricingAssetUSDC,
+			FeeAsset:  itypes.FeeAssetOf(vtypes.PricingAssetUSDC),
+			Charged:   200,
+			Collected: 150,
+		},
+		{
+			Asset:    vtypes.PricingAssetUSDT,
+			FeeAsset: itypes.FeeAssetOf(vtypes.PricingAssetUSDT),
+			Charged:  50,
+		},
+	}, invoice.Totals)
+
+	// retries keep the issued invoices
+	issued, err := svc.GenerateInvoices(context.Background(), invoice.PeriodStart)
+	require.NoError(t, err)
+	require.Zero(t, issued)
+
+	err = svc.HandleMonthlyInvoices(context.Background(), asynq.NewTask(tasks.TypeMonthlyInvoices, []byte("2026-10")))
+	require.Error(t, err)
+	err = svc.HandleMonthlyInvoices(context.Background(), asynq.NewTask(tasks.TypeMonthlyInvoices, []byte("september")))
+	require.ErrorIs(t, err, asynq.SkipRetry)
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hibiken/asynq"
+	"github.com/sirupsen/logrus"
+)
+
+type KeysignResultServiceStorage interface {
+	DeleteExpiredKeysignResults(ctx context.Context) (int64, error)
+}
+
+// KeysignResultService purges the keysign results plugins can no longer fetch
+type KeysignResultService struct {
+	db     KeysignResultServiceStorage
+	logger *logrus.Logger
+}
+
+func NewKeysignResultService(db KeysignResultServiceStorage, logger *logrus.Logger) (*KeysignResultService, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database storage cannot be nil")
+	}
+	return &KeysignResultService{
+		db:     db,
+		logger: logger.WithField("service", "keysign-result").Logger,
+	}, nil
+}
+
+// HandleKeysignResultPurge deletes the keysign results past their expiry
+func (s *KeysignResultService) HandleKeysignResultPurge(ctx context.Context, _ *asynq.Task) error {
+	deleted, err := s.db.DeleteExpiredKeysignResults(ctx)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to delete expired keysign results")
+		return err
+	}
+	if deleted > 0 {
+		s.logger.WithField("deleted", deleted).Info("Deleted expired keysign results")
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5"
+	"github.com/sirupsen/logrus"
+
+	"github.com/vultisig/verifier/config"
+	itypes "github.com/vultisig/verifier/internal/types"
+	vtypes "github.com/vultisig/verifier/types"
+)
+
+// maxRevenueShareBps is the whole of the collected fees, in basis points
+const maxRevenueShareBps = 10_000
+
+type PayoutServiceStorage interface {
+	WithTransaction(ctx context.Context, fn func(ctx context.Context, tx pgx.Tx) error) error
+	GetPayableFees(ctx context.Context, periodEnd time.Time) ([]itypes.PayableFee, error)
+	InsertDeveloperPayouts(ctx context.Context, dbTx pgx.Tx, payouts []*itypes.DeveloperPayout) error
+}
+
+// PayoutService aggregates the fees collected for each plugin into payouts to the developer payout address.
+// The fee plugin signs them from the treasury vault and the fee indexer tracks them to completion.
+type PayoutService struct {
+	db     PayoutServiceStorage
+	cfg    config.PayoutsConfig
+	logger *logrus.Logger
+	now    func() time.Time
+}
+
+func NewPayoutService(db PayoutServiceStorage, cfg config.PayoutsConfig, logger *logrus.Logger) (*PayoutService, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database storage cannot be nil")
+	}
+	if cfg.RevenueShareBps > maxRevenueShareBps {
+		return nil, fmt.Errorf("revenue share of %d bps exceeds %d", cfg.RevenueShareBps, maxRevenueShareBps)
+	}
+	return &PayoutService{
+		db:     db,
+		cfg:    cfg,
+		logger: logger.WithField("service", "payouts").Logger,
+		now:    time.Now,
+	}, nil
+}
+
+// HandleDeveloperPayouts creates the payouts of the fees collected until the start of the current month (UTC)
+func (s *PayoutService) HandleDeveloperPayouts(ctx context.Context, _ *asynq.Task) error {
+	now := s.now().UTC()
+	periodEnd := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	payouts, err := s.CreatePayouts(ctx, periodEnd)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to create developer payouts")
+		return err
+	}
+	s.logger.WithFields(logrus.Fields{
+		"period_end": periodEnd,
+		"payouts":    len(payouts),
+	}).Info("Developer payouts created")
+	return nil
+}
+
+// CreatePayouts creates a payout per plugin and asset of the fees collected before periodEnd.
+// Fees of plugins without payout address, or adding up to less than the minimum amount, are carried over.
+func (s *PayoutService) CreatePayouts(ctx context.Context, periodEnd time.Time) ([]*itypes.DeveloperPayout, error) {
+	fees, err := s.db.GetPayableFees(ctx, periodEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	payouts := s.aggregate(fees, periodEnd)
+	if len(payouts) == 0 {
+		return nil, nil
+	}
+	err = s.db.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		return s.db.InsertDeveloperPayouts(ctx, tx, payouts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return payouts, nil
+}
+
+func (s *PayoutService) aggregate(fees []itypes.PayableFee, periodEnd time.Time) []*itypes.DeveloperPayout {
+	type key struct {
+		pluginID vtypes.PluginID
+		asset    vtypes.PricingAsset
+	}
+	type balance struct {
+		payoutAddress string
+		gross         int64
+		feeIDs        []uint64
+	}
+	balances := make(map[key]*balance)
+	for _, fee := range fees {
+		k := key{pluginID: fee.PluginID, asset: fee.Asset}
+		b, ok := balances[k]
+		if !ok {
+			b = &balance{payoutAddress: fee.PayoutAddress}
+			balances[k] = b
+		}
+		b.gross += fee.Amount
+		b.feeIDs = append(b.feeIDs, fee.FeeID)
+	}
+
+	var payouts []*itypes.DeveloperPayout
+	for k, b := range balances {
+		logger := s.logger.WithFields(logrus.Fields{
+			"plugin_id": k.pluginID,
+			"asset":     k.asset,
+			"gross":     b.gross,
+		})
+		if b.gross <= 0 {
+			continue
+		}
+		if b.payoutAddress == "" {
+			logger.Warn("Plugin has no payout address, carrying its fees over")
+			continue
+		}
+
+		gross := uint64(b.gross)
+		share := revenueShare(gross, s.cfg.RevenueShareBps)
+		amount := gross - share
+		if amount == 0 || amount < s.cfg.MinAmount {
+			logger.Info("Payout under the minimum amount, carrying its fees over")
+			continue
+		}
+		payouts = append(payouts, &itypes.DeveloperPayout{
+			PluginID:      k.pluginID,
+			PayoutAddress: b.payoutAddress,
+			Asset:         k.asset,
+			PeriodEnd:     periodEnd,
+			GrossAmount:   gross,
+			PlatformShare: share,
+			Amount:        amount,
+			Status:        itypes.PayoutPending,
+			FeeIDs:        b.feeIDs,
+		})
+	}
+	sort.Slice(payouts, func(i, j int) bool {
+		if payouts[i].PluginID != payouts[j].PluginID {
+			return payouts[i].PluginID < payouts[j].PluginID
+		}
+		return payouts[i].Asset < payouts[j].Asset
+	})
+	return payouts
+}
+
+// revenueShare is the floor of bps basis points of amount, without overflowing
+func revenueShare(amount, bps uint64) uint64 {
+	return amount/maxRevenueShareBps*bps + amount%maxRevenueShareBps*bps/maxRevenueShareBps
+}
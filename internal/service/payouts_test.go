@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vultisig/verifier/config"
+	itypes "github.com/vultisig/verifier/internal/types"
+	"github.com/vultisig/verifier/types"
+)
+
+type fakePayoutStorage struct {
+	fees    []itypes.PayableFee
+	periods []time.Time
+	payouts []*itypes.DeveloperPayout
+}
+
+func (f *fakePayoutStorage) WithTransaction(ctx context.Context, fn func(ctx context.Context, tx pgx.Tx) error) error {
+	return fn(ctx, nil)
+}
+
+func (f *fakePayoutStorage) GetPayableFees(_ context.Context, periodEnd time.Time) ([]itypes.PayableFee, error) {
+	f.periods = append(f.periods, periodEnd)
+	return f.fees, nil
+}
+
+func (f *fakePayoutStorage) InsertDeveloperPayouts(_ context.Context, _ pgx.Tx, payouts []*itypes.DeveloperPayout) error {
+	for _, payout := range payouts {
+		payout.ID = uint64(len(f.payouts) + 1)
+		f.payouts = append(f.payouts, payout)
+	}
+	return nil
+}
+
+func TestDeveloperPayouts(t *testing.T) {
+	const address = "0x1111111111111111111111111111111111111111"
+	db := &fakePayoutStorage{
+		fees: []itypes.PayableFee{
+			{FeeID: 1, PluginID: "dca", PayoutAddress: address, Asset: types.PricingAssetUSDC, Amount: 30_000_000},
+			{FeeID: 2, PluginID: "dca", PayoutAddress: address, Asset: types.PricingAssetUSDC, Amount: 20_000_001},
+			// refund of a collected fee
+			{FeeID: 3, PluginID: "dca", PayoutAddress: address, Asset: types.PricingAssetUSDC, Amount: -5_000_000},
+			// under the minimum amount
+			{FeeID: 4, PluginID: "dca", PayoutAddress: address, Asset: types.PricingAssetUSDT, Amount: 1_000_000},
+			// no payout address
+			{FeeID: 5, PluginID: "payroll", Asset: types.PricingAssetUSDC, Amount: 50_000_000},
+			// refunded more than collected in the period
+			{FeeID: 6, PluginID: "sends", PayoutAddress: address, Asset: types.PricingAssetUSDC, Amount: 10_000_000},
+			{FeeID: 7, PluginID: "sends", PayoutAddress: address, Asset: types.PricingAssetUSDC, Amount: -20_000_000},
+		},
+	}
+
+	_, err := NewPayoutService(db, config.PayoutsConfig{RevenueShareBps: 10_001}, logrus.New())
+	require.Error(t, err)
+
+	svc, err := NewPayoutService(db, config.PayoutsConfig{
+		RevenueShareBps: 2000,
+		MinAmount:       10_000_000,
+	}, logrus.New())
+	require.NoError(t, err)
+	svc.now = func() time.Time { return time.Date(2026, time.October, 1, 4, 0, 0, 0, time.UTC) }
+
+	require.NoError(t, svc.HandleDeveloperPayouts(context.Background(), nil))
+	require.Equal(t, []time.Time{time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)}, db.periods)
+
+	require.Len(t, db.payouts, 1)
+	payout := db.payouts[0]
+	require.Equal(t, types.PluginID("dca"), payout.PluginID)
+	require.Equal(t, address, payout.PayoutAddress)
+	require.Equal(t, types.PricingAssetUSDC, payout.Asset)
+	require.Equal(t, uint64(45_000_001), payout.GrossAmount)
+	// the platform share is rounded down
+	require.Equal(t, uint64(9_000_000), payout.PlatformShare)
+	require.Equal(t, uint64(36_000_001), payout.Amount)
+	require.Equal(t, itypes.PayoutPending, payout.Status)
+	require.Equal(t, []uint64{1, 2, 3}, payout.FeeIDs)
+}
+
+func TestRevenueShare(t *testing.T) {
+	require.Equal(t, uint64(0), revenueShare(4, 2000))
+	require.Equal(t, uint64(1), revenueShare(5, 2000))
+	require.Equal(t, uint64(0), revenueShare(1_000_000, 0))
+	require.Equal(t, uint64(1_000_000), revenueShare(1_000_000, 10_000))
+	// no overflow on large amounts
+	require.Equal(t, uint64(1<<63)/5, revenueShare(1<<63, 2000))
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/sirupsen/logrus"
 	rtypes "github.com/vultisig/recipes/types"
 	"github.com/vultisig/verifier/plugin/libhttp"
+	"github.com/vultisig/verifier/plugin/reqsign"
 	"google.golang.org/protobuf/encoding/protojson"
 
 	"github.com/vultisig/verifier/internal/storage"
@@ -57,23 +58,30 @@ type PluginServiceStorage interface {
 	FindReviewByUserAndPlugin(ctx context.Context, dbTx pgx.Tx, pluginId string, userAddress string) (*types.ReviewDto, error)
 
 	FindPluginById(ctx context.Context, dbTx pgx.Tx, id ptypes.PluginID) (*types.Plugin, error)
-	GetAPIKeyByPluginId(ctx context.Context, pluginId string) (*types.APIKey, error)
 	GetPluginTitlesByIDs(ctx context.Context, ids []string) (map[string]string, error)
 }
 
 type PluginService struct {
 	db     PluginServiceStorage
 	redis  *storage.RedisStorage
+	signer *reqsign.Signer
 	logger *logrus.Logger
+	// legacyTokens are the bearer tokens of the plugin servers that don't verify the signature yet, by plugin ID
+	legacyTokens map[string]string
 }
 
-func NewPluginService(db PluginServiceStorage, redis *storage.RedisStorage, logger *logrus.Logger) (*PluginService, error) {
+// NewPluginService signs the requests to the plugin servers with signer
+func NewPluginService(db PluginServiceStorage, redis *storage.RedisStorage, signer *reqsign.Signer, logger *logrus.Logger) (*PluginService, error) {
 	if db == nil {
 		return nil, fmt.Errorf("database storage cannot be nil")
 	}
+	if signer == nil {
+		return nil, fmt.Errorf("signer cannot be nil")
+	}
 	return &PluginService{
 		db:     db,
 		redis:  redis,
+		signer: signer,
 		logger: logger,
 	}, nil
 }
@@ -191,6 +199,18 @@ func (s *PluginService) CreatePluginReviewWithRating(ctx context.Context, review
 }
 
 // GetPluginRecipeSpecification fetches recipe specification from plugin server with caching
+// SetLegacyBearerTokens sends the bearer token of a plugin next to the signature,
+// for the plugin servers deployed before they verified the verifier signature
+func (s *PluginService) SetLegacyBearerTokens(tokens map[string]string) {
+	s.legacyTokens = tokens
+}
+
+func (s *PluginService) authorizeLegacy(req *http.Request, pluginID string) {
+	if token, ok := s.legacyTokens[pluginID]; ok {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+}
+
 func (s *PluginService) GetPluginRecipeSpecification(ctx context.Context, pluginID string) (*rtypes.RecipeSchema, error) {
 	// Check cache first
 	cacheKey := fmt.Sprintf("recipe_spec:%s", pluginID)
@@ -211,13 +231,8 @@ func (s *PluginService) GetPluginRecipeSpecification(ctx context.Context, plugin
 	if err != nil {
 		return nil, fmt.Errorf("failed to find plugin: %w", err)
 	}
-	keyInfo, err := s.db.GetAPIKeyByPluginId(ctx, pluginID)
-	if err != nil || keyInfo == nil {
-		return nil, fmt.Errorf("failed to find plugin server info: %w", err)
-	}
-
 	// Call plugin server endpoint
-	recipeSpec, err := s.fetchRecipeSpecificationFromPlugin(ctx, plugin.ServerEndpoint, keyInfo.ApiKey)
+	recipeSpec, err := s.fetchRecipeSpecificationFromPlugin(ctx, pluginID, plugin.ServerEndpoint)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch recipe specification from plugin: %w", err)
 	}
@@ -250,23 +265,22 @@ func (s *PluginService) GetPluginRecipeSpecificationSuggest(
 	if err != nil {
 		return nil, fmt.Errorf("failed to find plugin: %w", err)
 	}
-	keyInfo, err := s.db.GetAPIKeyByPluginId(ctx, pluginID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to find plugin server info: %w", err)
-	}
-
 	type req struct {
 		Configuration map[string]any `json:"configuration"`
 	}
 
-	policySuggestStr, err := libhttp.Call[string](
+	headers := map[string]string{
+		"Content-Type": "application/json",
+	}
+	if token, ok := s.legacyTokens[pluginID]; ok {
+		headers["Authorization"] = "Bearer " + token
+	}
+	policySuggestStr, err := libhttp.CallSigned[string](
 		ctx,
+		s.signer,
 		http.MethodPost,
 		plugin.ServerEndpoint+"/plugin/recipe-specification/suggest",
-		map[string]string{
-			"Content-Type":  "application/json",
-			"Authorization": "Bearer " + keyInfo.ApiKey,
-		},
+		headers,
 		req{
 			Configuration: configuration,
 		},
@@ -351,7 +365,7 @@ func (s *PluginService) GetPluginRecipeFunctions(ctx context.Context, pluginID s
 }
 
 // Helper method to call plugin server
-func (s *PluginService) fetchRecipeSpecificationFromPlugin(ctx context.Context, serverEndpoint, token string) (*rtypes.RecipeSchema, error) {
+func (s *PluginService) fetchRecipeSpecificationFromPlugin(ctx context.Context, pluginID, serverEndpoint string) (*rtypes.RecipeSchema, error) {
 	url := fmt.Sprintf("%s/plugin/recipe-specification", strings.TrimSuffix(serverEndpoint, "/"))
 
 	s.logger.Debugf("[fetchRecipeSpecificationFromPlugin] Calling plugin endpoint: %s\n", url)
@@ -360,7 +374,8 @@ func (s *PluginService) fetchRecipeSpecificationFromPlugin(ctx context.Context,
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
-	req.Header.Add("Authorization", "Bearer "+token)
+	s.signer.Sign(req, nil)
+	s.authorizeLegacy(req, pluginID)
 
 	client := &http.Client{Timeout: 30 * time.Second}
 	resp, err := client.Do(req)
@@ -405,13 +420,8 @@ func (s *PluginService) GetPluginSkills(ctx context.Context, pluginID string) (*
 	if err != nil {
 		return nil, fmt.Errorf("failed to find plugin: %w", err)
 	}
-	keyInfo, err := s.db.GetAPIKeyByPluginId(ctx, pluginID)
-	if err != nil || keyInfo == nil {
-		return nil, fmt.Errorf("failed to find plugin server info: %w", err)
-	}
-
 	// Call plugin server endpoint
-	skills, err := s.fetchSkillsFromPlugin(ctx, plugin.ServerEndpoint, keyInfo.ApiKey)
+	skills, err := s.fetchSkillsFromPlugin(ctx, pluginID, plugin.ServerEndpoint)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch skills from plugin: %w", err)
 	}
@@ -434,7 +444,7 @@ func (s *PluginService) GetPluginSkills(ctx context.Context, pluginID string) (*
 	return skills, nil
 }
 
-func (s *PluginService) fetchSkillsFromPlugin(ctx context.Context, serverEndpoint, token string) (*PluginSkills, error) {
+func (s *PluginService) fetchSkillsFromPlugin(ctx context.Context, pluginID, serverEndpoint string) (*PluginSkills, error) {
 	url := fmt.Sprintf("%s/skills", strings.TrimSuffix(serverEndpoint, "/"))
 
 	s.logger.Debugf("[fetchSkillsFromPlugin] Calling plugin endpoint: %s", url)
@@ -443,7 +453,8 @@ func (s *PluginService) fetchSkillsFromPlugin(ctx context.Context, serverEndpoin
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
-	req.Header.Add("Authorization", "Bearer "+token)
+	s.signer.Sign(req, nil)
+	s.authorizeLegacy(req, pluginID)
 
 	client := &http.Client{Timeout: 30 * time.Second}
 	resp, err := client.Do(req)
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/vultisig/verifier/config"
+	itypes "github.com/vultisig/verifier/internal/types"
+	"github.com/vultisig/verifier/types"
+)
+
+const (
+	pluginHealthPath = "/healthz"
+	// pluginHealthConcurrency is how many plugin servers are probed at once
+	pluginHealthConcurrency = 10
+)
+
+type PluginHealthServiceStorage interface {
+	GetPluginEndpoints(ctx context.Context) (map[types.PluginID]string, error)
+	UpsertPluginHealth(ctx context.Context, health itypes.PluginHealth) error
+}
+
+// PluginHealthService probes the plugin servers, the results show as the availability of the plugins
+type PluginHealthService struct {
+	db     PluginHealthServiceStorage
+	client *http.Client
+	logger *logrus.Logger
+	now    func() time.Time
+}
+
+func NewPluginHealthService(db PluginHealthServiceStorage, cfg config.PluginHealthConfig, logger *logrus.Logger) (*PluginHealthService, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database storage cannot be nil")
+	}
+	if cfg.Timeout <= 0 {
+		return nil, fmt.Errorf("plugin health timeout must be positive")
+	}
+	return &PluginHealthService{
+		db:     db,
+		client: &http.Client{Timeout: cfg.Timeout},
+		logger: logger.WithField("service", "plugin-health").Logger,
+		now:    time.Now,
+	}, nil
+}
+
+// HandlePluginHealthCheck probes every plugin server and records the results
+func (s *PluginHealthService) HandlePluginHealthCheck(ctx context.Context, _ *asynq.Task) error {
+	results, err := s.CheckAll(ctx)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to check plugin health")
+		return err
+	}
+
+	unavailable := 0
+	for _, health := range results {
+		if !health.Available {
+			unavailable++
+		}
+	}
+	if unavailable > 0 {
+		s.logger.WithFields(logrus.Fields{
+			"checked":     len(results),
+			"unavailable": unavailable,
+		}).Warn("Plugin servers unavailable")
+	}
+	return nil
+}
+
+// CheckAll probes the plugin servers concurrently and records each result
+func (s *PluginHealthService) CheckAll(ctx context.Context) ([]itypes.PluginHealth, error) {
+	endpoints, err := s.db.GetPluginEndpoints(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get plugin endpoints: %w", err)
+	}
+
+	var mu sync.Mutex
+	results := make([]itypes.PluginHealth, 0, len(endpoints))
+	eg := &errgroup.Group{}
+	eg.SetLimit(pluginHealthConcurrency)
+	for pluginID, endpoint := range endpoints {
+		eg.Go(func() error {
+			health := s.probe(ctx, pluginID, endpoint)
+			err := s.db.UpsertPluginHealth(ctx, health)
+			if err != nil {
+				s.logger.WithError(err).WithField("plugin_id", pluginID).Error("failed to record plugin health")
+			}
+			mu.Lock()
+			results = append(results, health)
+			mu.Unlock()
+			return nil
+		})
+	}
+	_ = eg.Wait()
+	return results, nil
+}
+
+func (s *PluginHealthService) probe(ctx context.Context, pluginID types.PluginID, endpoint string) itypes.PluginHealth {
+	health := itypes.PluginHealth{
+		PluginID:  pluginID,
+		CheckedAt: s.now(),
+	}
+	fail := func(err error) itypes.PluginHealth {
+		reason := err.Error()
+		health.LastError = &reason
+		return health
+	}
+
+	url := strings.TrimSuffix(endpoint, "/") + pluginHealthPath
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fail(fmt.Errorf("failed to create request: %w", err))
+	}
+
+	start := time.Now()
+	resp, err := s.client.Do(req)
+	health.LatencyMs = int(time.Since(start).Milliseconds())
+	if err != nil {
+		return fail(fmt.Errorf("failed to reach plugin server: %w", err))
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	health.StatusCode = &resp.StatusCode
+	if resp.StatusCode != http.StatusOK {
+		return fail(fmt.Errorf("plugin server answered with status %d", resp.StatusCode))
+	}
+	health.Available = true
+	return health
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vultisig/verifier/config"
+	itypes "github.com/vultisig/verifier/internal/types"
+	"github.com/vultisig/verifier/types"
+)
+
+type fakePluginHealthStorage struct {
+	mu        sync.Mutex
+	endpoints map[types.PluginID]string
+	health    map[types.PluginID]itypes.PluginHealth
+}
+
+func (f *fakePluginHealthStorage) GetPluginEndpoints(_ context.Context) (map[types.PluginID]string, error) {
+	return f.endpoints, nil
+}
+
+func (f *fakePluginHealthStorage) UpsertPluginHealth(_ context.Context, health itypes.PluginHealth) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.health == nil {
+		f.health = make(map[types.PluginID]itypes.PluginHealth)
+	}
+	f.health[health.PluginID] = health
+	return nil
+}
+
+func TestPluginHealthCheck(t *testing.T) {
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != pluginHealthPath {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte("plugin server is running"))
+	}))
+	defer healthy.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+	down := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	down.Close()
+
+	db := &fakePluginHealthStorage{endpoints: map[types.PluginID]string{
+		"dca":     healthy.URL + "/",
+		"payroll": failing.URL,
+		"fees":    down.URL,
+	}}
+	_, err := NewPluginHealthService(db, config.PluginHealthConfig{}, logrus.New())
+	require.Error(t, err)
+	svc, err := NewPluginHealthService(db, config.PluginHealthConfig{Timeout: time.Second}, logrus.New())
+	require.NoError(t, err)
+
+	results, err := svc.CheckAll(context.Background())
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	require.Len(t, db.health, 3)
+
+	require.True(t, db.health["dca"].Available)
+	require.Equal(t, http.StatusOK, *db.health["dca"].StatusCode)
+	require.Nil(t, db.health["dca"].LastError)
+
+	require.False(t, db.health["payroll"].Available)
+	require.Equal(t, http.StatusServiceUnavailable, *db.health["payroll"].StatusCode)
+	require.Contains(t, *db.health["payroll"].LastError, "503")
+
+	require.False(t, db.health["fees"].Available)
+	require.Nil(t, db.health["fees"].StatusCode)
+	require.NotNil(t, db.health["fees"].LastError)
+}
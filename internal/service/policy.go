@@ -2,16 +2,19 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 
 	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
 	"github.com/jackc/pgx/v5"
 	"github.com/sirupsen/logrus"
 
 	"github.com/vultisig/verifier/internal/storage"
 	"github.com/vultisig/verifier/internal/syncer"
 	itypes "github.com/vultisig/verifier/internal/types"
+	"github.com/vultisig/verifier/plugin/tasks"
 	"github.com/vultisig/verifier/types"
 )
 
@@ -21,26 +24,29 @@ type Policy interface {
 	DeletePolicy(ctx context.Context, policyID uuid.UUID, pluginID types.PluginID, signature string) error
 	GetPluginPolicies(ctx context.Context, publicKey string, pluginID types.PluginID, take int, skip int, activeFilter *bool) (*itypes.PluginPolicyPaginatedList, error)
 	GetPluginPolicy(ctx context.Context, policyID uuid.UUID) (*types.PluginPolicy, error)
+	GetPolicySyncs(ctx context.Context, policyID uuid.UUID) ([]itypes.PluginPolicySync, error)
 	GetPluginInstallationsCount(ctx context.Context, pluginID types.PluginID) (itypes.PluginTotalCount, error)
 	DeleteAllPolicies(ctx context.Context, pluginID types.PluginID, publicKey string) error
 }
 
 var _ Policy = (*PolicyService)(nil)
 
+// PolicyService writes the policies, each change is queued in the policy sync outbox within its transaction
+// and delivered to the plugin server by the worker.
 type PolicyService struct {
 	db     storage.DatabaseStorage
 	logger *logrus.Logger
-	syncer *syncer.Syncer
+	client *asynq.Client
 }
 
-func NewPolicyService(db storage.DatabaseStorage, syncer *syncer.Syncer) (*PolicyService, error) {
+func NewPolicyService(db storage.DatabaseStorage, client *asynq.Client) (*PolicyService, error) {
 	if db == nil {
 		return nil, fmt.Errorf("database storage cannot be nil")
 	}
 	return &PolicyService{
 		db:     db,
 		logger: logrus.WithField("service", "policy").Logger,
-		syncer: syncer,
+		client: client,
 	}, nil
 }
 
@@ -58,12 +64,25 @@ func compareBillingPricing(pricing *types.Pricing, billing *types.BillingPolicy)
 		sameFrequency = *pricing.Frequency == *billing.Frequency
 	}
 	sameAmount := pricing.Amount == billing.Amount
-	//metrics to be added later for support. For now the only pricing db entry supported in fixed.
+	// an asset the user didn't sign is the one of the pricing
+	sameAsset := billing.Asset == "" || string(pricing.Asset) == billing.Asset
+	// the amount of a percentage pricing is in basis points, its metric and caps must be signed too
+	sameMetric := pricing.Metric == billing.Metric
+	sameCaps := sameCap(pricing.MinAmount, billing.MinAmount) && sameCap(pricing.MaxAmount, billing.MaxAmount)
 
-	return sameType && sameFrequency && sameAmount
+	return sameType && sameFrequency && sameAmount && sameAsset && sameMetric && sameCaps
 }
 
-func (s *PolicyService) validateBillingInformation(ctx context.Context, tx pgx.Tx, policy types.PluginPolicy) error {
+func sameCap(a, b *uint64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// validateBillingInformation matches the billing of the policy recipe to the plugin pricing,
+// a billing gets the asset of its pricing and a billing signed in another asset is rejected.
+func (s *PolicyService) validateBillingInformation(ctx context.Context, tx pgx.Tx, policy *types.PluginPolicy) error {
 	pluginData, err := s.db.FindPluginById(ctx, tx, policy.PluginID)
 	if err != nil {
 		return fmt.Errorf("failed to find plugin: %w", err)
@@ -87,6 +106,7 @@ func (s *PolicyService) validateBillingInformation(ctx context.Context, tx pgx.T
 				continue
 			}
 			if compareBillingPricing(&pricing, &billing) {
+				policy.Billing[i].Asset = string(pricing.Asset)
 				usedPricing[j] = true
 				found = true
 				break
@@ -122,7 +142,7 @@ func (s *PolicyService) CreatePolicy(ctx context.Context, policy types.PluginPol
 	}
 
 	// Compare and contrast the billing information (signed by user) with the pricing information (defined in the pricings table and connected to the plugin definition)
-	if err := s.validateBillingInformation(ctx, tx, policy); err != nil {
+	if err := s.validateBillingInformation(ctx, tx, &policy); err != nil {
 		return nil, fmt.Errorf("failed to validate billing information: %w", err)
 	}
 
@@ -132,15 +152,14 @@ func (s *PolicyService) CreatePolicy(ctx context.Context, policy types.PluginPol
 		return nil, fmt.Errorf("failed to insert policy: %w", err)
 	}
 
-	// Sync policy synchronously - if this fails, the entire operation fails
-	if err := s.syncer.CreatePolicySync(ctx, policy); err != nil {
-		s.logger.WithError(err).Error("failed to sync policy with plugin server")
-		return nil, fmt.Errorf("failed to sync policy with plugin server: %w", err)
+	if err := s.queueSync(ctx, tx, policy, itypes.AddPolicy); err != nil {
+		return nil, err
 	}
 
 	if err := tx.Commit(ctx); err != nil {
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
+	s.notifySync(ctx)
 
 	return newPolicy, nil
 }
@@ -159,37 +178,58 @@ func (s *PolicyService) UpdatePolicy(ctx context.Context, policy types.PluginPol
 		return nil, fmt.Errorf("failed to update policy: %w", err)
 	}
 
-	syncPolicyEntity := itypes.PluginPolicySync{
-		ID:         uuid.New(),
-		PolicyID:   updatedPolicy.ID,
-		PluginID:   updatedPolicy.PluginID,
-		Signature:  updatedPolicy.Signature,
-		SyncType:   itypes.UpdatePolicy,
-		Status:     itypes.NotSynced,
-		FailReason: "",
-	}
-	if err := s.db.AddPluginPolicySync(ctx, tx, syncPolicyEntity); err != nil {
-		return nil, fmt.Errorf("failed to add policy sync: %w", err)
-	}
-
-	// Try to sync policy update with plugin server (best effort)
-	// Don't fail the entire operation if sync fails - verifier DB is source of truth
-	if s.syncer != nil {
-		if err := s.syncer.UpdatePolicySync(ctx, policy); err != nil {
-			s.logger.WithError(err).WithFields(logrus.Fields{
-				"policy_id": updatedPolicy.ID,
-				"plugin_id": updatedPolicy.PluginID,
-			}).Warn("Failed to sync policy update with plugin server, proceeding with verifier update")
-		}
+	if err := s.queueSync(ctx, tx, policy, itypes.UpdatePolicy); err != nil {
+		return nil, err
 	}
 
-	// Always update verifier database (source of truth)
 	if err := tx.Commit(ctx); err != nil {
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
+	s.notifySync(ctx)
+
 	return updatedPolicy, nil
 }
 
+// queueSync adds the policy change to the sync outbox, the payload is the body sent to the plugin server
+func (s *PolicyService) queueSync(ctx context.Context, tx pgx.Tx, policy types.PluginPolicy, syncType itypes.PolicySyncType) error {
+	var body any = policy
+	if syncType == itypes.RemovePolicy {
+		body = syncer.DeleteRequestBody{Signature: policy.Signature}
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal policy sync payload: %w", err)
+	}
+
+	err = s.db.AddPluginPolicySync(ctx, tx, itypes.PluginPolicySync{
+		ID:        uuid.New(),
+		PolicyID:  policy.ID,
+		PluginID:  policy.PluginID,
+		Signature: policy.Signature,
+		SyncType:  syncType,
+		Status:    itypes.NotSynced,
+		Payload:   payload,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add policy sync: %w", err)
+	}
+	return nil
+}
+
+// notifySync has the worker deliver the queued changes right away instead of on the next scheduled run
+func (s *PolicyService) notifySync(ctx context.Context) {
+	if s.client == nil {
+		return
+	}
+	_, err := s.client.EnqueueContext(ctx,
+		asynq.NewTask(tasks.TypePolicySync, nil),
+		asynq.MaxRetry(0),
+		asynq.Queue(tasks.QUEUE_NAME))
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to enqueue policy sync task")
+	}
+}
+
 func (s *PolicyService) handleRollback(tx pgx.Tx) {
 	ctx := context.Background()
 	if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
@@ -210,24 +250,19 @@ func (s *PolicyService) DeletePolicy(ctx context.Context, policyID uuid.UUID, pl
 		return fmt.Errorf("failed to get policy: %w", err)
 	}
 
-	// Try to sync policy deletion with plugin server (best effort)
-	// Don't fail the entire operation if sync fails - verifier DB is source of truth
-	if err := s.syncer.DeletePolicySync(ctx, *policy); err != nil {
-		s.logger.WithError(err).WithFields(logrus.Fields{
-			"policy_id": policyID,
-			"plugin_id": pluginID,
-		}).Warn("Failed to sync policy deletion with plugin server, proceeding with verifier deletion")
-	}
-
-	// Always delete from verifier database (source of truth)
 	err = s.db.DeletePluginPolicyTx(ctx, tx, policyID)
 	if err != nil {
 		return fmt.Errorf("failed to delete policy from verifier: %w", err)
 	}
 
+	if err := s.queueSync(ctx, tx, *policy, itypes.RemovePolicy); err != nil {
+		return err
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
+	s.notifySync(ctx)
 
 	return nil
 }
@@ -248,6 +283,14 @@ func (s *PolicyService) GetPluginPolicy(ctx context.Context, policyID uuid.UUID)
 	return policy, nil
 }
 
+func (s *PolicyService) GetPolicySyncs(ctx context.Context, policyID uuid.UUID) ([]itypes.PluginPolicySync, error) {
+	syncs, err := s.db.GetPolicySyncs(ctx, policyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get policy syncs: %w", err)
+	}
+	return syncs, nil
+}
+
 func (s *PolicyService) GetPluginInstallationsCount(ctx context.Context, pluginID types.PluginID) (itypes.PluginTotalCount, error) {
 	count, err := s.db.GetPluginInstallationsCount(ctx, pluginID)
 	if err != nil {
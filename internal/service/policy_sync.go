@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/sirupsen/logrus"
+
+	"github.com/vultisig/verifier/internal/syncer"
+	itypes "github.com/vultisig/verifier/internal/types"
+)
+
+const (
+	// policySyncLease is how long a claimed change is reserved for its delivery
+	policySyncLease = time.Minute
+	// policySyncMaxAttempts is how many deliveries of a change fail before it is dead-lettered,
+	// about six hours with the outbox backoff
+	policySyncMaxAttempts = 12
+)
+
+type PolicySyncServiceStorage interface {
+	ClaimPolicySyncs(ctx context.Context, lease time.Duration, limit int) ([]itypes.PluginPolicySync, error)
+	MarkPolicySyncDelivered(ctx context.Context, id uuid.UUID) error
+	MarkPolicySyncFailed(ctx context.Context, id uuid.UUID, reason string, nextAttemptAt time.Time) error
+	MarkPolicySyncDeadLettered(ctx context.Context, id uuid.UUID, reason string) error
+}
+
+type PolicySyncSender interface {
+	SendPolicySync(ctx context.Context, sync itypes.PluginPolicySync) error
+}
+
+// PolicySyncService delivers the policy sync outbox to the plugin servers. The changes of a policy are
+// delivered one at a time in the order they were written, a failed change holds back the later ones until
+// its retry succeeds. A change the plugin server rejects, or that keeps failing, is dead-lettered so that
+// the later ones go out, and a policy whose creation is dead-lettered is deactivated.
+type PolicySyncService struct {
+	db     PolicySyncServiceStorage
+	sender PolicySyncSender
+	logger *logrus.Logger
+	now    func() time.Time
+}
+
+func NewPolicySyncService(db PolicySyncServiceStorage, sender PolicySyncSender, logger *logrus.Logger) (*PolicySyncService, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database storage cannot be nil")
+	}
+	if sender == nil {
+		return nil, fmt.Errorf("policy sync sender cannot be nil")
+	}
+	return &PolicySyncService{
+		db:     db,
+		sender: sender,
+		logger: logger.WithField("service", "policy-sync").Logger,
+		now:    time.Now,
+	}, nil
+}
+
+// HandlePolicySync delivers the policy changes that are due, it is enqueued after policy writes and
+// scheduled to retry the failed deliveries
+func (s *PolicySyncService) HandlePolicySync(ctx context.Context, _ *asynq.Task) error {
+	delivered, err := s.DeliverPending(ctx)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to deliver policy syncs")
+		return err
+	}
+	if delivered > 0 {
+		s.logger.WithField("delivered", delivered).Info("Policies synced to plugins")
+	}
+	return nil
+}
+
+// DeliverPending delivers the due changes until none is left, it returns how many were delivered
+func (s *PolicySyncService) DeliverPending(ctx context.Context) (int, error) {
+	delivered := 0
+	for {
+		syncs, err := s.db.ClaimPolicySyncs(ctx, policySyncLease, outboxBatchSize)
+		if err != nil {
+			return delivered, fmt.Errorf("failed to claim policy syncs: %w", err)
+		}
+
+		unblocked := 0
+		for _, sync := range syncs {
+			logger := s.logger.WithFields(logrus.Fields{
+				"sync_id":   sync.ID,
+				"policy_id": sync.PolicyID,
+				"plugin_id": sync.PluginID,
+				"attempts":  sync.Attempts + 1,
+			})
+			err = s.deliver(ctx, sync)
+			switch {
+			case err == nil:
+				delivered++
+			case errors.Is(err, errPolicySyncDeadLettered):
+				logger.WithError(err).Error("gave up syncing policy to plugin")
+			default:
+				logger.WithError(err).Warn("failed to sync policy to plugin, will retry")
+				continue
+			}
+			unblocked++
+		}
+
+		// a delivered or dead-lettered change unblocks the next change of its policy
+		if unblocked == 0 {
+			return delivered, nil
+		}
+	}
+}
+
+var errPolicySyncDeadLettered = errors.New("policy sync dead-lettered")
+
+func (s *PolicySyncService) deliver(ctx context.Context, sync itypes.PluginPolicySync) error {
+	sendErr := s.sender.SendPolicySync(ctx, sync)
+	if sendErr != nil {
+		if errors.Is(sendErr, syncer.ErrPolicyRejected) || sync.Attempts+1 >= policySyncMaxAttempts {
+			err := s.db.MarkPolicySyncDeadLettered(ctx, sync.ID, sendErr.Error())
+			if err != nil {
+				return fmt.Errorf("failed to mark policy sync dead-lettered: %w (sync: %v)", err, sendErr)
+			}
+			return fmt.Errorf("%w: %w", errPolicySyncDeadLettered, sendErr)
+		}
+		err := s.db.MarkPolicySyncFailed(ctx, sync.ID, sendErr.Error(), s.now().Add(outboxBackoff(sync.Attempts)))
+		if err != nil {
+			return fmt.Errorf("failed to mark policy sync failed: %w (sync: %v)", err, sendErr)
+		}
+		return sendErr
+	}
+
+	err := s.db.MarkPolicySyncDelivered(ctx, sync.ID)
+	if err != nil {
+		return fmt.Errorf("failed to mark policy sync delivered: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vultisig/verifier/internal/syncer"
+	itypes "github.com/vultisig/verifier/internal/types"
+	"github.com/vultisig/verifier/types"
+)
+
+type fakePolicySyncStorage struct {
+	syncs  []*itypes.PluginPolicySync
+	locked map[uuid.UUID]time.Time
+	now    func() time.Time
+}
+
+func (f *fakePolicySyncStorage) add(policyID uuid.UUID, syncType itypes.PolicySyncType) uuid.UUID {
+	sync := &itypes.PluginPolicySync{
+		ID:            uuid.New(),
+		PolicyID:      policyID,
+		PluginID:      types.PluginVultisigDCA_0000,
+		SyncType:      syncType,
+		Status:        itypes.NotSynced,
+		NextAttemptAt: f.now(),
+	}
+	f.syncs = append(f.syncs, sync)
+	return sync.ID
+}
+
+func (f *fakePolicySyncStorage) find(id uuid.UUID) *itypes.PluginPolicySync {
+	for _, sync := range f.syncs {
+		if sync.ID == id {
+			return sync
+		}
+	}
+	return nil
+}
+
+func (f *fakePolicySyncStorage) ClaimPolicySyncs(_ context.Context, lease time.Duration, limit int) ([]itypes.PluginPolicySync, error) {
+	if f.locked == nil {
+		f.locked = make(map[uuid.UUID]time.Time)
+	}
+	heads := make(map[uuid.UUID]bool)
+	var claimed []itypes.PluginPolicySync
+	for _, sync := range f.syncs {
+		if sync.Status == itypes.Synced || sync.Status == itypes.DeadLettered || heads[sync.PolicyID] {
+			continue
+		}
+		heads[sync.PolicyID] = true
+		if sync.NextAttemptAt.After(f.now()) || f.locked[sync.ID].After(f.now()) || len(claimed) == limit {
+			continue
+		}
+		f.locked[sync.ID] = f.now().Add(lease)
+		claimed = append(claimed, *sync)
+	}
+	return claimed, nil
+}
+
+func (f *fakePolicySyncStorage) MarkPolicySyncDelivered(_ context.Context, id uuid.UUID) error {
+	sync := f.find(id)
+	sync.Status = itypes.Synced
+	sync.Attempts++
+	delete(f.locked, id)
+	return nil
+}
+
+func (f *fakePolicySyncStorage) MarkPolicySyncFailed(_ context.Context, id uuid.UUID, reason string, nextAttemptAt time.Time) error {
+	sync := f.find(id)
+	sync.Status = itypes.Failed
+	sync.FailReason = reason
+	sync.Attempts++
+	sync.NextAttemptAt = nextAttemptAt
+	delete(f.locked, id)
+	return nil
+}
+
+func (f *fakePolicySyncStorage) MarkPolicySyncDeadLettered(_ context.Context, id uuid.UUID, reason string) error {
+	sync := f.find(id)
+	sync.Status = itypes.DeadLettered
+	sync.FailReason = reason
+	sync.Attempts++
+	delete(f.locked, id)
+	return nil
+}
+
+type fakePolicySyncSender struct {
+	down     map[uuid.UUID]bool
+	rejected map[uuid.UUID]bool
+	sent     []uuid.UUID
+}
+
+func (f *fakePolicySyncSender) SendPolicySync(_ context.Context, sync itypes.PluginPolicySync) error {
+	if f.down[sync.PolicyID] {
+		return errors.New("plugin server down")
+	}
+	if f.rejected[sync.ID] {
+		return fmt.Errorf("%w: status: 400", syncer.ErrPolicyRejected)
+	}
+	f.sent = append(f.sent, sync.ID)
+	return nil
+}
+
+func TestPolicySyncDelivery(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	db := &fakePolicySyncStorage{now: func() time.Time { return now }}
+	dca, payroll := uuid.New(), uuid.New()
+	sender := &fakePolicySyncSender{down: map[uuid.UUID]bool{dca: true}}
+
+	_, err := NewPolicySyncService(db, nil, logrus.New())
+	require.Error(t, err)
+	svc, err := NewPolicySyncService(db, sender, logrus.New())
+	require.NoError(t, err)
+	svc.now = db.now
+
+	dcaAdd := db.add(dca, itypes.AddPolicy)
+	payrollAdd := db.add(payroll, itypes.AddPolicy)
+	dcaUpdate := db.add(dca, itypes.UpdatePolicy)
+	payrollUpdate := db.add(payroll, itypes.UpdatePolicy)
+	payrollRemove := db.add(payroll, itypes.RemovePolicy)
+
+	// the changes of a policy go out in order, a failed change holds back the later ones
+	delivered, err := svc.DeliverPending(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 3, delivered)
+	require.Equal(t, []uuid.UUID{payrollAdd, payrollUpdate, payrollRemove}, sender.sent)
+	require.Equal(t, itypes.Failed, db.find(dcaAdd).Status)
+	require.Equal(t, "plugin server down", db.find(dcaAdd).FailReason)
+	require.Equal(t, now.Add(outboxBaseDelay), db.find(dcaAdd).NextAttemptAt)
+	require.Equal(t, itypes.NotSynced, db.find(dcaUpdate).Status)
+
+	// the retry waits for the backoff
+	sender.down = nil
+	delivered, err = svc.DeliverPending(ctx)
+	require.NoError(t, err)
+	require.Zero(t, delivered)
+
+	now = now.Add(outboxBaseDelay)
+	delivered, err = svc.DeliverPending(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, delivered)
+	require.Equal(t, []uuid.UUID{dcaAdd, dcaUpdate}, sender.sent[3:])
+	require.Equal(t, itypes.Synced, db.find(dcaAdd).Status)
+	require.Equal(t, 2, db.find(dcaAdd).Attempts)
+
+	// a change claimed by another worker is left alone until its lease expires
+	dcaRemove := db.add(dca, itypes.RemovePolicy)
+	_, err = db.ClaimPolicySyncs(ctx, policySyncLease, outboxBatchSize)
+	require.NoError(t, err)
+	delivered, err = svc.DeliverPending(ctx)
+	require.NoError(t, err)
+	require.Zero(t, delivered)
+	now = now.Add(policySyncLease)
+	delivered, err = svc.DeliverPending(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, delivered)
+	require.Equal(t, dcaRemove, sender.sent[len(sender.sent)-1])
+}
+
+func TestPolicySyncDeadLetter(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	db := &fakePolicySyncStorage{now: func() time.Time { return now }}
+	dca, payroll := uuid.New(), uuid.New()
+	sender := &fakePolicySyncSender{down: map[uuid.UUID]bool{payroll: true}, rejected: map[uuid.UUID]bool{}}
+	svc, err := NewPolicySyncService(db, sender, logrus.New())
+	require.NoError(t, err)
+	svc.now = db.now
+
+	// a rejected change isn't retried, the later changes of the policy go out
+	dcaAdd := db.add(dca, itypes.AddPolicy)
+	dcaUpdate := db.add(dca, itypes.UpdatePolicy)
+	sender.rejected[dcaAdd] = true
+	payrollAdd := db.add(payroll, itypes.AddPolicy)
+
+	delivered, err := svc.DeliverPending(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, delivered)
+	require.Equal(t, []uuid.UUID{dcaUpdate}, sender.sent)
+	require.Equal(t, itypes.DeadLettered, db.find(dcaAdd).Status)
+	require.Contains(t, db.find(dcaAdd).FailReason, "status: 400")
+
+	// a change that keeps failing is given up on after the last attempt
+	require.Equal(t, itypes.Failed, db.find(payrollAdd).Status)
+	for i := 2; i < policySyncMaxAttempts; i++ {
+		now = now.Add(outboxMaxDelay)
+		_, err = svc.DeliverPending(ctx)
+		require.NoError(t, err)
+		require.Equal(t, itypes.Failed, db.find(payrollAdd).Status)
+	}
+	now = now.Add(outboxMaxDelay)
+	_, err = svc.DeliverPending(ctx)
+	require.NoError(t, err)
+	require.Equal(t, itypes.DeadLettered, db.find(payrollAdd).Status)
+	require.Equal(t, policySyncMaxAttempts, db.find(payrollAdd).Attempts)
+
+	now = now.Add(outboxMaxDelay)
+	delivered, err = svc.DeliverPending(ctx)
+	require.NoError(t, err)
+	require.Zero(t, delivered)
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vultisig/verifier/types"
+)
+
+func TestCompareBillingPricing(t *testing.T) {
+	monthly := types.PricingFrequencyMonthly
+	pricing := &types.Pricing{
+		Type:      types.PricingTypeRecurring,
+		Frequency: &monthly,
+		Amount:    1_000_000,
+		Asset:     types.PricingAssetUSDT,
+		Metric:    types.PricingMetricFixed,
+	}
+	billing := &types.BillingPolicy{
+		Type:      types.PricingTypeRecurring,
+		Frequency: &monthly,
+		Amount:    1_000_000,
+		Asset:     string(types.PricingAssetUSDT),
+		Metric:    types.PricingMetricFixed,
+	}
+	require.True(t, compareBillingPricing(pricing, billing))
+
+	// the asset the user signed must be the one the plugin charges in
+	billing.Asset = string(types.PricingAssetUSDC)
+	require.False(t, compareBillingPricing(pricing, billing))
+
+	billing.Asset = ""
+	require.True(t, compareBillingPricing(pricing, billing))
+
+	// a percentage fee is only agreed with its metric and caps
+	billing.Asset = string(types.PricingAssetUSDT)
+	minAmount, maxAmount := uint64(10_000), uint64(5_000_000)
+	pricing = &types.Pricing{
+		Type:      types.PricingTypePerTx,
+		Amount:    50,
+		Asset:     types.PricingAssetUSDT,
+		Metric:    types.PricingMetricPercentage,
+		MinAmount: &minAmount,
+		MaxAmount: &maxAmount,
+	}
+	billing = &types.BillingPolicy{
+		Type:   types.PricingTypePerTx,
+		Amount: 50,
+		Asset:  string(types.PricingAssetUSDT),
+		Metric: types.PricingMetricFixed,
+	}
+	require.False(t, compareBillingPricing(pricing, billing))
+
+	billing.Metric = types.PricingMetricPercentage
+	require.False(t, compareBillingPricing(pricing, billing))
+
+	billing.MinAmount, billing.MaxAmount = &minAmount, &maxAmount
+	require.True(t, compareBillingPricing(pricing, billing))
+
+	higherMax := maxAmount * 2
+	billing.MaxAmount = &higherMax
+	require.False(t, compareBillingPricing(pricing, billing))
+}
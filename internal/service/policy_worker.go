@@ -2,95 +2,70 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/hibiken/asynq"
 	"github.com/sirupsen/logrus"
+
+	"github.com/vultisig/verifier/internal/pricing"
 	"github.com/vultisig/verifier/types"
 )
 
-const QUERY_GET_EXPIRED_SUBSCRIPTIONS = `SELECT
-  p.public_key,
-  b.plugin_policy_id AS policy_id,
-  p.plugin_id,
-  b.amount
-FROM plugin_policies p
-JOIN plugin_policy_billing b ON b.plugin_policy_id = p.id
-LEFT JOIN (
-  SELECT policy_id, public_key, MAX(created_at) AS last_fee_at
-  FROM fees
-  WHERE fee_type = 'subscription_fee'
-  GROUP BY policy_id, public_key
-) f ON f.policy_id = p.id AND f.public_key = p.public_key
-WHERE b.type = 'recurring'
-  AND (
-    f.last_fee_at IS NULL
-    OR (f.last_fee_at + b.frequency::interval) <= CURRENT_DATE
-  )`
-
-func (s *PolicyService) HandleScheduledFees(ctx context.Context, task *asynq.Task) error {
-	query := QUERY_GET_EXPIRED_SUBSCRIPTIONS
-	rows, err := s.db.Pool().Query(ctx, query)
+// HandleScheduledFees bills the recurring pricings of the policies for the periods ended so far,
+// prorated on the time each policy was active in the period
+func (s *PolicyService) HandleScheduledFees(ctx context.Context, _ *asynq.Task) error {
+	now := time.Now().UTC()
+	subs, err := s.db.GetSubscriptionsToBill(ctx, now)
 	if err != nil {
-		fmt.Println(err)
-		s.logger.WithError(err).Error("Failed to query scheduled fees")
-		return fmt.Errorf("failed to query scheduled fees: %w", err)
+		s.logger.WithError(err).Error("Failed to get subscriptions to bill")
+		return fmt.Errorf("failed to get subscriptions to bill: %w", err)
 	}
-	defer rows.Close()
 
-	var feesToInsert []struct {
-		publicKey string
-		policyId  string
-		pluginId  string
-		amount    uint64
-	}
-	for rows.Next() {
-		var res struct {
-			publicKey string
-			policyId  string
-			pluginId  string
-			amount    uint64
-		}
-		err := rows.Scan(&res.publicKey, &res.policyId, &res.pluginId, &res.amount)
+	for _, sub := range subs {
+		fees, err := pricing.SubscriptionFees(sub, now)
 		if err != nil {
-			fmt.Println(err)
-			s.logger.WithError(err).Error("Failed to scan scheduled fee row")
-			return fmt.Errorf("failed to scan scheduled fee row: %w", err)
+			s.logger.WithError(err).WithField("plugin_policy_id", sub.PolicyID).Error("Failed to compute subscription fees")
+			continue
 		}
-		feesToInsert = append(feesToInsert, res)
-	}
 
-	if err := rows.Err(); err != nil {
-		return fmt.Errorf("error iterating rows: %w", err)
-	}
+		for _, fee := range fees {
+			metadata, err := json.Marshal(fee)
+			if err != nil {
+				return fmt.Errorf("failed to marshal subscription fee metadata: %w", err)
+			}
+			feeID, err := s.db.InsertFee(ctx, nil, &types.Fee{
+				PolicyID:       sub.PolicyID,
+				PluginID:       sub.PluginID,
+				PublicKey:      sub.PublicKey,
+				TxType:         types.TxTypeDebit,
+				Amount:         fee.Amount,
+				Asset:          sub.Asset,
+				FeeType:        types.FeeSubscriptionFee,
+				Metadata:       metadata,
+				UnderlyingType: "policy",
+				UnderlyingID:   sub.PolicyID.String(),
+			})
+			if err != nil {
+				s.logger.WithError(err).WithFields(logrus.Fields{
+					"plugin_policy_id": sub.PolicyID,
+					"amount":           fee.Amount,
+				}).Error("Failed to insert scheduled fee record")
+				return fmt.Errorf("failed to insert scheduled fee record: %w", err)
+			}
+			if feeID == 0 {
+				continue // period already billed
+			}
 
-	for _, res := range feesToInsert {
-		_, err = s.db.InsertFee(ctx, nil, &types.Fee{
-			PluginID:       res.pluginId,
-			PublicKey:      res.publicKey,
-			TxType:         types.TxTypeDebit,
-			Amount:         res.amount,
-			FeeType:        types.FeeSubscriptionFee,
-			UnderlyingType: "policy",
-			UnderlyingID:   res.policyId,
-		})
-		if err != nil {
-			s.logger.WithError(err).WithFields(logrus.Fields{
-				"plugin_policy_id": res.policyId,
-				"amount":           res.amount,
-			}).Error("Failed to insert scheduled fee record")
-			return fmt.Errorf("failed to insert scheduled fee record: %w", err)
+			s.logger.WithFields(logrus.Fields{
+				"plugin_policy_id": sub.PolicyID,
+				"period_start":     fee.PeriodStart,
+				"amount":           fee.Amount,
+				"full_amount":      fee.FullAmount,
+			}).Info("Inserted scheduled fee record")
 		}
-
-		s.logger.WithFields(logrus.Fields{
-			"plugin_policy_id": res.policyId,
-			"amount":           res.amount,
-		}).Info("Inserted scheduled fee record")
-	}
-
-	if len(feesToInsert) > 0 {
-		return s.HandleScheduledFees(ctx, task)
 	}
 
 	return nil
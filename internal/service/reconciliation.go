@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/sirupsen/logrus"
+
+	itypes "github.com/vultisig/verifier/internal/types"
+	"github.com/vultisig/verifier/plugin/tx_indexer/pkg/rpc"
+	vtypes "github.com/vultisig/verifier/types"
+)
+
+// reconcileSettleWindow is how long a collection tx can stay unknown to the tx indexer before it's flagged
+const reconcileSettleWindow = 24 * time.Hour
+
+type ReconciliationServiceStorage interface {
+	GetLedgerBalances(ctx context.Context, accountType itypes.LedgerAccountType, accountID string) ([]itypes.LedgerBalance, error)
+	GetBatchCollections(ctx context.Context) ([]itypes.BatchCollection, error)
+	GetLedgerIntegrityIssues(ctx context.Context) ([]uint64, []uint64, error)
+	InsertReconciliationReport(ctx context.Context, report *itypes.ReconciliationReport) error
+}
+
+// ReconciliationService compares the fee ledger with the fee batches collected on chain
+type ReconciliationService struct {
+	db     ReconciliationServiceStorage
+	logger *logrus.Logger
+	now    func() time.Time
+}
+
+func NewReconciliationService(db ReconciliationServiceStorage, logger *logrus.Logger) (*ReconciliationService, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database storage cannot be nil")
+	}
+	return &ReconciliationService{
+		db:     db,
+		logger: logger.WithField("service", "reconciliation").Logger,
+		now:    time.Now,
+	}, nil
+}
+
+// HandleReconciliation reconciles the ledger and stores the report
+func (s *ReconciliationService) HandleReconciliation(ctx context.Context, _ *asynq.Task) error {
+	report, err := s.Reconcile(ctx)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to reconcile the fee ledger")
+		return err
+	}
+	if err := s.db.InsertReconciliationReport(ctx, report); err != nil {
+		return err
+	}
+
+	logger := s.logger.WithFields(logrus.Fields{
+		"report_id":     report.ID,
+		"discrepancies": len(report.Discrepancies),
+	})
+	if len(report.Discrepancies) > 0 {
+		logger.Warn("Fee ledger discrepancies found")
+		return nil
+	}
+	logger.Info("Fee ledger reconciled")
+	return nil
+}
+
+// Reconcile builds a reconciliation report, it flags:
+//   - ledger entries that don't balance and fees that weren't posted,
+//   - batches whose collection tx is unknown to the tx indexer past reconcileSettleWindow,
+//   - batches whose status or ledger amount doesn't match the on-chain outcome of their collection tx,
+//   - assets whose collections account doesn't match the amount collected on chain
+func (s *ReconciliationService) Reconcile(ctx context.Context) (*itypes.ReconciliationReport, error) {
+	unbalanced, unposted, err := s.db.GetLedgerIntegrityIssues(ctx)
+	if err != nil {
+		return nil, err
+	}
+	batches, err := s.db.GetBatchCollections(ctx)
+	if err != nil {
+		return nil, err
+	}
+	balances, err := s.db.GetLedgerBalances(ctx, itypes.LedgerAccountTreasury, itypes.TreasuryCollections)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &itypes.ReconciliationReport{
+		Discrepancies: make([]itypes.ReconciliationDiscrepancy, 0),
+	}
+	for _, id := range unbalanced {
+		report.Discrepancies = append(report.Discrepancies, itypes.ReconciliationDiscrepancy{
+			Kind:        itypes.DiscrepancyUnbalancedEntry,
+			ReferenceID: id,
+		})
+	}
+	for _, id := range unposted {
+		report.Discrepancies = append(report.Discrepancies, itypes.ReconciliationDiscrepancy{
+			Kind:        itypes.DiscrepancyUnpostedFee,
+			ReferenceID: id,
+		})
+	}
+
+	totals := make(map[vtypes.PricingAsset]*itypes.ReconciliationTotal)
+	total := func(asset vtypes.PricingAsset) *itypes.ReconciliationTotal {
+		t, ok := totals[asset]
+		if !ok {
+			t = &itypes.ReconciliationTotal{Asset: asset}
+			totals[asset] = t
+		}
+		return t
+	}
+	for _, balance := range balances {
+		total(balance.Asset).LedgerCollected += balance.Balance
+	}
+
+	now := s.now()
+	for _, batch := range batches {
+		t := total(batch.Asset)
+		mismatch := func(kind itypes.DiscrepancyKind, expected, actual string) {
+			report.Discrepancies = append(report.Discrepancies, itypes.ReconciliationDiscrepancy{
+				Kind:     kind,
+				Asset:    batch.Asset,
+				BatchID:  batch.BatchID,
+				TxHash:   batch.TxHash,
+				Expected: expected,
+				Actual:   actual,
+			})
+		}
+
+		if batch.OnChainStatus == nil {
+			t.InFlight += batch.LedgerCollected
+			if now.Sub(batch.CreatedAt) > reconcileSettleWindow {
+				mismatch(itypes.DiscrepancyMissingOnChainTx, "indexed collection tx", "")
+			}
+			continue
+		}
+
+		switch rpc.TxOnChainStatus(*batch.OnChainStatus) {
+		case rpc.TxOnChainSuccess:
+			t.OnChainCollected += int64(batch.TotalValue)
+			if batch.Status != "COMPLETED" {
+				mismatch(itypes.DiscrepancyStatusMismatch, "COMPLETED", batch.Status)
+			}
+			if batch.LedgerCollected != int64(batch.TotalValue) {
+				mismatch(itypes.DiscrepancyAmountMismatch,
+					strconv.FormatUint(batch.TotalValue, 10), strconv.FormatInt(batch.LedgerCollected, 10))
+			}
+			if batch.OnChainAmount != nil && *batch.OnChainAmount != "" &&
+				*batch.OnChainAmount != strconv.FormatUint(batch.TotalValue, 10) {
+				mismatch(itypes.DiscrepancyAmountMismatch, strconv.FormatUint(batch.TotalValue, 10), *batch.OnChainAmount)
+			}
+		case rpc.TxOnChainFail:
+			if batch.Status != "FAILED" {
+				mismatch(itypes.DiscrepancyStatusMismatch, "FAILED", batch.Status)
+			}
+			if batch.LedgerCollected != 0 {
+				mismatch(itypes.DiscrepancyAmountMismatch, "0", strconv.FormatInt(batch.LedgerCollected, 10))
+			}
+		default:
+			t.InFlight += batch.LedgerCollected
+		}
+	}
+
+	for _, t := range totals {
+		report.Totals = append(report.Totals, *t)
+		if t.LedgerCollected-t.InFlight != t.OnChainCollected {
+			report.Discrepancies = append(report.Discrepancies, itypes.ReconciliationDiscrepancy{
+				Kind:     itypes.DiscrepancyTotalMismatch,
+				Asset:    t.Asset,
+				Expected: strconv.FormatInt(t.OnChainCollected, 10),
+				Actual:   strconv.FormatInt(t.LedgerCollected-t.InFlight, 10),
+			})
+		}
+	}
+	sort.Slice(report.Totals, func(i, j int) bool {
+		return report.Totals[i].Asset < report.Totals[j].Asset
+	})
+	return report, nil
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	itypes "github.com/vultisig/verifier/internal/types"
+	"github.com/vultisig/verifier/plugin/tx_indexer/pkg/rpc"
+	"github.com/vultisig/verifier/types"
+)
+
+type fakeReconciliationStorage struct {
+	balances   []itypes.LedgerBalance
+	batches    []itypes.BatchCollection
+	unbalanced []uint64
+	unposted   []uint64
+	reports    []*itypes.ReconciliationReport
+}
+
+func (f *fakeReconciliationStorage) GetLedgerBalances(_ context.Context, _ itypes.LedgerAccountType, _ string) ([]itypes.LedgerBalance, error) {
+	return f.balances, nil
+}
+
+func (f *fakeReconciliationStorage) GetBatchCollections(_ context.Context) ([]itypes.BatchCollection, error) {
+	return f.batches, nil
+}
+
+func (f *fakeReconciliationStorage) GetLedgerIntegrityIssues(_ context.Context) ([]uint64, []uint64, error) {
+	return f.unbalanced, f.unposted, nil
+}
+
+func (f *fakeReconciliationStorage) InsertReconciliationReport(_ context.Context, report *itypes.ReconciliationReport) error {
+	report.ID = uint64(len(f.reports) + 1)
+	f.reports = append(f.reports, report)
+	return nil
+}
+
+func onChain(status rpc.TxOnChainStatus) *string {
+	s := string(status)
+	return &s
+}
+
+func TestReconcile(t *testing.T) {
+	now := time.Date(2026, time.October, 10, 0, 0, 0, 0, time.UTC)
+	usdc := types.PricingAsset("usdc")
+	db := &fakeReconciliationStorage{
+		balances: []itypes.LedgerBalance{
+			{AccountType: itypes.LedgerAccountTreasury, AccountID: itypes.TreasuryCollections, Asset: usdc, Balance: 600},
+		},
+		batches: []itypes.BatchCollection{
+			// collected
+			{BatchID: 1, Asset: usdc, TotalValue: 100, Status: "COMPLETED", TxHash: "0x1",
+				CreatedAt: now.Add(-48 * time.Hour), LedgerCollected: 100, OnChainStatus: onChain(rpc.TxOnChainSuccess)},
+			// failed on chain but still collected by the ledger
+			{BatchID: 2, Asset: usdc, TotalValue: 200, Status: "SENT", TxHash: "0x2",
+				CreatedAt: now.Add(-48 * time.Hour), LedgerCollected: 200, OnChainStatus: onChain(rpc.TxOnChainFail)},
+			// recent and not indexed yet
+			{BatchID: 3, Asset: usdc, TotalValue: 300, Status: "SENT", TxHash: "0x3",
+				CreatedAt: now.Add(-time.Hour), LedgerCollected: 300},
+			// never indexed
+			{BatchID: 4, Asset: usdc, TotalValue: 0, Status: "SENT", TxHash: "0x4",
+				CreatedAt: now.Add(-48 * time.Hour)},
+		},
+		unposted: []uint64{42},
+	}
+	svc, err := NewReconciliationService(db, logrus.New())
+	require.NoError(t, err)
+	svc.now = func() time.Time { return now }
+
+	require.NoError(t, svc.HandleReconciliation(context.Background(), nil))
+	require.Len(t, db.reports, 1)
+	report := db.reports[0]
+
+	require.Equal(t, []itypes.ReconciliationTotal{
+		{Asset: usdc, LedgerCollected: 600, InFlight: 300, OnChainCollected: 100},
+	}, report.Totals)
+
+	var kinds []itypes.DiscrepancyKind
+	for _, d := range report.Discrepancies {
+		kinds = append(kinds, d.Kind)
+	}
+	require.Equal(t, []itypes.DiscrepancyKind{
+		itypes.DiscrepancyUnpostedFee,
+		itypes.DiscrepancyStatusMismatch,
+		itypes.DiscrepancyAmountMismatch,
+		itypes.DiscrepancyMissingOnChainTx,
+		itypes.DiscrepancyTotalMismatch,
+	}, kinds)
+	require.Equal(t, uint64(42), report.Discrepancies[0].ReferenceID)
+	require.Equal(t, uint64(2), report.Discrepancies[1].BatchID)
+	require.Equal(t, uint64(4), report.Discrepancies[3].BatchID)
+}
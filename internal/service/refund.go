@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5"
+	"github.com/sirupsen/logrus"
+
+	"github.com/vultisig/verifier/types"
+)
+
+// subscriptionRefundLookback bounds the subscription fees looked at, a monthly period ended
+// within the last month was charged less than two months ago
+const subscriptionRefundLookback = 62 * 24 * time.Hour
+
+type RefundServiceStorage interface {
+	WithTransaction(ctx context.Context, fn func(ctx context.Context, tx pgx.Tx) error) error
+	RefundFee(ctx context.Context, dbTx pgx.Tx, refund types.Refund) (uint64, error)
+	GetIdleSubscriptionFees(ctx context.Context, since, now time.Time) ([]*types.Fee, error)
+}
+
+type RefundService struct {
+	db     RefundServiceStorage
+	logger *logrus.Logger
+	now    func() time.Time
+}
+
+func NewRefundService(db RefundServiceStorage, logger *logrus.Logger) (*RefundService, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database storage cannot be nil")
+	}
+	return &RefundService{
+		db:     db,
+		logger: logger.WithField("service", "refund").Logger,
+		now:    time.Now,
+	}, nil
+}
+
+// Refund credits back a debit, it returns the ID of the credit, 0 when the debit was already refunded
+func (s *RefundService) Refund(ctx context.Context, refund types.Refund) (uint64, error) {
+	var creditID uint64
+	err := s.db.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		var err error
+		creditID, err = s.db.RefundFee(ctx, tx, refund)
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to refund fee %d: %w", refund.DebitFeeID, err)
+	}
+	return creditID, nil
+}
+
+// HandleSubscriptionRefunds refunds the subscription periods which ended without a successful execution
+func (s *RefundService) HandleSubscriptionRefunds(ctx context.Context, _ *asynq.Task) error {
+	now := s.now()
+	fees, err := s.db.GetIdleSubscriptionFees(ctx, now.Add(-subscriptionRefundLookback), now)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to get idle subscription fees")
+		return fmt.Errorf("failed to get idle subscription fees: %w", err)
+	}
+
+	for _, fee := range fees {
+		_, err := s.Refund(ctx, types.Refund{
+			DebitFeeID: fee.ID,
+			Reason:     types.RefundReasonNoExecutions,
+			Note:       fmt.Sprintf("no successful execution of policy %s in the subscription period", fee.UnderlyingID),
+		})
+		if err != nil {
+			s.logger.WithError(err).WithField("fee_id", fee.ID).Error("Failed to refund idle subscription")
+			return err
+		}
+		s.logger.WithFields(logrus.Fields{
+			"fee_id":    fee.ID,
+			"policy_id": fee.UnderlyingID,
+			"amount":    fee.Amount,
+		}).Info("Refunded idle subscription period")
+	}
+	return nil
+}
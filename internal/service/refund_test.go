@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vultisig/verifier/types"
+)
+
+type fakeRefundStorage struct {
+	idle    []*types.Fee
+	refunds map[uint64]types.Refund
+	since   time.Time
+}
+
+func (f *fakeRefundStorage) WithTransaction(ctx context.Context, fn func(ctx context.Context, tx pgx.Tx) error) error {
+	return fn(ctx, nil)
+}
+
+func (f *fakeRefundStorage) RefundFee(_ context.Context, _ pgx.Tx, refund types.Refund) (uint64, error) {
+	if !refund.Reason.IsValid() {
+		return 0, errors.New("invalid reason")
+	}
+	if _, ok := f.refunds[refund.DebitFeeID]; ok {
+		return 0, nil
+	}
+	f.refunds[refund.DebitFeeID] = refund
+	return refund.DebitFeeID + 1000, nil
+}
+
+func (f *fakeRefundStorage) GetIdleSubscriptionFees(_ context.Context, since, _ time.Time) ([]*types.Fee, error) {
+	f.since = since
+	var idle []*types.Fee
+	for _, fee := range f.idle {
+		if _, ok := f.refunds[fee.ID]; !ok {
+			idle = append(idle, fee)
+		}
+	}
+	return idle, nil
+}
+
+func TestHandleSubscriptionRefunds(t *testing.T) {
+	now := time.Date(2026, time.October, 1, 2, 0, 0, 0, time.UTC)
+	db := &fakeRefundStorage{
+		idle: []*types.Fee{
+			{ID: 1, Amount: 500, FeeType: types.FeeSubscriptionFee, UnderlyingID: "policy-a"},
+			{ID: 2, Amount: 900, FeeType: types.FeeSubscriptionFee, UnderlyingID: "policy-b"},
+		},
+		refunds: make(map[uint64]types.Refund),
+	}
+	svc, err := NewRefundService(db, logrus.New())
+	require.NoError(t, err)
+	svc.now = func() time.Time { return now }
+
+	require.NoError(t, svc.HandleSubscriptionRefunds(context.Background(), nil))
+	require.Equal(t, now.Add(-subscriptionRefundLookback), db.since)
+	require.Len(t, db.refunds, 2)
+	require.Equal(t, types.RefundReasonNoExecutions, db.refunds[1].Reason)
+	require.Zero(t, db.refunds[1].Amount)
+	require.Contains(t, db.refunds[2].Note, "policy-b")
+
+	// a refunded debit isn't refunded twice
+	require.NoError(t, svc.HandleSubscriptionRefunds(context.Background(), nil))
+	creditID, err := svc.Refund(context.Background(), types.Refund{
+		DebitFeeID: 1,
+		Reason:     types.RefundReasonGoodwill,
+	})
+	require.NoError(t, err)
+	require.Zero(t, creditID)
+	require.Equal(t, types.RefundReasonNoExecutions, db.refunds[1].Reason)
+
+	_, err = svc.Refund(context.Background(), types.Refund{DebitFeeID: 3, Reason: "because"})
+	require.Error(t, err)
+}
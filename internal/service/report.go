@@ -15,8 +15,10 @@ import (
 )
 
 var (
-	ErrNotEligible    = errors.New("not eligible to report: no installation found")
-	ErrCooldownActive = errors.New("cooldown active")
+	ErrNotEligible     = errors.New("not eligible to report: no installation found")
+	ErrCooldownActive  = errors.New("cooldown active")
+	ErrReportNotFound  = errors.New("report not found")
+	ErrPluginNotPaused = errors.New("plugin is not paused")
 )
 
 type ReportServiceStorage interface {
@@ -26,29 +28,28 @@ type ReportServiceStorage interface {
 	HasInstallation(ctx context.Context, pluginID types.PluginID, publicKey string) (bool, error)
 	CountInstallations(ctx context.Context, pluginID types.PluginID) (int, error)
 	IsPluginPaused(ctx context.Context, pluginID types.PluginID) (bool, error)
-	PausePlugin(ctx context.Context, pluginID types.PluginID, record itypes.PauseHistoryRecord) error
-}
-
-type SafetySyncer interface {
-	SyncSafetyToPlugin(ctx context.Context, pluginID types.PluginID, flags []psafety.ControlFlag) error
+	ReviewReport(ctx context.Context, pluginID types.PluginID, publicKey string, status itypes.ReportStatus, reviewedBy, note string) (*itypes.PluginReport, error)
 }
 
 type ReportService struct {
 	db     ReportServiceStorage
-	syncer SafetySyncer
+	flags  ControlFlagChanger
 	logger *logrus.Logger
 }
 
-func NewReportService(db ReportServiceStorage, syncer SafetySyncer, logger *logrus.Logger) (*ReportService, error) {
+func NewReportService(db ReportServiceStorage, flags ControlFlagChanger, logger *logrus.Logger) (*ReportService, error) {
 	if db == nil {
 		return nil, fmt.Errorf("database storage cannot be nil")
 	}
+	if flags == nil {
+		return nil, fmt.Errorf("control flag changer cannot be nil")
+	}
 	if logger == nil {
 		return nil, fmt.Errorf("logger cannot be nil")
 	}
 	return &ReportService{
 		db:     db,
-		syncer: syncer,
+		flags:  flags,
 		logger: logger,
 	}, nil
 }
@@ -148,29 +149,95 @@ func (s *ReportService) evaluateAndPause(ctx context.Context, pluginID types.Plu
 	reason := fmt.Sprintf("%d reports (%.1f%%) exceeded threshold (%.1f%%)", reportsInWindow, rate*100, threshold*100)
 	triggeredBy := "system"
 
-	err = s.db.PausePlugin(ctx, pluginID, itypes.PauseHistoryRecord{
-		PluginID:          pluginID,
-		Action:            "auto_paused",
-		ReportCountWindow: &reportCount,
-		ActiveUsers:       &users,
-		ThresholdRate:     &thresholdRate,
-		Reason:            &reason,
-		TriggeredBy:       &triggeredBy,
+	_, err = s.flags.Change(ctx, itypes.ControlFlagChange{
+		Flags: pauseFlags(pluginID, false),
+		Record: &itypes.PauseHistoryRecord{
+			PluginID:          pluginID,
+			Action:            itypes.PauseActionAutoPaused,
+			ReportCountWindow: &reportCount,
+			ActiveUsers:       &users,
+			ThresholdRate:     &thresholdRate,
+			Reason:            &reason,
+			TriggeredBy:       &triggeredBy,
+		},
+		SyncTo: []types.PluginID{pluginID},
 	})
 	if err != nil {
 		return fmt.Errorf("failed to pause plugin: %w", err)
 	}
 
-	if s.syncer != nil {
-		flags := []psafety.ControlFlag{
-			{Key: psafety.KeysignFlagKey(string(pluginID)), Enabled: false},
-			{Key: psafety.KeygenFlagKey(string(pluginID)), Enabled: false},
-		}
-		syncErr := s.syncer.SyncSafetyToPlugin(ctx, pluginID, flags)
-		if syncErr != nil {
-			s.logger.WithError(syncErr).WithField("plugin_id", pluginID).Warn("failed to sync safety to plugin")
-		}
+	return nil
+}
+
+// ReviewReport records an admin decision on a report, invalid reports no longer count towards auto-pause
+func (s *ReportService) ReviewReport(ctx context.Context, pluginID types.PluginID, publicKey string, status itypes.ReportStatus, reviewedBy, note string) (*itypes.PluginReport, error) {
+	if !status.IsValid() {
+		return nil, fmt.Errorf("invalid report status: %s", status)
+	}
+
+	report, err := s.db.ReviewReport(ctx, pluginID, publicKey, status, reviewedBy, note)
+	if err != nil {
+		return nil, fmt.Errorf("failed to review report: %w", err)
+	}
+	if report == nil {
+		return nil, ErrReportNotFound
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"plugin_id":   pluginID,
+		"reporter":    publicKey,
+		"status":      status,
+		"reviewed_by": reviewedBy,
+	}).Info("report reviewed")
+
+	return report, nil
+}
+
+// UnpausePlugin resumes a plugin paused on reports or signing anomalies and records the decision in its pause history.
+// Invalid reports should be dismissed first, otherwise the next report pauses the plugin again.
+func (s *ReportService) UnpausePlugin(ctx context.Context, pluginID types.PluginID, unpausedBy, reason string) error {
+	reportsInWindow, err := s.db.CountReportsInWindow(ctx, pluginID, safety.ReportsWindowDuration)
+	if err != nil {
+		return fmt.Errorf("failed to count reports: %w", err)
+	}
+
+	activeUsers, err := s.db.CountInstallations(ctx, pluginID)
+	if err != nil {
+		return fmt.Errorf("failed to count installations: %w", err)
+	}
+
+	// a plugin is paused while any of its flags is disabled
+	changed, err := s.flags.Change(ctx, itypes.ControlFlagChange{
+		Flags: pauseFlags(pluginID, true),
+		Record: &itypes.PauseHistoryRecord{
+			PluginID:          pluginID,
+			Action:            itypes.PauseActionUnpaused,
+			ReportCountWindow: &reportsInWindow,
+			ActiveUsers:       &activeUsers,
+			Reason:            &reason,
+			TriggeredBy:       &unpausedBy,
+		},
+		SyncTo: []types.PluginID{pluginID},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to unpause plugin: %w", err)
+	}
+	if !changed {
+		return ErrPluginNotPaused
 	}
 
+	s.logger.WithFields(logrus.Fields{
+		"plugin_id":   pluginID,
+		"unpaused_by": unpausedBy,
+	}).Info("plugin unpaused")
+
 	return nil
 }
+
+// pauseFlags are the keysign and keygen flags of a plugin
+func pauseFlags(pluginID types.PluginID, enabled bool) []psafety.ControlFlag {
+	return []psafety.ControlFlag{
+		{Key: psafety.KeysignFlagKey(string(pluginID)), Enabled: enabled},
+		{Key: psafety.KeygenFlagKey(string(pluginID)), Enabled: enabled},
+	}
+}
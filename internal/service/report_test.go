@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	itypes "github.com/vultisig/verifier/internal/types"
+	psafety "github.com/vultisig/verifier/plugin/safety"
+	"github.com/vultisig/verifier/types"
+)
+
+type fakeReportStorage struct {
+	ReportServiceStorage
+	reports map[string]*itypes.PluginReport
+}
+
+func (f *fakeReportStorage) CountReportsInWindow(_ context.Context, _ types.PluginID, _ time.Duration) (int, error) {
+	count := 0
+	for _, report := range f.reports {
+		if report.Status != itypes.ReportInvalid {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (f *fakeReportStorage) CountInstallations(_ context.Context, _ types.PluginID) (int, error) {
+	return 100, nil
+}
+
+func (f *fakeReportStorage) ReviewReport(_ context.Context, _ types.PluginID, publicKey string, status itypes.ReportStatus, reviewedBy, _ string) (*itypes.PluginReport, error) {
+	report, ok := f.reports[publicKey]
+	if !ok {
+		return nil, nil
+	}
+	report.Status = status
+	report.ReviewedBy = &reviewedBy
+	return report, nil
+}
+
+func TestReportReview(t *testing.T) {
+	ctx := context.Background()
+	db := &fakeReportStorage{
+		reports: map[string]*itypes.PluginReport{
+			"spam":  {PluginID: "dca", ReporterPubKey: "spam", Status: itypes.ReportPending},
+			"valid": {PluginID: "dca", ReporterPubKey: "valid", Status: itypes.ReportPending},
+		},
+	}
+	// paused on a signing anomaly, keygen is still enabled
+	flagDB := &fakeControlFlagStorage{flags: map[string]bool{psafety.KeysignFlagKey("dca"): false}}
+	syncer := &fakeSafetySyncer{}
+	svc, err := NewReportService(db, newTestControlFlagService(t, flagDB, syncer), logrus.New())
+	require.NoError(t, err)
+
+	_, err = svc.ReviewReport(ctx, "dca", "spam", "DISMISSED", "admin", "")
+	require.Error(t, err)
+	_, err = svc.ReviewReport(ctx, "dca", "unknown", itypes.ReportInvalid, "admin", "")
+	require.ErrorIs(t, err, ErrReportNotFound)
+
+	report, err := svc.ReviewReport(ctx, "dca", "spam", itypes.ReportInvalid, "admin", "spam")
+	require.NoError(t, err)
+	require.Equal(t, itypes.ReportInvalid, report.Status)
+
+	require.NoError(t, svc.UnpausePlugin(ctx, "dca", "admin", "reports were spam"))
+	require.Len(t, flagDB.history, 1)
+	record := flagDB.history[0]
+	require.Equal(t, itypes.PauseActionUnpaused, record.Action)
+	require.Equal(t, "admin", *record.TriggeredBy)
+	require.Equal(t, "reports were spam", *record.Reason)
+	// the dismissed report is excluded
+	require.Equal(t, 1, *record.ReportCountWindow)
+	require.Equal(t, []psafety.ControlFlag{
+		{Key: psafety.KeysignFlagKey("dca"), Enabled: true},
+		{Key: psafety.KeygenFlagKey("dca"), Enabled: true},
+	}, syncer.flags)
+
+	require.ErrorIs(t, svc.UnpausePlugin(ctx, "dca", "admin", "again"), ErrPluginNotPaused)
+	require.Len(t, flagDB.history, 1)
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+
+	"github.com/vultisig/verifier/internal/metrics"
+	"github.com/vultisig/verifier/types"
+	"github.com/vultisig/verifier/vault"
+)
+
+const sessionStatusTTL = 30 * time.Minute
+
+type SessionStatusStorage interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value string, expiry time.Duration) error
+}
+
+// SessionStatusService publishes keygen and reshare phase transitions to Redis,
+// so the API can report the progress of a session the worker is running
+type SessionStatusService struct {
+	redis   SessionStatusStorage
+	metrics metrics.WorkerMetricsInterface
+	logger  *logrus.Logger
+}
+
+var _ vault.SessionReporter = (*SessionStatusService)(nil)
+
+func NewSessionStatusService(redis SessionStatusStorage, workerMetrics metrics.WorkerMetricsInterface, logger *logrus.Logger) (*SessionStatusService, error) {
+	if redis == nil {
+		return nil, fmt.Errorf("redis storage cannot be nil")
+	}
+	if logger == nil {
+		return nil, fmt.Errorf("logger cannot be nil")
+	}
+	if workerMetrics == nil {
+		workerMetrics = metrics.NewNoOpWorkerMetrics()
+	}
+	return &SessionStatusService{
+		redis:   redis,
+		metrics: workerMetrics,
+		logger:  logger,
+	}, nil
+}
+
+func sessionStatusKey(sessionID string) string {
+	return "session_status:" + sessionID
+}
+
+func sessionRequesterKey(sessionID string) string {
+	return "session_requester:" + sessionID
+}
+
+// SetSessionRequester records the vault that requested a session, it may read the status of the session
+// before the session is bound to a vault
+func (s *SessionStatusService) SetSessionRequester(ctx context.Context, sessionID, publicKey string) error {
+	err := s.redis.Set(ctx, sessionRequesterKey(sessionID), publicKey, sessionStatusTTL)
+	if err != nil {
+		return fmt.Errorf("failed to store session requester: %w", err)
+	}
+	return nil
+}
+
+// GetSessionRequester returns an empty string if the requester of the session is unknown or has expired
+func (s *SessionStatusService) GetSessionRequester(ctx context.Context, sessionID string) (string, error) {
+	value, err := s.redis.Get(ctx, sessionRequesterKey(sessionID))
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get session requester: %w", err)
+	}
+	return value, nil
+}
+
+func (s *SessionStatusService) ReportSessionStatus(ctx context.Context, status types.SessionStatus) {
+	// every applied message is a round, only the first one is worth a metric
+	if status.Phase != types.SessionPhaseRound || status.Round == 1 {
+		s.metrics.RecordSessionPhase(status.Operation, string(status.Phase), status.UpdatedAt.Sub(status.StartedAt).Seconds())
+	}
+
+	buf, err := json.Marshal(status)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to marshal session status")
+		return
+	}
+	if err := s.redis.Set(ctx, sessionStatusKey(status.SessionID), string(buf), sessionStatusTTL); err != nil {
+		s.logger.WithError(err).WithFields(logrus.Fields{
+			"session_id": status.SessionID,
+			"phase":      status.Phase,
+		}).Error("failed to store session status")
+	}
+}
+
+// GetSessionStatus returns nil if the session is unknown or its status has expired
+func (s *SessionStatusService) GetSessionStatus(ctx context.Context, sessionID string) (*types.SessionStatus, error) {
+	value, err := s.redis.Get(ctx, sessionStatusKey(sessionID))
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get session status: %w", err)
+	}
+	var status types.SessionStatus
+	if err := json.Unmarshal([]byte(value), &status); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal session status: %w", err)
+	}
+	return &status, nil
+}
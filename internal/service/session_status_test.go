@@ -0,0 +1,76 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vultisig/verifier/internal/service"
+	"github.com/vultisig/verifier/types"
+)
+
+type memorySessionStorage struct {
+	values map[string]string
+}
+
+func (m *memorySessionStorage) Get(ctx context.Context, key string) (string, error) {
+	value, ok := m.values[key]
+	if !ok {
+		return "", redis.Nil
+	}
+	return value, nil
+}
+
+func (m *memorySessionStorage) Set(ctx context.Context, key string, value string, expiry time.Duration) error {
+	m.values[key] = value
+	return nil
+}
+
+func TestSessionStatusService_ReportAndGet(t *testing.T) {
+	svc, err := service.NewSessionStatusService(&memorySessionStorage{values: map[string]string{}}, nil, testLogger)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	status, err := svc.GetSessionStatus(ctx, "unknown")
+	require.NoError(t, err)
+	require.Nil(t, status)
+
+	started := time.Now().UTC()
+	for _, update := range []types.SessionStatus{
+		{Phase: types.SessionPhaseRegistered},
+		{Phase: types.SessionPhaseRound, Key: "ecdsa", Round: 3},
+		{Phase: types.SessionPhaseSaved, PublicKey: testPublicKey},
+	} {
+		update.SessionID = "session"
+		update.Operation = types.SessionOperationReshare
+		update.StartedAt = started
+		update.UpdatedAt = time.Now().UTC()
+		svc.ReportSessionStatus(ctx, update)
+
+		status, err = svc.GetSessionStatus(ctx, "session")
+		require.NoError(t, err)
+		require.NotNil(t, status)
+		require.Equal(t, update.Phase, status.Phase)
+		require.Equal(t, update.Round, status.Round)
+	}
+	require.True(t, status.IsFinal())
+	require.Equal(t, testPublicKey, status.PublicKey)
+}
+
+func TestSessionStatusService_Requester(t *testing.T) {
+	svc, err := service.NewSessionStatusService(&memorySessionStorage{values: map[string]string{}}, nil, testLogger)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	requester, err := svc.GetSessionRequester(ctx, "session")
+	require.NoError(t, err)
+	require.Empty(t, requester)
+
+	require.NoError(t, svc.SetSessionRequester(ctx, "session", testPublicKey))
+	requester, err = svc.GetSessionRequester(ctx, "session")
+	require.NoError(t, err)
+	require.Equal(t, testPublicKey, requester)
+}
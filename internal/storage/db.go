@@ -32,6 +32,8 @@ type DatabaseStorage interface {
 	ProposedPluginRepository
 	PluginOwnerRepository
 	PluginImageRepository
+	PluginHealthRepository
+	PluginSigningKeyRepository
 	FeeRepository
 	TagRepository
 	ReviewRepository
@@ -39,6 +41,14 @@ type DatabaseStorage interface {
 	ApiKeyRepository
 	ReportRepository
 	ControlFlagsRepository
+	ControlFlagChangeRepository
+	PresignRepository
+	KeysignResultRepository
+	InvoiceRepository
+	DunningRepository
+	LedgerRepository
+	PayoutRepository
+	AnomalyRepository
 	Close() error
 }
 
@@ -65,6 +75,52 @@ type FeeRepository interface {
 	GetUserFees(ctx context.Context, publicKey string) (*types.UserFeeStatus, error)
 	UpdateBatchStatus(ctx context.Context, dbTx pgx.Tx, txHash string, status *rpc.TxOnChainStatus) error
 	IsTrialActive(ctx context.Context, dbTx pgx.Tx, pubKey string) (bool, time.Duration, error)
+	RefundFee(ctx context.Context, dbTx pgx.Tx, refund types.Refund) (uint64, error)
+	GetUnrefundedTxFees(ctx context.Context, since time.Time) ([]itypes.BilledTxFee, error)
+	UpsertUnbilledTx(ctx context.Context, tx itypes.UnbilledTx) error
+	GetDueUnbilledTxs(ctx context.Context, now time.Time, limit int) ([]itypes.UnbilledTx, error)
+	DeleteUnbilledTx(ctx context.Context, dbTx pgx.Tx, txID uuid.UUID) error
+	GetIdleSubscriptionFees(ctx context.Context, since, now time.Time) ([]*types.Fee, error)
+	GetSubscriptionsToBill(ctx context.Context, now time.Time) ([]itypes.Subscription, error)
+}
+
+type InvoiceRepository interface {
+	GetInvoicePublicKeys(ctx context.Context, start, end time.Time) ([]string, error)
+	GetInvoiceData(ctx context.Context, publicKey string, start, end time.Time) (itypes.InvoiceData, error)
+	InsertInvoice(ctx context.Context, invoice *itypes.Invoice) (bool, error)
+	GetInvoices(ctx context.Context, publicKey string, skip, take uint32) ([]itypes.Invoice, uint32, error)
+	GetInvoice(ctx context.Context, publicKey string, id uuid.UUID) (*itypes.Invoice, error)
+}
+
+type DunningRepository interface {
+	GetUnpaidAccounts(ctx context.Context, publicKey string, dueBefore time.Time) ([]itypes.UnpaidAccount, error)
+	GetDunningStates(ctx context.Context, publicKey string) ([]itypes.DunningState, error)
+	UpsertDunningState(ctx context.Context, dbTx pgx.Tx, state itypes.DunningState) error
+	DeleteDunningState(ctx context.Context, dbTx pgx.Tx, publicKey string) error
+	InsertDunningEvent(ctx context.Context, dbTx pgx.Tx, event itypes.DunningEvent) error
+}
+
+type LedgerRepository interface {
+	GetLedgerBalances(ctx context.Context, accountType itypes.LedgerAccountType, accountID string) ([]itypes.LedgerBalance, error)
+	GetBatchCollections(ctx context.Context) ([]itypes.BatchCollection, error)
+	GetLedgerIntegrityIssues(ctx context.Context) ([]uint64, []uint64, error)
+	InsertReconciliationReport(ctx context.Context, report *itypes.ReconciliationReport) error
+	GetReconciliationReports(ctx context.Context, skip, take uint32) ([]itypes.ReconciliationReport, uint32, error)
+}
+
+type PayoutRepository interface {
+	GetPayableFees(ctx context.Context, periodEnd time.Time) ([]itypes.PayableFee, error)
+	InsertDeveloperPayouts(ctx context.Context, dbTx pgx.Tx, payouts []*itypes.DeveloperPayout) error
+	GetOpenPayouts(ctx context.Context, staleBefore time.Time) ([]itypes.DeveloperPayout, error)
+	ClaimPayout(ctx context.Context, id uint64, staleBefore time.Time) (*itypes.DeveloperPayout, error)
+	UpdatePayoutStatus(ctx context.Context, dbTx pgx.Tx, keysignPolicyID uuid.UUID, txHash string, status *rpc.TxOnChainStatus) (bool, error)
+	GetDeveloperPayouts(ctx context.Context, ownerPublicKey string, pluginID string, skip, take uint32) ([]itypes.DeveloperPayout, uint32, error)
+}
+
+type AnomalyRepository interface {
+	InsertKeysignRejection(ctx context.Context, pluginID types.PluginID, policyID uuid.UUID, reason string) error
+	DeleteKeysignRejectionsBefore(ctx context.Context, before time.Time) (int64, error)
+	GetSigningStats(ctx context.Context, window, baseline time.Duration) ([]itypes.SigningStats, error)
 }
 
 type PluginPolicySyncRepository interface {
@@ -73,6 +129,11 @@ type PluginPolicySyncRepository interface {
 	DeletePluginPolicySync(ctx context.Context, id uuid.UUID) error
 	GetUnFinishedPluginPolicySyncs(ctx context.Context) ([]itypes.PluginPolicySync, error)
 	UpdatePluginPolicySync(ctx context.Context, dbTx pgx.Tx, policy itypes.PluginPolicySync) error
+	ClaimPolicySyncs(ctx context.Context, lease time.Duration, limit int) ([]itypes.PluginPolicySync, error)
+	MarkPolicySyncDelivered(ctx context.Context, id uuid.UUID) error
+	MarkPolicySyncFailed(ctx context.Context, id uuid.UUID, reason string, nextAttemptAt time.Time) error
+	MarkPolicySyncDeadLettered(ctx context.Context, id uuid.UUID, reason string) error
+	GetPolicySyncs(ctx context.Context, policyID uuid.UUID) ([]itypes.PluginPolicySync, error)
 }
 
 type PricingRepository interface {
@@ -86,10 +147,25 @@ type PluginRepository interface {
 	FindPlugins(ctx context.Context, filters itypes.PluginFilters, take int, skip int, sort string) (*itypes.PluginsPaginatedList, error)
 	FindPluginById(ctx context.Context, dbTx pgx.Tx, id types.PluginID) (*itypes.Plugin, error)
 	GetPluginTitlesByIDs(ctx context.Context, ids []string) (map[string]string, error)
+	GetPluginIDs(ctx context.Context) ([]types.PluginID, error)
+	ListenPluginServerInfo(ctx context.Context, fn func(types.PluginID)) error
 
 	Pool() *pgxpool.Pool
 }
 
+type PluginHealthRepository interface {
+	GetPluginEndpoints(ctx context.Context) (map[types.PluginID]string, error)
+	UpsertPluginHealth(ctx context.Context, health itypes.PluginHealth) error
+	GetPluginHealth(ctx context.Context, pluginIDs []types.PluginID) (map[types.PluginID]itypes.PluginHealth, error)
+}
+
+type PluginSigningKeyRepository interface {
+	CreatePluginSigningKey(ctx context.Context, key itypes.PluginSigningKey, overlap time.Duration) (*itypes.PluginSigningKey, error)
+	GetPluginSigningKeys(ctx context.Context, pluginID types.PluginID) ([]itypes.PluginSigningKey, error)
+	GetPluginSigningKey(ctx context.Context, keyID string) (*itypes.PluginSigningKey, error)
+	RevokePluginSigningKey(ctx context.Context, pluginID types.PluginID, keyID string) (bool, error)
+}
+
 type ProposedPluginRepository interface {
 	IsProposedPluginApproved(ctx context.Context, pluginID string) (bool, error)
 }
@@ -109,8 +185,10 @@ type PluginImageRepository interface {
 
 type VaultTokenRepository interface {
 	CreateVaultToken(ctx context.Context, token itypes.VaultTokenCreate) (*itypes.VaultToken, error)
+	RotateVaultToken(ctx context.Context, tokenID string, next itypes.VaultTokenCreate) (*itypes.VaultToken, error)
 	GetVaultToken(ctx context.Context, tokenID string) (*itypes.VaultToken, error)
 	RevokeVaultToken(ctx context.Context, tokenID string) error
+	IsVaultTokenFamilyRevoked(ctx context.Context, familyID string) (bool, error)
 	RevokeAllVaultTokens(ctx context.Context, publicKey string) error
 	UpdateVaultTokenLastUsed(ctx context.Context, tokenID string) error
 	GetActiveVaultTokens(ctx context.Context, publicKey string) ([]itypes.VaultToken, error)
@@ -137,11 +215,38 @@ type RatingRepository interface {
 
 type ApiKeyRepository interface {
 	GetAPIKey(ctx context.Context, apiKey string) (*itypes.APIKey, error)
-	GetAPIKeyByPluginId(ctx context.Context, pluginId string) (*itypes.APIKey, error)
+	TouchAPIKey(ctx context.Context, id string, ip string) error
+}
+
+// PresignRepository satisfies vault.PresignStore
+type PresignRepository interface {
+	SavePresign(ctx context.Context, presign types.Presign) error
+	ConsumePresign(ctx context.Context, publicKey, pluginID, id string) (*types.Presign, error)
+	ListPresignIDs(ctx context.Context, publicKey, pluginID, derivePath string, limit int) ([]string, error)
+	CountPresigns(ctx context.Context, publicKey, pluginID, derivePath string) (int, error)
+	DeletePresigns(ctx context.Context, publicKey, pluginID string) (int64, error)
+}
+
+// KeysignResultRepository satisfies vault.KeysignResultStore
+type KeysignResultRepository interface {
+	CreateKeysignResult(ctx context.Context, result types.KeysignResult) error
+	UpdateKeysignResult(ctx context.Context, txIndexerID uuid.UUID, status types.KeysignResultStatus, failureReason string, data []byte) error
+	GetKeysignResult(ctx context.Context, txIndexerID uuid.UUID) (*types.KeysignResult, error)
+	DeleteExpiredKeysignResults(ctx context.Context) (int64, error)
 }
 
 type ControlFlagsRepository interface {
 	GetControlFlags(ctx context.Context, k1, k2 string) (map[string]bool, error)
+	GetControlFlagsByKeys(ctx context.Context, keys []string) (map[string]bool, error)
+	ListControlFlags(ctx context.Context) ([]itypes.ControlFlag, error)
+}
+
+type ControlFlagChangeRepository interface {
+	ApplyControlFlagChange(ctx context.Context, change itypes.ControlFlagChange) ([]itypes.ControlFlagOutboxEntry, bool, error)
+	GetPendingControlFlagOutbox(ctx context.Context, limit int) ([]itypes.ControlFlagOutboxEntry, error)
+	MarkControlFlagOutboxDelivered(ctx context.Context, id int64) error
+	MarkControlFlagOutboxFailed(ctx context.Context, id int64, lastError string, nextAttemptAt time.Time) error
+	DeleteDeliveredControlFlagOutboxBefore(ctx context.Context, before time.Time) (int64, error)
 }
 
 type ReportRepository interface {
@@ -151,5 +256,7 @@ type ReportRepository interface {
 	HasInstallation(ctx context.Context, pluginID types.PluginID, publicKey string) (bool, error)
 	CountInstallations(ctx context.Context, pluginID types.PluginID) (int, error)
 	IsPluginPaused(ctx context.Context, pluginID types.PluginID) (bool, error)
-	PausePlugin(ctx context.Context, pluginID types.PluginID, record itypes.PauseHistoryRecord) error
+	GetPauseHistory(ctx context.Context, pluginID types.PluginID, skip, take uint32) ([]itypes.PauseHistoryRecord, uint32, error)
+	ListReports(ctx context.Context, pluginID types.PluginID, status itypes.ReportStatus, skip, take uint32) ([]itypes.PluginReport, uint32, error)
+	ReviewReport(ctx context.Context, pluginID types.PluginID, publicKey string, status itypes.ReportStatus, reviewedBy, note string) (*itypes.PluginReport, error)
 }
@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	itypes "github.com/vultisig/verifier/internal/types"
+	"github.com/vultisig/verifier/types"
+)
+
+func (p *PostgresBackend) InsertKeysignRejection(ctx context.Context, pluginID types.PluginID, policyID uuid.UUID, reason string) error {
+	_, err := p.pool.Exec(ctx, `
+		INSERT INTO keysign_rejections (plugin_id, policy_id, reason)
+		VALUES ($1, $2, $3)`,
+		pluginID, policyID, reason)
+	if err != nil {
+		return fmt.Errorf("failed to insert keysign rejection: %w", err)
+	}
+	return nil
+}
+
+func (p *PostgresBackend) DeleteKeysignRejectionsBefore(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := p.pool.Exec(ctx, `DELETE FROM keysign_rejections WHERE created_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete keysign rejections: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// GetSigningStats returns the signing activity of the plugins active in the window and in the baseline before it.
+// Payouts are signed by the verifier and left out, so are the plugins unpaused during the window.
+func (p *PostgresBackend) GetSigningStats(ctx context.Context, window, baseline time.Duration) ([]itypes.SigningStats, error) {
+	windowStr := fmt.Sprintf("%d seconds", int64(window.Seconds()))
+	sinceStr := fmt.Sprintf("%d seconds", int64((window + baseline).Seconds()))
+
+	rows, err := p.pool.Query(ctx, `
+		WITH requests AS (
+			SELECT t.plugin_id::text AS plugin_id,
+			       t.created_at >= NOW() - $1::interval AS recent,
+			       false AS rejected,
+			       t.status_onchain,
+			       t.to_public_key::text AS to_public_key
+			FROM tx_indexer t
+			WHERE t.created_at >= NOW() - $2::interval
+			  AND NOT EXISTS (SELECT 1 FROM developer_payouts dp WHERE dp.keysign_policy_id = t.policy_id)
+			UNION ALL
+			SELECT r.plugin_id::text, r.created_at >= NOW() - $1::interval, true, NULL, NULL
+			FROM keysign_rejections r
+			WHERE r.created_at >= NOW() - $2::interval
+		),
+		flagged AS (
+			SELECT q.*,
+			       q.recent AND NOT q.rejected AND NOT EXISTS (
+			           SELECT 1 FROM requests b
+			           WHERE b.plugin_id = q.plugin_id
+			             AND NOT b.recent AND NOT b.rejected
+			             AND b.to_public_key = q.to_public_key
+			       ) AS new_recipient
+			FROM requests q
+		)
+		SELECT f.plugin_id,
+		       COUNT(*) FILTER (WHERE f.recent),
+		       COUNT(*) FILTER (WHERE f.recent AND f.rejected),
+		       COUNT(*) FILTER (WHERE f.recent AND f.status_onchain IN ('SUCCESS', 'FAIL')),
+		       COUNT(*) FILTER (WHERE f.recent AND f.status_onchain = 'FAIL'),
+		       COUNT(*) FILTER (WHERE NOT f.recent),
+		       COUNT(*) FILTER (WHERE NOT f.recent AND f.rejected),
+		       COUNT(*) FILTER (WHERE NOT f.recent AND f.status_onchain IN ('SUCCESS', 'FAIL')),
+		       COUNT(*) FILTER (WHERE NOT f.recent AND f.status_onchain = 'FAIL'),
+		       COUNT(*) FILTER (WHERE f.new_recipient)
+		FROM flagged f
+		WHERE NOT EXISTS (
+			SELECT 1 FROM plugin_pause_history h
+			WHERE h.plugin_id = f.plugin_id AND h.action = $3 AND h.created_at >= NOW() - $1::interval
+		)
+		GROUP BY f.plugin_id
+		HAVING COUNT(*) FILTER (WHERE f.recent) > 0
+		ORDER BY f.plugin_id`,
+		windowStr, sinceStr, itypes.PauseActionUnpaused)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query signing stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := make([]itypes.SigningStats, 0)
+	for rows.Next() {
+		var s itypes.SigningStats
+		err = rows.Scan(
+			&s.PluginID,
+			&s.Window.Requests,
+			&s.Window.Rejections,
+			&s.Window.Resolved,
+			&s.Window.Failed,
+			&s.Baseline.Requests,
+			&s.Baseline.Rejections,
+			&s.Baseline.Resolved,
+			&s.Baseline.Failed,
+			&s.NewRecipients,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan signing stats: %w", err)
+		}
+		stats = append(stats, s)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate signing stats: %w", err)
+	}
+	return stats, nil
+}
@@ -4,51 +4,72 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/jackc/pgx/v5"
+
+	"github.com/vultisig/verifier/internal/apikey"
 	"github.com/vultisig/verifier/internal/types"
 )
 
+// apiKeyTouchInterval throttles the last used updates of a key
+const apiKeyTouchInterval = "1 minute"
+
+// GetAPIKey finds the key by its prefix and checks it against the stored hash, pgx.ErrNoRows is wrapped when no key matches
 func (p *PostgresBackend) GetAPIKey(ctx context.Context, apiKey string) (*types.APIKey, error) {
 	query := `
 		SELECT 
 			id, 
 			plugin_id, 
-			apikey, 
+			key_prefix, 
+			key_salt, 
+			key_hash, 
 			status, 
-			expires_at
+			scopes, 
+			allowed_ips, 
+			expires_at, 
+			last_used_at
 		FROM plugin_apikey
-		WHERE apikey = $1
+		WHERE key_prefix = $1
 	`
-	var key types.APIKey
-	if err := p.pool.QueryRow(ctx, query, apiKey).Scan(
-		&key.ID,
-		&key.PluginID,
-		&key.ApiKey,
-		&key.Status,
-		&key.ExpiresAt); err != nil {
+	rows, err := p.pool.Query(ctx, query, apikey.Prefix(apiKey))
+	if err != nil {
 		return nil, fmt.Errorf("fail to get API key: %w", err)
 	}
-	return &key, nil
-}
+	defer rows.Close()
 
-func (p *PostgresBackend) GetAPIKeyByPluginId(ctx context.Context, pluginId string) (*types.APIKey, error) {
-	query := `
-		SELECT 
-			id, 
-			plugin_id, 
-			apikey, 
-			status, 
-			expires_at
-		FROM plugin_apikey
-		WHERE plugin_id = $1
-	`
-	var key types.APIKey
-	if err := p.pool.QueryRow(ctx, query, pluginId).Scan(
-		&key.ID,
-		&key.PluginID,
-		&key.ApiKey,
-		&key.Status,
-		&key.ExpiresAt); err != nil {
+	for rows.Next() {
+		var key types.APIKey
+		if err := rows.Scan(
+			&key.ID,
+			&key.PluginID,
+			&key.Prefix,
+			&key.Salt,
+			&key.Hash,
+			&key.Status,
+			&key.Scopes,
+			&key.AllowedIPs,
+			&key.ExpiresAt,
+			&key.LastUsedAt); err != nil {
+			return nil, fmt.Errorf("fail to scan API key: %w", err)
+		}
+		if apikey.Matches(apiKey, key.Salt, key.Hash) {
+			return &key, nil
+		}
+	}
+	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("fail to get API key: %w", err)
 	}
-	return &key, nil
+	return nil, fmt.Errorf("fail to get API key: %w", pgx.ErrNoRows)
+}
+
+// TouchAPIKey records the last use of a key, at most once per apiKeyTouchInterval
+func (p *PostgresBackend) TouchAPIKey(ctx context.Context, id string, ip string) error {
+	_, err := p.pool.Exec(ctx, `
+		UPDATE plugin_apikey
+		SET last_used_at = NOW(), last_used_ip = $2
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - $3::interval)`,
+		id, ip, apiKeyTouchInterval)
+	if err != nil {
+		return fmt.Errorf("fail to update API key last use: %w", err)
+	}
+	return nil
 }
@@ -0,0 +1,164 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	itypes "github.com/vultisig/verifier/internal/types"
+	"github.com/vultisig/verifier/types"
+)
+
+// ApplyControlFlagChange sets the flags, records the change and queues it for the plugin servers in one transaction.
+// Nothing is recorded or queued when no flag changes, a missing flag counts as enabled.
+func (p *PostgresBackend) ApplyControlFlagChange(ctx context.Context, change itypes.ControlFlagChange) ([]itypes.ControlFlagOutboxEntry, bool, error) {
+	var entries []itypes.ControlFlagOutboxEntry
+	var changed bool
+	err := p.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		keys := make([]string, 0, len(change.Flags))
+		for _, flag := range change.Flags {
+			keys = append(keys, flag.Key)
+
+			query := `
+				INSERT INTO control_flags (key, enabled, updated_at)
+				VALUES ($1, false, NOW())
+				ON CONFLICT (key) DO UPDATE
+				SET enabled = false, updated_at = NOW()
+				WHERE control_flags.enabled`
+			if flag.Enabled {
+				query = `
+					UPDATE control_flags
+					SET enabled = true, updated_at = NOW()
+					WHERE key = $1 AND NOT enabled`
+			}
+			tag, err := tx.Exec(ctx, query, flag.Key)
+			if err != nil {
+				return fmt.Errorf("failed to set control flag %s: %w", flag.Key, err)
+			}
+			changed = changed || tag.RowsAffected() > 0
+		}
+		if !changed {
+			return nil
+		}
+
+		if change.Record != nil {
+			err := p.recordPauseHistoryTx(ctx, tx, *change.Record)
+			if err != nil {
+				return fmt.Errorf("failed to record pause history: %w", err)
+			}
+		}
+
+		for _, pluginID := range change.SyncTo {
+			entry := itypes.ControlFlagOutboxEntry{PluginID: pluginID, Keys: keys}
+			err := tx.QueryRow(ctx, `
+				INSERT INTO control_flag_outbox (plugin_id, keys)
+				VALUES ($1, $2)
+				RETURNING id, next_attempt_at, created_at`,
+				pluginID, keys).Scan(&entry.ID, &entry.NextAttemptAt, &entry.CreatedAt)
+			if err != nil {
+				return fmt.Errorf("failed to queue control flags for %s: %w", pluginID, err)
+			}
+			entries = append(entries, entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return entries, changed, nil
+}
+
+func (p *PostgresBackend) GetPendingControlFlagOutbox(ctx context.Context, limit int) ([]itypes.ControlFlagOutboxEntry, error) {
+	rows, err := p.pool.Query(ctx, `
+		SELECT id, plugin_id, keys, attempts, last_error, next_attempt_at, created_at
+		FROM control_flag_outbox
+		WHERE delivered_at IS NULL AND next_attempt_at <= NOW()
+		ORDER BY id
+		LIMIT $1`,
+		limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query control flag outbox: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]itypes.ControlFlagOutboxEntry, 0)
+	for rows.Next() {
+		var entry itypes.ControlFlagOutboxEntry
+		err = rows.Scan(
+			&entry.ID,
+			&entry.PluginID,
+			&entry.Keys,
+			&entry.Attempts,
+			&entry.LastError,
+			&entry.NextAttemptAt,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan control flag outbox entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate control flag outbox: %w", err)
+	}
+	return entries, nil
+}
+
+func (p *PostgresBackend) MarkControlFlagOutboxDelivered(ctx context.Context, id int64) error {
+	_, err := p.pool.Exec(ctx, `
+		UPDATE control_flag_outbox
+		SET delivered_at = NOW(), attempts = attempts + 1, last_error = NULL
+		WHERE id = $1`,
+		id)
+	if err != nil {
+		return fmt.Errorf("failed to mark control flag outbox entry delivered: %w", err)
+	}
+	return nil
+}
+
+func (p *PostgresBackend) MarkControlFlagOutboxFailed(ctx context.Context, id int64, lastError string, nextAttemptAt time.Time) error {
+	_, err := p.pool.Exec(ctx, `
+		UPDATE control_flag_outbox
+		SET attempts = attempts + 1, last_error = $2, next_attempt_at = $3
+		WHERE id = $1`,
+		id, lastError, nextAttemptAt)
+	if err != nil {
+		return fmt.Errorf("failed to mark control flag outbox entry failed: %w", err)
+	}
+	return nil
+}
+
+func (p *PostgresBackend) DeleteDeliveredControlFlagOutboxBefore(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := p.pool.Exec(ctx, `
+		DELETE FROM control_flag_outbox
+		WHERE delivered_at IS NOT NULL AND delivered_at < $1`,
+		before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete delivered control flag outbox entries: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// GetPluginIDs returns the IDs of every plugin, the plugin servers global flags are pushed to
+func (p *PostgresBackend) GetPluginIDs(ctx context.Context) ([]types.PluginID, error) {
+	rows, err := p.pool.Query(ctx, `SELECT id FROM plugins ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query plugin ids: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make([]types.PluginID, 0)
+	for rows.Next() {
+		var id types.PluginID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan plugin id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate plugin ids: %w", err)
+	}
+	return ids, nil
+}
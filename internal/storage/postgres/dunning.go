@@ -0,0 +1,122 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	itypes "github.com/vultisig/verifier/internal/types"
+)
+
+// GetUnpaidAccounts returns the vaults owing fees billed before dueBefore in any asset, the one of publicKey when it isn't empty.
+// Credits pay the oldest debits first, so the fees billed since dueBefore and not collected yet are not overdue.
+func (p *PostgresBackend) GetUnpaidAccounts(ctx context.Context, publicKey string, dueBefore time.Time) ([]itypes.UnpaidAccount, error) {
+	rows, err := p.pool.Query(ctx, `
+        SELECT public_key, SUM(overdue)::bigint
+        FROM (
+            SELECT
+                public_key,
+                asset,
+                SUM(CASE
+                    WHEN transaction_type = 'credit' THEN -amount
+                    WHEN created_at < $2 THEN amount
+                    ELSE 0
+                END) AS overdue
+            FROM fees
+            WHERE $1 = '' OR public_key = $1
+            GROUP BY public_key, asset
+        ) b
+        WHERE overdue > 0
+        GROUP BY public_key
+        ORDER BY public_key
+    `, publicKey, dueBefore)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query unpaid accounts: %w", err)
+	}
+	defer rows.Close()
+
+	var accounts []itypes.UnpaidAccount
+	for rows.Next() {
+		var account itypes.UnpaidAccount
+		if err := rows.Scan(&account.PublicKey, &account.Unpaid); err != nil {
+			return nil, fmt.Errorf("failed to scan unpaid account: %w", err)
+		}
+		accounts = append(accounts, account)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating unpaid accounts: %w", err)
+	}
+	return accounts, nil
+}
+
+// GetDunningStates returns the vaults in dunning, the one of publicKey when it isn't empty
+func (p *PostgresBackend) GetDunningStates(ctx context.Context, publicKey string) ([]itypes.DunningState, error) {
+	rows, err := p.pool.Query(ctx, `
+        SELECT public_key, stage, overdue_since, last_reminder_at, suspended_at
+        FROM dunning_states
+        WHERE $1 = '' OR public_key = $1
+        ORDER BY public_key
+    `, publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query dunning states: %w", err)
+	}
+	defer rows.Close()
+
+	var states []itypes.DunningState
+	for rows.Next() {
+		var state itypes.DunningState
+		err := rows.Scan(
+			&state.PublicKey,
+			&state.Stage,
+			&state.OverdueSince,
+			&state.LastReminderAt,
+			&state.SuspendedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan dunning state: %w", err)
+		}
+		states = append(states, state)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating dunning states: %w", err)
+	}
+	return states, nil
+}
+
+func (p *PostgresBackend) UpsertDunningState(ctx context.Context, dbTx pgx.Tx, state itypes.DunningState) error {
+	_, err := dbTx.Exec(ctx, `
+        INSERT INTO dunning_states (public_key, stage, overdue_since, last_reminder_at, suspended_at)
+        VALUES ($1, $2, $3, $4, $5)
+        ON CONFLICT (public_key) DO UPDATE SET
+            stage = EXCLUDED.stage,
+            overdue_since = EXCLUDED.overdue_since,
+            last_reminder_at = EXCLUDED.last_reminder_at,
+            suspended_at = EXCLUDED.suspended_at,
+            updated_at = NOW()
+    `, state.PublicKey, state.Stage, state.OverdueSince, state.LastReminderAt, state.SuspendedAt)
+	if err != nil {
+		return fmt.Errorf("failed to upsert dunning state: %w", err)
+	}
+	return nil
+}
+
+func (p *PostgresBackend) DeleteDunningState(ctx context.Context, dbTx pgx.Tx, publicKey string) error {
+	_, err := dbTx.Exec(ctx, `DELETE FROM dunning_states WHERE public_key = $1`, publicKey)
+	if err != nil {
+		return fmt.Errorf("failed to delete dunning state: %w", err)
+	}
+	return nil
+}
+
+func (p *PostgresBackend) InsertDunningEvent(ctx context.Context, dbTx pgx.Tx, event itypes.DunningEvent) error {
+	_, err := dbTx.Exec(ctx, `
+        INSERT INTO dunning_events (public_key, event_type, unpaid_amount)
+        VALUES ($1, $2, $3)
+    `, event.PublicKey, event.EventType, event.UnpaidAmount)
+	if err != nil {
+		return fmt.Errorf("failed to insert dunning event: %w", err)
+	}
+	return nil
+}
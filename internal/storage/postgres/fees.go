@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 
 	itypes "github.com/vultisig/verifier/internal/types"
@@ -17,8 +18,8 @@ import (
 const (
 	queryInsertPluginInstallation = `INSERT INTO fees (
         policy_id, plugin_id, public_key, transaction_type, amount,
-        fee_type, metadata, underlying_type, underlying_id
-    ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+        fee_type, metadata, underlying_type, underlying_id, asset
+    ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
     ON CONFLICT (underlying_id, public_key)
     WHERE fee_type = 'installation_fee' AND underlying_type = 'plugin'
     DO NOTHING
@@ -26,8 +27,8 @@ const (
     `
 	queryInsertTrial = `INSERT INTO fees (
             policy_id, plugin_id, public_key, transaction_type, amount,
-            fee_type, metadata, underlying_type, underlying_id
-        ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+            fee_type, metadata, underlying_type, underlying_id, asset
+        ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
         ON CONFLICT (public_key)
         WHERE fee_type = 'trial'
         DO NOTHING
@@ -35,8 +36,26 @@ const (
         `
 	queryInsertFee = `INSERT INTO fees (
             policy_id, plugin_id, public_key, transaction_type, amount,
-            fee_type, metadata, underlying_type, underlying_id
-        ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+            fee_type, metadata, underlying_type, underlying_id, asset
+        ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+        RETURNING id
+        `
+	queryInsertRefund = `INSERT INTO fees (
+            policy_id, plugin_id, public_key, transaction_type, amount,
+            fee_type, metadata, underlying_type, underlying_id, asset
+        ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+        ON CONFLICT (underlying_id)
+        WHERE fee_type = 'refund' AND underlying_type = 'fee'
+        DO NOTHING
+        RETURNING id
+        `
+	queryInsertSubscription = `INSERT INTO fees (
+            policy_id, plugin_id, public_key, transaction_type, amount,
+            fee_type, metadata, underlying_type, underlying_id, asset
+        ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+        ON CONFLICT (underlying_id, (metadata->>'period_start'))
+        WHERE fee_type = 'subscription_fee' AND underlying_type = 'policy'
+        DO NOTHING
         RETURNING id
         `
 	queryTrialStartDate = `SELECT created_at
@@ -56,22 +75,30 @@ func (p *PostgresBackend) InsertFee(ctx context.Context, dbTx pgx.Tx, fee *types
 		query = queryInsertPluginInstallation
 	case types.FeeTypeTrial:
 		query = queryInsertTrial
+	case types.FeeTypeRefund:
+		query = queryInsertRefund
+	case types.FeeSubscriptionFee:
+		query = queryInsertSubscription
 	default:
 		query = queryInsertFee
 	}
 
+	if fee.Asset == "" {
+		fee.Asset = types.PricingAssetUSDC
+	}
+
 	var feeID uint64
 	var err error
 
 	if dbTx != nil {
 		err = dbTx.QueryRow(ctx, query,
 			fee.PolicyID, fee.PluginID, fee.PublicKey, fee.TxType, fee.Amount,
-			fee.FeeType, fee.Metadata, fee.UnderlyingType, fee.UnderlyingID,
+			fee.FeeType, fee.Metadata, fee.UnderlyingType, fee.UnderlyingID, fee.Asset,
 		).Scan(&feeID)
 	} else {
 		err = p.pool.QueryRow(ctx, query,
 			fee.PolicyID, fee.PluginID, fee.PublicKey, fee.TxType, fee.Amount,
-			fee.FeeType, fee.Metadata, fee.UnderlyingType, fee.UnderlyingID,
+			fee.FeeType, fee.Metadata, fee.UnderlyingType, fee.UnderlyingID, fee.Asset,
 		).Scan(&feeID)
 	}
 
@@ -88,7 +115,7 @@ func (p *PostgresBackend) InsertFee(ctx context.Context, dbTx pgx.Tx, fee *types
 func (p *PostgresBackend) GetFeesByPublicKey(ctx context.Context, publicKey string) ([]*types.Fee, error) {
 	query := `
     WITH last_cutoff AS (
-        SELECT COALESCE(MAX(fb.batch_cutoff), 0) as cutoff_id
+        SELECT fb.asset, MAX(fb.batch_cutoff) as cutoff_id
         FROM fee_batches fb
         WHERE fb.batch_cutoff IS NOT NULL
           AND EXISTS (
@@ -98,6 +125,7 @@ func (p *PostgresBackend) GetFeesByPublicKey(ctx context.Context, publicKey stri
               WHERE fbm.batch_id = fb.id
                 AND f.public_key = $1
           )
+        GROUP BY fb.asset
     )
     SELECT
         f.id,
@@ -110,10 +138,12 @@ func (p *PostgresBackend) GetFeesByPublicKey(ctx context.Context, publicKey stri
         f.fee_type,
         f.metadata,
         f.underlying_type,
-        f.underlying_id
-    FROM fees f, last_cutoff lc
+        f.underlying_id,
+        f.asset
+    FROM fees f
+    LEFT JOIN last_cutoff lc ON lc.asset = f.asset
     WHERE f.public_key = $1
-      AND f.id > lc.cutoff_id
+      AND f.id > COALESCE(lc.cutoff_id, 0)
     ORDER BY f.created_at ASC
     `
 
@@ -139,6 +169,7 @@ func (p *PostgresBackend) GetFeesByPublicKey(ctx context.Context, publicKey stri
 			&fee.Metadata,
 			&fee.UnderlyingType,
 			&fee.UnderlyingID,
+			&fee.Asset,
 		)
 		if pluginID != nil {
 			fee.PluginID = *pluginID
@@ -169,7 +200,8 @@ func (p *PostgresBackend) GetFeeById(ctx context.Context, id uint64) (*types.Fee
             fee_type,
             metadata,
             underlying_type,
-            underlying_id
+            underlying_id,
+            asset
         FROM fees
         WHERE id = $1
     `
@@ -188,6 +220,7 @@ func (p *PostgresBackend) GetFeeById(ctx context.Context, id uint64) (*types.Fee
 		&fee.Metadata,
 		&fee.UnderlyingType,
 		&fee.UnderlyingID,
+		&fee.Asset,
 	)
 	if pluginID != nil {
 		fee.PluginID = *pluginID
@@ -205,16 +238,20 @@ func (p *PostgresBackend) GetFeeById(ctx context.Context, id uint64) (*types.Fee
 
 func (p *PostgresBackend) MarkFeesCollected(ctx context.Context, dbTx pgx.Tx, feeIDs []uint64, txHash string, totalAmount uint64) error {
 	var publicKey string
+	var asset types.PricingAsset
 	var feeCount int
 	var distinctKeys int
+	var distinctAssets int
 	err := dbTx.QueryRow(ctx, `
         SELECT 
             MIN(public_key) as public_key,
+            MIN(asset::text) as asset,
             COUNT(*) as fee_count,
-            COUNT(DISTINCT public_key) as distinct_keys
+            COUNT(DISTINCT public_key) as distinct_keys,
+            COUNT(DISTINCT asset) as distinct_assets
         FROM fees
         WHERE id = ANY($1)
-    `, feeIDs).Scan(&publicKey, &feeCount, &distinctKeys)
+    `, feeIDs).Scan(&publicKey, &asset, &feeCount, &distinctKeys, &distinctAssets)
 	if err != nil {
 		return fmt.Errorf("failed to validate fees: %w", err)
 	}
@@ -227,12 +264,16 @@ func (p *PostgresBackend) MarkFeesCollected(ctx context.Context, dbTx pgx.Tx, fe
 		return fmt.Errorf("fees belong to multiple public keys: found %d distinct keys", distinctKeys)
 	}
 
+	if distinctAssets != 1 {
+		return fmt.Errorf("fees are billed in multiple assets: found %d distinct assets", distinctAssets)
+	}
+
 	var batchID int64
 	err = dbTx.QueryRow(ctx, `
-        INSERT INTO fee_batches (total_value, status, batch_cutoff, collection_tx_id)
-        VALUES ($1, 'SIGNED', 0, $2)
+        INSERT INTO fee_batches (total_value, status, batch_cutoff, collection_tx_id, asset)
+        VALUES ($1, 'SIGNED', 0, $2, $3)
         RETURNING id
-    `, totalAmount, txHash).Scan(&batchID)
+    `, totalAmount, txHash, asset).Scan(&batchID)
 	if err != nil {
 		return fmt.Errorf("failed to create batch: %w", err)
 	}
@@ -263,6 +304,7 @@ func (p *PostgresBackend) MarkFeesCollected(ctx context.Context, dbTx pgx.Tx, fe
 		Metadata:       metadataJSON,
 		UnderlyingType: "batch",
 		UnderlyingID:   fmt.Sprint(batchID),
+		Asset:          asset,
 	}
 
 	creditID, err := p.InsertFee(ctx, dbTx, creditFee)
@@ -298,7 +340,8 @@ func (p *PostgresBackend) GetUserFees(
         f.fee_type,
         f.metadata,
         f.underlying_type,
-        f.underlying_id
+        f.underlying_id,
+        f.asset
     FROM fees f
     WHERE f.public_key = $1
     ORDER BY f.created_at ASC
@@ -315,7 +358,9 @@ func (p *PostgresBackend) GetUserFees(
 		Fees:         make([]*types.Fee, 0),
 		Balance:      0,
 		UnpaidAmount: 0,
+		Balances:     make([]types.AssetBalance, 0),
 	}
+	balances := make(map[types.PricingAsset]int64)
 
 	for rows.Next() {
 		fee := &types.Fee{}
@@ -332,6 +377,7 @@ func (p *PostgresBackend) GetUserFees(
 			&fee.Metadata,
 			&fee.UnderlyingType,
 			&fee.UnderlyingID,
+			&fee.Asset,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan fee row: %w", err)
@@ -341,19 +387,31 @@ func (p *PostgresBackend) GetUserFees(
 		}
 
 		if fee.TxType == types.TxTypeCredit {
-			result.Balance += int64(fee.Amount)
+			balances[fee.Asset] += int64(fee.Amount)
 		} else if fee.TxType == types.TxTypeDebit {
-			result.Balance -= int64(fee.Amount)
+			balances[fee.Asset] -= int64(fee.Amount)
 			result.Fees = append(result.Fees, fee)
 		}
 	}
 
-	result.UnpaidAmount = max(0, -result.Balance)
-
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("error iterating fee rows: %w", err)
 	}
 
+	for _, asset := range types.FeeAssets() {
+		balance, ok := balances[asset.ID]
+		if !ok {
+			continue
+		}
+		result.Balances = append(result.Balances, types.AssetBalance{
+			Asset:        asset.ID,
+			Balance:      balance,
+			UnpaidAmount: max(0, -balance),
+		})
+	}
+	result.Balance = balances[types.PricingAssetUSDC]
+	result.UnpaidAmount = max(0, -result.Balance)
+
 	return result, nil
 }
 
@@ -384,7 +442,8 @@ func (p *PostgresBackend) UpdateBatchStatus(ctx context.Context, dbTx pgx.Tx, tx
         f.amount,
         f.metadata,
         f.underlying_type,
-        f.underlying_id
+        f.underlying_id,
+        f.asset
     FROM fee_batches fb
     JOIN fees f ON f.id = fb.batch_cutoff
     WHERE fb.collection_tx_id = $1
@@ -396,6 +455,7 @@ func (p *PostgresBackend) UpdateBatchStatus(ctx context.Context, dbTx pgx.Tx, tx
 			&originalFee.Metadata,
 			&originalFee.UnderlyingType,
 			&originalFee.UnderlyingID,
+			&originalFee.Asset,
 		)
 		if err != nil {
 			return fmt.Errorf("failed to get batch and original fee: %w", err)
@@ -410,6 +470,7 @@ func (p *PostgresBackend) UpdateBatchStatus(ctx context.Context, dbTx pgx.Tx, tx
 			Metadata:       originalFee.Metadata,
 			UnderlyingType: "batch",
 			UnderlyingID:   fmt.Sprint(batchID),
+			Asset:          originalFee.Asset,
 		}
 
 		_, err = p.InsertFee(ctx, dbTx, compensationFee)
@@ -507,7 +568,12 @@ func (p *PostgresBackend) GetFeesByPluginID(
 			f.metadata,
 			f.underlying_type,
 			f.underlying_id,
+			f.asset,
 			CASE
+				WHEN EXISTS (
+					SELECT 1 FROM fees r
+					WHERE r.fee_type = 'refund' AND r.underlying_type = 'fee' AND r.underlying_id = f.id::text
+				) THEN 'REFUNDED'
 				WHEN fb.status IS NULL THEN 'PENDING'
 				WHEN fb.status IN ('BATCHED', 'SIGNED') THEN 'PROCESSING'
 				WHEN fb.status = 'COMPLETED' THEN 'COLLECTED'
@@ -547,6 +613,7 @@ func (p *PostgresBackend) GetFeesByPluginID(
 			&feeWithStatus.Metadata,
 			&feeWithStatus.UnderlyingType,
 			&feeWithStatus.UnderlyingID,
+			&feeWithStatus.Asset,
 			&statusStr,
 		)
 		if err != nil {
@@ -570,17 +637,18 @@ func (p *PostgresBackend) GetPluginBillingSummary(
 	ctx context.Context,
 	publicKey string,
 ) ([]itypes.PluginBillingSummaryRow, error) {
-	// Simple aggregate: one row per plugin with total fees and start date
+	// Simple aggregate: one row per plugin and asset with total fees and start date
 	query := `
 		SELECT
 			f.plugin_id,
+			f.asset,
 			MIN(f.created_at) as start_date,
 			SUM(f.amount) as total_fees
 		FROM fees f
 		WHERE f.public_key = $1
 		  AND f.transaction_type = 'debit'
 		  AND f.plugin_id IS NOT NULL
-		GROUP BY f.plugin_id
+		GROUP BY f.plugin_id, f.asset
 		ORDER BY total_fees DESC
 	`
 
@@ -595,6 +663,7 @@ func (p *PostgresBackend) GetPluginBillingSummary(
 		var row itypes.PluginBillingSummaryRow
 		err := rows.Scan(
 			&row.PluginID,
+			&row.Asset,
 			&row.StartDate,
 			&row.TotalFees,
 		)
@@ -625,7 +694,10 @@ func (p *PostgresBackend) GetPricingsByPluginIDs(
 			type::text,
 			amount,
 			asset::text,
-			frequency::text
+			frequency::text,
+			metric::text,
+			min_amount,
+			max_amount
 		FROM pricings
 		WHERE plugin_id = ANY($1)
 	`
@@ -646,6 +718,9 @@ func (p *PostgresBackend) GetPricingsByPluginIDs(
 			&info.Amount,
 			&info.Asset,
 			&info.Frequency,
+			&info.Metric,
+			&info.MinAmount,
+			&info.MaxAmount,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan pricing row: %w", err)
@@ -659,3 +734,308 @@ func (p *PostgresBackend) GetPricingsByPluginIDs(
 
 	return result, nil
 }
+
+// RefundFee credits back a debit, the credit links to it with CreditMetadata.DebitFeeID.
+// A debit is refunded at most once, 0 is returned when it already was.
+func (p *PostgresBackend) RefundFee(ctx context.Context, dbTx pgx.Tx, refund types.Refund) (uint64, error) {
+	var debit types.Fee
+	var pluginID *string
+	err := dbTx.QueryRow(ctx, `
+        SELECT policy_id, plugin_id, public_key, transaction_type, amount, asset
+        FROM fees
+        WHERE id = $1
+        FOR UPDATE
+    `, refund.DebitFeeID).Scan(
+		&debit.PolicyID,
+		&pluginID,
+		&debit.PublicKey,
+		&debit.TxType,
+		&debit.Amount,
+		&debit.Asset,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, fmt.Errorf("%w: fee %d not found", types.ErrFeeNotRefundable, refund.DebitFeeID)
+		}
+		return 0, fmt.Errorf("failed to get fee %d: %w", refund.DebitFeeID, err)
+	}
+	if pluginID != nil {
+		debit.PluginID = *pluginID
+	}
+
+	if debit.TxType != types.TxTypeDebit {
+		return 0, fmt.Errorf("%w: fee %d is a %s", types.ErrFeeNotRefundable, refund.DebitFeeID, debit.TxType)
+	}
+	if !refund.Reason.IsValid() {
+		return 0, fmt.Errorf("invalid refund reason: %q", refund.Reason)
+	}
+	amount := refund.Amount
+	if amount == 0 {
+		amount = debit.Amount
+	}
+	if amount > debit.Amount {
+		return 0, fmt.Errorf("%w: refund of %d exceeds fee %d of %d", types.ErrFeeNotRefundable, amount, refund.DebitFeeID, debit.Amount)
+	}
+
+	metadata, err := json.Marshal(types.CreditMetadata{
+		DebitFeeID: refund.DebitFeeID,
+		TxHash:     refund.TxHash,
+		Network:    refund.Network,
+		Reason:     refund.Reason,
+		Note:       refund.Note,
+		IssuedBy:   refund.IssuedBy,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	// the refund keeps the policy and plugin of the debit, so it offsets the plugin earnings
+	return p.InsertFee(ctx, dbTx, &types.Fee{
+		PolicyID:       debit.PolicyID,
+		PluginID:       debit.PluginID,
+		PublicKey:      debit.PublicKey,
+		TxType:         types.TxTypeCredit,
+		Amount:         amount,
+		Asset:          debit.Asset,
+		FeeType:        types.FeeTypeRefund,
+		Metadata:       metadata,
+		UnderlyingType: "fee",
+		UnderlyingID:   fmt.Sprint(refund.DebitFeeID),
+	})
+}
+
+// GetUnrefundedTxFees returns the tx execution fees billed since, which weren't refunded
+func (p *PostgresBackend) GetUnrefundedTxFees(ctx context.Context, since time.Time) ([]itypes.BilledTxFee, error) {
+	rows, err := p.pool.Query(ctx, `
+        SELECT f.id, f.underlying_id::uuid
+        FROM fees f
+        WHERE f.fee_type = $1
+          AND f.transaction_type = 'debit'
+          AND f.underlying_type = 'tx_indexer_record'
+          AND f.created_at >= $2
+          AND NOT EXISTS (
+              SELECT 1
+              FROM fees r
+              WHERE r.fee_type = 'refund'
+                AND r.underlying_type = 'fee'
+                AND r.underlying_id = f.id::text
+          )
+        ORDER BY f.id
+    `, types.FeeTxExecFee, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query billed tx fees: %w", err)
+	}
+	defer rows.Close()
+
+	var fees []itypes.BilledTxFee
+	for rows.Next() {
+		var fee itypes.BilledTxFee
+		if err := rows.Scan(&fee.FeeID, &fee.TxIndexerID); err != nil {
+			return nil, fmt.Errorf("failed to scan billed tx fee: %w", err)
+		}
+		fees = append(fees, fee)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating billed tx fees: %w", err)
+	}
+	return fees, nil
+}
+
+// GetIdleSubscriptionFees returns the subscription fees created since, whose period ended before now
+// without a successful execution of the policy, and which weren't refunded
+func (p *PostgresBackend) GetIdleSubscriptionFees(ctx context.Context, since, now time.Time) ([]*types.Fee, error) {
+	rows, err := p.pool.Query(ctx, `
+        SELECT DISTINCT ON (f.id)
+            f.id,
+            f.policy_id,
+            f.plugin_id,
+            f.public_key,
+            f.transaction_type,
+            f.amount,
+            f.created_at,
+            f.fee_type,
+            f.metadata,
+            f.underlying_type,
+            f.underlying_id,
+            f.asset
+        FROM fees f
+        JOIN plugin_policy_billing b
+          ON b.plugin_policy_id::text = f.underlying_id
+         AND b.type = 'recurring'
+        CROSS JOIN LATERAL (
+            SELECT
+                COALESCE((f.metadata->>'period_start')::timestamptz, f.created_at) AS period_start,
+                COALESCE((f.metadata->>'period_end')::timestamptz, f.created_at + CASE b.frequency
+                    WHEN 'daily' THEN INTERVAL '1 day'
+                    WHEN 'weekly' THEN INTERVAL '7 days'
+                    WHEN 'biweekly' THEN INTERVAL '14 days'
+                    ELSE INTERVAL '1 month'
+                END) AS period_end
+        ) pe
+        WHERE f.fee_type = $1
+          AND f.transaction_type = 'debit'
+          AND f.underlying_type = 'policy'
+          AND f.created_at >= $2
+          AND pe.period_end <= $3
+          AND NOT EXISTS (
+              SELECT 1
+              FROM fees r
+              WHERE r.fee_type = 'refund'
+                AND r.underlying_type = 'fee'
+                AND r.underlying_id = f.id::text
+          )
+          AND NOT EXISTS (
+              SELECT 1
+              FROM tx_indexer t
+              WHERE t.policy_id = b.plugin_policy_id
+                AND t.status_onchain = 'SUCCESS'
+                AND t.created_at >= pe.period_start
+                AND t.created_at < pe.period_end
+          )
+        ORDER BY f.id
+    `, types.FeeSubscriptionFee, since, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query idle subscription fees: %w", err)
+	}
+	defer rows.Close()
+
+	var fees []*types.Fee
+	for rows.Next() {
+		fee := &types.Fee{}
+		var pluginID *string
+		err := rows.Scan(
+			&fee.ID,
+			&fee.PolicyID,
+			&pluginID,
+			&fee.PublicKey,
+			&fee.TxType,
+			&fee.Amount,
+			&fee.CreatedAt,
+			&fee.FeeType,
+			&fee.Metadata,
+			&fee.UnderlyingType,
+			&fee.UnderlyingID,
+			&fee.Asset,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan fee row: %w", err)
+		}
+		if pluginID != nil {
+			fee.PluginID = *pluginID
+		}
+		fees = append(fees, fee)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating fee rows: %w", err)
+	}
+	return fees, nil
+}
+
+// GetSubscriptionsToBill returns the recurring billings of the policies with a period to bill by now,
+// the ones active since the end of their last billed period
+func (p *PostgresBackend) GetSubscriptionsToBill(ctx context.Context, now time.Time) ([]itypes.Subscription, error) {
+	rows, err := p.pool.Query(ctx, `
+        SELECT
+            p.id,
+            p.public_key,
+            p.plugin_id,
+            b.amount,
+            b.asset,
+            b.frequency,
+            b.start_date::timestamp AT TIME ZONE 'UTC',
+            lf.billed_until
+        FROM plugin_policies p
+        JOIN plugin_policy_billing b ON b.plugin_policy_id = p.id
+        LEFT JOIN LATERAL (
+            SELECT COALESCE((f.metadata->>'period_end')::timestamptz, f.created_at + CASE b.frequency
+                WHEN 'daily' THEN INTERVAL '1 day'
+                WHEN 'weekly' THEN INTERVAL '7 days'
+                WHEN 'biweekly' THEN INTERVAL '14 days'
+                ELSE INTERVAL '1 month'
+            END) AS billed_until
+            FROM fees f
+            WHERE f.fee_type = $1
+              AND f.transaction_type = 'debit'
+              AND f.underlying_type = 'policy'
+              AND f.underlying_id = p.id::text
+            ORDER BY f.id DESC
+            LIMIT 1
+        ) lf ON true
+        WHERE b.type = 'recurring'
+          AND (lf.billed_until IS NULL OR lf.billed_until < $2)
+          AND EXISTS (
+              SELECT 1
+              FROM plugin_policy_activations a
+              WHERE a.policy_id = p.id
+                AND (a.deactivated_at IS NULL OR lf.billed_until IS NULL OR a.deactivated_at > lf.billed_until)
+          )
+        ORDER BY p.id
+    `, types.FeeSubscriptionFee, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	var subs []itypes.Subscription
+	var policyIDs []uuid.UUID
+	for rows.Next() {
+		var sub itypes.Subscription
+		err := rows.Scan(
+			&sub.PolicyID,
+			&sub.PublicKey,
+			&sub.PluginID,
+			&sub.Amount,
+			&sub.Asset,
+			&sub.Frequency,
+			&sub.StartDate,
+			&sub.BilledUntil,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan subscription: %w", err)
+		}
+		subs = append(subs, sub)
+		policyIDs = append(policyIDs, sub.PolicyID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating subscriptions: %w", err)
+	}
+	if len(subs) == 0 {
+		return nil, nil
+	}
+
+	activations, err := p.getPolicyActivations(ctx, policyIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range subs {
+		subs[i].Activations = activations[subs[i].PolicyID]
+	}
+	return subs, nil
+}
+
+func (p *PostgresBackend) getPolicyActivations(ctx context.Context, policyIDs []uuid.UUID) (map[uuid.UUID][]types.PolicyActivation, error) {
+	rows, err := p.pool.Query(ctx, `
+        SELECT policy_id, activated_at, deactivated_at
+        FROM plugin_policy_activations
+        WHERE policy_id = ANY($1)
+        ORDER BY policy_id, activated_at
+    `, policyIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query policy activations: %w", err)
+	}
+	defer rows.Close()
+
+	activations := make(map[uuid.UUID][]types.PolicyActivation)
+	for rows.Next() {
+		var policyID uuid.UUID
+		var a types.PolicyActivation
+		if err := rows.Scan(&policyID, &a.ActivatedAt, &a.DeactivatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan policy activation: %w", err)
+		}
+		activations[policyID] = append(activations[policyID], a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating policy activations: %w", err)
+	}
+	return activations, nil
+}
@@ -0,0 +1,249 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	itypes "github.com/vultisig/verifier/internal/types"
+	"github.com/vultisig/verifier/types"
+)
+
+// GetInvoicePublicKeys returns the public keys with fees or collections in [start, end)
+func (p *PostgresBackend) GetInvoicePublicKeys(ctx context.Context, start, end time.Time) ([]string, error) {
+	rows, err := p.pool.Query(ctx, `
+		SELECT DISTINCT public_key
+		FROM fees
+		WHERE created_at >= $1
+		  AND created_at < $2
+		ORDER BY public_key
+	`, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query invoice public keys: %w", err)
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+// GetInvoiceData aggregates the fees, credits and collections of publicKey in [start, end)
+func (p *PostgresBackend) GetInvoiceData(
+	ctx context.Context,
+	publicKey string,
+	start, end time.Time,
+) (itypes.InvoiceData, error) {
+	data := itypes.InvoiceData{
+		LineItems: make([]itypes.InvoiceLineItem, 0),
+		Credits:   make([]itypes.InvoiceCredit, 0),
+		Batches:   make([]itypes.InvoiceBatch, 0),
+	}
+
+	rows, err := p.pool.Query(ctx, `
+		SELECT
+			COALESCE(plugin_id, ''),
+			fee_type,
+			asset,
+			COUNT(*),
+			SUM(amount)::bigint
+		FROM fees
+		WHERE public_key = $1
+		  AND transaction_type = 'debit'
+		  AND created_at >= $2
+		  AND created_at < $3
+		GROUP BY plugin_id, fee_type, asset
+		ORDER BY plugin_id, fee_type, asset
+	`, publicKey, start, end)
+	if err != nil {
+		return itypes.InvoiceData{}, fmt.Errorf("failed to query invoice line items: %w", err)
+	}
+	for rows.Next() {
+		var item itypes.InvoiceLineItem
+		err := rows.Scan(&item.PluginID, &item.FeeType, &item.Asset, &item.Count, &item.Amount)
+		if err != nil {
+			rows.Close()
+			return itypes.InvoiceData{}, fmt.Errorf("failed to scan invoice line item: %w", err)
+		}
+		data.LineItems = append(data.LineItems, item)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return itypes.InvoiceData{}, fmt.Errorf("error iterating invoice line items: %w", err)
+	}
+
+	// batch credits record collections, they are listed from fee_batches with their tx hash
+	rows, err = p.pool.Query(ctx, `
+		SELECT id, fee_type, COALESCE(metadata->>'reason', underlying_type), asset, amount, created_at
+		FROM fees
+		WHERE public_key = $1
+		  AND transaction_type = 'credit'
+		  AND fee_type <> $4
+		  AND created_at >= $2
+		  AND created_at < $3
+		ORDER BY created_at, id
+	`, publicKey, start, end, types.FeeTypeBatch)
+	if err != nil {
+		return itypes.InvoiceData{}, fmt.Errorf("failed to query invoice credits: %w", err)
+	}
+	for rows.Next() {
+		var credit itypes.InvoiceCredit
+		err := rows.Scan(
+			&credit.FeeID,
+			&credit.FeeType,
+			&credit.Reason,
+			&credit.Asset,
+			&credit.Amount,
+			&credit.CreatedAt,
+		)
+		if err != nil {
+			rows.Close()
+			return itypes.InvoiceData{}, fmt.Errorf("failed to scan invoice credit: %w", err)
+		}
+		data.Credits = append(data.Credits, credit)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return itypes.InvoiceData{}, fmt.Errorf("error iterating invoice credits: %w", err)
+	}
+
+	rows, err = p.pool.Query(ctx, `
+		SELECT fb.id, fb.asset, fb.total_value, fb.status::text, COALESCE(fb.collection_tx_id, ''), fb.created_at
+		FROM fee_batches fb
+		WHERE fb.created_at >= $2
+		  AND fb.created_at < $3
+		  AND EXISTS (
+			SELECT 1
+			FROM fee_batch_members fbm
+			JOIN fees f ON f.id = fbm.fee_id
+			WHERE fbm.batch_id = fb.id
+			  AND f.public_key = $1
+		  )
+		ORDER BY fb.created_at, fb.id
+	`, publicKey, start, end)
+	if err != nil {
+		return itypes.InvoiceData{}, fmt.Errorf("failed to query invoice batches: %w", err)
+	}
+	for rows.Next() {
+		var batch itypes.InvoiceBatch
+		err := rows.Scan(
+			&batch.BatchID,
+			&batch.Asset,
+			&batch.Amount,
+			&batch.Status,
+			&batch.TxHash,
+			&batch.CreatedAt,
+		)
+		if err != nil {
+			rows.Close()
+			return itypes.InvoiceData{}, fmt.Errorf("failed to scan invoice batch: %w", err)
+		}
+		data.Batches = append(data.Batches, batch)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return itypes.InvoiceData{}, fmt.Errorf("error iterating invoice batches: %w", err)
+	}
+
+	data.ComputeTotals()
+	return data, nil
+}
+
+// InsertInvoice stores an invoice, it returns false when one already exists for the period
+func (p *PostgresBackend) InsertInvoice(ctx context.Context, invoice *itypes.Invoice) (bool, error) {
+	data, err := json.Marshal(invoice.InvoiceData)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal invoice data: %w", err)
+	}
+
+	err = p.pool.QueryRow(ctx, `
+		INSERT INTO invoices (public_key, period_start, period_end, data)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (public_key, period_start) DO NOTHING
+		RETURNING id, created_at
+	`, invoice.PublicKey, invoice.PeriodStart, invoice.PeriodEnd, data).Scan(&invoice.ID, &invoice.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to insert invoice: %w", err)
+	}
+	return true, nil
+}
+
+// GetInvoices returns the invoices of publicKey, most recent period first
+func (p *PostgresBackend) GetInvoices(
+	ctx context.Context,
+	publicKey string,
+	skip, take uint32,
+) ([]itypes.Invoice, uint32, error) {
+	var totalCount uint32
+	err := p.pool.QueryRow(ctx, `SELECT COUNT(*) FROM invoices WHERE public_key = $1`, publicKey).Scan(&totalCount)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count invoices: %w", err)
+	}
+
+	rows, err := p.pool.Query(ctx, `
+		SELECT id, public_key, period_start, period_end, data, created_at
+		FROM invoices
+		WHERE public_key = $1
+		ORDER BY period_start DESC
+		LIMIT $2 OFFSET $3
+	`, publicKey, take, skip)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query invoices: %w", err)
+	}
+	defer rows.Close()
+
+	invoices := make([]itypes.Invoice, 0)
+	for rows.Next() {
+		invoice, err := scanInvoice(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		invoices = append(invoices, *invoice)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating invoices: %w", err)
+	}
+	return invoices, totalCount, nil
+}
+
+// GetInvoice returns an invoice of publicKey, nil when it doesn't exist or isn't theirs
+func (p *PostgresBackend) GetInvoice(ctx context.Context, publicKey string, id uuid.UUID) (*itypes.Invoice, error) {
+	row := p.pool.QueryRow(ctx, `
+		SELECT id, public_key, period_start, period_end, data, created_at
+		FROM invoices
+		WHERE id = $1
+		  AND public_key = $2
+	`, id, publicKey)
+	invoice, err := scanInvoice(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return invoice, nil
+}
+
+func scanInvoice(row pgx.Row) (*itypes.Invoice, error) {
+	var invoice itypes.Invoice
+	var data []byte
+	err := row.Scan(
+		&invoice.ID,
+		&invoice.PublicKey,
+		&invoice.PeriodStart,
+		&invoice.PeriodEnd,
+		&data,
+		&invoice.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan invoice: %w", err)
+	}
+	if err := json.Unmarshal(data, &invoice.InvoiceData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal invoice %s: %w", invoice.ID, err)
+	}
+	return &invoice, nil
+}
@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/vultisig/verifier/types"
+)
+
+func (p *PostgresBackend) CreateKeysignResult(ctx context.Context, result types.KeysignResult) error {
+	query := `
+		INSERT INTO keysign_results (tx_indexer_id, session_id, task_id, plugin_id, public_key, status, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	_, err := p.pool.Exec(ctx, query,
+		result.TxIndexerID,
+		result.SessionID,
+		result.TaskID,
+		result.PluginID,
+		result.PublicKey,
+		result.Status,
+		result.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert keysign result: %w", err)
+	}
+	return nil
+}
+
+// UpdateKeysignResult moves a keysign result forward, a finished (failed or completed) result is never changed again
+func (p *PostgresBackend) UpdateKeysignResult(
+	ctx context.Context,
+	txIndexerID uuid.UUID,
+	status types.KeysignResultStatus,
+	failureReason string,
+	data []byte,
+) error {
+	query := `
+		UPDATE keysign_results
+		SET status = $2, failure_reason = NULLIF($3, ''), data = $4, updated_at = NOW()
+		WHERE tx_indexer_id = $1 AND status IN ('queued', 'running')`
+
+	_, err := p.pool.Exec(ctx, query, txIndexerID, status, failureReason, data)
+	if err != nil {
+		return fmt.Errorf("failed to update keysign result: %w", err)
+	}
+	return nil
+}
+
+// GetKeysignResult returns nil if the result doesn't exist or has expired
+func (p *PostgresBackend) GetKeysignResult(ctx context.Context, txIndexerID uuid.UUID) (*types.KeysignResult, error) {
+	query := `
+		SELECT tx_indexer_id, session_id, task_id, plugin_id, public_key, status,
+		       COALESCE(failure_reason, ''), data, created_at, updated_at, expires_at
+		FROM keysign_results
+		WHERE tx_indexer_id = $1 AND expires_at > NOW()`
+
+	var result types.KeysignResult
+	err := p.pool.QueryRow(ctx, query, txIndexerID).Scan(
+		&result.TxIndexerID,
+		&result.SessionID,
+		&result.TaskID,
+		&result.PluginID,
+		&result.PublicKey,
+		&result.Status,
+		&result.FailureReason,
+		&result.Data,
+		&result.CreatedAt,
+		&result.UpdatedAt,
+		&result.ExpiresAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get keysign result: %w", err)
+	}
+	return &result, nil
+}
+
+func (p *PostgresBackend) DeleteExpiredKeysignResults(ctx context.Context) (int64, error) {
+	ct, err := p.pool.Exec(ctx, `DELETE FROM keysign_results WHERE expires_at <= NOW()`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired keysign results: %w", err)
+	}
+	return ct.RowsAffected(), nil
+}
@@ -0,0 +1,216 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	itypes "github.com/vultisig/verifier/internal/types"
+	"github.com/vultisig/verifier/types"
+)
+
+// ledgerIntegrityLimit bounds the entries and fees listed by GetLedgerIntegrityIssues
+const ledgerIntegrityLimit = 100
+
+// GetLedgerBalances returns the balances of the accounts of accountType, of accountID only when it isn't empty
+func (p *PostgresBackend) GetLedgerBalances(
+	ctx context.Context,
+	accountType itypes.LedgerAccountType,
+	accountID string,
+) ([]itypes.LedgerBalance, error) {
+	rows, err := p.pool.Query(ctx, `
+        SELECT account_type, account_id, asset, SUM(amount)::bigint
+        FROM ledger_postings
+        WHERE account_type = $1
+          AND ($2 = '' OR account_id = $2)
+        GROUP BY account_type, account_id, asset
+        ORDER BY account_id, asset
+    `, accountType, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ledger balances: %w", err)
+	}
+	defer rows.Close()
+
+	var balances []itypes.LedgerBalance
+	for rows.Next() {
+		var balance itypes.LedgerBalance
+		err := rows.Scan(&balance.AccountType, &balance.AccountID, &balance.Asset, &balance.Balance)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan ledger balance: %w", err)
+		}
+		balances = append(balances, balance)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating ledger balances: %w", err)
+	}
+	return balances, nil
+}
+
+// GetBatchCollections returns the fee batches with a collection tx, with the amount the ledger
+// posted to the collections account for them and the collection tx known by the tx indexer
+func (p *PostgresBackend) GetBatchCollections(ctx context.Context) ([]itypes.BatchCollection, error) {
+	rows, err := p.pool.Query(ctx, `
+        SELECT
+            fb.id,
+            fb.asset,
+            fb.total_value,
+            fb.status::text,
+            fb.collection_tx_id,
+            fb.created_at,
+            COALESCE((
+                SELECT SUM(lp.amount)
+                FROM fees f
+                JOIN ledger_entries le ON le.fee_id = f.id
+                JOIN ledger_postings lp ON lp.entry_id = le.id
+                WHERE f.underlying_type = 'batch'
+                  AND f.underlying_id = fb.id::text
+                  AND f.fee_type IN ($1, $2)
+                  AND lp.account_type = 'treasury'
+                  AND lp.account_id = $3
+            ), 0)::bigint,
+            t.status_onchain::text,
+            t.amount
+        FROM fee_batches fb
+        LEFT JOIN LATERAL (
+            SELECT status_onchain, amount
+            FROM tx_indexer
+            WHERE tx_hash = fb.collection_tx_id
+              AND plugin_id = $4
+            ORDER BY created_at DESC
+            LIMIT 1
+        ) t ON true
+        WHERE fb.collection_tx_id IS NOT NULL
+        ORDER BY fb.id
+    `, types.FeeTypeBatch, types.FeeTypeBatchFailed, itypes.TreasuryCollections, types.PluginVultisigFees_feee)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query batch collections: %w", err)
+	}
+	defer rows.Close()
+
+	var batches []itypes.BatchCollection
+	for rows.Next() {
+		var batch itypes.BatchCollection
+		err := rows.Scan(
+			&batch.BatchID,
+			&batch.Asset,
+			&batch.TotalValue,
+			&batch.Status,
+			&batch.TxHash,
+			&batch.CreatedAt,
+			&batch.LedgerCollected,
+			&batch.OnChainStatus,
+			&batch.OnChainAmount,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan batch collection: %w", err)
+		}
+		batches = append(batches, batch)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating batch collections: %w", err)
+	}
+	return batches, nil
+}
+
+// GetLedgerIntegrityIssues returns the ledger entries whose postings don't balance and the fees without an entry
+func (p *PostgresBackend) GetLedgerIntegrityIssues(ctx context.Context) ([]uint64, []uint64, error) {
+	unbalanced, err := p.queryIDs(ctx, `
+        SELECT entry_id
+        FROM ledger_postings
+        GROUP BY entry_id
+        HAVING SUM(amount) <> 0
+        ORDER BY entry_id
+        LIMIT $1
+    `)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query unbalanced ledger entries: %w", err)
+	}
+	unposted, err := p.queryIDs(ctx, `
+        SELECT f.id
+        FROM fees f
+        WHERE NOT EXISTS (SELECT 1 FROM ledger_entries le WHERE le.fee_id = f.id)
+        ORDER BY f.id
+        LIMIT $1
+    `)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query unposted fees: %w", err)
+	}
+	return unbalanced, unposted, nil
+}
+
+func (p *PostgresBackend) queryIDs(ctx context.Context, query string) ([]uint64, error) {
+	rows, err := p.pool.Query(ctx, query, ledgerIntegrityLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uint64
+	for rows.Next() {
+		var id uint64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (p *PostgresBackend) InsertReconciliationReport(ctx context.Context, report *itypes.ReconciliationReport) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to marshal reconciliation report: %w", err)
+	}
+	err = p.pool.QueryRow(ctx, `
+        INSERT INTO ledger_reconciliations (discrepancy_count, report)
+        VALUES ($1, $2)
+        RETURNING id, created_at
+    `, len(report.Discrepancies), data).Scan(&report.ID, &report.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert reconciliation report: %w", err)
+	}
+	return nil
+}
+
+func (p *PostgresBackend) GetReconciliationReports(
+	ctx context.Context,
+	skip, take uint32,
+) ([]itypes.ReconciliationReport, uint32, error) {
+	var totalCount uint32
+	err := p.pool.QueryRow(ctx, `SELECT COUNT(*) FROM ledger_reconciliations`).Scan(&totalCount)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count reconciliation reports: %w", err)
+	}
+
+	rows, err := p.pool.Query(ctx, `
+        SELECT id, report, created_at
+        FROM ledger_reconciliations
+        ORDER BY id DESC
+        LIMIT $1 OFFSET $2
+    `, take, skip)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query reconciliation reports: %w", err)
+	}
+	defer rows.Close()
+
+	reports := make([]itypes.ReconciliationReport, 0)
+	for rows.Next() {
+		var report itypes.ReconciliationReport
+		var id uint64
+		var data []byte
+		if err := rows.Scan(&id, &data, &report.CreatedAt); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan reconciliation report: %w", err)
+		}
+		createdAt := report.CreatedAt
+		if err := json.Unmarshal(data, &report); err != nil {
+			return nil, 0, fmt.Errorf("failed to unmarshal reconciliation report %d: %w", id, err)
+		}
+		report.ID = id
+		report.CreatedAt = createdAt
+		reports = append(reports, report)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating reconciliation reports: %w", err)
+	}
+	return reports, totalCount, nil
+}
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE presignatures (
+    id TEXT NOT NULL,
+    public_key TEXT NOT NULL,
+    plugin_id TEXT NOT NULL,
+    derive_path TEXT NOT NULL,
+    data BYTEA,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+    consumed_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (public_key, plugin_id, id)
+);
+
+CREATE INDEX idx_presignatures_available ON presignatures(public_key, plugin_id, derive_path, created_at)
+WHERE consumed_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS presignatures;
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE keysign_result_status AS ENUM ('queued', 'running', 'failed', 'completed');
+
+CREATE TABLE keysign_results (
+    tx_indexer_id UUID PRIMARY KEY,
+    session_id TEXT NOT NULL,
+    task_id TEXT NOT NULL,
+    plugin_id TEXT NOT NULL,
+    public_key TEXT NOT NULL,
+    status keysign_result_status NOT NULL DEFAULT 'queued',
+    failure_reason TEXT,
+    data BYTEA,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_keysign_results_session_id ON keysign_results(session_id);
+CREATE INDEX idx_keysign_results_expires_at ON keysign_results(expires_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS keysign_results;
+DROP TYPE IF EXISTS keysign_result_status;
+-- +goose StatementEnd
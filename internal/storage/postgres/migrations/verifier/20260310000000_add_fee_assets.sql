@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TYPE pricing_asset ADD VALUE IF NOT EXISTS 'usdt';
+ALTER TYPE pricing_asset ADD VALUE IF NOT EXISTS 'usdc-arbitrum';
+
+-- fees and batches were all billed in usdc before assets were introduced
+ALTER TABLE fees ADD COLUMN asset pricing_asset NOT NULL DEFAULT 'usdc';
+ALTER TABLE fee_batches ADD COLUMN asset pricing_asset NOT NULL DEFAULT 'usdc';
+
+CREATE INDEX idx_fees_public_key_asset ON fees(public_key, asset);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_fees_public_key_asset;
+ALTER TABLE fee_batches DROP COLUMN IF EXISTS asset;
+ALTER TABLE fees DROP COLUMN IF EXISTS asset;
+
+-- enum values can't be dropped, usdt and usdc-arbitrum stay in pricing_asset
+
+-- +goose StatementEnd
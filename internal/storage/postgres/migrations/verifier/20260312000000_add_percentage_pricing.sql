@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TYPE pricing_metric ADD VALUE IF NOT EXISTS 'percentage';
+
+-- caps of percentage pricings, in the smallest unit of the pricing asset
+ALTER TABLE pricings ADD COLUMN min_amount BIGINT;
+ALTER TABLE pricings ADD COLUMN max_amount BIGINT;
+
+-- percentage pricings are per-tx and their amount is in basis points
+ALTER TABLE pricings ADD CONSTRAINT percentage_check CHECK (
+    (metric::text <> 'percentage' AND min_amount IS NULL AND max_amount IS NULL)
+    OR (metric::text = 'percentage' AND type = 'per-tx' AND amount <= 10000)
+);
+ALTER TABLE pricings ADD CONSTRAINT min_max_amount_check CHECK (
+    min_amount IS NULL OR max_amount IS NULL OR min_amount <= max_amount
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE pricings DROP CONSTRAINT IF EXISTS min_max_amount_check;
+ALTER TABLE pricings DROP CONSTRAINT IF EXISTS percentage_check;
+ALTER TABLE pricings DROP COLUMN IF EXISTS max_amount;
+ALTER TABLE pricings DROP COLUMN IF EXISTS min_amount;
+
+-- enum values can't be dropped, percentage stays in pricing_metric
+
+-- +goose StatementEnd
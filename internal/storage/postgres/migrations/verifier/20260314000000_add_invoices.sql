@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE invoices (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    public_key TEXT NOT NULL,
+    period_start TIMESTAMPTZ NOT NULL,
+    period_end TIMESTAMPTZ NOT NULL,
+    data JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT invoices_public_key_period_start_key UNIQUE (public_key, period_start),
+    CONSTRAINT invoices_period_check CHECK (period_end > period_start)
+);
+
+CREATE INDEX idx_invoices_public_key_period ON invoices(public_key, period_start DESC);
+
+CREATE OR REPLACE FUNCTION prevent_invoice_modification()
+    RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION '% operation not allowed on invoices table. Invoices are immutable once issued.', TG_OP
+        USING HINT = 'issue a credit, it is listed on the next invoice.';
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trigger_prevent_invoice_modification
+    BEFORE UPDATE OR DELETE ON invoices
+    FOR EACH ROW
+    EXECUTE FUNCTION prevent_invoice_modification();
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TRIGGER IF EXISTS trigger_prevent_invoice_modification ON invoices;
+DROP FUNCTION IF EXISTS prevent_invoice_modification();
+DROP TABLE IF EXISTS invoices;
+
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- a debit is refunded at most once, refunds reference it with underlying_type 'fee'
+CREATE UNIQUE INDEX idx_unique_refund_per_fee ON fees(underlying_id)
+    WHERE fee_type = 'refund' AND underlying_type = 'fee';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_unique_refund_per_fee;
+
+-- +goose StatementEnd
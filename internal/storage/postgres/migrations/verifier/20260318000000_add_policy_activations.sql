@@ -0,0 +1,67 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- periods a policy was active, recurring fees are prorated on them
+CREATE TABLE IF NOT EXISTS plugin_policy_activations (
+    id BIGSERIAL PRIMARY KEY,
+    policy_id UUID NOT NULL REFERENCES plugin_policies(id) ON DELETE CASCADE,
+    activated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deactivated_at TIMESTAMPTZ,
+    CONSTRAINT plugin_policy_activations_period_check CHECK (deactivated_at IS NULL OR deactivated_at >= activated_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_plugin_policy_activations_policy_id ON plugin_policy_activations(policy_id, activated_at);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_open_policy_activation ON plugin_policy_activations(policy_id)
+    WHERE deactivated_at IS NULL;
+
+-- deletes set active to false in a BEFORE trigger, the history is kept AFTER it
+CREATE OR REPLACE FUNCTION track_policy_activation() RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.active THEN
+        INSERT INTO plugin_policy_activations (policy_id, activated_at)
+        VALUES (NEW.id, NOW())
+        ON CONFLICT DO NOTHING;
+    ELSE
+        UPDATE plugin_policy_activations
+        SET deactivated_at = NOW()
+        WHERE policy_id = NEW.id
+          AND deactivated_at IS NULL;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_track_policy_activation_insert
+    AFTER INSERT ON plugin_policies
+    FOR EACH ROW
+    WHEN (NEW.active = true)
+    EXECUTE FUNCTION track_policy_activation();
+
+CREATE TRIGGER trg_track_policy_activation_update
+    AFTER UPDATE ON plugin_policies
+    FOR EACH ROW
+    WHEN (OLD.active IS DISTINCT FROM NEW.active)
+    EXECUTE FUNCTION track_policy_activation();
+
+-- existing policies count as active since they were created, inactive ones until their last update
+INSERT INTO plugin_policy_activations (policy_id, activated_at, deactivated_at)
+SELECT id, created_at, CASE WHEN active THEN NULL ELSE GREATEST(updated_at, created_at) END
+FROM plugin_policies;
+
+-- a subscription period is billed once
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_subscription_period ON fees(underlying_id, (metadata->>'period_start'))
+    WHERE fee_type = 'subscription_fee' AND underlying_type = 'policy';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_unique_subscription_period;
+DROP TRIGGER IF EXISTS trg_track_policy_activation_update ON plugin_policies;
+DROP TRIGGER IF EXISTS trg_track_policy_activation_insert ON plugin_policies;
+DROP FUNCTION IF EXISTS track_policy_activation();
+DROP TABLE IF EXISTS plugin_policy_activations;
+
+-- +goose StatementEnd
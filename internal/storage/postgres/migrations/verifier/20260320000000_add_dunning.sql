@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- vaults with an unpaid balance, the row is removed once the balance is paid back
+CREATE TABLE IF NOT EXISTS dunning_states (
+    public_key TEXT PRIMARY KEY,
+    stage TEXT NOT NULL,
+    overdue_since TIMESTAMPTZ NOT NULL,
+    last_reminder_at TIMESTAMPTZ,
+    suspended_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT dunning_states_stage_check CHECK (stage IN ('grace', 'reminded', 'suspended'))
+);
+
+CREATE TABLE IF NOT EXISTS dunning_events (
+    id BIGSERIAL PRIMARY KEY,
+    public_key TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    unpaid_amount BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT dunning_events_event_type_check CHECK (event_type IN ('reminder', 'suspended', 'reactivated'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_dunning_events_public_key ON dunning_events(public_key, created_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS dunning_events;
+DROP TABLE IF EXISTS dunning_states;
+
+-- +goose StatementEnd
@@ -0,0 +1,144 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- every fee is posted as a balanced entry between the vault (user) account and its counterparty:
+-- the developer account of the plugin, or the treasury revenue and collections accounts.
+-- Postings are positive on debit, so a user balance is what the vault owes and a developer
+-- balance is negative by what the developer earned.
+CREATE TABLE IF NOT EXISTS ledger_entries (
+    id BIGSERIAL PRIMARY KEY,
+    fee_id BIGINT NOT NULL UNIQUE REFERENCES fees(id),
+    entry_type TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS ledger_postings (
+    id BIGSERIAL PRIMARY KEY,
+    entry_id BIGINT NOT NULL REFERENCES ledger_entries(id),
+    account_type TEXT NOT NULL,
+    account_id TEXT NOT NULL,
+    asset pricing_asset NOT NULL,
+    amount BIGINT NOT NULL,
+    CONSTRAINT ledger_postings_account_type_check CHECK (account_type IN ('user', 'developer', 'treasury')),
+    CONSTRAINT ledger_postings_amount_check CHECK (amount <> 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_ledger_postings_entry_id ON ledger_postings(entry_id);
+CREATE INDEX IF NOT EXISTS idx_ledger_postings_account ON ledger_postings(account_type, account_id, asset);
+
+CREATE TABLE IF NOT EXISTS ledger_reconciliations (
+    id BIGSERIAL PRIMARY KEY,
+    discrepancy_count INTEGER NOT NULL,
+    report JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE OR REPLACE FUNCTION ledger_counterparty(fee_type TEXT, plugin_id TEXT, OUT account_type TEXT, OUT account_id TEXT) AS $$
+BEGIN
+    IF fee_type IN ('batch', 'batch_failed') THEN
+        account_type := 'treasury';
+        account_id := 'collections';
+    ELSIF plugin_id IS NOT NULL AND plugin_id <> '' AND plugin_id <> 'vultisig-fees-feee' THEN
+        account_type := 'developer';
+        account_id := plugin_id;
+    ELSE
+        account_type := 'treasury';
+        account_id := 'revenue';
+    END IF;
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+CREATE OR REPLACE FUNCTION post_fee_to_ledger() RETURNS TRIGGER AS $$
+DECLARE
+    new_entry_id BIGINT;
+    signed_amount BIGINT;
+    counterparty RECORD;
+BEGIN
+    signed_amount := CASE WHEN NEW.transaction_type = 'debit' THEN NEW.amount ELSE -NEW.amount END;
+    SELECT * INTO counterparty FROM ledger_counterparty(NEW.fee_type, NEW.plugin_id);
+
+    INSERT INTO ledger_entries (fee_id, entry_type, created_at)
+    VALUES (NEW.id, NEW.fee_type, NEW.created_at)
+    RETURNING id INTO new_entry_id;
+
+    INSERT INTO ledger_postings (entry_id, account_type, account_id, asset, amount) VALUES
+        (new_entry_id, 'user', NEW.public_key, NEW.asset, signed_amount),
+        (new_entry_id, counterparty.account_type, counterparty.account_id, NEW.asset, -signed_amount);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION check_ledger_entry_balanced() RETURNS TRIGGER AS $$
+DECLARE
+    total BIGINT;
+BEGIN
+    SELECT SUM(amount) INTO total FROM ledger_postings WHERE entry_id = NEW.entry_id;
+    IF total <> 0 THEN
+        RAISE EXCEPTION 'ledger entry % is not balanced: %', NEW.entry_id, total;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION prevent_ledger_modification() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION '% operation not allowed on %. The ledger is append only.', TG_OP, TG_TABLE_NAME
+        USING HINT = 'post a compensating fee instead.';
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_post_fee_to_ledger
+    AFTER INSERT ON fees
+    FOR EACH ROW
+    EXECUTE FUNCTION post_fee_to_ledger();
+
+CREATE CONSTRAINT TRIGGER trg_check_ledger_entry_balanced
+    AFTER INSERT ON ledger_postings
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW
+    EXECUTE FUNCTION check_ledger_entry_balanced();
+
+CREATE TRIGGER trg_prevent_ledger_entries_modification
+    BEFORE UPDATE OR DELETE ON ledger_entries
+    FOR EACH ROW
+    EXECUTE FUNCTION prevent_ledger_modification();
+
+CREATE TRIGGER trg_prevent_ledger_postings_modification
+    BEFORE UPDATE OR DELETE ON ledger_postings
+    FOR EACH ROW
+    EXECUTE FUNCTION prevent_ledger_modification();
+
+-- post the existing fees
+INSERT INTO ledger_entries (fee_id, entry_type, created_at)
+SELECT id, fee_type, created_at
+FROM fees
+ORDER BY id;
+
+INSERT INTO ledger_postings (entry_id, account_type, account_id, asset, amount)
+SELECT e.id, 'user', f.public_key, f.asset,
+       CASE WHEN f.transaction_type = 'debit' THEN f.amount ELSE -f.amount END
+FROM ledger_entries e
+JOIN fees f ON f.id = e.fee_id
+UNION ALL
+SELECT e.id, c.account_type, c.account_id, f.asset,
+       CASE WHEN f.transaction_type = 'debit' THEN -f.amount ELSE f.amount END
+FROM ledger_entries e
+JOIN fees f ON f.id = e.fee_id
+CROSS JOIN LATERAL ledger_counterparty(f.fee_type, f.plugin_id) c;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TRIGGER IF EXISTS trg_post_fee_to_ledger ON fees;
+DROP TABLE IF EXISTS ledger_reconciliations;
+DROP TABLE IF EXISTS ledger_postings;
+DROP TABLE IF EXISTS ledger_entries;
+DROP FUNCTION IF EXISTS prevent_ledger_modification();
+DROP FUNCTION IF EXISTS check_ledger_entry_balanced();
+DROP FUNCTION IF EXISTS post_fee_to_ledger();
+DROP FUNCTION IF EXISTS ledger_counterparty(TEXT, TEXT);
+
+-- +goose StatementEnd
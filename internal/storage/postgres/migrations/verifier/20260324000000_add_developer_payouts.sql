@@ -0,0 +1,53 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TYPE payout_status AS ENUM ('PENDING', 'SIGNING', 'COMPLETED', 'FAILED');
+
+-- a payout sends a developer the collected fees of a plugin in an asset, minus the platform share
+CREATE TABLE IF NOT EXISTS developer_payouts (
+    id BIGSERIAL PRIMARY KEY,
+    plugin_id plugin_id NOT NULL REFERENCES plugins(id),
+    payout_address TEXT NOT NULL,
+    asset pricing_asset NOT NULL,
+    period_end TIMESTAMPTZ NOT NULL,
+    gross_amount BIGINT NOT NULL,
+    platform_share BIGINT NOT NULL,
+    amount BIGINT NOT NULL,
+    status payout_status NOT NULL DEFAULT 'PENDING',
+    -- the policy ID of the payout txs in the tx indexer
+    keysign_policy_id UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+    tx_hash TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT developer_payouts_amount_check CHECK (amount > 0 AND platform_share >= 0),
+    CONSTRAINT developer_payouts_split_check CHECK (gross_amount = platform_share + amount)
+);
+
+CREATE INDEX IF NOT EXISTS idx_developer_payouts_plugin_id ON developer_payouts(plugin_id);
+CREATE INDEX IF NOT EXISTS idx_developer_payouts_open ON developer_payouts(status) WHERE status IN ('PENDING', 'SIGNING');
+
+-- the fees a payout pays, a fee is paid at most once and released when its payout fails
+CREATE TABLE IF NOT EXISTS developer_payout_fees (
+    fee_id BIGINT PRIMARY KEY REFERENCES fees(id),
+    payout_id BIGINT NOT NULL REFERENCES developer_payouts(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_developer_payout_fees_payout_id ON developer_payout_fees(payout_id);
+
+-- completed payouts are posted to the ledger too
+ALTER TABLE ledger_entries ALTER COLUMN fee_id DROP NOT NULL;
+ALTER TABLE ledger_entries ADD COLUMN payout_id BIGINT UNIQUE REFERENCES developer_payouts(id);
+ALTER TABLE ledger_entries ADD CONSTRAINT ledger_entries_source_check CHECK (num_nonnulls(fee_id, payout_id) = 1);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE ledger_entries DROP CONSTRAINT IF EXISTS ledger_entries_source_check;
+ALTER TABLE ledger_entries DROP COLUMN IF EXISTS payout_id;
+DROP TABLE IF EXISTS developer_payout_fees;
+DROP TABLE IF EXISTS developer_payouts;
+DROP TYPE IF EXISTS payout_status;
+
+-- +goose StatementEnd
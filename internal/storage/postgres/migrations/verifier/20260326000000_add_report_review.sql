@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TYPE report_status AS ENUM ('PENDING', 'VALID', 'INVALID');
+
+-- reports reviewed as INVALID no longer count towards auto-pause
+ALTER TABLE plugin_reports
+    ADD COLUMN status report_status NOT NULL DEFAULT 'PENDING',
+    ADD COLUMN reviewed_by TEXT,
+    ADD COLUMN reviewed_at TIMESTAMPTZ,
+    ADD COLUMN review_note TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_plugin_reports_status ON plugin_reports(status, last_reported_at DESC);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_plugin_reports_status;
+
+ALTER TABLE plugin_reports
+    DROP COLUMN IF EXISTS review_note,
+    DROP COLUMN IF EXISTS reviewed_at,
+    DROP COLUMN IF EXISTS reviewed_by,
+    DROP COLUMN IF EXISTS status;
+
+DROP TYPE IF EXISTS report_status;
+
+-- +goose StatementEnd
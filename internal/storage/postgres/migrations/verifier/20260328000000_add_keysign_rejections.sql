@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- sign requests rejected by the policy engine, accepted ones are tracked in tx_indexer
+CREATE TABLE IF NOT EXISTS keysign_rejections (
+    id BIGSERIAL PRIMARY KEY,
+    plugin_id plugin_id NOT NULL,
+    policy_id UUID NOT NULL,
+    reason TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_keysign_rejections_created_at ON keysign_rejections(created_at);
+
+CREATE INDEX IF NOT EXISTS idx_tx_indexer_plugin_id_created_at ON tx_indexer(plugin_id, created_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_tx_indexer_plugin_id_created_at;
+DROP TABLE IF EXISTS keysign_rejections;
+
+-- +goose StatementEnd
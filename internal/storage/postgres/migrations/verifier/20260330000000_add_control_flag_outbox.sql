@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- control flag changes to push to plugin servers, delivery sends the current value of the keys
+CREATE TABLE IF NOT EXISTS control_flag_outbox (
+    id BIGSERIAL PRIMARY KEY,
+    plugin_id plugin_id NOT NULL,
+    keys TEXT[] NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    delivered_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_control_flag_outbox_pending ON control_flag_outbox(next_attempt_at) WHERE delivered_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS control_flag_outbox;
+
+-- +goose StatementEnd
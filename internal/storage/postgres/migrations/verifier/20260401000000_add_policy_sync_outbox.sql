@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- plugin_policy_sync becomes the outbox of policy changes to deliver to plugin servers,
+-- seq orders the changes of a policy and payload is the body sent to the plugin server
+ALTER TABLE plugin_policy_sync
+    ADD COLUMN IF NOT EXISTS seq BIGSERIAL,
+    ADD COLUMN IF NOT EXISTS payload JSONB,
+    ADD COLUMN IF NOT EXISTS attempts INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ADD COLUMN IF NOT EXISTS locked_until TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS synced_at TIMESTAMPTZ;
+
+-- the rows written so far were synced during the request, they have no payload to deliver
+UPDATE plugin_policy_sync SET status = 2 WHERE status <> 2;
+
+CREATE INDEX IF NOT EXISTS idx_plugin_policy_sync_pending ON plugin_policy_sync(policy_id, seq) WHERE status <> 2;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_plugin_policy_sync_pending;
+
+ALTER TABLE plugin_policy_sync
+    DROP COLUMN IF EXISTS seq,
+    DROP COLUMN IF EXISTS payload,
+    DROP COLUMN IF EXISTS attempts,
+    DROP COLUMN IF EXISTS next_attempt_at,
+    DROP COLUMN IF EXISTS locked_until,
+    DROP COLUMN IF EXISTS synced_at;
+
+-- +goose StatementEnd
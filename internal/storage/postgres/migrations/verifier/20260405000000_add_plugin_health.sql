@@ -0,0 +1,53 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- the verifier caches the endpoint and API key of each plugin server, changes are notified so the cache is dropped
+CREATE OR REPLACE FUNCTION notify_plugin_server_info() RETURNS trigger AS $$
+DECLARE
+    rec RECORD;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        rec := OLD;
+    ELSE
+        rec := NEW;
+    END IF;
+    IF TG_TABLE_NAME = 'plugins' THEN
+        PERFORM pg_notify('plugin_server_info', rec.id::text);
+    ELSE
+        PERFORM pg_notify('plugin_server_info', rec.plugin_id::text);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_notify_plugins_server_info
+    AFTER UPDATE OF server_endpoint OR DELETE ON plugins
+    FOR EACH ROW EXECUTE FUNCTION notify_plugin_server_info();
+
+CREATE TRIGGER trg_notify_plugin_apikey_server_info
+    AFTER INSERT OR UPDATE OR DELETE ON plugin_apikey
+    FOR EACH ROW EXECUTE FUNCTION notify_plugin_server_info();
+
+-- latest probe of the /healthz endpoint of each plugin server
+CREATE TABLE IF NOT EXISTS plugin_health (
+    plugin_id plugin_id PRIMARY KEY REFERENCES plugins(id) ON DELETE CASCADE,
+    available BOOLEAN NOT NULL,
+    status_code INTEGER,
+    latency_ms INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    consecutive_failures INTEGER NOT NULL DEFAULT 0,
+    checked_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_available_at TIMESTAMPTZ
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS plugin_health;
+DROP TRIGGER IF EXISTS trg_notify_plugin_apikey_server_info ON plugin_apikey;
+DROP TRIGGER IF EXISTS trg_notify_plugins_server_info ON plugins;
+DROP FUNCTION IF EXISTS notify_plugin_server_info();
+
+-- +goose StatementEnd
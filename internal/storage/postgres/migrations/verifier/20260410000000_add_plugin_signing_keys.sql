@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS plugin_signing_keys (
+    key_id TEXT PRIMARY KEY,
+    plugin_id plugin_id NOT NULL REFERENCES plugins(id) ON DELETE CASCADE,
+    public_key TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_plugin_signing_keys_plugin_id ON plugin_signing_keys(plugin_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS plugin_signing_keys;
+-- +goose StatementEnd
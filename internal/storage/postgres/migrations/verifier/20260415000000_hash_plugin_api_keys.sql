@@ -0,0 +1,61 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- API keys are stored as a salted SHA-256 hash, the first characters of a key are kept in clear to find it
+ALTER TABLE plugin_apikey
+    ADD COLUMN key_prefix TEXT,
+    ADD COLUMN key_salt TEXT,
+    ADD COLUMN key_hash TEXT,
+    ADD COLUMN scopes TEXT[] NOT NULL DEFAULT ARRAY['sign', 'fees:read', 'fees:collect'],
+    ADD COLUMN allowed_ips TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN last_used_at TIMESTAMPTZ,
+    ADD COLUMN last_used_ip TEXT;
+
+UPDATE plugin_apikey
+SET key_prefix = LEFT(apikey, 12),
+    key_salt = md5(random()::text || clock_timestamp()::text || id::text);
+
+UPDATE plugin_apikey
+SET key_hash = encode(sha256(decode(key_salt, 'hex') || convert_to(apikey, 'UTF8')), 'hex');
+
+-- existing keys keep the access they had, new keys are issued with explicit scopes
+ALTER TABLE plugin_apikey
+    ALTER COLUMN key_prefix SET NOT NULL,
+    ALTER COLUMN key_salt SET NOT NULL,
+    ALTER COLUMN key_hash SET NOT NULL,
+    ALTER COLUMN scopes DROP DEFAULT;
+
+-- the verifier no longer caches API keys, and last used updates must not drop the server info cache
+DROP TRIGGER IF EXISTS trg_notify_plugin_apikey_server_info ON plugin_apikey;
+
+DROP INDEX IF EXISTS idx_plugin_apikey_apikey;
+ALTER TABLE plugin_apikey DROP COLUMN apikey;
+
+CREATE INDEX IF NOT EXISTS idx_plugin_apikey_key_prefix ON plugin_apikey(key_prefix);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+-- the plaintext keys cannot be restored, the keys have to be issued again
+DROP INDEX IF EXISTS idx_plugin_apikey_key_prefix;
+ALTER TABLE plugin_apikey ADD COLUMN apikey TEXT;
+UPDATE plugin_apikey SET apikey = key_hash;
+ALTER TABLE plugin_apikey
+    ALTER COLUMN apikey SET NOT NULL,
+    ADD CONSTRAINT plugin_apikey_apikey_key UNIQUE (apikey),
+    DROP COLUMN key_prefix,
+    DROP COLUMN key_salt,
+    DROP COLUMN key_hash,
+    DROP COLUMN scopes,
+    DROP COLUMN allowed_ips,
+    DROP COLUMN last_used_at,
+    DROP COLUMN last_used_ip;
+CREATE INDEX IF NOT EXISTS idx_plugin_apikey_apikey ON plugin_apikey(apikey);
+
+CREATE TRIGGER trg_notify_plugin_apikey_server_info
+    AFTER INSERT OR UPDATE OR DELETE ON plugin_apikey
+    FOR EACH ROW EXECUTE FUNCTION notify_plugin_server_info();
+
+-- +goose StatementEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+-- every refresh rotates the refresh token, the tokens rotated from the same login share a family
+ALTER TABLE vault_tokens
+    ADD COLUMN IF NOT EXISTS family_id UUID,
+    ADD COLUMN IF NOT EXISTS parent_token_id VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS rotated_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS device_name TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS user_agent TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS ip_address TEXT NOT NULL DEFAULT '';
+
+UPDATE vault_tokens SET family_id = gen_random_uuid() WHERE family_id IS NULL;
+
+ALTER TABLE vault_tokens
+    ALTER COLUMN family_id SET DEFAULT gen_random_uuid(),
+    ALTER COLUMN family_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_vault_tokens_family_id ON vault_tokens(family_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_vault_tokens_family_id;
+
+ALTER TABLE vault_tokens
+    DROP COLUMN IF EXISTS family_id,
+    DROP COLUMN IF EXISTS parent_token_id,
+    DROP COLUMN IF EXISTS rotated_at,
+    DROP COLUMN IF EXISTS device_name,
+    DROP COLUMN IF EXISTS user_agent,
+    DROP COLUMN IF EXISTS ip_address;
+-- +goose StatementEnd
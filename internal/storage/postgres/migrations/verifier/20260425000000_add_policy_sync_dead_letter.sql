@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- a dead-lettered change (status 5) is no longer retried and doesn't hold back the later changes of its policy
+DROP INDEX IF EXISTS idx_plugin_policy_sync_pending;
+CREATE INDEX IF NOT EXISTS idx_plugin_policy_sync_pending ON plugin_policy_sync(policy_id, seq) WHERE status NOT IN (2, 5);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_plugin_policy_sync_pending;
+CREATE INDEX IF NOT EXISTS idx_plugin_policy_sync_pending ON plugin_policy_sync(policy_id, seq) WHERE status <> 2;
+
+-- +goose StatementEnd
@@ -0,0 +1,49 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- a tx put back to pending by a recheck was billed a second time, the extra fees are refunded
+-- and moved apart so that a tx_indexer record carries a single tx execution fee
+WITH duplicates AS (
+    SELECT id
+    FROM (
+        SELECT id, ROW_NUMBER() OVER (PARTITION BY underlying_id ORDER BY id) AS n
+        FROM fees
+        WHERE fee_type = 'transaction_execution_fee'
+          AND underlying_type = 'tx_indexer_record'
+          AND transaction_type = 'debit'
+    ) billed
+    WHERE n > 1
+)
+INSERT INTO fees (policy_id, plugin_id, public_key, transaction_type, amount, asset, fee_type, metadata, underlying_type, underlying_id)
+SELECT f.policy_id, f.plugin_id, f.public_key, 'credit', f.amount, f.asset, 'refund',
+       jsonb_build_object('debit_fee_id', f.id, 'tx_hash', '', 'network', '', 'reason', 'duplicate_charge'),
+       'fee', f.id::text
+FROM fees f
+JOIN duplicates d ON d.id = f.id
+WHERE NOT EXISTS (
+    SELECT 1 FROM fees r
+    WHERE r.fee_type = 'refund' AND r.underlying_type = 'fee' AND r.underlying_id = f.id::text
+);
+
+UPDATE fees f
+SET underlying_type = 'tx_indexer_record_duplicate'
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY underlying_id ORDER BY id) AS n
+    FROM fees
+    WHERE fee_type = 'transaction_execution_fee'
+      AND underlying_type = 'tx_indexer_record'
+      AND transaction_type = 'debit'
+) billed
+WHERE billed.id = f.id AND billed.n > 1;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_tx_exec_fee_per_tx ON fees(underlying_id)
+WHERE fee_type = 'transaction_execution_fee' AND underlying_type = 'tx_indexer_record' AND transaction_type = 'debit';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_unique_tx_exec_fee_per_tx;
+
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- successful txs whose fee couldn't be priced yet, the pricing is retried with a backoff
+-- while the tx keeps its on-chain status
+CREATE TABLE IF NOT EXISTS unbilled_txs (
+    tx_id UUID PRIMARY KEY REFERENCES tx_indexer(id) ON DELETE CASCADE,
+    attempts INTEGER NOT NULL DEFAULT 1,
+    last_error TEXT NOT NULL,
+    next_attempt_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_unbilled_txs_next_attempt_at ON unbilled_txs(next_attempt_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS unbilled_txs;
+
+-- +goose StatementEnd
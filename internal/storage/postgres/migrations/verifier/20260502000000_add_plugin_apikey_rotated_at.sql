@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- a rotated key stays valid until the end of its overlap, it must not be rotated again meanwhile
+ALTER TABLE plugin_apikey
+    ADD COLUMN IF NOT EXISTS rotated_at TIMESTAMPTZ;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE plugin_apikey
+    DROP COLUMN IF EXISTS rotated_at;
+
+-- +goose StatementEnd
@@ -0,0 +1,325 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	itypes "github.com/vultisig/verifier/internal/types"
+	"github.com/vultisig/verifier/plugin/tx_indexer/pkg/rpc"
+	"github.com/vultisig/verifier/types"
+)
+
+const payoutColumns = `dp.id, dp.plugin_id, dp.payout_address, dp.asset, dp.period_end, dp.gross_amount,
+    dp.platform_share, dp.amount, dp.status, dp.keysign_policy_id, dp.tx_hash, dp.created_at, dp.updated_at`
+
+func scanPayout(row pgx.Row) (*itypes.DeveloperPayout, error) {
+	var payout itypes.DeveloperPayout
+	err := row.Scan(
+		&payout.ID,
+		&payout.PluginID,
+		&payout.PayoutAddress,
+		&payout.Asset,
+		&payout.PeriodEnd,
+		&payout.GrossAmount,
+		&payout.PlatformShare,
+		&payout.Amount,
+		&payout.Status,
+		&payout.KeysignPolicyID,
+		&payout.TxHash,
+		&payout.CreatedAt,
+		&payout.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &payout, nil
+}
+
+// GetPayableFees returns the fees of the plugins collected before periodEnd which weren't paid out yet,
+// with the refunds of collected fees. Fees of a failed batch are collected once its compensation fee is.
+func (p *PostgresBackend) GetPayableFees(ctx context.Context, periodEnd time.Time) ([]itypes.PayableFee, error) {
+	rows, err := p.pool.Query(ctx, `
+        WITH completed AS (
+            SELECT id FROM fee_batches WHERE status = 'COMPLETED' AND created_at < $1
+        ),
+        collected AS (
+            SELECT fbm.fee_id
+            FROM fee_batch_members fbm
+            JOIN completed c ON c.id = fbm.batch_id
+            UNION
+            SELECT fbm.fee_id
+            FROM fee_batch_members fbm
+            JOIN fee_batches fb ON fb.id = fbm.batch_id AND fb.status = 'FAILED'
+            JOIN fees bf ON bf.fee_type = $4 AND bf.underlying_type = 'batch' AND bf.underlying_id = fb.id::text
+            JOIN fee_batch_members bfm ON bfm.fee_id = bf.id
+            JOIN completed c ON c.id = bfm.batch_id
+        )
+        SELECT
+            f.id,
+            f.plugin_id,
+            COALESCE(p.payout_address, ''),
+            f.asset,
+            (CASE WHEN f.transaction_type = 'debit' THEN f.amount ELSE -f.amount END)::bigint
+        FROM fees f
+        JOIN plugins p ON p.id = f.plugin_id
+        WHERE f.plugin_id <> $2
+          AND f.created_at < $1
+          AND NOT EXISTS (SELECT 1 FROM developer_payout_fees dpf WHERE dpf.fee_id = f.id)
+          AND (
+              (f.transaction_type = 'debit' AND f.id IN (SELECT fee_id FROM collected))
+              OR (f.fee_type = $3 AND f.underlying_type = 'fee'
+                  AND f.underlying_id IN (SELECT fee_id::text FROM collected))
+          )
+        ORDER BY f.id
+    `, periodEnd, types.PluginVultisigFees_feee, types.FeeTypeRefund, types.FeeTypeBatchFailed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query payable fees: %w", err)
+	}
+	defer rows.Close()
+
+	var fees []itypes.PayableFee
+	for rows.Next() {
+		var fee itypes.PayableFee
+		err := rows.Scan(&fee.FeeID, &fee.PluginID, &fee.PayoutAddress, &fee.Asset, &fee.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan payable fee: %w", err)
+		}
+		fees = append(fees, fee)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating payable fees: %w", err)
+	}
+	return fees, nil
+}
+
+// InsertDeveloperPayouts inserts the payouts with the fees they pay, and sets their IDs
+func (p *PostgresBackend) InsertDeveloperPayouts(ctx context.Context, dbTx pgx.Tx, payouts []*itypes.DeveloperPayout) error {
+	for _, payout := range payouts {
+		err := dbTx.QueryRow(ctx, `
+            INSERT INTO developer_payouts
+                (plugin_id, payout_address, asset, period_end, gross_amount, platform_share, amount)
+            VALUES ($1, $2, $3, $4, $5, $6, $7)
+            RETURNING id, status, keysign_policy_id, created_at, updated_at
+        `,
+			payout.PluginID,
+			payout.PayoutAddress,
+			payout.Asset,
+			payout.PeriodEnd,
+			payout.GrossAmount,
+			payout.PlatformShare,
+			payout.Amount,
+		).Scan(&payout.ID, &payout.Status, &payout.KeysignPolicyID, &payout.CreatedAt, &payout.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to insert payout of %s: %w", payout.PluginID, err)
+		}
+
+		_, err = dbTx.Exec(ctx, `
+            INSERT INTO developer_payout_fees (fee_id, payout_id)
+            SELECT unnest($1::bigint[]), $2
+        `, payout.FeeIDs, payout.ID)
+		if err != nil {
+			return fmt.Errorf("failed to insert fees of payout %d: %w", payout.ID, err)
+		}
+	}
+	return nil
+}
+
+// GetOpenPayouts returns the payouts to sign: the pending ones, and the ones whose keysign
+// was requested before staleBefore but never produced a signed tx
+func (p *PostgresBackend) GetOpenPayouts(ctx context.Context, staleBefore time.Time) ([]itypes.DeveloperPayout, error) {
+	rows, err := p.pool.Query(ctx, `
+        SELECT `+payoutColumns+`
+        FROM developer_payouts dp
+        WHERE dp.status = 'PENDING'
+           OR (dp.status = 'SIGNING' AND dp.updated_at < $1 AND NOT EXISTS (
+               SELECT 1 FROM tx_indexer t WHERE t.policy_id = dp.keysign_policy_id AND t.tx_hash IS NOT NULL
+           ))
+        ORDER BY dp.id
+    `, staleBefore)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query open payouts: %w", err)
+	}
+	defer rows.Close()
+
+	payouts := make([]itypes.DeveloperPayout, 0)
+	for rows.Next() {
+		payout, err := scanPayout(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan payout: %w", err)
+		}
+		payouts = append(payouts, *payout)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating open payouts: %w", err)
+	}
+	return payouts, nil
+}
+
+// ClaimPayout marks an open payout (see GetOpenPayouts) as signing, nil is returned when it isn't open
+func (p *PostgresBackend) ClaimPayout(ctx context.Context, id uint64, staleBefore time.Time) (*itypes.DeveloperPayout, error) {
+	payout, err := scanPayout(p.pool.QueryRow(ctx, `
+        UPDATE developer_payouts dp
+        SET status = 'SIGNING', updated_at = NOW()
+        WHERE dp.id = $1
+          AND (dp.status = 'PENDING'
+               OR (dp.status = 'SIGNING' AND dp.updated_at < $2 AND NOT EXISTS (
+                   SELECT 1 FROM tx_indexer t WHERE t.policy_id = dp.keysign_policy_id AND t.tx_hash IS NOT NULL
+               )))
+        RETURNING `+payoutColumns, id, staleBefore))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to claim payout %d: %w", id, err)
+	}
+	return payout, nil
+}
+
+// UpdatePayoutStatus updates the payout of the tx indexer policy ID with the on-chain status of its tx.
+// A completed payout is posted to the ledger, the fees of a failed one are released for the next payout.
+// It returns false when the policy ID isn't the one of a payout.
+func (p *PostgresBackend) UpdatePayoutStatus(
+	ctx context.Context,
+	dbTx pgx.Tx,
+	keysignPolicyID uuid.UUID,
+	txHash string,
+	status *rpc.TxOnChainStatus,
+) (bool, error) {
+	if status == nil {
+		return false, fmt.Errorf("status cannot be nil")
+	}
+
+	payout, err := scanPayout(dbTx.QueryRow(ctx, `
+        SELECT `+payoutColumns+`
+        FROM developer_payouts dp
+        WHERE dp.keysign_policy_id = $1
+        FOR UPDATE
+    `, keysignPolicyID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get payout of keysign policy %s: %w", keysignPolicyID, err)
+	}
+	if payout.Status != itypes.PayoutSigning {
+		return true, nil
+	}
+
+	switch *status {
+	case rpc.TxOnChainSuccess:
+		_, err = dbTx.Exec(ctx, `
+            UPDATE developer_payouts
+            SET status = 'COMPLETED', tx_hash = $2, updated_at = NOW()
+            WHERE id = $1
+        `, payout.ID, txHash)
+		if err != nil {
+			return true, fmt.Errorf("failed to update payout %d status to COMPLETED: %w", payout.ID, err)
+		}
+		if err := postPayoutToLedger(ctx, dbTx, payout); err != nil {
+			return true, err
+		}
+		return true, nil
+	case rpc.TxOnChainFail:
+		_, err = dbTx.Exec(ctx, `
+            UPDATE developer_payouts
+            SET status = 'FAILED', tx_hash = $2, updated_at = NOW()
+            WHERE id = $1
+        `, payout.ID, txHash)
+		if err != nil {
+			return true, fmt.Errorf("failed to update payout %d status to FAILED: %w", payout.ID, err)
+		}
+		_, err = dbTx.Exec(ctx, `DELETE FROM developer_payout_fees WHERE payout_id = $1`, payout.ID)
+		if err != nil {
+			return true, fmt.Errorf("failed to release fees of payout %d: %w", payout.ID, err)
+		}
+		return true, nil
+	case rpc.TxOnChainPending:
+		return true, nil
+	default:
+		return true, fmt.Errorf("unknown status: %s", *status)
+	}
+}
+
+// postPayoutToLedger settles the developer account: the platform share goes to the treasury revenue,
+// the amount sent on chain to the treasury payouts account
+func postPayoutToLedger(ctx context.Context, dbTx pgx.Tx, payout *itypes.DeveloperPayout) error {
+	var entryID uint64
+	err := dbTx.QueryRow(ctx, `
+        INSERT INTO ledger_entries (payout_id, entry_type)
+        VALUES ($1, 'payout')
+        RETURNING id
+    `, payout.ID).Scan(&entryID)
+	if err != nil {
+		return fmt.Errorf("failed to insert ledger entry of payout %d: %w", payout.ID, err)
+	}
+
+	_, err = dbTx.Exec(ctx, `
+        INSERT INTO ledger_postings (entry_id, account_type, account_id, asset, amount)
+        SELECT $1, account_type, account_id, $2, amount
+        FROM (VALUES
+            ('developer', $3::text, $4::bigint),
+            ('treasury', $5::text, -$6::bigint),
+            ('treasury', $7::text, -$8::bigint)
+        ) AS postings(account_type, account_id, amount)
+        WHERE amount <> 0
+    `,
+		entryID,
+		payout.Asset,
+		payout.PluginID.String(), payout.GrossAmount,
+		itypes.TreasuryRevenue, payout.PlatformShare,
+		itypes.TreasuryPayouts, payout.Amount,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert ledger postings of payout %d: %w", payout.ID, err)
+	}
+	return nil
+}
+
+// GetDeveloperPayouts returns the payouts of the plugins owned by ownerPublicKey, of pluginID only when it
+// isn't empty, most recent first
+func (p *PostgresBackend) GetDeveloperPayouts(
+	ctx context.Context,
+	ownerPublicKey string,
+	pluginID string,
+	skip, take uint32,
+) ([]itypes.DeveloperPayout, uint32, error) {
+	const filter = `
+        FROM developer_payouts dp
+        WHERE dp.plugin_id IN (
+            SELECT po.plugin_id FROM plugin_owners po WHERE po.public_key = $1 AND po.active = true
+        )
+          AND ($2 = '' OR dp.plugin_id = $2)`
+
+	var totalCount uint32
+	err := p.pool.QueryRow(ctx, `SELECT COUNT(*)`+filter, ownerPublicKey, pluginID).Scan(&totalCount)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count developer payouts: %w", err)
+	}
+
+	rows, err := p.pool.Query(ctx, `SELECT `+payoutColumns+filter+`
+        ORDER BY dp.id DESC
+        LIMIT $3 OFFSET $4
+    `, ownerPublicKey, pluginID, take, skip)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query developer payouts: %w", err)
+	}
+	defer rows.Close()
+
+	payouts := make([]itypes.DeveloperPayout, 0)
+	for rows.Next() {
+		payout, err := scanPayout(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan developer payout: %w", err)
+		}
+		payouts = append(payouts, *payout)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating developer payouts: %w", err)
+	}
+	return payouts, totalCount, nil
+}
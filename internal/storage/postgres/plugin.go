@@ -33,6 +33,8 @@ type nullablePricing struct {
 	PluginID  *string
 	CreatedAt *time.Time
 	UpdatedAt *time.Time
+	MinAmount *uint64
+	MaxAmount *uint64
 }
 
 func convertNullablePricing(np *nullablePricing) *types.Pricing {
@@ -58,6 +60,8 @@ func convertNullablePricing(np *nullablePricing) *types.Pricing {
 		PluginID:  types.PluginID(*np.PluginID),
 		CreatedAt: *np.CreatedAt,
 		UpdatedAt: *np.UpdatedAt,
+		MinAmount: np.MinAmount,
+		MaxAmount: np.MaxAmount,
 	}
 }
 
@@ -76,6 +80,7 @@ func (p *PostgresBackend) collectPlugins(rows pgx.Rows) ([]itypes.Plugin, error)
 		var faqJSON []byte
 		var featuresJSON []byte
 		var audited sql.NullBool
+		var payoutAddress sql.NullString
 		var installations sql.NullInt64
 		var ratesCount sql.NullInt64
 		var avgRating sql.NullFloat64
@@ -93,6 +98,7 @@ func (p *PostgresBackend) collectPlugins(rows pgx.Rows) ([]itypes.Plugin, error)
 			&faqJSON,
 			&featuresJSON,
 			&audited,
+			&payoutAddress,
 			&tagID,
 			&tagName,
 			&tagCreatedAt,
@@ -105,6 +111,8 @@ func (p *PostgresBackend) collectPlugins(rows pgx.Rows) ([]itypes.Plugin, error)
 			&nullablePricing.PluginID,
 			&nullablePricing.CreatedAt,
 			&nullablePricing.UpdatedAt,
+			&nullablePricing.MinAmount,
+			&nullablePricing.MaxAmount,
 			&installations,
 			&ratesCount,
 			&avgRating,
@@ -146,6 +154,7 @@ func (p *PostgresBackend) collectPlugins(rows pgx.Rows) ([]itypes.Plugin, error)
 			} else {
 				plugin.Audited = false
 			}
+			plugin.PayoutAddress = payoutAddress.String
 			if installations.Valid {
 				plugin.Installations = int(installations.Int64)
 			} else {
@@ -664,6 +673,51 @@ func (p *PostgresBackend) GetControlFlags(ctx context.Context, k1, k2 string) (m
 	return result, nil
 }
 
+func (p *PostgresBackend) GetControlFlagsByKeys(ctx context.Context, keys []string) (map[string]bool, error) {
+	result := make(map[string]bool, len(keys))
+
+	rows, err := p.pool.Query(ctx, `SELECT key, enabled FROM control_flags WHERE key = ANY($1)`, keys)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var k string
+		var enabled bool
+		if err := rows.Scan(&k, &enabled); err != nil {
+			return nil, err
+		}
+		result[k] = enabled
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (p *PostgresBackend) ListControlFlags(ctx context.Context) ([]itypes.ControlFlag, error) {
+	rows, err := p.pool.Query(ctx, `SELECT key, enabled, updated_at FROM control_flags ORDER BY key`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query control flags: %w", err)
+	}
+	defer rows.Close()
+
+	flags := make([]itypes.ControlFlag, 0)
+	for rows.Next() {
+		var flag itypes.ControlFlag
+		if err := rows.Scan(&flag.Key, &flag.Enabled, &flag.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan control flag: %w", err)
+		}
+		flags = append(flags, flag)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate control flags: %w", err)
+	}
+	return flags, nil
+}
+
 func EnrichPluginsWithImages(plugins []itypes.Plugin, imageRecords []itypes.PluginImageRecord, assetBaseURL string) {
 	imagesByPlugin := make(map[types.PluginID][]itypes.PluginImageRecord)
 	for _, rec := range imageRecords {
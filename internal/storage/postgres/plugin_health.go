@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	itypes "github.com/vultisig/verifier/internal/types"
+	"github.com/vultisig/verifier/types"
+)
+
+// PluginServerInfoChannel is notified with the plugin ID when the endpoint of a plugin changes
+const PluginServerInfoChannel = "plugin_server_info"
+
+// ListenPluginServerInfo calls fn with every plugin notified on PluginServerInfoChannel until ctx is done
+func (p *PostgresBackend) ListenPluginServerInfo(ctx context.Context, fn func(types.PluginID)) error {
+	conn, err := p.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	_, err = conn.Exec(ctx, "LISTEN "+PluginServerInfoChannel)
+	if err != nil {
+		return fmt.Errorf("failed to listen to plugin server info: %w", err)
+	}
+	defer func() {
+		_, _ = conn.Exec(context.Background(), "UNLISTEN "+PluginServerInfoChannel)
+	}()
+
+	for {
+		notification, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to wait for plugin server info: %w", err)
+		}
+		fn(types.PluginID(notification.Payload))
+	}
+}
+
+func (p *PostgresBackend) GetPluginEndpoints(ctx context.Context) (map[types.PluginID]string, error) {
+	rows, err := p.pool.Query(ctx, `SELECT id, server_endpoint FROM plugins`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query plugin endpoints: %w", err)
+	}
+	defer rows.Close()
+
+	endpoints := make(map[types.PluginID]string)
+	for rows.Next() {
+		var pluginID types.PluginID
+		var endpoint string
+		err = rows.Scan(&pluginID, &endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan plugin endpoint: %w", err)
+		}
+		endpoints[pluginID] = endpoint
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+	return endpoints, nil
+}
+
+// UpsertPluginHealth records a probe, the failures are counted until the plugin server answers again
+func (p *PostgresBackend) UpsertPluginHealth(ctx context.Context, health itypes.PluginHealth) error {
+	_, err := p.pool.Exec(ctx, `
+		INSERT INTO plugin_health (plugin_id, available, status_code, latency_ms, last_error, consecutive_failures, checked_at, last_available_at)
+		VALUES ($1, $2, $3, $4, $5, CASE WHEN $2 THEN 0 ELSE 1 END, $6, CASE WHEN $2 THEN $6::timestamptz END)
+		ON CONFLICT (plugin_id) DO UPDATE SET
+			available = EXCLUDED.available,
+			status_code = EXCLUDED.status_code,
+			latency_ms = EXCLUDED.latency_ms,
+			last_error = EXCLUDED.last_error,
+			consecutive_failures = CASE WHEN EXCLUDED.available THEN 0 ELSE plugin_health.consecutive_failures + 1 END,
+			checked_at = EXCLUDED.checked_at,
+			last_available_at = COALESCE(EXCLUDED.last_available_at, plugin_health.last_available_at)`,
+		health.PluginID, health.Available, health.StatusCode, health.LatencyMs, health.LastError, health.CheckedAt)
+	if err != nil {
+		return fmt.Errorf("failed to upsert plugin health: %w", err)
+	}
+	return nil
+}
+
+func (p *PostgresBackend) GetPluginHealth(ctx context.Context, pluginIDs []types.PluginID) (map[types.PluginID]itypes.PluginHealth, error) {
+	rows, err := p.pool.Query(ctx, `
+		SELECT plugin_id, available, status_code, latency_ms, last_error, consecutive_failures, checked_at, last_available_at
+		FROM plugin_health
+		WHERE plugin_id = ANY($1)`, pluginIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query plugin health: %w", err)
+	}
+	defer rows.Close()
+
+	health := make(map[types.PluginID]itypes.PluginHealth)
+	for rows.Next() {
+		var h itypes.PluginHealth
+		err = rows.Scan(&h.PluginID, &h.Available, &h.StatusCode, &h.LatencyMs, &h.LastError,
+			&h.ConsecutiveFailures, &h.CheckedAt, &h.LastAvailableAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan plugin health: %w", err)
+		}
+		health[h.PluginID] = h
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+	return health, nil
+}
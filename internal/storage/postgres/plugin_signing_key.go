@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	itypes "github.com/vultisig/verifier/internal/types"
+	"github.com/vultisig/verifier/types"
+)
+
+const pluginSigningKeyColumns = `key_id, plugin_id, public_key, created_at, expires_at, revoked_at`
+
+func scanPluginSigningKey(row pgx.Row) (*itypes.PluginSigningKey, error) {
+	var key itypes.PluginSigningKey
+	err := row.Scan(&key.KeyID, &key.PluginID, &key.PublicKey, &key.CreatedAt, &key.ExpiresAt, &key.RevokedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// CreatePluginSigningKey registers a signing key of a plugin, the keys it already has expire after overlap
+func (p *PostgresBackend) CreatePluginSigningKey(ctx context.Context, key itypes.PluginSigningKey, overlap time.Duration) (*itypes.PluginSigningKey, error) {
+	var created *itypes.PluginSigningKey
+	err := p.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `
+			UPDATE plugin_signing_keys
+			SET expires_at = LEAST(COALESCE(expires_at, NOW() + $2::interval), NOW() + $2::interval)
+			WHERE plugin_id = $1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > NOW())`,
+			key.PluginID, fmt.Sprintf("%d seconds", int64(overlap.Seconds())))
+		if err != nil {
+			return fmt.Errorf("failed to expire previous signing keys: %w", err)
+		}
+
+		created, err = scanPluginSigningKey(tx.QueryRow(ctx, `
+			INSERT INTO plugin_signing_keys (key_id, plugin_id, public_key, expires_at)
+			VALUES ($1, $2, $3, $4)
+			RETURNING `+pluginSigningKeyColumns,
+			key.KeyID, key.PluginID, key.PublicKey, key.ExpiresAt))
+		if err != nil {
+			return fmt.Errorf("failed to insert signing key: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (p *PostgresBackend) GetPluginSigningKeys(ctx context.Context, pluginID types.PluginID) ([]itypes.PluginSigningKey, error) {
+	rows, err := p.pool.Query(ctx, `
+		SELECT `+pluginSigningKeyColumns+`
+		FROM plugin_signing_keys
+		WHERE plugin_id = $1
+		ORDER BY created_at DESC`, pluginID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query signing keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []itypes.PluginSigningKey
+	for rows.Next() {
+		key, err := scanPluginSigningKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan signing key: %w", err)
+		}
+		keys = append(keys, *key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+	return keys, nil
+}
+
+// GetPluginSigningKey returns nil when no key has the ID
+func (p *PostgresBackend) GetPluginSigningKey(ctx context.Context, keyID string) (*itypes.PluginSigningKey, error) {
+	key, err := scanPluginSigningKey(p.pool.QueryRow(ctx, `
+		SELECT `+pluginSigningKeyColumns+`
+		FROM plugin_signing_keys
+		WHERE key_id = $1`, keyID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get signing key: %w", err)
+	}
+	return key, nil
+}
+
+// RevokePluginSigningKey reports whether the plugin had the key and it was not revoked yet
+func (p *PostgresBackend) RevokePluginSigningKey(ctx context.Context, pluginID types.PluginID, keyID string) (bool, error) {
+	tag, err := p.pool.Exec(ctx, `
+		UPDATE plugin_signing_keys
+		SET revoked_at = NOW()
+		WHERE plugin_id = $1 AND key_id = $2 AND revoked_at IS NULL`, pluginID, keyID)
+	if err != nil {
+		return false, fmt.Errorf("failed to revoke signing key: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
@@ -353,8 +353,8 @@ func (p *PostgresBackend) DeletePluginPolicyTx(ctx context.Context, dbTx pgx.Tx,
 }
 
 func (p *PostgresBackend) AddPluginPolicySync(ctx context.Context, dbTx pgx.Tx, policy itypes.PluginPolicySync) error {
-	qry := `INSERT INTO plugin_policy_sync (id, policy_id, sync_type, signature, status, reason, plugin_id) 
-		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	qry := `INSERT INTO plugin_policy_sync (id, policy_id, sync_type, signature, status, reason, plugin_id, payload) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
 	_, err := dbTx.Exec(ctx, qry,
 		policy.ID,
 		policy.PolicyID,
@@ -362,7 +362,8 @@ func (p *PostgresBackend) AddPluginPolicySync(ctx context.Context, dbTx pgx.Tx,
 		policy.Signature,
 		policy.Status,
 		policy.FailReason,
-		policy.PluginID)
+		policy.PluginID,
+		policy.Payload)
 	if err != nil {
 		return fmt.Errorf("failed to insert plugin policy sync: %w", err)
 	}
@@ -0,0 +1,154 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	itypes "github.com/vultisig/verifier/internal/types"
+	"github.com/vultisig/verifier/types"
+)
+
+const policySyncColumns = `id, policy_id, plugin_id, sync_type, COALESCE(signature, ''), status, COALESCE(reason, ''),
+	attempts, next_attempt_at, synced_at, created_at`
+
+func scanPolicySync(row pgx.Row, dest ...any) (itypes.PluginPolicySync, error) {
+	var sync itypes.PluginPolicySync
+	err := row.Scan(append([]any{
+		&sync.ID,
+		&sync.PolicyID,
+		&sync.PluginID,
+		&sync.SyncType,
+		&sync.Signature,
+		&sync.Status,
+		&sync.FailReason,
+		&sync.Attempts,
+		&sync.NextAttemptAt,
+		&sync.SyncedAt,
+		&sync.CreatedAt,
+	}, dest...)...)
+	return sync, err
+}
+
+// ClaimPolicySyncs leases the oldest unsynced change of each policy that is due, the later changes of a
+// policy wait until it is synced or dead-lettered. A lease that expires makes the change claimable again.
+func (p *PostgresBackend) ClaimPolicySyncs(ctx context.Context, lease time.Duration, limit int) ([]itypes.PluginPolicySync, error) {
+	rows, err := p.pool.Query(ctx, `
+		WITH heads AS (
+			SELECT DISTINCT ON (policy_id) id, next_attempt_at, locked_until
+			FROM plugin_policy_sync
+			WHERE status NOT IN ($1, $4)
+			ORDER BY policy_id, seq
+		), due AS (
+			SELECT id AS due_id
+			FROM heads
+			WHERE next_attempt_at <= NOW() AND (locked_until IS NULL OR locked_until <= NOW())
+			ORDER BY next_attempt_at
+			LIMIT $3
+		)
+		UPDATE plugin_policy_sync
+		SET locked_until = NOW() + $2::interval, updated_at = NOW()
+		FROM due
+		WHERE id = due.due_id AND (locked_until IS NULL OR locked_until <= NOW())
+		RETURNING `+policySyncColumns+`, payload`,
+		itypes.Synced, fmt.Sprintf("%d seconds", int(lease.Seconds())), limit, itypes.DeadLettered)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim policy syncs: %w", err)
+	}
+	defer rows.Close()
+
+	var syncs []itypes.PluginPolicySync
+	for rows.Next() {
+		var payload []byte
+		sync, err := scanPolicySync(rows, &payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan policy sync: %w", err)
+		}
+		sync.Payload = payload
+		syncs = append(syncs, sync)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+	return syncs, nil
+}
+
+func (p *PostgresBackend) MarkPolicySyncDelivered(ctx context.Context, id uuid.UUID) error {
+	_, err := p.pool.Exec(ctx, `
+		UPDATE plugin_policy_sync
+		SET status = $2, reason = '', attempts = attempts + 1, synced_at = NOW(), locked_until = NULL, updated_at = NOW()
+		WHERE id = $1`, id, itypes.Synced)
+	if err != nil {
+		return fmt.Errorf("failed to mark policy sync delivered: %w", err)
+	}
+	return nil
+}
+
+func (p *PostgresBackend) MarkPolicySyncFailed(ctx context.Context, id uuid.UUID, reason string, nextAttemptAt time.Time) error {
+	_, err := p.pool.Exec(ctx, `
+		UPDATE plugin_policy_sync
+		SET status = $2, reason = $3, attempts = attempts + 1, next_attempt_at = $4, locked_until = NULL, updated_at = NOW()
+		WHERE id = $1`, id, itypes.Failed, reason, nextAttemptAt)
+	if err != nil {
+		return fmt.Errorf("failed to mark policy sync failed: %w", err)
+	}
+	return nil
+}
+
+// MarkPolicySyncDeadLettered gives up on a change, the next change of its policy becomes claimable.
+// Giving up on the creation of a policy deactivates it, the plugin server never runs nor bills it.
+func (p *PostgresBackend) MarkPolicySyncDeadLettered(ctx context.Context, id uuid.UUID, reason string) error {
+	return p.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		var policyID uuid.UUID
+		var syncType itypes.PolicySyncType
+		err := tx.QueryRow(ctx, `
+			UPDATE plugin_policy_sync
+			SET status = $2, reason = $3, attempts = attempts + 1, locked_until = NULL, updated_at = NOW()
+			WHERE id = $1
+			RETURNING policy_id, sync_type`, id, itypes.DeadLettered, reason).Scan(&policyID, &syncType)
+		if err != nil {
+			return fmt.Errorf("failed to mark policy sync dead-lettered: %w", err)
+		}
+		if syncType != itypes.AddPolicy {
+			return nil
+		}
+
+		_, err = tx.Exec(ctx, `
+			UPDATE plugin_policies
+			SET active = false, deactivation_reason = $2
+			WHERE id = $1 AND active`, policyID, types.DeactivationReasonPluginRejected)
+		if err != nil {
+			return fmt.Errorf("failed to deactivate rejected policy: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetPolicySyncs returns the changes of a policy in the order they are delivered
+func (p *PostgresBackend) GetPolicySyncs(ctx context.Context, policyID uuid.UUID) ([]itypes.PluginPolicySync, error) {
+	rows, err := p.pool.Query(ctx, `
+		SELECT `+policySyncColumns+`
+		FROM plugin_policy_sync
+		WHERE policy_id = $1
+		ORDER BY seq`, policyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get policy syncs: %w", err)
+	}
+	defer rows.Close()
+
+	var syncs []itypes.PluginPolicySync
+	for rows.Next() {
+		sync, err := scanPolicySync(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan policy sync: %w", err)
+		}
+		syncs = append(syncs, sync)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+	return syncs, nil
+}
@@ -0,0 +1,122 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/vultisig/verifier/types"
+)
+
+// SavePresign inserts the presignature unless its pool already holds types.MaxPresignPoolSize unused ones,
+// in which case it returns types.ErrPresignPoolFull. The saves to a pool are serialized by an advisory lock
+// so that concurrent sessions can't both fill its last slot.
+func (p *PostgresBackend) SavePresign(ctx context.Context, presign types.Presign) error {
+	return p.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtextextended($1 || ':' || $2 || ':' || $3, 0))`,
+			presign.PublicKey, presign.PluginID, presign.DerivePath)
+		if err != nil {
+			return fmt.Errorf("failed to lock presign pool: %w", err)
+		}
+
+		query := `
+			INSERT INTO presignatures (id, public_key, plugin_id, derive_path, data, created_at)
+			SELECT $1, $2, $3, $4, $5, $6
+			WHERE (
+				SELECT COUNT(*) FROM presignatures
+				WHERE public_key = $2 AND plugin_id = $3 AND derive_path = $4 AND consumed_at IS NULL
+			) < $7`
+
+		ct, err := tx.Exec(ctx, query,
+			presign.ID,
+			presign.PublicKey,
+			presign.PluginID,
+			presign.DerivePath,
+			presign.Data,
+			presign.CreatedAt,
+			types.MaxPresignPoolSize,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert presign: %w", err)
+		}
+		if ct.RowsAffected() == 0 {
+			return types.ErrPresignPoolFull
+		}
+		return nil
+	})
+}
+
+// ConsumePresign hands the presignature out once: the row is locked, marked consumed and its data dropped
+// in one statement, so concurrent callers can't both get it. The row stays to record the use.
+func (p *PostgresBackend) ConsumePresign(ctx context.Context, publicKey, pluginID, id string) (*types.Presign, error) {
+	query := `
+		WITH available AS (
+			SELECT public_key, plugin_id, id, data
+			FROM presignatures
+			WHERE public_key = $1 AND plugin_id = $2 AND id = $3 AND consumed_at IS NULL
+			FOR UPDATE
+		)
+		UPDATE presignatures p
+		SET consumed_at = NOW(), data = NULL
+		FROM available
+		WHERE p.public_key = available.public_key AND p.plugin_id = available.plugin_id AND p.id = available.id
+		RETURNING p.id, p.public_key, p.plugin_id, p.derive_path, available.data, p.created_at, p.consumed_at`
+
+	var presign types.Presign
+	err := p.pool.QueryRow(ctx, query, publicKey, pluginID, id).Scan(
+		&presign.ID,
+		&presign.PublicKey,
+		&presign.PluginID,
+		&presign.DerivePath,
+		&presign.Data,
+		&presign.CreatedAt,
+		&presign.ConsumedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to consume presign: %w", err)
+	}
+	return &presign, nil
+}
+
+func (p *PostgresBackend) ListPresignIDs(ctx context.Context, publicKey, pluginID, derivePath string, limit int) ([]string, error) {
+	query := `
+		SELECT id FROM presignatures
+		WHERE public_key = $1 AND plugin_id = $2 AND derive_path = $3 AND consumed_at IS NULL
+		ORDER BY created_at
+		LIMIT $4`
+
+	rows, err := p.pool.Query(ctx, query, publicKey, pluginID, derivePath, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list presigns: %w", err)
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan presigns: %w", err)
+	}
+	return ids, nil
+}
+
+func (p *PostgresBackend) CountPresigns(ctx context.Context, publicKey, pluginID, derivePath string) (int, error) {
+	query := `
+		SELECT COUNT(*) FROM presignatures
+		WHERE public_key = $1 AND plugin_id = $2 AND derive_path = $3 AND consumed_at IS NULL`
+
+	var count int
+	if err := p.pool.QueryRow(ctx, query, publicKey, pluginID, derivePath).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count presigns: %w", err)
+	}
+	return count, nil
+}
+
+func (p *PostgresBackend) DeletePresigns(ctx context.Context, publicKey, pluginID string) (int64, error) {
+	ct, err := p.pool.Exec(ctx, `DELETE FROM presignatures WHERE public_key = $1 AND plugin_id = $2`, publicKey, pluginID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete presigns: %w", err)
+	}
+	return ct.RowsAffected(), nil
+}
@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -43,11 +44,24 @@ func (p *PostgresBackend) FindPricingById(ctx context.Context, id uuid.UUID) (*t
 }
 
 func (p *PostgresBackend) CreatePricing(ctx context.Context, pricingDto types.PricingCreateDto) (*types.Pricing, error) {
-	query := `INSERT INTO pricings (plugin_id, type, frequency, amount, metric) 
-	VALUES ($1, $2, $3, $4, $5) 
+	asset := pricingDto.Asset
+	if asset == "" {
+		asset = types.PricingAssetUSDC
+	}
+	if !asset.IsValid() {
+		return nil, fmt.Errorf("unsupported pricing asset: %s", asset)
+	}
+
+	if err := pricingDto.Validate(); err != nil {
+		return nil, err
+	}
+
+	query := `INSERT INTO pricings (plugin_id, type, frequency, amount, asset, metric, min_amount, max_amount) 
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8) 
 	RETURNING *`
 
-	rows, err := p.pool.Query(ctx, query, pricingDto.PluginID, pricingDto.Type, pricingDto.Frequency, pricingDto.Amount, pricingDto.Metric)
+	rows, err := p.pool.Query(ctx, query, pricingDto.PluginID, pricingDto.Type, pricingDto.Frequency, pricingDto.Amount, asset, pricingDto.Metric,
+		pricingDto.MinAmount, pricingDto.MaxAmount)
 	if err != nil {
 		return nil, err
 	}
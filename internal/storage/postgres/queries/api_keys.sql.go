@@ -35,27 +35,46 @@ func (q *Queries) CountActiveApiKeys(ctx context.Context, pluginID string) (int6
 }
 
 const createPluginApiKey = `-- name: CreatePluginApiKey :one
-INSERT INTO plugin_apikey (plugin_id, apikey, expires_at, status)
-VALUES ($1, $2, $3, 1)
-RETURNING id, plugin_id, apikey, created_at, expires_at, status
+INSERT INTO plugin_apikey (plugin_id, key_prefix, key_salt, key_hash, scopes, allowed_ips, expires_at, status)
+VALUES ($1, $2, $3, $4, $5, $6, $7, 1)
+RETURNING id, plugin_id, created_at, expires_at, status, key_prefix, key_salt, key_hash, scopes, allowed_ips, last_used_at, last_used_ip, rotated_at
 `
 
 type CreatePluginApiKeyParams struct {
-	PluginID  string             `json:"plugin_id"`
-	Apikey    string             `json:"apikey"`
-	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	PluginID   string             `json:"plugin_id"`
+	KeyPrefix  string             `json:"key_prefix"`
+	KeySalt    string             `json:"key_salt"`
+	KeyHash    string             `json:"key_hash"`
+	Scopes     []string           `json:"scopes"`
+	AllowedIps []string           `json:"allowed_ips"`
+	ExpiresAt  pgtype.Timestamptz `json:"expires_at"`
 }
 
 func (q *Queries) CreatePluginApiKey(ctx context.Context, arg *CreatePluginApiKeyParams) (*PluginApikey, error) {
-	row := q.db.QueryRow(ctx, createPluginApiKey, arg.PluginID, arg.Apikey, arg.ExpiresAt)
+	row := q.db.QueryRow(ctx, createPluginApiKey,
+		arg.PluginID,
+		arg.KeyPrefix,
+		arg.KeySalt,
+		arg.KeyHash,
+		arg.Scopes,
+		arg.AllowedIps,
+		arg.ExpiresAt,
+	)
 	var i PluginApikey
 	err := row.Scan(
 		&i.ID,
 		&i.PluginID,
-		&i.Apikey,
 		&i.CreatedAt,
 		&i.ExpiresAt,
 		&i.Status,
+		&i.KeyPrefix,
+		&i.KeySalt,
+		&i.KeyHash,
+		&i.Scopes,
+		&i.AllowedIps,
+		&i.LastUsedAt,
+		&i.LastUsedIp,
+		&i.RotatedAt,
 	)
 	return &i, err
 }
@@ -64,7 +83,7 @@ const expirePluginApiKey = `-- name: ExpirePluginApiKey :one
 UPDATE plugin_apikey
 SET expires_at = NOW()
 WHERE id = $1
-RETURNING id, plugin_id, apikey, created_at, expires_at, status
+RETURNING id, plugin_id, created_at, expires_at, status, key_prefix, key_salt, key_hash, scopes, allowed_ips, last_used_at, last_used_ip, rotated_at
 `
 
 func (q *Queries) ExpirePluginApiKey(ctx context.Context, id pgtype.UUID) (*PluginApikey, error) {
@@ -73,16 +92,23 @@ func (q *Queries) ExpirePluginApiKey(ctx context.Context, id pgtype.UUID) (*Plug
 	err := row.Scan(
 		&i.ID,
 		&i.PluginID,
-		&i.Apikey,
 		&i.CreatedAt,
 		&i.ExpiresAt,
 		&i.Status,
+		&i.KeyPrefix,
+		&i.KeySalt,
+		&i.KeyHash,
+		&i.Scopes,
+		&i.AllowedIps,
+		&i.LastUsedAt,
+		&i.LastUsedIp,
+		&i.RotatedAt,
 	)
 	return &i, err
 }
 
 const getPluginApiKeyByID = `-- name: GetPluginApiKeyByID :one
-SELECT id, plugin_id, apikey, created_at, expires_at, status FROM plugin_apikey
+SELECT id, plugin_id, created_at, expires_at, status, key_prefix, key_salt, key_hash, scopes, allowed_ips, last_used_at, last_used_ip, rotated_at FROM plugin_apikey
 WHERE id = $1
 `
 
@@ -92,17 +118,24 @@ func (q *Queries) GetPluginApiKeyByID(ctx context.Context, id pgtype.UUID) (*Plu
 	err := row.Scan(
 		&i.ID,
 		&i.PluginID,
-		&i.Apikey,
 		&i.CreatedAt,
 		&i.ExpiresAt,
 		&i.Status,
+		&i.KeyPrefix,
+		&i.KeySalt,
+		&i.KeyHash,
+		&i.Scopes,
+		&i.AllowedIps,
+		&i.LastUsedAt,
+		&i.LastUsedIp,
+		&i.RotatedAt,
 	)
 	return &i, err
 }
 
 const getPluginApiKeys = `-- name: GetPluginApiKeys :many
 
-SELECT id, plugin_id, apikey, created_at, expires_at, status FROM plugin_apikey
+SELECT id, plugin_id, created_at, expires_at, status, key_prefix, key_salt, key_hash, scopes, allowed_ips, last_used_at, last_used_ip, rotated_at FROM plugin_apikey
 WHERE plugin_id = $1
 ORDER BY created_at DESC
 `
@@ -120,10 +153,17 @@ func (q *Queries) GetPluginApiKeys(ctx context.Context, pluginID string) ([]*Plu
 		if err := rows.Scan(
 			&i.ID,
 			&i.PluginID,
-			&i.Apikey,
 			&i.CreatedAt,
 			&i.ExpiresAt,
 			&i.Status,
+			&i.KeyPrefix,
+			&i.KeySalt,
+			&i.KeyHash,
+			&i.Scopes,
+			&i.AllowedIps,
+			&i.LastUsedAt,
+			&i.LastUsedIp,
+			&i.RotatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -135,11 +175,45 @@ func (q *Queries) GetPluginApiKeys(ctx context.Context, pluginID string) ([]*Plu
 	return items, nil
 }
 
+const shortenPluginApiKeyExpiry = `-- name: ShortenPluginApiKeyExpiry :one
+UPDATE plugin_apikey
+SET expires_at = LEAST(COALESCE(expires_at, $1::timestamptz), $1::timestamptz),
+    rotated_at = NOW()
+WHERE id = $2
+RETURNING id, plugin_id, created_at, expires_at, status, key_prefix, key_salt, key_hash, scopes, allowed_ips, last_used_at, last_used_ip, rotated_at
+`
+
+type ShortenPluginApiKeyExpiryParams struct {
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	ID        pgtype.UUID        `json:"id"`
+}
+
+func (q *Queries) ShortenPluginApiKeyExpiry(ctx context.Context, arg *ShortenPluginApiKeyExpiryParams) (*PluginApikey, error) {
+	row := q.db.QueryRow(ctx, shortenPluginApiKeyExpiry, arg.ExpiresAt, arg.ID)
+	var i PluginApikey
+	err := row.Scan(
+		&i.ID,
+		&i.PluginID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.Status,
+		&i.KeyPrefix,
+		&i.KeySalt,
+		&i.KeyHash,
+		&i.Scopes,
+		&i.AllowedIps,
+		&i.LastUsedAt,
+		&i.LastUsedIp,
+		&i.RotatedAt,
+	)
+	return &i, err
+}
+
 const updatePluginApiKeyStatus = `-- name: UpdatePluginApiKeyStatus :one
 UPDATE plugin_apikey
 SET status = $2
 WHERE id = $1
-RETURNING id, plugin_id, apikey, created_at, expires_at, status
+RETURNING id, plugin_id, created_at, expires_at, status, key_prefix, key_salt, key_hash, scopes, allowed_ips, last_used_at, last_used_ip, rotated_at
 `
 
 type UpdatePluginApiKeyStatusParams struct {
@@ -153,10 +227,17 @@ func (q *Queries) UpdatePluginApiKeyStatus(ctx context.Context, arg *UpdatePlugi
 	err := row.Scan(
 		&i.ID,
 		&i.PluginID,
-		&i.Apikey,
 		&i.CreatedAt,
 		&i.ExpiresAt,
 		&i.Status,
+		&i.KeyPrefix,
+		&i.KeySalt,
+		&i.KeyHash,
+		&i.Scopes,
+		&i.AllowedIps,
+		&i.LastUsedAt,
+		&i.LastUsedIp,
+		&i.RotatedAt,
 	)
 	return &i, err
 }
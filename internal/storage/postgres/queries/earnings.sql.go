@@ -49,12 +49,12 @@ func (q *Queries) CountEarningsByPluginOwnerFiltered(ctx context.Context, arg *C
 const getEarningsByPluginForOwner = `-- name: GetEarningsByPluginForOwner :many
 SELECT
     f.plugin_id,
-    COALESCE(SUM(f.amount), 0)::bigint as total
+    COALESCE(SUM(CASE WHEN f.transaction_type = 'debit' THEN f.amount ELSE -f.amount END), 0)::bigint as total
 FROM fees f
 WHERE f.plugin_id IN (
     SELECT po.plugin_id FROM plugin_owners po WHERE po.public_key = $1 AND po.active = true
 )
-AND f.transaction_type = 'debit'
+AND (f.transaction_type = 'debit' OR f.fee_type = 'refund')
 GROUP BY f.plugin_id
 `
 
@@ -90,12 +90,16 @@ SELECT
     f.plugin_id,
     p.title as plugin_name,
     f.amount,
-    'usdc' as asset,
+    f.asset::text as asset,
     COALESCE(ppb.type::text, 'per-tx') as pricing_type,
     f.created_at,
     f.public_key as from_address,
     COALESCE(ti.tx_hash, '') as tx_hash,
     CASE
+        WHEN EXISTS (
+            SELECT 1 FROM fees r
+            WHERE r.fee_type = 'refund' AND r.underlying_type = 'fee' AND r.underlying_id = f.id::text
+        ) THEN 'refunded'
         WHEN ti.status_onchain = 'SUCCESS' THEN 'completed'
         WHEN ti.status_onchain = 'FAIL' THEN 'failed'
         ELSE 'pending'
@@ -163,12 +167,16 @@ SELECT
     f.plugin_id,
     p.title as plugin_name,
     f.amount,
-    'usdc' as asset,
+    f.asset::text as asset,
     COALESCE(ppb.type::text, 'per-tx') as pricing_type,
     f.created_at,
     f.public_key as from_address,
     COALESCE(ti.tx_hash, '') as tx_hash,
     CASE
+        WHEN EXISTS (
+            SELECT 1 FROM fees r
+            WHERE r.fee_type = 'refund' AND r.underlying_type = 'fee' AND r.underlying_id = f.id::text
+        ) THEN 'refunded'
         WHEN ti.status_onchain = 'SUCCESS' THEN 'completed'
         WHEN ti.status_onchain = 'FAIL' THEN 'failed'
         ELSE 'pending'
@@ -251,13 +259,13 @@ func (q *Queries) GetEarningsByPluginOwnerFiltered(ctx context.Context, arg *Get
 
 const getEarningsSummaryByPluginOwner = `-- name: GetEarningsSummaryByPluginOwner :one
 SELECT
-    COALESCE(SUM(f.amount), 0)::bigint as total_earnings,
-    COUNT(f.id)::bigint as total_transactions
+    COALESCE(SUM(CASE WHEN f.transaction_type = 'debit' THEN f.amount ELSE -f.amount END), 0)::bigint as total_earnings,
+    COUNT(f.id) FILTER (WHERE f.transaction_type = 'debit')::bigint as total_transactions
 FROM fees f
 WHERE f.plugin_id IN (
     SELECT po.plugin_id FROM plugin_owners po WHERE po.public_key = $1 AND po.active = true
 )
-AND f.transaction_type = 'debit'
+AND (f.transaction_type = 'debit' OR f.fee_type = 'refund')
 `
 
 type GetEarningsSummaryByPluginOwnerRow struct {
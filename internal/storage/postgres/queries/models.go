@@ -274,7 +274,9 @@ func (ns NullPortalApproverAddedVia) Value() (driver.Value, error) {
 type PricingAsset string
 
 const (
-	PricingAssetUsdc PricingAsset = "usdc"
+	PricingAssetUsdc         PricingAsset = "usdc"
+	PricingAssetUsdt         PricingAsset = "usdt"
+	PricingAssetUsdcArbitrum PricingAsset = "usdc-arbitrum"
 )
 
 func (e *PricingAsset) Scan(src interface{}) error {
@@ -359,7 +361,8 @@ func (ns NullPricingFrequency) Value() (driver.Value, error) {
 type PricingMetric string
 
 const (
-	PricingMetricFixed PricingMetric = "fixed"
+	PricingMetricFixed      PricingMetric = "fixed"
+	PricingMetricPercentage PricingMetric = "percentage"
 )
 
 func (e *PricingMetric) Scan(src interface{}) error {
@@ -527,6 +530,49 @@ func (ns NullProposedPluginStatus) Value() (driver.Value, error) {
 	return string(ns.ProposedPluginStatus), nil
 }
 
+type ReportStatus string
+
+const (
+	ReportStatusPENDING ReportStatus = "PENDING"
+	ReportStatusVALID   ReportStatus = "VALID"
+	ReportStatusINVALID ReportStatus = "INVALID"
+)
+
+func (e *ReportStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ReportStatus(s)
+	case string:
+		*e = ReportStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ReportStatus: %T", src)
+	}
+	return nil
+}
+
+type NullReportStatus struct {
+	ReportStatus ReportStatus `json:"report_status"`
+	Valid        bool         `json:"valid"` // Valid is true if ReportStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullReportStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.ReportStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ReportStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullReportStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ReportStatus), nil
+}
+
 type TransactionType string
 
 const (
@@ -673,6 +719,7 @@ type Fee struct {
 	UnderlyingType  string             `json:"underlying_type"`
 	UnderlyingID    string             `json:"underlying_id"`
 	PluginID        pgtype.Text        `json:"plugin_id"`
+	Asset           PricingAsset       `json:"asset"`
 }
 
 type FeeBatch struct {
@@ -682,6 +729,7 @@ type FeeBatch struct {
 	Status         BatchStatus        `json:"status"`
 	BatchCutoff    int32              `json:"batch_cutoff"`
 	CollectionTxID pgtype.Text        `json:"collection_tx_id"`
+	Asset          PricingAsset       `json:"asset"`
 }
 
 type FeeBatchMember struct {
@@ -704,12 +752,19 @@ type Plugin struct {
 }
 
 type PluginApikey struct {
-	ID        pgtype.UUID        `json:"id"`
-	PluginID  string             `json:"plugin_id"`
-	Apikey    string             `json:"apikey"`
-	CreatedAt pgtype.Timestamptz `json:"created_at"`
-	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
-	Status    int32              `json:"status"`
+	ID         pgtype.UUID        `json:"id"`
+	PluginID   string             `json:"plugin_id"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	ExpiresAt  pgtype.Timestamptz `json:"expires_at"`
+	Status     int32              `json:"status"`
+	KeyPrefix  string             `json:"key_prefix"`
+	KeySalt    string             `json:"key_salt"`
+	KeyHash    string             `json:"key_hash"`
+	Scopes     []string           `json:"scopes"`
+	AllowedIps []string           `json:"allowed_ips"`
+	LastUsedAt pgtype.Timestamptz `json:"last_used_at"`
+	LastUsedIp pgtype.Text        `json:"last_used_ip"`
+	RotatedAt  pgtype.Timestamptz `json:"rotated_at"`
 }
 
 type PluginImage struct {
@@ -783,15 +838,21 @@ type PluginPolicyBilling struct {
 }
 
 type PluginPolicySync struct {
-	ID        pgtype.UUID        `json:"id"`
-	PolicyID  pgtype.UUID        `json:"policy_id"`
-	PluginID  string             `json:"plugin_id"`
-	SyncType  int32              `json:"sync_type"`
-	Signature pgtype.Text        `json:"signature"`
-	Status    int32              `json:"status"`
-	Reason    pgtype.Text        `json:"reason"`
-	CreatedAt pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+	ID            pgtype.UUID        `json:"id"`
+	PolicyID      pgtype.UUID        `json:"policy_id"`
+	PluginID      string             `json:"plugin_id"`
+	SyncType      int32              `json:"sync_type"`
+	Signature     pgtype.Text        `json:"signature"`
+	Status        int32              `json:"status"`
+	Reason        pgtype.Text        `json:"reason"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+	Seq           int64              `json:"seq"`
+	Payload       []byte             `json:"payload"`
+	Attempts      int32              `json:"attempts"`
+	NextAttemptAt pgtype.Timestamptz `json:"next_attempt_at"`
+	LockedUntil   pgtype.Timestamptz `json:"locked_until"`
+	SyncedAt      pgtype.Timestamptz `json:"synced_at"`
 }
 
 type PluginRating struct {
@@ -814,6 +875,10 @@ type PluginReport struct {
 	LastReportedAt    pgtype.Timestamptz `json:"last_reported_at"`
 	ReportCount       int32              `json:"report_count"`
 	Details           string             `json:"details"`
+	Status            ReportStatus       `json:"status"`
+	ReviewedBy        pgtype.Text        `json:"reviewed_by"`
+	ReviewedAt        pgtype.Timestamptz `json:"reviewed_at"`
+	ReviewNote        pgtype.Text        `json:"review_note"`
 }
 
 type PluginTag struct {
@@ -840,6 +905,8 @@ type Pricing struct {
 	PluginID  string               `json:"plugin_id"`
 	CreatedAt pgtype.Timestamptz   `json:"created_at"`
 	UpdatedAt pgtype.Timestamptz   `json:"updated_at"`
+	MinAmount pgtype.Int8          `json:"min_amount"`
+	MaxAmount pgtype.Int8          `json:"max_amount"`
 }
 
 type ProposedPlugin struct {
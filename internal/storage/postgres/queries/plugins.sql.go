@@ -68,7 +68,7 @@ func (q *Queries) GetPluginByIDAndOwner(ctx context.Context, arg *GetPluginByIDA
 }
 
 const getPluginPricings = `-- name: GetPluginPricings :many
-SELECT id, type, frequency, amount, asset, metric, plugin_id, created_at, updated_at FROM pricings
+SELECT id, type, frequency, amount, asset, metric, plugin_id, created_at, updated_at, min_amount, max_amount FROM pricings
 WHERE plugin_id = $1
 ORDER BY created_at DESC
 `
@@ -92,6 +92,8 @@ func (q *Queries) GetPluginPricings(ctx context.Context, pluginID string) ([]*Pr
 			&i.PluginID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.MinAmount,
+			&i.MaxAmount,
 		); err != nil {
 			return nil, err
 		}
@@ -32,7 +32,11 @@ func (p *PostgresBackend) UpsertReport(ctx context.Context, pluginID types.Plugi
 		SET last_reported_at = NOW(),
 		    reason = EXCLUDED.reason,
 		    details = EXCLUDED.details,
-		    report_count = %s.report_count + 1
+		    report_count = %s.report_count + 1,
+		    status = 'PENDING',
+		    reviewed_by = NULL,
+		    reviewed_at = NULL,
+		    review_note = NULL
 		WHERE %s.last_reported_at < NOW() - $5::interval`,
 		PLUGIN_REPORTS_TABLE, PLUGIN_REPORTS_TABLE, PLUGIN_REPORTS_TABLE)
 
@@ -48,19 +52,12 @@ func (p *PostgresBackend) UpsertReport(ctx context.Context, pluginID types.Plugi
 	return nil
 }
 
-func (p *PostgresBackend) GetReport(ctx context.Context, pluginID types.PluginID, publicKey string) (*itypes.PluginReport, error) {
-	if p.pool == nil {
-		return nil, fmt.Errorf("database pool is nil")
-	}
-
-	query := fmt.Sprintf(`
-		SELECT plugin_id, reporter_public_key, reason, details, created_at, last_reported_at, report_count
-		FROM %s
-		WHERE plugin_id = $1 AND reporter_public_key = $2`,
-		PLUGIN_REPORTS_TABLE)
+const reportColumns = `plugin_id, reporter_public_key, reason, details, created_at, last_reported_at, report_count,
+		status, reviewed_by, reviewed_at, review_note`
 
+func scanReport(row pgx.Row) (*itypes.PluginReport, error) {
 	var report itypes.PluginReport
-	err := p.pool.QueryRow(ctx, query, pluginID, publicKey).Scan(
+	err := row.Scan(
 		&report.PluginID,
 		&report.ReporterPubKey,
 		&report.Reason,
@@ -68,7 +65,29 @@ func (p *PostgresBackend) GetReport(ctx context.Context, pluginID types.PluginID
 		&report.CreatedAt,
 		&report.LastReportedAt,
 		&report.ReportCount,
+		&report.Status,
+		&report.ReviewedBy,
+		&report.ReviewedAt,
+		&report.ReviewNote,
 	)
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+func (p *PostgresBackend) GetReport(ctx context.Context, pluginID types.PluginID, publicKey string) (*itypes.PluginReport, error) {
+	if p.pool == nil {
+		return nil, fmt.Errorf("database pool is nil")
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		WHERE plugin_id = $1 AND reporter_public_key = $2`,
+		reportColumns, PLUGIN_REPORTS_TABLE)
+
+	report, err := scanReport(p.pool.QueryRow(ctx, query, pluginID, publicKey))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil
@@ -76,7 +95,73 @@ func (p *PostgresBackend) GetReport(ctx context.Context, pluginID types.PluginID
 		return nil, fmt.Errorf("failed to get report: %w", err)
 	}
 
-	return &report, nil
+	return report, nil
+}
+
+// ListReports returns the reports, optionally of a plugin and in a status, most recently reported first
+func (p *PostgresBackend) ListReports(ctx context.Context, pluginID types.PluginID, status itypes.ReportStatus, skip, take uint32) ([]itypes.PluginReport, uint32, error) {
+	if p.pool == nil {
+		return nil, 0, fmt.Errorf("database pool is nil")
+	}
+
+	filter := fmt.Sprintf(`
+		FROM %s
+		WHERE ($1 = '' OR plugin_id = $1)
+		  AND ($2 = '' OR status::text = $2)`,
+		PLUGIN_REPORTS_TABLE)
+
+	var totalCount uint32
+	err := p.pool.QueryRow(ctx, `SELECT COUNT(*)`+filter, string(pluginID), string(status)).Scan(&totalCount)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count reports: %w", err)
+	}
+
+	rows, err := p.pool.Query(ctx, `SELECT `+reportColumns+filter+`
+		ORDER BY last_reported_at DESC, plugin_id, reporter_public_key
+		LIMIT $3 OFFSET $4`,
+		string(pluginID), string(status), take, skip)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query reports: %w", err)
+	}
+	defer rows.Close()
+
+	reports := make([]itypes.PluginReport, 0)
+	for rows.Next() {
+		report, err := scanReport(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan report: %w", err)
+		}
+		reports = append(reports, *report)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to iterate reports: %w", err)
+	}
+
+	return reports, totalCount, nil
+}
+
+// ReviewReport records the review decision of a report, it returns nil if the report does not exist
+func (p *PostgresBackend) ReviewReport(ctx context.Context, pluginID types.PluginID, publicKey string, status itypes.ReportStatus, reviewedBy, note string) (*itypes.PluginReport, error) {
+	if p.pool == nil {
+		return nil, fmt.Errorf("database pool is nil")
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %s
+		SET status = $3, reviewed_by = $4, reviewed_at = NOW(), review_note = NULLIF($5, '')
+		WHERE plugin_id = $1 AND reporter_public_key = $2
+		RETURNING %s`,
+		PLUGIN_REPORTS_TABLE, reportColumns)
+
+	report, err := scanReport(p.pool.QueryRow(ctx, query, pluginID, publicKey, status, reviewedBy, note))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to review report: %w", err)
+	}
+
+	return report, nil
 }
 
 func (p *PostgresBackend) CountReportsInWindow(ctx context.Context, pluginID types.PluginID, window time.Duration) (int, error) {
@@ -89,7 +174,7 @@ func (p *PostgresBackend) CountReportsInWindow(ctx context.Context, pluginID typ
 	query := fmt.Sprintf(`
 		SELECT COUNT(*)
 		FROM %s
-		WHERE plugin_id = $1 AND last_reported_at >= NOW() - $2::interval`,
+		WHERE plugin_id = $1 AND last_reported_at >= NOW() - $2::interval AND status <> 'INVALID'`,
 		PLUGIN_REPORTS_TABLE)
 
 	var count int
@@ -185,21 +270,6 @@ func (p *PostgresBackend) IsPluginPaused(ctx context.Context, pluginID types.Plu
 	return !keysignEnabled && !keygenEnabled, nil
 }
 
-func (p *PostgresBackend) setControlFlagTx(ctx context.Context, tx pgx.Tx, key string, enabled bool) error {
-	query := `
-		INSERT INTO control_flags (key, enabled, updated_at)
-		VALUES ($1, $2, NOW())
-		ON CONFLICT (key) DO UPDATE
-		SET enabled = EXCLUDED.enabled, updated_at = NOW()`
-
-	_, err := tx.Exec(ctx, query, key, enabled)
-	if err != nil {
-		return fmt.Errorf("failed to set control flag: %w", err)
-	}
-
-	return nil
-}
-
 func (p *PostgresBackend) recordPauseHistoryTx(ctx context.Context, tx pgx.Tx, record itypes.PauseHistoryRecord) error {
 	query := fmt.Sprintf(`
 		INSERT INTO %s (plugin_id, action, report_count_window, active_users, threshold_rate, reason, triggered_by, created_at)
@@ -222,26 +292,54 @@ func (p *PostgresBackend) recordPauseHistoryTx(ctx context.Context, tx pgx.Tx, r
 	return nil
 }
 
-func (p *PostgresBackend) PausePlugin(ctx context.Context, pluginID types.PluginID, record itypes.PauseHistoryRecord) error {
-	return p.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
-		keysignKey := safety.KeysignFlagKey(string(pluginID))
-		keygenKey := safety.KeygenFlagKey(string(pluginID))
+// GetPauseHistory returns the pause and unpause records of a plugin, most recent first
+func (p *PostgresBackend) GetPauseHistory(ctx context.Context, pluginID types.PluginID, skip, take uint32) ([]itypes.PauseHistoryRecord, uint32, error) {
+	if p.pool == nil {
+		return nil, 0, fmt.Errorf("database pool is nil")
+	}
 
-		err := p.setControlFlagTx(ctx, tx, keysignKey, false)
-		if err != nil {
-			return fmt.Errorf("failed to set keysign flag: %w", err)
-		}
+	var totalCount uint32
+	err := p.pool.QueryRow(ctx, fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE plugin_id = $1`, PLUGIN_PAUSE_HISTORY_TABLE), pluginID).Scan(&totalCount)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count pause history: %w", err)
+	}
 
-		err = p.setControlFlagTx(ctx, tx, keygenKey, false)
-		if err != nil {
-			return fmt.Errorf("failed to set keygen flag: %w", err)
-		}
+	query := fmt.Sprintf(`
+		SELECT id, plugin_id, action, report_count_window, active_users, threshold_rate::float8, reason, triggered_by, created_at
+		FROM %s
+		WHERE plugin_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3`,
+		PLUGIN_PAUSE_HISTORY_TABLE)
+
+	rows, err := p.pool.Query(ctx, query, pluginID, take, skip)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query pause history: %w", err)
+	}
+	defer rows.Close()
 
-		err = p.recordPauseHistoryTx(ctx, tx, record)
+	records := make([]itypes.PauseHistoryRecord, 0)
+	for rows.Next() {
+		var record itypes.PauseHistoryRecord
+		err = rows.Scan(
+			&record.ID,
+			&record.PluginID,
+			&record.Action,
+			&record.ReportCountWindow,
+			&record.ActiveUsers,
+			&record.ThresholdRate,
+			&record.Reason,
+			&record.TriggeredBy,
+			&record.CreatedAt,
+		)
 		if err != nil {
-			return fmt.Errorf("failed to record pause history: %w", err)
+			return nil, 0, fmt.Errorf("failed to scan pause history: %w", err)
 		}
+		records = append(records, record)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to iterate pause history: %w", err)
+	}
 
-		return nil
-	})
+	return records, totalCount, nil
 }
@@ -10,6 +10,13 @@ CREATE TYPE "billing_asset" AS ENUM (
     'usdc'
 );
 
+CREATE TYPE "keysign_result_status" AS ENUM (
+    'queued',
+    'running',
+    'failed',
+    'completed'
+);
+
 CREATE TYPE "plugin_category" AS ENUM (
     'ai-agent',
     'plugin',
@@ -33,6 +40,13 @@ CREATE TYPE "plugin_owner_role" AS ENUM (
     'viewer'
 );
 
+CREATE TYPE "payout_status" AS ENUM (
+    'PENDING',
+    'SIGNING',
+    'COMPLETED',
+    'FAILED'
+);
+
 CREATE TYPE "portal_approver_added_via" AS ENUM (
     'bootstrap',
     'admin_portal',
@@ -40,7 +54,9 @@ CREATE TYPE "portal_approver_added_via" AS ENUM (
 );
 
 CREATE TYPE "pricing_asset" AS ENUM (
-    'usdc'
+    'usdc',
+    'usdt',
+    'usdc-arbitrum'
 );
 
 CREATE TYPE "pricing_frequency" AS ENUM (
@@ -51,7 +67,8 @@ CREATE TYPE "pricing_frequency" AS ENUM (
 );
 
 CREATE TYPE "pricing_metric" AS ENUM (
-    'fixed'
+    'fixed',
+    'percentage'
 );
 
 CREATE TYPE "pricing_type" AS ENUM (
@@ -73,6 +90,12 @@ CREATE TYPE "proposed_plugin_status" AS ENUM (
     'archived'
 );
 
+CREATE TYPE "report_status" AS ENUM (
+    'PENDING',
+    'VALID',
+    'INVALID'
+);
+
 CREATE TYPE "transaction_type" AS ENUM (
     'debit',
     'credit'
@@ -90,6 +113,79 @@ CREATE TYPE "tx_indexer_status_onchain" AS ENUM (
     'FAIL'
 );
 
+CREATE FUNCTION "check_ledger_entry_balanced"() RETURNS "trigger"
+    LANGUAGE "plpgsql"
+    AS $$
+DECLARE
+    total BIGINT;
+BEGIN
+    SELECT SUM(amount) INTO total FROM ledger_postings WHERE entry_id = NEW.entry_id;
+    IF total <> 0 THEN
+        RAISE EXCEPTION 'ledger entry % is not balanced: %', NEW.entry_id, total;
+    END IF;
+    RETURN NULL;
+END;
+$$;
+
+CREATE FUNCTION "ledger_counterparty"("fee_type" "text", "plugin_id" "text", OUT "account_type" "text", OUT "account_id" "text") RETURNS "record"
+    LANGUAGE "plpgsql" IMMUTABLE
+    AS $$
+BEGIN
+    IF fee_type IN ('batch', 'batch_failed') THEN
+        account_type := 'treasury';
+        account_id := 'collections';
+    ELSIF plugin_id IS NOT NULL AND plugin_id <> '' AND plugin_id <> 'vultisig-fees-feee' THEN
+        account_type := 'developer';
+        account_id := plugin_id;
+    ELSE
+        account_type := 'treasury';
+        account_id := 'revenue';
+    END IF;
+END;
+$$;
+
+CREATE FUNCTION "notify_plugin_server_info"() RETURNS "trigger"
+    LANGUAGE "plpgsql"
+    AS $$
+DECLARE
+    rec RECORD;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        rec := OLD;
+    ELSE
+        rec := NEW;
+    END IF;
+    IF TG_TABLE_NAME = 'plugins' THEN
+        PERFORM pg_notify('plugin_server_info', rec.id::text);
+    ELSE
+        PERFORM pg_notify('plugin_server_info', rec.plugin_id::text);
+    END IF;
+    RETURN NULL;
+END;
+$$;
+
+CREATE FUNCTION "post_fee_to_ledger"() RETURNS "trigger"
+    LANGUAGE "plpgsql"
+    AS $$
+DECLARE
+    new_entry_id BIGINT;
+    signed_amount BIGINT;
+    counterparty RECORD;
+BEGIN
+    signed_amount := CASE WHEN NEW.transaction_type = 'debit' THEN NEW.amount ELSE -NEW.amount END;
+    SELECT * INTO counterparty FROM ledger_counterparty(NEW.fee_type, NEW.plugin_id);
+
+    INSERT INTO ledger_entries (fee_id, entry_type, created_at)
+    VALUES (NEW.id, NEW.fee_type, NEW.created_at)
+    RETURNING id INTO new_entry_id;
+
+    INSERT INTO ledger_postings (entry_id, account_type, account_id, asset, amount) VALUES
+        (new_entry_id, 'user', NEW.public_key, NEW.asset, signed_amount),
+        (new_entry_id, counterparty.account_type, counterparty.account_id, NEW.asset, -signed_amount);
+    RETURN NEW;
+END;
+$$;
+
 CREATE FUNCTION "prevent_billing_update_if_policy_deleted"() RETURNS "trigger"
     LANGUAGE "plpgsql"
     AS $$
@@ -114,6 +210,16 @@ BEGIN
 END;
 $$;
 
+CREATE FUNCTION "prevent_invoice_modification"() RETURNS "trigger"
+    LANGUAGE "plpgsql"
+    AS $$
+BEGIN
+    RAISE EXCEPTION '% operation not allowed on invoices table. Invoices are immutable once issued.', TG_OP
+        USING HINT = 'issue a credit, it is listed on the next invoice.';
+    RETURN NULL;
+END;
+$$;
+
 CREATE FUNCTION "prevent_insert_if_policy_deleted"() RETURNS "trigger"
     LANGUAGE "plpgsql"
     AS $$
@@ -125,6 +231,16 @@ BEGIN
 END;
 $$;
 
+CREATE FUNCTION "prevent_ledger_modification"() RETURNS "trigger"
+    LANGUAGE "plpgsql"
+    AS $$
+BEGIN
+    RAISE EXCEPTION '% operation not allowed on %. The ledger is append only.', TG_OP, TG_TABLE_NAME
+        USING HINT = 'post a compensating fee instead.';
+    RETURN NULL;
+END;
+$$;
+
 CREATE FUNCTION "prevent_update_if_policy_deleted"() RETURNS "trigger"
     LANGUAGE "plpgsql"
     AS $$
@@ -147,12 +263,110 @@ BEGIN
 END;
 $$;
 
+CREATE FUNCTION "track_policy_activation"() RETURNS "trigger"
+    LANGUAGE "plpgsql"
+    AS $$
+BEGIN
+    IF NEW.active THEN
+        INSERT INTO plugin_policy_activations (policy_id, activated_at)
+        VALUES (NEW.id, NOW())
+        ON CONFLICT DO NOTHING;
+    ELSE
+        UPDATE plugin_policy_activations
+        SET deactivated_at = NOW()
+        WHERE policy_id = NEW.id
+          AND deactivated_at IS NULL;
+    END IF;
+    RETURN NEW;
+END;
+$$;
+
+CREATE TABLE "control_flag_outbox" (
+    "id" bigint NOT NULL,
+    "plugin_id" "plugin_id" NOT NULL,
+    "keys" "text"[] NOT NULL,
+    "attempts" integer DEFAULT 0 NOT NULL,
+    "last_error" "text",
+    "next_attempt_at" timestamp with time zone DEFAULT "now"() NOT NULL,
+    "delivered_at" timestamp with time zone,
+    "created_at" timestamp with time zone DEFAULT "now"() NOT NULL
+);
+
+CREATE SEQUENCE "control_flag_outbox_id_seq"
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+ALTER SEQUENCE "control_flag_outbox_id_seq" OWNED BY "public"."control_flag_outbox"."id";
+
 CREATE TABLE "control_flags" (
     "key" "text" NOT NULL,
     "enabled" boolean NOT NULL,
     "updated_at" timestamp with time zone DEFAULT "now"() NOT NULL
 );
 
+CREATE TABLE "developer_payout_fees" (
+    "fee_id" bigint NOT NULL,
+    "payout_id" bigint NOT NULL
+);
+
+CREATE TABLE "developer_payouts" (
+    "id" bigint NOT NULL,
+    "plugin_id" "plugin_id" NOT NULL,
+    "payout_address" "text" NOT NULL,
+    "asset" "pricing_asset" NOT NULL,
+    "period_end" timestamp with time zone NOT NULL,
+    "gross_amount" bigint NOT NULL,
+    "platform_share" bigint NOT NULL,
+    "amount" bigint NOT NULL,
+    "status" "payout_status" DEFAULT 'PENDING'::"public"."payout_status" NOT NULL,
+    "keysign_policy_id" "uuid" DEFAULT "gen_random_uuid"() NOT NULL,
+    "tx_hash" "text",
+    "created_at" timestamp with time zone DEFAULT "now"() NOT NULL,
+    "updated_at" timestamp with time zone DEFAULT "now"() NOT NULL,
+    CONSTRAINT "developer_payouts_amount_check" CHECK ((("amount" > 0) AND ("platform_share" >= 0))),
+    CONSTRAINT "developer_payouts_split_check" CHECK (("gross_amount" = ("platform_share" + "amount")))
+);
+
+CREATE SEQUENCE "developer_payouts_id_seq"
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+ALTER SEQUENCE "developer_payouts_id_seq" OWNED BY "public"."developer_payouts"."id";
+
+CREATE TABLE "dunning_events" (
+    "id" bigint NOT NULL,
+    "public_key" "text" NOT NULL,
+    "event_type" "text" NOT NULL,
+    "unpaid_amount" bigint NOT NULL,
+    "created_at" timestamp with time zone DEFAULT "now"() NOT NULL,
+    CONSTRAINT "dunning_events_event_type_check" CHECK (("event_type" = ANY (ARRAY['reminder'::"text", 'suspended'::"text", 'reactivated'::"text"])))
+);
+
+CREATE SEQUENCE "dunning_events_id_seq"
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+ALTER SEQUENCE "dunning_events_id_seq" OWNED BY "public"."dunning_events"."id";
+
+CREATE TABLE "dunning_states" (
+    "public_key" "text" NOT NULL,
+    "stage" "text" NOT NULL,
+    "overdue_since" timestamp with time zone NOT NULL,
+    "last_reminder_at" timestamp with time zone,
+    "suspended_at" timestamp with time zone,
+    "updated_at" timestamp with time zone DEFAULT "now"() NOT NULL,
+    CONSTRAINT "dunning_states_stage_check" CHECK (("stage" = ANY (ARRAY['grace'::"text", 'reminded'::"text", 'suspended'::"text"])))
+);
+
 CREATE TABLE "fee_batch_members" (
     "batch_id" bigint NOT NULL,
     "fee_id" bigint NOT NULL
@@ -165,6 +379,7 @@ CREATE TABLE "fee_batches" (
     "status" "batch_status" DEFAULT 'BATCHED'::"public"."batch_status" NOT NULL,
     "batch_cutoff" integer NOT NULL,
     "collection_tx_id" "text",
+    "asset" "pricing_asset" DEFAULT 'usdc'::"public"."pricing_asset" NOT NULL,
     CONSTRAINT "fee_batches_total_value_check" CHECK (("total_value" >= 0))
 );
 
@@ -189,6 +404,7 @@ CREATE TABLE "fees" (
     "underlying_type" "text" NOT NULL,
     "underlying_id" "text" NOT NULL,
     "plugin_id" character varying(255),
+    "asset" "pricing_asset" DEFAULT 'usdc'::"public"."pricing_asset" NOT NULL,
     CONSTRAINT "fees_amount_check" CHECK (("amount" > 0)),
     CONSTRAINT "policy_id_required_for_policies" CHECK (((("underlying_type" = 'policy'::"text") AND ("policy_id" IS NOT NULL)) OR ("underlying_type" <> 'policy'::"text")))
 );
@@ -202,16 +418,129 @@ CREATE SEQUENCE "fees_id_seq"
 
 ALTER SEQUENCE "fees_id_seq" OWNED BY "public"."fees"."id";
 
+CREATE TABLE "invoices" (
+    "id" "uuid" DEFAULT "gen_random_uuid"() NOT NULL,
+    "public_key" "text" NOT NULL,
+    "period_start" timestamp with time zone NOT NULL,
+    "period_end" timestamp with time zone NOT NULL,
+    "data" "jsonb" NOT NULL,
+    "created_at" timestamp with time zone DEFAULT "now"() NOT NULL,
+    CONSTRAINT "invoices_period_check" CHECK (("period_end" > "period_start"))
+);
+
+CREATE TABLE "keysign_rejections" (
+    "id" bigint NOT NULL,
+    "plugin_id" "plugin_id" NOT NULL,
+    "policy_id" "uuid" NOT NULL,
+    "reason" "text" NOT NULL,
+    "created_at" timestamp with time zone DEFAULT "now"() NOT NULL
+);
+
+CREATE SEQUENCE "keysign_rejections_id_seq"
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+ALTER SEQUENCE "keysign_rejections_id_seq" OWNED BY "public"."keysign_rejections"."id";
+
+CREATE TABLE "keysign_results" (
+    "tx_indexer_id" "uuid" NOT NULL,
+    "session_id" "text" NOT NULL,
+    "task_id" "text" NOT NULL,
+    "plugin_id" "text" NOT NULL,
+    "public_key" "text" NOT NULL,
+    "status" "keysign_result_status" DEFAULT 'queued'::"public"."keysign_result_status" NOT NULL,
+    "failure_reason" "text",
+    "data" "bytea",
+    "created_at" timestamp with time zone DEFAULT "now"() NOT NULL,
+    "updated_at" timestamp with time zone DEFAULT "now"() NOT NULL,
+    "expires_at" timestamp with time zone NOT NULL
+);
+
+CREATE TABLE "ledger_entries" (
+    "id" bigint NOT NULL,
+    "fee_id" bigint,
+    "entry_type" "text" NOT NULL,
+    "created_at" timestamp with time zone DEFAULT "now"() NOT NULL,
+    "payout_id" bigint,
+    CONSTRAINT "ledger_entries_source_check" CHECK (("num_nonnulls"("fee_id", "payout_id") = 1))
+);
+
+CREATE SEQUENCE "ledger_entries_id_seq"
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+ALTER SEQUENCE "ledger_entries_id_seq" OWNED BY "public"."ledger_entries"."id";
+
+CREATE TABLE "ledger_postings" (
+    "id" bigint NOT NULL,
+    "entry_id" bigint NOT NULL,
+    "account_type" "text" NOT NULL,
+    "account_id" "text" NOT NULL,
+    "asset" "pricing_asset" NOT NULL,
+    "amount" bigint NOT NULL,
+    CONSTRAINT "ledger_postings_account_type_check" CHECK (("account_type" = ANY (ARRAY['user'::"text", 'developer'::"text", 'treasury'::"text"]))),
+    CONSTRAINT "ledger_postings_amount_check" CHECK (("amount" <> 0))
+);
+
+CREATE SEQUENCE "ledger_postings_id_seq"
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+ALTER SEQUENCE "ledger_postings_id_seq" OWNED BY "public"."ledger_postings"."id";
+
+CREATE TABLE "ledger_reconciliations" (
+    "id" bigint NOT NULL,
+    "discrepancy_count" integer NOT NULL,
+    "report" "jsonb" NOT NULL,
+    "created_at" timestamp with time zone DEFAULT "now"() NOT NULL
+);
+
+CREATE SEQUENCE "ledger_reconciliations_id_seq"
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+ALTER SEQUENCE "ledger_reconciliations_id_seq" OWNED BY "public"."ledger_reconciliations"."id";
+
 CREATE TABLE "plugin_apikey" (
     "id" "uuid" DEFAULT "gen_random_uuid"() NOT NULL,
     "plugin_id" "plugin_id" NOT NULL,
-    "apikey" "text" NOT NULL,
     "created_at" timestamp with time zone DEFAULT "now"() NOT NULL,
     "expires_at" timestamp with time zone,
     "status" integer DEFAULT 1 NOT NULL,
+    "key_prefix" "text" NOT NULL,
+    "key_salt" "text" NOT NULL,
+    "key_hash" "text" NOT NULL,
+    "scopes" "text"[] NOT NULL,
+    "allowed_ips" "text"[] DEFAULT '{}'::"text"[] NOT NULL,
+    "last_used_at" timestamp with time zone,
+    "last_used_ip" "text",
+    "rotated_at" timestamp with time zone,
     CONSTRAINT "plugin_apikey_status_check" CHECK (("status" = ANY (ARRAY[0, 1])))
 );
 
+CREATE TABLE "plugin_health" (
+    "plugin_id" "plugin_id" NOT NULL,
+    "available" boolean NOT NULL,
+    "status_code" integer,
+    "latency_ms" integer DEFAULT 0 NOT NULL,
+    "last_error" "text",
+    "consecutive_failures" integer DEFAULT 0 NOT NULL,
+    "checked_at" timestamp with time zone DEFAULT "now"() NOT NULL,
+    "last_available_at" timestamp with time zone
+);
+
 CREATE TABLE "plugin_images" (
     "id" "uuid" DEFAULT "gen_random_uuid"() NOT NULL,
     "plugin_id" "plugin_id" NOT NULL,
@@ -274,6 +603,23 @@ CREATE TABLE "plugin_policies" (
     "deactivation_reason" "text"
 );
 
+CREATE TABLE "plugin_policy_activations" (
+    "id" bigint NOT NULL,
+    "policy_id" "uuid" NOT NULL,
+    "activated_at" timestamp with time zone DEFAULT "now"() NOT NULL,
+    "deactivated_at" timestamp with time zone,
+    CONSTRAINT "plugin_policy_activations_period_check" CHECK ((("deactivated_at" IS NULL) OR ("deactivated_at" >= "activated_at")))
+);
+
+CREATE SEQUENCE "plugin_policy_activations_id_seq"
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+ALTER SEQUENCE "plugin_policy_activations_id_seq" OWNED BY "public"."plugin_policy_activations"."id";
+
 CREATE TABLE "plugin_policy_billing" (
     "id" "uuid" DEFAULT "gen_random_uuid"() NOT NULL,
     "type" "pricing_type" NOT NULL,
@@ -294,9 +640,24 @@ CREATE TABLE "plugin_policy_sync" (
     "status" integer DEFAULT 0 NOT NULL,
     "reason" "text",
     "created_at" timestamp with time zone DEFAULT "now"() NOT NULL,
-    "updated_at" timestamp with time zone DEFAULT "now"() NOT NULL
+    "updated_at" timestamp with time zone DEFAULT "now"() NOT NULL,
+    "seq" bigint NOT NULL,
+    "payload" "jsonb",
+    "attempts" integer DEFAULT 0 NOT NULL,
+    "next_attempt_at" timestamp with time zone DEFAULT "now"() NOT NULL,
+    "locked_until" timestamp with time zone,
+    "synced_at" timestamp with time zone
 );
 
+CREATE SEQUENCE "plugin_policy_sync_seq_seq"
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+ALTER SEQUENCE "plugin_policy_sync_seq_seq" OWNED BY "public"."plugin_policy_sync"."seq";
+
 CREATE TABLE "plugin_ratings" (
     "plugin_id" "plugin_id" NOT NULL,
     "avg_rating" numeric(3,2) DEFAULT 0 NOT NULL,
@@ -316,7 +677,20 @@ CREATE TABLE "plugin_reports" (
     "created_at" timestamp with time zone DEFAULT "now"() NOT NULL,
     "last_reported_at" timestamp with time zone DEFAULT "now"() NOT NULL,
     "report_count" integer DEFAULT 1 NOT NULL,
-    "details" "text" DEFAULT ''::"text" NOT NULL
+    "details" "text" DEFAULT ''::"text" NOT NULL,
+    "status" "report_status" DEFAULT 'PENDING'::"report_status" NOT NULL,
+    "reviewed_by" "text",
+    "reviewed_at" timestamp with time zone,
+    "review_note" "text"
+);
+
+CREATE TABLE "plugin_signing_keys" (
+    "key_id" "text" NOT NULL,
+    "plugin_id" "plugin_id" NOT NULL,
+    "public_key" "text" NOT NULL,
+    "created_at" timestamp with time zone DEFAULT "now"() NOT NULL,
+    "expires_at" timestamp with time zone,
+    "revoked_at" timestamp with time zone
 );
 
 CREATE TABLE "plugin_tags" (
@@ -347,6 +721,16 @@ CREATE TABLE "portal_approvers" (
     "updated_at" timestamp with time zone DEFAULT "now"() NOT NULL
 );
 
+CREATE TABLE "presignatures" (
+    "id" "text" NOT NULL,
+    "public_key" "text" NOT NULL,
+    "plugin_id" "text" NOT NULL,
+    "derive_path" "text" NOT NULL,
+    "data" "bytea",
+    "created_at" timestamp with time zone DEFAULT "now"() NOT NULL,
+    "consumed_at" timestamp with time zone
+);
+
 CREATE TABLE "pricings" (
     "id" "uuid" DEFAULT "gen_random_uuid"() NOT NULL,
     "type" "pricing_type" NOT NULL,
@@ -357,7 +741,11 @@ CREATE TABLE "pricings" (
     "plugin_id" "plugin_id" NOT NULL,
     "created_at" timestamp with time zone DEFAULT "now"() NOT NULL,
     "updated_at" timestamp with time zone DEFAULT "now"() NOT NULL,
-    CONSTRAINT "frequency_check" CHECK (((("type" = 'recurring'::"pricing_type") AND ("frequency" IS NOT NULL)) OR (("type" = ANY (ARRAY['per-tx'::"public"."pricing_type", 'once'::"public"."pricing_type"])) AND ("frequency" IS NULL))))
+    "min_amount" bigint,
+    "max_amount" bigint,
+    CONSTRAINT "frequency_check" CHECK (((("type" = 'recurring'::"pricing_type") AND ("frequency" IS NOT NULL)) OR (("type" = ANY (ARRAY['per-tx'::"public"."pricing_type", 'once'::"public"."pricing_type"])) AND ("frequency" IS NULL)))),
+    CONSTRAINT "min_max_amount_check" CHECK ((("min_amount" IS NULL) OR ("max_amount" IS NULL) OR ("min_amount" <= "max_amount"))),
+    CONSTRAINT "percentage_check" CHECK (((("metric")::"text" <> 'percentage'::"text") AND ("min_amount" IS NULL) AND ("max_amount" IS NULL)) OR ((("metric")::"text" = 'percentage'::"text") AND ("type" = 'per-tx'::"public"."pricing_type") AND ("amount" <= 10000))))
 );
 
 CREATE TABLE "proposed_plugin_images" (
@@ -430,6 +818,14 @@ CREATE TABLE "tx_indexer" (
     "error_message" "text"
 );
 
+CREATE TABLE "unbilled_txs" (
+    "tx_id" "uuid" NOT NULL,
+    "attempts" integer DEFAULT 1 NOT NULL,
+    "last_error" "text" NOT NULL,
+    "next_attempt_at" timestamp with time zone NOT NULL,
+    "created_at" timestamp with time zone DEFAULT "now"() NOT NULL
+);
+
 CREATE TABLE "vault_tokens" (
     "id" "uuid" DEFAULT "gen_random_uuid"() NOT NULL,
     "token_id" character varying(255) NOT NULL,
@@ -438,16 +834,58 @@ CREATE TABLE "vault_tokens" (
     "expires_at" timestamp with time zone NOT NULL,
     "last_used_at" timestamp with time zone,
     "revoked_at" timestamp with time zone,
-    "updated_at" timestamp with time zone DEFAULT "now"() NOT NULL
+    "updated_at" timestamp with time zone DEFAULT "now"() NOT NULL,
+    "family_id" "uuid" DEFAULT "gen_random_uuid"() NOT NULL,
+    "parent_token_id" character varying(255),
+    "rotated_at" timestamp with time zone,
+    "device_name" "text" DEFAULT ''::"text" NOT NULL,
+    "user_agent" "text" DEFAULT ''::"text" NOT NULL,
+    "ip_address" "text" DEFAULT ''::"text" NOT NULL
 );
 
+ALTER TABLE ONLY "control_flag_outbox" ALTER COLUMN "id" SET DEFAULT "nextval"('"public"."control_flag_outbox_id_seq"'::"regclass");
+
+ALTER TABLE ONLY "developer_payouts" ALTER COLUMN "id" SET DEFAULT "nextval"('"public"."developer_payouts_id_seq"'::"regclass");
+
+ALTER TABLE ONLY "dunning_events" ALTER COLUMN "id" SET DEFAULT "nextval"('"public"."dunning_events_id_seq"'::"regclass");
+
 ALTER TABLE ONLY "fee_batches" ALTER COLUMN "id" SET DEFAULT "nextval"('"public"."fee_batches_id_seq"'::"regclass");
 
 ALTER TABLE ONLY "fees" ALTER COLUMN "id" SET DEFAULT "nextval"('"public"."fees_id_seq"'::"regclass");
 
+ALTER TABLE ONLY "keysign_rejections" ALTER COLUMN "id" SET DEFAULT "nextval"('"public"."keysign_rejections_id_seq"'::"regclass");
+
+ALTER TABLE ONLY "ledger_entries" ALTER COLUMN "id" SET DEFAULT "nextval"('"public"."ledger_entries_id_seq"'::"regclass");
+
+ALTER TABLE ONLY "ledger_postings" ALTER COLUMN "id" SET DEFAULT "nextval"('"public"."ledger_postings_id_seq"'::"regclass");
+
+ALTER TABLE ONLY "ledger_reconciliations" ALTER COLUMN "id" SET DEFAULT "nextval"('"public"."ledger_reconciliations_id_seq"'::"regclass");
+
+ALTER TABLE ONLY "plugin_policy_activations" ALTER COLUMN "id" SET DEFAULT "nextval"('"public"."plugin_policy_activations_id_seq"'::"regclass");
+
+ALTER TABLE ONLY "plugin_policy_sync" ALTER COLUMN "seq" SET DEFAULT "nextval"('"public"."plugin_policy_sync_seq_seq"'::"regclass");
+
+ALTER TABLE ONLY "control_flag_outbox"
+    ADD CONSTRAINT "control_flag_outbox_pkey" PRIMARY KEY ("id");
+
 ALTER TABLE ONLY "control_flags"
     ADD CONSTRAINT "control_flags_pkey" PRIMARY KEY ("key");
 
+ALTER TABLE ONLY "developer_payout_fees"
+    ADD CONSTRAINT "developer_payout_fees_pkey" PRIMARY KEY ("fee_id");
+
+ALTER TABLE ONLY "developer_payouts"
+    ADD CONSTRAINT "developer_payouts_keysign_policy_id_key" UNIQUE ("keysign_policy_id");
+
+ALTER TABLE ONLY "developer_payouts"
+    ADD CONSTRAINT "developer_payouts_pkey" PRIMARY KEY ("id");
+
+ALTER TABLE ONLY "dunning_events"
+    ADD CONSTRAINT "dunning_events_pkey" PRIMARY KEY ("id");
+
+ALTER TABLE ONLY "dunning_states"
+    ADD CONSTRAINT "dunning_states_pkey" PRIMARY KEY ("public_key");
+
 ALTER TABLE ONLY "fee_batch_members"
     ADD CONSTRAINT "fee_batch_members_fee_id_key" UNIQUE ("fee_id");
 
@@ -460,12 +898,39 @@ ALTER TABLE ONLY "fee_batches"
 ALTER TABLE ONLY "fees"
     ADD CONSTRAINT "fees_pkey" PRIMARY KEY ("id");
 
-ALTER TABLE ONLY "plugin_apikey"
-    ADD CONSTRAINT "plugin_apikey_apikey_key" UNIQUE ("apikey");
+ALTER TABLE ONLY "invoices"
+    ADD CONSTRAINT "invoices_pkey" PRIMARY KEY ("id");
+
+ALTER TABLE ONLY "invoices"
+    ADD CONSTRAINT "invoices_public_key_period_start_key" UNIQUE ("public_key", "period_start");
+
+ALTER TABLE ONLY "keysign_rejections"
+    ADD CONSTRAINT "keysign_rejections_pkey" PRIMARY KEY ("id");
+
+ALTER TABLE ONLY "keysign_results"
+    ADD CONSTRAINT "keysign_results_pkey" PRIMARY KEY ("tx_indexer_id");
+
+ALTER TABLE ONLY "ledger_entries"
+    ADD CONSTRAINT "ledger_entries_fee_id_key" UNIQUE ("fee_id");
+
+ALTER TABLE ONLY "ledger_entries"
+    ADD CONSTRAINT "ledger_entries_payout_id_key" UNIQUE ("payout_id");
+
+ALTER TABLE ONLY "ledger_entries"
+    ADD CONSTRAINT "ledger_entries_pkey" PRIMARY KEY ("id");
+
+ALTER TABLE ONLY "ledger_postings"
+    ADD CONSTRAINT "ledger_postings_pkey" PRIMARY KEY ("id");
+
+ALTER TABLE ONLY "ledger_reconciliations"
+    ADD CONSTRAINT "ledger_reconciliations_pkey" PRIMARY KEY ("id");
 
 ALTER TABLE ONLY "plugin_apikey"
     ADD CONSTRAINT "plugin_apikey_pkey" PRIMARY KEY ("id");
 
+ALTER TABLE ONLY "plugin_health"
+    ADD CONSTRAINT "plugin_health_pkey" PRIMARY KEY ("plugin_id");
+
 ALTER TABLE ONLY "plugin_images"
     ADD CONSTRAINT "plugin_images_pkey" PRIMARY KEY ("id");
 
@@ -481,6 +946,9 @@ ALTER TABLE ONLY "plugin_pause_history"
 ALTER TABLE ONLY "plugin_policies"
     ADD CONSTRAINT "plugin_policies_pkey" PRIMARY KEY ("id");
 
+ALTER TABLE ONLY "plugin_policy_activations"
+    ADD CONSTRAINT "plugin_policy_activations_pkey" PRIMARY KEY ("id");
+
 ALTER TABLE ONLY "plugin_policy_billing"
     ADD CONSTRAINT "plugin_policy_billing_pkey" PRIMARY KEY ("id");
 
@@ -493,6 +961,9 @@ ALTER TABLE ONLY "plugin_ratings"
 ALTER TABLE ONLY "plugin_reports"
     ADD CONSTRAINT "plugin_reports_pkey" PRIMARY KEY ("plugin_id", "reporter_public_key");
 
+ALTER TABLE ONLY "plugin_signing_keys"
+    ADD CONSTRAINT "plugin_signing_keys_pkey" PRIMARY KEY ("key_id");
+
 ALTER TABLE ONLY "plugin_tags"
     ADD CONSTRAINT "plugin_tags_pkey" PRIMARY KEY ("plugin_id", "tag_id");
 
@@ -502,6 +973,9 @@ ALTER TABLE ONLY "plugins"
 ALTER TABLE ONLY "portal_approvers"
     ADD CONSTRAINT "portal_approvers_pkey" PRIMARY KEY ("public_key");
 
+ALTER TABLE ONLY "presignatures"
+    ADD CONSTRAINT "presignatures_pkey" PRIMARY KEY ("public_key", "plugin_id", "id");
+
 ALTER TABLE ONLY "pricings"
     ADD CONSTRAINT "pricings_pkey" PRIMARY KEY ("id");
 
@@ -523,12 +997,25 @@ ALTER TABLE ONLY "tags"
 ALTER TABLE ONLY "tx_indexer"
     ADD CONSTRAINT "tx_indexer_pkey" PRIMARY KEY ("id");
 
+ALTER TABLE ONLY "unbilled_txs"
+    ADD CONSTRAINT "unbilled_txs_pkey" PRIMARY KEY ("tx_id");
+
 ALTER TABLE ONLY "vault_tokens"
     ADD CONSTRAINT "vault_tokens_pkey" PRIMARY KEY ("id");
 
 ALTER TABLE ONLY "vault_tokens"
     ADD CONSTRAINT "vault_tokens_token_id_key" UNIQUE ("token_id");
 
+CREATE INDEX "idx_control_flag_outbox_pending" ON "control_flag_outbox" USING "btree" ("next_attempt_at") WHERE ("delivered_at" IS NULL);
+
+CREATE INDEX "idx_developer_payout_fees_payout_id" ON "developer_payout_fees" USING "btree" ("payout_id");
+
+CREATE INDEX "idx_developer_payouts_open" ON "developer_payouts" USING "btree" ("status") WHERE ("status" = ANY (ARRAY['PENDING'::"public"."payout_status", 'SIGNING'::"public"."payout_status"]));
+
+CREATE INDEX "idx_developer_payouts_plugin_id" ON "developer_payouts" USING "btree" ("plugin_id");
+
+CREATE INDEX "idx_dunning_events_public_key" ON "dunning_events" USING "btree" ("public_key", "created_at");
+
 CREATE INDEX "idx_fee_batches_collection_tx_id" ON "fee_batches" USING "btree" ("collection_tx_id") WHERE ("collection_tx_id" IS NOT NULL);
 
 CREATE INDEX "idx_fee_batches_created_at" ON "fee_batches" USING "btree" ("created_at" DESC);
@@ -545,11 +1032,25 @@ CREATE INDEX "idx_fees_policy_id" ON "fees" USING "btree" ("policy_id") WHERE ("
 
 CREATE INDEX "idx_fees_public_key" ON "fees" USING "btree" ("public_key");
 
+CREATE INDEX "idx_fees_public_key_asset" ON "fees" USING "btree" ("public_key", "asset");
+
 CREATE INDEX "idx_fees_transaction_type" ON "fees" USING "btree" ("transaction_type");
 
 CREATE INDEX "idx_fees_underlying_entity" ON "fees" USING "btree" ("underlying_type", "underlying_id");
 
-CREATE INDEX "idx_plugin_apikey_apikey" ON "plugin_apikey" USING "btree" ("apikey");
+CREATE INDEX "idx_invoices_public_key_period" ON "invoices" USING "btree" ("public_key", "period_start" DESC);
+
+CREATE INDEX "idx_keysign_rejections_created_at" ON "keysign_rejections" USING "btree" ("created_at");
+
+CREATE INDEX "idx_keysign_results_expires_at" ON "keysign_results" USING "btree" ("expires_at");
+
+CREATE INDEX "idx_keysign_results_session_id" ON "keysign_results" USING "btree" ("session_id");
+
+CREATE INDEX "idx_ledger_postings_account" ON "ledger_postings" USING "btree" ("account_type", "account_id", "asset");
+
+CREATE INDEX "idx_ledger_postings_entry_id" ON "ledger_postings" USING "btree" ("entry_id");
+
+CREATE INDEX "idx_plugin_apikey_key_prefix" ON "plugin_apikey" USING "btree" ("key_prefix");
 
 CREATE INDEX "idx_plugin_apikey_plugin_id" ON "plugin_apikey" USING "btree" ("plugin_id");
 
@@ -577,14 +1078,24 @@ CREATE INDEX "idx_plugin_policies_plugin_id" ON "plugin_policies" USING "btree"
 
 CREATE INDEX "idx_plugin_policies_public_key" ON "plugin_policies" USING "btree" ("public_key");
 
+CREATE INDEX "idx_plugin_policy_activations_policy_id" ON "plugin_policy_activations" USING "btree" ("policy_id", "activated_at");
+
 CREATE INDEX "idx_plugin_policy_billing_id" ON "plugin_policy_billing" USING "btree" ("id");
 
+CREATE INDEX "idx_plugin_policy_sync_pending" ON "plugin_policy_sync" USING "btree" ("policy_id", "seq") WHERE ("status" <> ALL (ARRAY[2, 5]));
+
 CREATE INDEX "idx_plugin_policy_sync_policy_id" ON "plugin_policy_sync" USING "btree" ("policy_id");
 
 CREATE INDEX "idx_plugin_reports_window" ON "plugin_reports" USING "btree" ("plugin_id", "last_reported_at" DESC);
 
+CREATE INDEX "idx_plugin_reports_status" ON "plugin_reports" USING "btree" ("status", "last_reported_at" DESC);
+
+CREATE INDEX "idx_plugin_signing_keys_plugin_id" ON "plugin_signing_keys" USING "btree" ("plugin_id");
+
 CREATE INDEX "idx_plugins_payout_address" ON "plugins" USING "btree" ("payout_address") WHERE ("payout_address" IS NOT NULL);
 
+CREATE INDEX "idx_presignatures_available" ON "presignatures" USING "btree" ("public_key", "plugin_id", "derive_path", "created_at") WHERE ("consumed_at" IS NULL);
+
 CREATE INDEX "idx_proposed_plugins_public_key" ON "proposed_plugins" USING "btree" ("public_key");
 
 CREATE INDEX "idx_proposed_plugins_public_key_status" ON "proposed_plugins" USING "btree" ("public_key", "status");
@@ -597,14 +1108,28 @@ CREATE INDEX "idx_reviews_public_key" ON "reviews" USING "btree" ("public_key");
 
 CREATE INDEX "idx_tx_indexer_key" ON "tx_indexer" USING "btree" ("chain_id", "plugin_id", "policy_id", "token_id", "to_public_key", "created_at");
 
+CREATE INDEX "idx_tx_indexer_plugin_id_created_at" ON "tx_indexer" USING "btree" ("plugin_id", "created_at");
+
 CREATE INDEX "idx_tx_indexer_policy_id_created_at" ON "tx_indexer" USING "btree" ("policy_id", "created_at");
 
 CREATE INDEX "idx_tx_indexer_status_onchain_lost" ON "tx_indexer" USING "btree" ("status_onchain", "lost");
 
 CREATE UNIQUE INDEX "idx_unique_installation_fee_per_plugin_user" ON "fees" USING "btree" ("underlying_id", "public_key") WHERE (("fee_type" = 'installation_fee'::"text") AND ("underlying_type" = 'plugin'::"text"));
 
+CREATE UNIQUE INDEX "idx_unique_open_policy_activation" ON "plugin_policy_activations" USING "btree" ("policy_id") WHERE ("deactivated_at" IS NULL);
+
+CREATE UNIQUE INDEX "idx_unique_refund_per_fee" ON "fees" USING "btree" ("underlying_id") WHERE (("fee_type" = 'refund'::"text") AND ("underlying_type" = 'fee'::"text"));
+
+CREATE UNIQUE INDEX "idx_unique_subscription_period" ON "fees" USING "btree" ("underlying_id", (("metadata" ->> 'period_start'::"text"))) WHERE (("fee_type" = 'subscription_fee'::"text") AND ("underlying_type" = 'policy'::"text"));
+
 CREATE UNIQUE INDEX "idx_unique_trial_fee" ON "fees" USING "btree" ("public_key") WHERE ("fee_type" = 'trial'::"text");
 
+CREATE UNIQUE INDEX "idx_unique_tx_exec_fee_per_tx" ON "fees" USING "btree" ("underlying_id") WHERE (("fee_type" = 'transaction_execution_fee'::"text") AND ("underlying_type" = 'tx_indexer_record'::"text") AND ("transaction_type" = 'debit'::"public"."transaction_type"));
+
+CREATE INDEX "idx_unbilled_txs_next_attempt_at" ON "unbilled_txs" USING "btree" ("next_attempt_at");
+
+CREATE INDEX "idx_vault_tokens_family_id" ON "vault_tokens" USING "btree" ("family_id");
+
 CREATE INDEX "idx_vault_tokens_public_key" ON "vault_tokens" USING "btree" ("public_key");
 
 CREATE INDEX "idx_vault_tokens_token_id" ON "vault_tokens" USING "btree" ("token_id");
@@ -621,28 +1146,68 @@ CREATE INDEX "proposed_plugin_images_plugin_id_type_idx" ON "proposed_plugin_ima
 
 CREATE UNIQUE INDEX "unique_fees_policy_per_public_key" ON "plugin_policies" USING "btree" ("plugin_id", "public_key") WHERE (("plugin_id" = 'vultisig-fees-feee'::"text") AND ("active" = true));
 
+CREATE CONSTRAINT TRIGGER "trg_check_ledger_entry_balanced" AFTER INSERT ON "ledger_postings" DEFERRABLE INITIALLY DEFERRED FOR EACH ROW EXECUTE FUNCTION "public"."check_ledger_entry_balanced"();
+
+CREATE TRIGGER "trg_notify_plugins_server_info" AFTER DELETE OR UPDATE OF "server_endpoint" ON "plugins" FOR EACH ROW EXECUTE FUNCTION "public"."notify_plugin_server_info"();
+
+CREATE TRIGGER "trg_post_fee_to_ledger" AFTER INSERT ON "fees" FOR EACH ROW EXECUTE FUNCTION "public"."post_fee_to_ledger"();
+
 CREATE TRIGGER "trg_prevent_billing_update_if_policy_deleted" BEFORE INSERT OR DELETE OR UPDATE ON "plugin_policy_billing" FOR EACH ROW EXECUTE FUNCTION "public"."prevent_billing_update_if_policy_deleted"();
 
 CREATE TRIGGER "trg_prevent_insert_if_policy_deleted" BEFORE INSERT ON "plugin_policies" FOR EACH ROW EXECUTE FUNCTION "public"."prevent_insert_if_policy_deleted"();
 
+CREATE TRIGGER "trg_prevent_ledger_entries_modification" BEFORE DELETE OR UPDATE ON "ledger_entries" FOR EACH ROW EXECUTE FUNCTION "public"."prevent_ledger_modification"();
+
+CREATE TRIGGER "trg_prevent_ledger_postings_modification" BEFORE DELETE OR UPDATE ON "ledger_postings" FOR EACH ROW EXECUTE FUNCTION "public"."prevent_ledger_modification"();
+
 CREATE TRIGGER "trg_prevent_update_if_policy_deleted" BEFORE UPDATE ON "plugin_policies" FOR EACH ROW WHEN (("old"."deleted" = true)) EXECUTE FUNCTION "public"."prevent_update_if_policy_deleted"();
 
 CREATE TRIGGER "trg_set_policy_inactive_on_delete" BEFORE INSERT OR UPDATE ON "plugin_policies" FOR EACH ROW WHEN (("new"."deleted" = true)) EXECUTE FUNCTION "public"."set_policy_inactive_on_delete"();
 
+CREATE TRIGGER "trg_track_policy_activation_insert" AFTER INSERT ON "plugin_policies" FOR EACH ROW WHEN (("new"."active" = true)) EXECUTE FUNCTION "public"."track_policy_activation"();
+
+CREATE TRIGGER "trg_track_policy_activation_update" AFTER UPDATE ON "plugin_policies" FOR EACH ROW WHEN (("old"."active" IS DISTINCT FROM "new"."active")) EXECUTE FUNCTION "public"."track_policy_activation"();
+
 CREATE TRIGGER "trigger_prevent_fee_deletion" BEFORE DELETE ON "fees" FOR EACH ROW EXECUTE FUNCTION "public"."prevent_fee_deletion"();
 
+CREATE TRIGGER "trigger_prevent_invoice_modification" BEFORE DELETE OR UPDATE ON "invoices" FOR EACH ROW EXECUTE FUNCTION "public"."prevent_invoice_modification"();
+
+ALTER TABLE ONLY "developer_payout_fees"
+    ADD CONSTRAINT "developer_payout_fees_fee_id_fkey" FOREIGN KEY ("fee_id") REFERENCES "fees"("id");
+
+ALTER TABLE ONLY "developer_payout_fees"
+    ADD CONSTRAINT "developer_payout_fees_payout_id_fkey" FOREIGN KEY ("payout_id") REFERENCES "developer_payouts"("id") ON DELETE CASCADE;
+
+ALTER TABLE ONLY "developer_payouts"
+    ADD CONSTRAINT "developer_payouts_plugin_id_fkey" FOREIGN KEY ("plugin_id") REFERENCES "plugins"("id");
+
 ALTER TABLE ONLY "fee_batch_members"
     ADD CONSTRAINT "fee_batch_members_batch_id_fkey" FOREIGN KEY ("batch_id") REFERENCES "fee_batches"("id") ON DELETE CASCADE;
 
 ALTER TABLE ONLY "fee_batch_members"
     ADD CONSTRAINT "fee_batch_members_fee_id_fkey" FOREIGN KEY ("fee_id") REFERENCES "fees"("id") ON DELETE RESTRICT;
 
+ALTER TABLE ONLY "plugin_policy_activations"
+    ADD CONSTRAINT "plugin_policy_activations_policy_id_fkey" FOREIGN KEY ("policy_id") REFERENCES "plugin_policies"("id") ON DELETE CASCADE;
+
 ALTER TABLE ONLY "plugin_policy_billing"
     ADD CONSTRAINT "fk_plugin_policy" FOREIGN KEY ("plugin_policy_id") REFERENCES "plugin_policies"("id") ON DELETE CASCADE;
 
+ALTER TABLE ONLY "ledger_entries"
+    ADD CONSTRAINT "ledger_entries_fee_id_fkey" FOREIGN KEY ("fee_id") REFERENCES "fees"("id");
+
+ALTER TABLE ONLY "ledger_entries"
+    ADD CONSTRAINT "ledger_entries_payout_id_fkey" FOREIGN KEY ("payout_id") REFERENCES "developer_payouts"("id");
+
+ALTER TABLE ONLY "ledger_postings"
+    ADD CONSTRAINT "ledger_postings_entry_id_fkey" FOREIGN KEY ("entry_id") REFERENCES "ledger_entries"("id");
+
 ALTER TABLE ONLY "plugin_apikey"
     ADD CONSTRAINT "plugin_apikey_plugin_id_fkey" FOREIGN KEY ("plugin_id") REFERENCES "plugins"("id") ON DELETE CASCADE;
 
+ALTER TABLE ONLY "plugin_health"
+    ADD CONSTRAINT "plugin_health_plugin_id_fkey" FOREIGN KEY ("plugin_id") REFERENCES "plugins"("id") ON DELETE CASCADE;
+
 ALTER TABLE ONLY "plugin_images"
     ADD CONSTRAINT "plugin_images_plugin_id_fkey" FOREIGN KEY ("plugin_id") REFERENCES "plugins"("id") ON DELETE CASCADE;
 
@@ -664,6 +1229,9 @@ ALTER TABLE ONLY "plugin_ratings"
 ALTER TABLE ONLY "plugin_reports"
     ADD CONSTRAINT "plugin_reports_plugin_id_fkey" FOREIGN KEY ("plugin_id") REFERENCES "plugins"("id") ON DELETE CASCADE;
 
+ALTER TABLE ONLY "plugin_signing_keys"
+    ADD CONSTRAINT "plugin_signing_keys_plugin_id_fkey" FOREIGN KEY ("plugin_id") REFERENCES "plugins"("id") ON DELETE CASCADE;
+
 ALTER TABLE ONLY "plugin_tags"
     ADD CONSTRAINT "plugin_tags_plugin_id_fkey" FOREIGN KEY ("plugin_id") REFERENCES "plugins"("id") ON DELETE CASCADE;
 
@@ -679,3 +1247,6 @@ ALTER TABLE ONLY "proposed_plugin_images"
 ALTER TABLE ONLY "reviews"
     ADD CONSTRAINT "reviews_plugin_id_fkey" FOREIGN KEY ("plugin_id") REFERENCES "plugins"("id") ON DELETE CASCADE;
 
+
+ALTER TABLE ONLY "unbilled_txs"
+    ADD CONSTRAINT "unbilled_txs_tx_id_fkey" FOREIGN KEY ("tx_id") REFERENCES "tx_indexer"("id") ON DELETE CASCADE;
@@ -21,6 +21,13 @@ SET expires_at = NOW()
 WHERE id = $1
 RETURNING *;
 
+-- name: ShortenPluginApiKeyExpiry :one
+UPDATE plugin_apikey
+SET expires_at = LEAST(COALESCE(expires_at, @expires_at::timestamptz), @expires_at::timestamptz),
+    rotated_at = NOW()
+WHERE id = @id
+RETURNING *;
+
 -- name: AcquireApiKeyLock :exec
 SELECT pg_advisory_xact_lock(hashtextextended($1::text, 0));
 
@@ -31,6 +38,6 @@ WHERE plugin_id = $1
   AND (expires_at IS NULL OR expires_at > NOW());
 
 -- name: CreatePluginApiKey :one
-INSERT INTO plugin_apikey (plugin_id, apikey, expires_at, status)
-VALUES ($1, $2, $3, 1)
+INSERT INTO plugin_apikey (plugin_id, key_prefix, key_salt, key_hash, scopes, allowed_ips, expires_at, status)
+VALUES ($1, $2, $3, $4, $5, $6, $7, 1)
 RETURNING *;
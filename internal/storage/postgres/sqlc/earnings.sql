@@ -6,12 +6,16 @@ SELECT
     f.plugin_id,
     p.title as plugin_name,
     f.amount,
-    'usdc' as asset,
+    f.asset::text as asset,
     COALESCE(ppb.type::text, 'per-tx') as pricing_type,
     f.created_at,
     f.public_key as from_address,
     COALESCE(ti.tx_hash, '') as tx_hash,
     CASE
+        WHEN EXISTS (
+            SELECT 1 FROM fees r
+            WHERE r.fee_type = 'refund' AND r.underlying_type = 'fee' AND r.underlying_id = f.id::text
+        ) THEN 'refunded'
         WHEN ti.status_onchain = 'SUCCESS' THEN 'completed'
         WHEN ti.status_onchain = 'FAIL' THEN 'failed'
         ELSE 'pending'
@@ -33,12 +37,16 @@ SELECT
     f.plugin_id,
     p.title as plugin_name,
     f.amount,
-    'usdc' as asset,
+    f.asset::text as asset,
     COALESCE(ppb.type::text, 'per-tx') as pricing_type,
     f.created_at,
     f.public_key as from_address,
     COALESCE(ti.tx_hash, '') as tx_hash,
     CASE
+        WHEN EXISTS (
+            SELECT 1 FROM fees r
+            WHERE r.fee_type = 'refund' AND r.underlying_type = 'fee' AND r.underlying_id = f.id::text
+        ) THEN 'refunded'
         WHEN ti.status_onchain = 'SUCCESS' THEN 'completed'
         WHEN ti.status_onchain = 'FAIL' THEN 'failed'
         ELSE 'pending'
@@ -75,21 +83,21 @@ AND ($4::timestamptz IS NULL OR f.created_at <= $4);
 
 -- name: GetEarningsSummaryByPluginOwner :one
 SELECT
-    COALESCE(SUM(f.amount), 0)::bigint as total_earnings,
-    COUNT(f.id)::bigint as total_transactions
+    COALESCE(SUM(CASE WHEN f.transaction_type = 'debit' THEN f.amount ELSE -f.amount END), 0)::bigint as total_earnings,
+    COUNT(f.id) FILTER (WHERE f.transaction_type = 'debit')::bigint as total_transactions
 FROM fees f
 WHERE f.plugin_id IN (
     SELECT po.plugin_id FROM plugin_owners po WHERE po.public_key = $1 AND po.active = true
 )
-AND f.transaction_type = 'debit';
+AND (f.transaction_type = 'debit' OR f.fee_type = 'refund');
 
 -- name: GetEarningsByPluginForOwner :many
 SELECT
     f.plugin_id,
-    COALESCE(SUM(f.amount), 0)::bigint as total
+    COALESCE(SUM(CASE WHEN f.transaction_type = 'debit' THEN f.amount ELSE -f.amount END), 0)::bigint as total
 FROM fees f
 WHERE f.plugin_id IN (
     SELECT po.plugin_id FROM plugin_owners po WHERE po.public_key = $1 AND po.active = true
 )
-AND f.transaction_type = 'debit'
+AND (f.transaction_type = 'debit' OR f.fee_type = 'refund')
 GROUP BY f.plugin_id;
@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	itypes "github.com/vultisig/verifier/internal/types"
+)
+
+// UpsertUnbilledTx records a successful tx whose fee couldn't be priced, or the next attempt of a known one
+func (p *PostgresBackend) UpsertUnbilledTx(ctx context.Context, tx itypes.UnbilledTx) error {
+	query := `
+		INSERT INTO unbilled_txs (tx_id, attempts, last_error, next_attempt_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (tx_id) DO UPDATE
+		SET attempts = EXCLUDED.attempts, last_error = EXCLUDED.last_error, next_attempt_at = EXCLUDED.next_attempt_at`
+
+	_, err := p.pool.Exec(ctx, query, tx.TxID, tx.Attempts, tx.LastError, tx.NextAttemptAt)
+	if err != nil {
+		return fmt.Errorf("failed to upsert unbilled tx: %w", err)
+	}
+	return nil
+}
+
+// GetDueUnbilledTxs returns the unbilled txs whose next pricing attempt is due, the most overdue first
+func (p *PostgresBackend) GetDueUnbilledTxs(ctx context.Context, now time.Time, limit int) ([]itypes.UnbilledTx, error) {
+	query := `
+		SELECT tx_id, attempts, last_error, next_attempt_at
+		FROM unbilled_txs
+		WHERE next_attempt_at <= $1
+		ORDER BY next_attempt_at
+		LIMIT $2`
+
+	rows, err := p.pool.Query(ctx, query, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due unbilled txs: %w", err)
+	}
+	defer rows.Close()
+
+	var txs []itypes.UnbilledTx
+	for rows.Next() {
+		var tx itypes.UnbilledTx
+		if err := rows.Scan(&tx.TxID, &tx.Attempts, &tx.LastError, &tx.NextAttemptAt); err != nil {
+			return nil, fmt.Errorf("failed to scan unbilled tx: %w", err)
+		}
+		txs = append(txs, tx)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating unbilled txs: %w", err)
+	}
+	return txs, nil
+}
+
+// DeleteUnbilledTx clears the marker of a tx once it is billed, a tx without a marker is a no-op
+func (p *PostgresBackend) DeleteUnbilledTx(ctx context.Context, dbTx pgx.Tx, txID uuid.UUID) error {
+	_, err := dbTx.Exec(ctx, `DELETE FROM unbilled_txs WHERE tx_id = $1`, txID)
+	if err != nil {
+		return fmt.Errorf("failed to delete unbilled tx: %w", err)
+	}
+	return nil
+}
@@ -2,27 +2,21 @@ package postgres
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+
 	"github.com/vultisig/verifier/internal/types"
 )
 
-func (p *PostgresBackend) CreateVaultToken(ctx context.Context, token types.VaultTokenCreate) (*types.VaultToken, error) {
-	query := `
-		INSERT INTO vault_tokens (token_id, public_key, expires_at, last_used_at, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING id, token_id, public_key, expires_at, last_used_at, created_at, updated_at`
+const vaultTokenColumns = `id, token_id, public_key, expires_at, last_used_at, created_at, updated_at, revoked_at,
+	family_id, rotated_at, device_name, user_agent, ip_address`
 
-	now := time.Now()
+func scanVaultToken(row pgx.Row) (*types.VaultToken, error) {
 	var vaultToken types.VaultToken
-	err := p.pool.QueryRow(ctx, query,
-		token.TokenID,
-		token.PublicKey,
-		token.ExpiresAt,
-		now,
-		now,
-		now,
-	).Scan(
+	err := row.Scan(
 		&vaultToken.ID,
 		&vaultToken.TokenID,
 		&vaultToken.PublicKey,
@@ -30,48 +24,115 @@ func (p *PostgresBackend) CreateVaultToken(ctx context.Context, token types.Vaul
 		&vaultToken.LastUsedAt,
 		&vaultToken.CreatedAt,
 		&vaultToken.UpdatedAt,
+		&vaultToken.RevokedAt,
+		&vaultToken.FamilyID,
+		&vaultToken.RotatedAt,
+		&vaultToken.DeviceName,
+		&vaultToken.UserAgent,
+		&vaultToken.IPAddress,
 	)
 	if err != nil {
 		return nil, err
 	}
-
 	return &vaultToken, nil
 }
 
-func (p *PostgresBackend) GetVaultToken(ctx context.Context, tokenID string) (*types.VaultToken, error) {
+type queryRower interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// insertVaultToken starts a new family when token has no FamilyID
+func insertVaultToken(ctx context.Context, q queryRower, token types.VaultTokenCreate) (*types.VaultToken, error) {
 	query := `
-		SELECT id, token_id, public_key, expires_at, last_used_at, created_at, updated_at, revoked_at
-		FROM vault_tokens
-		WHERE token_id = $1`
+		INSERT INTO vault_tokens (token_id, public_key, expires_at, last_used_at, created_at, updated_at,
+			family_id, parent_token_id, device_name, user_agent, ip_address)
+		VALUES ($1, $2, $3, $4, $4, $4, COALESCE(NULLIF($5, '')::uuid, gen_random_uuid()), NULLIF($6, ''), $7, $8, $9)
+		RETURNING ` + vaultTokenColumns
 
-	var vaultToken types.VaultToken
-	err := p.pool.QueryRow(ctx, query, tokenID).Scan(
-		&vaultToken.ID,
-		&vaultToken.TokenID,
-		&vaultToken.PublicKey,
-		&vaultToken.ExpiresAt,
-		&vaultToken.LastUsedAt,
-		&vaultToken.CreatedAt,
-		&vaultToken.UpdatedAt,
-		&vaultToken.RevokedAt,
-	)
+	return scanVaultToken(q.QueryRow(ctx, query,
+		token.TokenID,
+		token.PublicKey,
+		token.ExpiresAt,
+		time.Now(),
+		token.FamilyID,
+		token.ParentTokenID,
+		token.DeviceName,
+		token.UserAgent,
+		token.IPAddress,
+	))
+}
+
+func (p *PostgresBackend) CreateVaultToken(ctx context.Context, token types.VaultTokenCreate) (*types.VaultToken, error) {
+	return insertVaultToken(ctx, p.pool, token)
+}
+
+// RotateVaultToken marks the refresh token tokenID as rotated and creates next in the same transaction.
+// It returns nil when tokenID was already rotated or revoked, so that only one of concurrent refreshes succeeds.
+func (p *PostgresBackend) RotateVaultToken(ctx context.Context, tokenID string, next types.VaultTokenCreate) (*types.VaultToken, error) {
+	var created *types.VaultToken
+	err := p.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		var familyID string
+		err := tx.QueryRow(ctx, `
+			UPDATE vault_tokens
+			SET rotated_at = NOW(), updated_at = NOW()
+			WHERE token_id = $1 AND rotated_at IS NULL AND revoked_at IS NULL
+			RETURNING family_id`, tokenID).Scan(&familyID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil
+			}
+			return fmt.Errorf("failed to mark token as rotated: %w", err)
+		}
+
+		next.FamilyID = familyID
+		next.ParentTokenID = tokenID
+		created, err = insertVaultToken(ctx, tx, next)
+		if err != nil {
+			return fmt.Errorf("failed to insert rotated token: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
+	return created, nil
+}
 
-	return &vaultToken, nil
+func (p *PostgresBackend) GetVaultToken(ctx context.Context, tokenID string) (*types.VaultToken, error) {
+	query := `
+		SELECT ` + vaultTokenColumns + `
+		FROM vault_tokens
+		WHERE token_id = $1`
+
+	return scanVaultToken(p.pool.QueryRow(ctx, query, tokenID))
 }
 
+// RevokeVaultToken revokes the token along with every token of its family, which signs the device out
 func (p *PostgresBackend) RevokeVaultToken(ctx context.Context, tokenID string) error {
 	query := `
 		UPDATE vault_tokens
 		SET revoked_at = $1, updated_at = $1
-		WHERE token_id = $2`
+		WHERE family_id = (SELECT family_id FROM vault_tokens WHERE token_id = $2)
+		AND revoked_at IS NULL`
 
 	_, err := p.pool.Exec(ctx, query, time.Now(), tokenID)
 	return err
 }
 
+// IsVaultTokenFamilyRevoked reports whether the tokens of the family were revoked, the whole family is revoked at once
+func (p *PostgresBackend) IsVaultTokenFamilyRevoked(ctx context.Context, familyID string) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM vault_tokens
+			WHERE family_id = $1::uuid
+			AND revoked_at IS NOT NULL
+		)`
+
+	var revoked bool
+	err := p.pool.QueryRow(ctx, query, familyID).Scan(&revoked)
+	return revoked, err
+}
+
 func (p *PostgresBackend) RevokeAllVaultTokens(ctx context.Context, publicKey string) error {
 	query := `
 		UPDATE vault_tokens
@@ -93,12 +154,14 @@ func (p *PostgresBackend) UpdateVaultTokenLastUsed(ctx context.Context, tokenID
 	return err
 }
 
+// GetActiveVaultTokens returns the current refresh token of every signed in device
 func (p *PostgresBackend) GetActiveVaultTokens(ctx context.Context, publicKey string) ([]types.VaultToken, error) {
 	query := `
-		SELECT id, token_id, public_key, expires_at, last_used_at, created_at, updated_at, revoked_at
+		SELECT ` + vaultTokenColumns + `
 		FROM vault_tokens
 		WHERE public_key = $1
 		AND revoked_at IS NULL
+		AND rotated_at IS NULL
 		AND expires_at > $2
 		ORDER BY created_at DESC`
 
@@ -110,21 +173,11 @@ func (p *PostgresBackend) GetActiveVaultTokens(ctx context.Context, publicKey st
 
 	var tokens []types.VaultToken
 	for rows.Next() {
-		var token types.VaultToken
-		err := rows.Scan(
-			&token.ID,
-			&token.TokenID,
-			&token.PublicKey,
-			&token.ExpiresAt,
-			&token.LastUsedAt,
-			&token.CreatedAt,
-			&token.UpdatedAt,
-			&token.RevokedAt,
-		)
+		token, err := scanVaultToken(rows)
 		if err != nil {
 			return nil, err
 		}
-		tokens = append(tokens, token)
+		tokens = append(tokens, *token)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -3,9 +3,11 @@ package storage
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/redis/go-redis/v9"
+	"github.com/vultisig/verifier/internal/ratelimit"
 	"github.com/vultisig/verifier/plugin/config"
 	"github.com/vultisig/vultiserver/contexthelper"
 )
@@ -45,6 +47,14 @@ func (r *RedisStorage) Set(ctx context.Context, key string, value string, expiry
 	return r.client.Set(ctx, key, value, expiry).Err()
 }
 
+// SetNX sets key unless it is already set, it reports whether key was set
+func (r *RedisStorage) SetNX(ctx context.Context, key string, value string, expiry time.Duration) (bool, error) {
+	if err := contexthelper.CheckCancellation(ctx); err != nil {
+		return false, err
+	}
+	return r.client.SetNX(ctx, key, value, expiry).Result()
+}
+
 func (r *RedisStorage) Exists(ctx context.Context, key string) (bool, error) {
 	if err := contexthelper.CheckCancellation(ctx); err != nil {
 		return false, err
@@ -95,3 +105,48 @@ func (r *RedisStorage) StoreNonce(ctx context.Context, nonce string, publicKey s
 	expiryDuration := time.Until(expiryTime)
 	return r.Set(ctx, key, "1", expiryDuration)
 }
+
+// takeTokenScript refills the bucket of KEYS[1] by the time elapsed on the redis clock, so that every verifier
+// instance shares it, and takes a token when there is one. It returns whether the token was taken and the tokens left.
+var takeTokenScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local time = redis.call('TIME')
+local now = tonumber(time[1]) + tonumber(time[2]) / 1000000
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(bucket[1])
+local ts = tonumber(bucket[2])
+if tokens == nil or ts == nil then
+	tokens = burst
+	ts = now
+end
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate)
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(now))
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst / rate * 1000) + 1000)
+return {allowed, tostring(tokens)}
+`)
+
+// TakeRateLimitToken takes a token from the bucket of key, the bucket expires once it is full again
+func (r *RedisStorage) TakeRateLimitToken(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	if err := contexthelper.CheckCancellation(ctx); err != nil {
+		return ratelimit.Result{}, err
+	}
+	values, err := takeTokenScript.Run(ctx, r.client, []string{"ratelimit:" + key}, limit.Rate, limit.Burst).Slice()
+	if err != nil {
+		return ratelimit.Result{}, fmt.Errorf("failed to take rate limit token: %w", err)
+	}
+	if len(values) != 2 {
+		return ratelimit.Result{}, fmt.Errorf("unexpected rate limit script result: %v", values)
+	}
+	allowed, _ := values[0].(int64)
+	tokens, err := strconv.ParseFloat(fmt.Sprint(values[1]), 64)
+	if err != nil {
+		return ratelimit.Result{}, fmt.Errorf("invalid rate limit tokens: %w", err)
+	}
+	return ratelimit.Result{Allowed: allowed == 1, Tokens: tokens}, nil
+}
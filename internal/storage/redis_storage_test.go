@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vultisig/verifier/internal/ratelimit"
+)
+
+func newTestRedisStorage(t *testing.T) (*RedisStorage, *miniredis.Miniredis) {
+	t.Helper()
+	m := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: m.Addr()})
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	return &RedisStorage{client: client}, m
+}
+
+func TestRedisStorage_TakeRateLimitToken(t *testing.T) {
+	r, m := newTestRedisStorage(t)
+	ctx := context.Background()
+	limit := ratelimit.Limit{Rate: 2, Burst: 3}
+	start := time.Unix(1_700_000_000, 0)
+	m.SetTime(start)
+
+	for i := 2; i >= 0; i-- {
+		res, err := r.TakeRateLimitToken(ctx, "ip:1.2.3.4", limit)
+		require.NoError(t, err)
+		require.True(t, res.Allowed)
+		require.InDelta(t, float64(i), res.Tokens, 1e-6)
+	}
+
+	res, err := r.TakeRateLimitToken(ctx, "ip:1.2.3.4", limit)
+	require.NoError(t, err)
+	require.False(t, res.Allowed, "the bucket is empty")
+	require.InDelta(t, 0, res.Tokens, 1e-6)
+
+	other, err := r.TakeRateLimitToken(ctx, "ip:5.6.7.8", limit)
+	require.NoError(t, err)
+	require.True(t, other.Allowed, "every key has its own bucket")
+
+	ttl := m.TTL("ratelimit:ip:1.2.3.4")
+	require.Positive(t, ttl)
+	require.LessOrEqual(t, ttl, 2500*time.Millisecond, "the bucket expires once it is full again")
+
+	// half a second refills one token at 2 per second
+	m.SetTime(start.Add(500 * time.Millisecond))
+	res, err = r.TakeRateLimitToken(ctx, "ip:1.2.3.4", limit)
+	require.NoError(t, err)
+	require.True(t, res.Allowed)
+	require.InDelta(t, 0, res.Tokens, 1e-6)
+
+	// the refill is capped by the burst
+	m.SetTime(start.Add(time.Hour))
+	res, err = r.TakeRateLimitToken(ctx, "ip:1.2.3.4", limit)
+	require.NoError(t, err)
+	require.True(t, res.Allowed)
+	require.InDelta(t, 2, res.Tokens, 1e-6)
+}
+
+func TestRedisStorage_TakeRateLimitTokenCancelled(t *testing.T) {
+	r, _ := newTestRedisStorage(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := r.TakeRateLimitToken(ctx, "ip:1.2.3.4", ratelimit.Limit{Rate: 1, Burst: 1})
+	require.Error(t, err)
+}
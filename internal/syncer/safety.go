@@ -31,7 +31,7 @@ func (s *Syncer) SyncSafetyToPlugin(ctx context.Context, pluginID types.PluginID
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+serverInfo.ApiKey)
+	s.authorize(req, pluginID, flagBytes)
 
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
@@ -3,7 +3,7 @@ package syncer
 import (
 	"bytes"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -17,18 +17,28 @@ import (
 
 	"github.com/vultisig/verifier/internal/storage"
 	itypes "github.com/vultisig/verifier/internal/types"
-	"github.com/vultisig/verifier/types"
+	"github.com/vultisig/verifier/plugin/reqsign"
 	ptypes "github.com/vultisig/verifier/types"
 )
 
 const (
 	defaultTimeout = 10 * time.Second
 	policyEndpoint = "/plugin/policy"
+	// deliveryIDHeader carries the ID of a policy sync, the plugin server dedupes on it
+	deliveryIDHeader = "X-Delivery-ID"
 
 	// Retry configuration
 	maxRetries = 3
+
+	// serverInfoTTL bounds how long a server info is cached when a change notification is missed
+	serverInfoTTL = 5 * time.Minute
+	// watchRetryDelay is how long the watcher waits before listening again after the connection failed
+	watchRetryDelay = 5 * time.Second
 )
 
+// ErrPolicyRejected is returned when the plugin server refuses a policy change, delivering it again won't succeed
+var ErrPolicyRejected = errors.New("plugin server rejected the policy change")
+
 type Action int
 
 const (
@@ -40,17 +50,26 @@ type Syncer struct {
 	logger  *logrus.Logger
 	client  *retryablehttp.Client
 	storage storage.DatabaseStorage
+	signer  *reqsign.Signer
+	// legacyTokens are the bearer tokens of the plugin servers that don't verify the signature yet, by plugin ID
+	legacyTokens map[string]string
 
 	cacheLocker           sync.Locker
-	pluginServerInfoCache map[ptypes.PluginID]*ServerInfo
+	pluginServerInfoCache map[ptypes.PluginID]serverInfoEntry
+	now                   func() time.Time
 }
 
 type ServerInfo struct {
-	Addr   string
-	ApiKey string
+	Addr string
 }
 
-func NewPolicySyncer(storage storage.DatabaseStorage) *Syncer {
+type serverInfoEntry struct {
+	info      *ServerInfo
+	expiresAt time.Time
+}
+
+// NewPolicySyncer signs the requests to the plugin servers with signer, they verify them with the verifier public keys
+func NewPolicySyncer(storage storage.DatabaseStorage, signer *reqsign.Signer) *Syncer {
 	logger := logrus.WithField("component", "policy-syncer").Logger
 	retryClient := retryablehttp.NewClient()
 	retryClient.HTTPClient.Timeout = defaultTimeout
@@ -60,8 +79,24 @@ func NewPolicySyncer(storage storage.DatabaseStorage) *Syncer {
 		logger:                logger,
 		client:                retryClient,
 		storage:               storage,
-		pluginServerInfoCache: make(map[ptypes.PluginID]*ServerInfo),
+		signer:                signer,
+		pluginServerInfoCache: make(map[ptypes.PluginID]serverInfoEntry),
 		cacheLocker:           &sync.Mutex{},
+		now:                   time.Now,
+	}
+}
+
+// SetLegacyBearerTokens sends the bearer token of a plugin next to the signature,
+// for the plugin servers deployed before they verified the verifier signature
+func (s *Syncer) SetLegacyBearerTokens(tokens map[string]string) {
+	s.legacyTokens = tokens
+}
+
+// authorize authenticates a request to the plugin server, body is the body req is sent with
+func (s *Syncer) authorize(req *http.Request, pluginID ptypes.PluginID, body []byte) {
+	s.signer.Sign(req, body)
+	if token, ok := s.legacyTokens[pluginID.String()]; ok {
+		req.Header.Set("Authorization", "Bearer "+token)
 	}
 }
 
@@ -69,18 +104,50 @@ func (s *Syncer) getServerInfo(ctx context.Context, pluginID ptypes.PluginID) (*
 	s.cacheLocker.Lock()
 	defer s.cacheLocker.Unlock()
 
-	if info, ok := s.pluginServerInfoCache[pluginID]; ok {
-		return info, nil
+	if entry, ok := s.pluginServerInfoCache[pluginID]; ok && s.now().Before(entry.expiresAt) {
+		return entry.info, nil
 	}
 
 	addr, err := s.getServerInfoFromStorage(ctx, pluginID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get server address from storage: %w", err)
 	}
-	s.pluginServerInfoCache[pluginID] = addr
+	s.pluginServerInfoCache[pluginID] = serverInfoEntry{
+		info:      addr,
+		expiresAt: s.now().Add(serverInfoTTL),
+	}
 	return addr, nil
 }
 
+// InvalidateServerInfo drops the cached server info of the plugin, the next sync reads it again
+func (s *Syncer) InvalidateServerInfo(pluginID ptypes.PluginID) {
+	s.cacheLocker.Lock()
+	defer s.cacheLocker.Unlock()
+	delete(s.pluginServerInfoCache, pluginID)
+}
+
+// WatchServerInfo invalidates the server info of the plugins whose endpoint changes until ctx is done.
+// The whole cache is dropped when the listener reconnects, the changes made meanwhile were not notified.
+func (s *Syncer) WatchServerInfo(ctx context.Context) {
+	for {
+		err := s.storage.ListenPluginServerInfo(ctx, s.InvalidateServerInfo)
+		if ctx.Err() != nil {
+			return
+		}
+		s.logger.WithError(err).Warn("stopped listening to plugin server info changes, retrying")
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(watchRetryDelay):
+		}
+
+		s.cacheLocker.Lock()
+		s.pluginServerInfoCache = make(map[ptypes.PluginID]serverInfoEntry)
+		s.cacheLocker.Unlock()
+	}
+}
+
 func (s *Syncer) getServerInfoFromStorage(ctx context.Context, pluginID ptypes.PluginID) (*ServerInfo, error) {
 	if err := contexthelper.CheckCancellation(ctx); err != nil {
 		return nil, err
@@ -90,195 +157,67 @@ func (s *Syncer) getServerInfoFromStorage(ctx context.Context, pluginID ptypes.P
 	if err != nil {
 		return nil, fmt.Errorf("failed to find plugin by id: %w", err)
 	}
-	apiKey, err := s.storage.GetAPIKeyByPluginId(ctx, pluginID.String())
-	if err != nil {
-		return nil, fmt.Errorf("failed to get api key by id: %w", err)
-	}
 	return &ServerInfo{
-		plugin.ServerEndpoint,
-		apiKey.ApiKey,
+		Addr: plugin.ServerEndpoint,
 	}, nil
 }
 
-// Creates a policy in a synchronous manner.
-func (s *Syncer) CreatePolicySync(ctx context.Context, pluginPolicy types.PluginPolicy) error {
-	policyBytes, err := json.Marshal(pluginPolicy)
-	if err != nil {
-		return fmt.Errorf("fail to marshal policy: %v", err)
-	}
-	serverInfo, err := s.getServerInfo(ctx, pluginPolicy.PluginID)
+// SendPolicySync delivers a policy change to the plugin server. The sync ID is sent as the delivery ID,
+// a plugin server that already applied the change answers it without applying it again.
+func (s *Syncer) SendPolicySync(ctx context.Context, sync itypes.PluginPolicySync) error {
+	serverInfo, err := s.getServerInfo(ctx, sync.PluginID)
 	if err != nil {
 		return fmt.Errorf("failed to get server address: %w", err)
 	}
 
-	url := serverInfo.Addr + policyEndpoint
-	req, err := retryablehttp.NewRequest(http.MethodPost, url, bytes.NewBuffer(policyBytes))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+serverInfo.ApiKey)
-	resp, err := s.client.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to sync policy with plugin server(%s): %s", url, err.Error())
-	}
-	defer s.closer(resp.Body)
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read response body: %w", err)
-	}
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to sync policy with plugin server(%s): status: %d, body: %s", url, resp.StatusCode, string(body))
-	}
-	return nil
-}
-
-// Updates a policy in a synchronous manner.
-func (s *Syncer) UpdatePolicySync(ctx context.Context, pluginPolicy types.PluginPolicy) error {
-	policyBytes, err := json.Marshal(pluginPolicy)
-	if err != nil {
-		return fmt.Errorf("fail to marshal policy: %v", err)
-	}
-	serverInfo, err := s.getServerInfo(ctx, pluginPolicy.PluginID)
-	if err != nil {
-		return fmt.Errorf("failed to get server address: %w", err)
+	method, url := http.MethodPost, serverInfo.Addr+policyEndpoint
+	switch sync.SyncType {
+	case itypes.AddPolicy:
+	case itypes.UpdatePolicy:
+		method = http.MethodPut
+	case itypes.RemovePolicy:
+		method, url = http.MethodDelete, url+"/"+sync.PolicyID.String()
+	default:
+		return fmt.Errorf("unknown policy sync type: %d", sync.SyncType)
 	}
 
-	url := serverInfo.Addr + policyEndpoint
-	req, err := retryablehttp.NewRequest(http.MethodPut, url, bytes.NewBuffer(policyBytes))
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(sync.Payload))
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+serverInfo.ApiKey)
-	resp, err := s.client.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to sync policy update with plugin server(%s): %s", url, err.Error())
-	}
-	defer s.closer(resp.Body)
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read response body: %w", err)
-	}
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to sync policy update with plugin server(%s): status: %d, body: %s", url, resp.StatusCode, string(body))
-	}
-	return nil
-}
-
-// Deprecated: use CreatePolicySync instead.
-func (s *Syncer) CreatePolicyAsync(ctx context.Context, policySyncEntity itypes.PluginPolicySync) error {
-	s.logger.WithFields(logrus.Fields{
-		"policy_id": policySyncEntity.PolicyID,
-		"sync_id":   policySyncEntity.ID,
-	}).Info("Starting policy creation sync")
-	policy, err := s.storage.GetPluginPolicy(ctx, policySyncEntity.PolicyID)
-	if err != nil {
-		return fmt.Errorf("failed to get policy for sync: %w", err)
-	}
-
-	policyBytes, err := json.Marshal(policy)
-	if err != nil {
-		return fmt.Errorf("fail to marshal policy: %v", err)
-	}
-	serverInfo, err := s.getServerInfo(ctx, policySyncEntity.PluginID)
-	if err != nil {
-		policySyncEntity.Status = itypes.Failed
-		policySyncEntity.FailReason = fmt.Sprintf("failed to get server address: %s", err)
-		// Update status in database before returning error
-		if updateErr := s.updatePolicySyncStatus(ctx, policySyncEntity); updateErr != nil {
-			s.logger.Errorf("failed to update policy sync status: %v", updateErr)
-		}
-		return fmt.Errorf("failed to get server address: %w", err)
-	}
+	req.Header.Set(deliveryIDHeader, sync.ID.String())
+	s.authorize(req, sync.PluginID, sync.Payload)
 
-	url := serverInfo.Addr + policyEndpoint
-	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(policyBytes))
+	// failed deliveries are retried by the outbox with a backoff
+	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+serverInfo.ApiKey)
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		policySyncEntity.Status = itypes.Failed
-		policySyncEntity.FailReason = fmt.Sprintf("failed to sync policy with plugin server(%s): %s", url, err.Error())
-		// Update status in database before returning error
-		if updateErr := s.updatePolicySyncStatus(ctx, policySyncEntity); updateErr != nil {
-			s.logger.Errorf("failed to update policy sync status: %v", updateErr)
-		}
-		return fmt.Errorf("fail to sync policy with verifier server: %w", err)
+		return fmt.Errorf("failed to sync policy %s with plugin server(%s): %w", sync.SyncType, url, err)
 	}
 	defer s.closer(resp.Body)
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		policySyncEntity.Status = itypes.Failed
-		policySyncEntity.FailReason = fmt.Sprintf("failed to sync policy with plugin server(%s): %s", url, err.Error())
-		// Update status in database before returning error
-		if updateErr := s.updatePolicySyncStatus(ctx, policySyncEntity); updateErr != nil {
-			s.logger.Errorf("failed to update policy sync status: %v", updateErr)
-		}
 		return fmt.Errorf("failed to read response body: %w", err)
 	}
-
 	if resp.StatusCode != http.StatusOK {
-		s.logger.WithFields(logrus.Fields{
-			"status_code": resp.StatusCode,
-			"body":        string(body),
-			"policy_id":   policy.ID,
-		}).Error("Failed to sync create policy")
-		policySyncEntity.Status = itypes.Failed
-		policySyncEntity.FailReason = fmt.Sprintf("failed to sync policy with plugin server(%s): status: %d, body: %s", url, resp.StatusCode, string(body))
-
-		// Update status in database before returning error
-		if updateErr := s.updatePolicySyncStatus(ctx, policySyncEntity); updateErr != nil {
-			s.logger.Errorf("failed to update policy sync status: %v", updateErr)
+		err := fmt.Errorf("failed to sync policy %s with plugin server(%s): status: %d, body: %s", sync.SyncType, url, resp.StatusCode, string(body))
+		if isPolicyRejection(resp.StatusCode) {
+			return fmt.Errorf("%w: %w", ErrPolicyRejected, err)
 		}
-
-		return fmt.Errorf("fail to sync policy with verifier server: status: %d", resp.StatusCode)
-	}
-
-	// Success case
-	policySyncEntity.Status = itypes.Synced
-	policySyncEntity.FailReason = ""
-
-	// Update status in database
-	if err := s.updatePolicySyncStatus(ctx, policySyncEntity); err != nil {
-		s.logger.Errorf("failed to update policy sync status: %v", err)
+		return err
 	}
-
-	s.logger.WithFields(logrus.Fields{
-		"policy_id": policy.ID,
-	}).Info("sync successfully")
-
 	return nil
 }
-func (s *Syncer) updatePolicySyncStatus(ctx context.Context, policySyncEntity itypes.PluginPolicySync) error {
-	// update plugin policy sync status
-	tx, err := s.storage.Pool().Begin(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
-	}
 
-	defer func() {
-		err := tx.Rollback(context.Background())
-		if err != nil {
-			s.logger.WithError(err).Error("failed to rollback transaction")
-		}
-	}()
-
-	err = s.storage.UpdatePluginPolicySync(ctx, tx, policySyncEntity)
-	if err != nil {
-		return fmt.Errorf("failed to update policy sync status: %w", err)
+// isPolicyRejection reports whether the plugin server refused the change itself. Authentication, missing routes,
+// timeouts and throttling are 4xx too, but they depend on the server state and are worth retrying.
+func isPolicyRejection(status int) bool {
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusRequestTimeout,
+		http.StatusTooEarly, http.StatusTooManyRequests:
+		return false
 	}
-
-	err = tx.Commit(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
-	}
-
-	return nil
+	return status >= http.StatusBadRequest && status < http.StatusInternalServerError
 }
 
 func (s *Syncer) closer(c io.Closer) {
@@ -290,143 +229,3 @@ func (s *Syncer) closer(c io.Closer) {
 type DeleteRequestBody struct {
 	Signature string `json:"signature"`
 }
-
-// Deletes a policy in a synchronous manner.
-func (s *Syncer) DeletePolicySync(ctx context.Context, pluginPolicy types.PluginPolicy) error {
-	reqBody := DeleteRequestBody{
-		Signature: pluginPolicy.Signature,
-	}
-	reqBodyBytes, err := json.Marshal(reqBody)
-	if err != nil {
-		return fmt.Errorf("failed to marshal request body: %v", err)
-	}
-
-	serverInfo, err := s.getServerInfo(ctx, pluginPolicy.PluginID)
-	if err != nil {
-		return fmt.Errorf("failed to get server address: %w", err)
-	}
-
-	url := serverInfo.Addr + policyEndpoint + "/" + pluginPolicy.ID.String()
-	req, err := http.NewRequest(http.MethodDelete, url, bytes.NewBuffer(reqBodyBytes))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+serverInfo.ApiKey)
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to delete policy with plugin server(%s): %s", url, err.Error())
-	}
-	defer s.closer(resp.Body)
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read response body: %w", err)
-	}
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to delete policy with plugin server(%s): status: %d, body: %s", url, resp.StatusCode, string(body))
-	}
-	return nil
-}
-
-// Deprecated: use DeletePolicySync instead.
-func (s *Syncer) DeletePolicyAsync(ctx context.Context, syncEntity itypes.PluginPolicySync) error {
-	s.logger.WithFields(logrus.Fields{
-		"sync_id":   syncEntity.ID,
-		"policy_id": syncEntity.PolicyID,
-	}).Info("Starting policy delete sync")
-
-	reqBody := DeleteRequestBody{
-		Signature: syncEntity.Signature,
-	}
-	reqBodyBytes, err := json.Marshal(reqBody)
-	if err != nil {
-		syncEntity.Status = itypes.Failed
-		syncEntity.FailReason = fmt.Sprintf("failed to marshal request body: %v", err)
-		// Update status in database before returning error
-		if updateErr := s.updatePolicySyncStatus(ctx, syncEntity); updateErr != nil {
-			s.logger.Errorf("failed to update policy sync status: %v", updateErr)
-		}
-		return fmt.Errorf("fail to marshal request body: %v", err)
-	}
-
-	serverInfo, err := s.getServerInfo(ctx, syncEntity.PluginID)
-	if err != nil {
-		syncEntity.Status = itypes.Failed
-		syncEntity.FailReason = fmt.Sprintf("failed to get server address: %s", err)
-		// Update status in database before returning error
-		if updateErr := s.updatePolicySyncStatus(ctx, syncEntity); updateErr != nil {
-			s.logger.Errorf("failed to update policy sync status: %v", updateErr)
-		}
-		return fmt.Errorf("failed to get server address: %w", err)
-	}
-
-	url := serverInfo.Addr + policyEndpoint + "/" + syncEntity.PolicyID.String()
-	req, err := retryablehttp.NewRequest(http.MethodDelete, url, bytes.NewBuffer(reqBodyBytes))
-	if err != nil {
-		syncEntity.Status = itypes.Failed
-		syncEntity.FailReason = fmt.Sprintf("failed to create request: %s", err)
-		// Update status in database before returning error
-		if updateErr := s.updatePolicySyncStatus(ctx, syncEntity); updateErr != nil {
-			s.logger.Errorf("failed to update policy sync status: %v", updateErr)
-		}
-		return fmt.Errorf("fail to create request, err: %w", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+serverInfo.ApiKey)
-
-	resp, err := s.client.Do(req)
-	if err != nil {
-		syncEntity.Status = itypes.Failed
-		syncEntity.FailReason = fmt.Sprintf("failed to delete policy with plugin server(%s): %s", url, err.Error())
-		// Update status in database before returning error
-		if updateErr := s.updatePolicySyncStatus(ctx, syncEntity); updateErr != nil {
-			s.logger.Errorf("failed to update policy sync status: %v", updateErr)
-		}
-		return fmt.Errorf("fail to delete policy on verifier server, err: %w", err)
-	}
-	defer s.closer(resp.Body)
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		syncEntity.Status = itypes.Failed
-		syncEntity.FailReason = fmt.Sprintf("failed to read response body: %s", err)
-		// Update status in database before returning error
-		if updateErr := s.updatePolicySyncStatus(ctx, syncEntity); updateErr != nil {
-			s.logger.Errorf("failed to update policy sync status: %v", updateErr)
-		}
-		return fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		s.logger.WithFields(logrus.Fields{
-			"status_code": resp.StatusCode,
-			"body":        string(body),
-			"policy_id":   syncEntity.PolicyID,
-		}).Error("Failed to sync delete policy")
-		syncEntity.Status = itypes.Failed
-		syncEntity.FailReason = fmt.Sprintf("failed to sync delete policy with plugin server(%s): status: %d, body: %s", url, resp.StatusCode, string(body))
-
-		// Update status in database before returning error
-		if updateErr := s.updatePolicySyncStatus(ctx, syncEntity); updateErr != nil {
-			s.logger.Errorf("failed to update policy sync status: %v", updateErr)
-		}
-
-		return fmt.Errorf("fail to delete policy on verifier server, status: %d", resp.StatusCode)
-	}
-
-	// Success case
-	syncEntity.Status = itypes.Synced
-	syncEntity.FailReason = ""
-
-	// Update status in database
-	if err := s.updatePolicySyncStatus(ctx, syncEntity); err != nil {
-		s.logger.Errorf("failed to update policy sync status: %v", err)
-	}
-
-	s.logger.WithFields(logrus.Fields{
-		"policy_id": syncEntity.PolicyID,
-	}).Info("Successfully sync deleted policy")
-
-	return nil
-}
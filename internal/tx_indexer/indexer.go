@@ -2,42 +2,96 @@ package tx_indexer
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"sync/atomic"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
 
+	"github.com/vultisig/verifier/internal/pricing"
 	vstorage "github.com/vultisig/verifier/internal/storage"
+	itypes "github.com/vultisig/verifier/internal/types"
 	"github.com/vultisig/verifier/plugin/tx_indexer"
+	"github.com/vultisig/verifier/plugin/tx_indexer/pkg/conv"
 	"github.com/vultisig/verifier/plugin/tx_indexer/pkg/graceful"
 	"github.com/vultisig/verifier/plugin/tx_indexer/pkg/rpc"
 	"github.com/vultisig/verifier/plugin/tx_indexer/pkg/storage"
 	"github.com/vultisig/verifier/types"
+	"github.com/vultisig/vultisig-go/common"
+)
+
+const (
+	defaultRecheckWindow = 24 * time.Hour
+	recheckInterval      = 5 * time.Minute
+	// reorgMisses is how many rechecks in a row must miss a billed tx on chain before it is refunded as reorged,
+	// so that a node lagging behind or a short reorg doesn't flip the fee between refund and rebill
+	reorgMisses = 3
+
+	// a successful tx whose fee can't be priced keeps its status and is billed again with a backoff
+	billingRetryInterval   = time.Minute
+	billingRetryMinBackoff = 5 * time.Minute
+	billingRetryMaxBackoff = 6 * time.Hour
+	billingRetryBatch      = 100
 )
 
 type FeeIndexer struct {
-	logger *logrus.Logger
-	db     vstorage.DatabaseStorage
-	worker *tx_indexer.Worker
+	logger        *logrus.Logger
+	db            vstorage.DatabaseStorage
+	worker        *tx_indexer.Worker
+	prices        pricing.PriceSource
+	recheckWindow time.Duration
+	// misses counts the rechecks in a row a billed tx was missing on chain, by fee ID.
+	// The tx keeps its stored status meanwhile, so that it isn't billed again.
+	misses map[uint64]int
 }
 
 func NewFeeIndexer(logger *logrus.Logger, db vstorage.DatabaseStorage, worker *tx_indexer.Worker) *FeeIndexer {
+	prices, err := pricing.NewStaticPriceSource(pricing.DefaultStaticPrices())
+	if err != nil {
+		// the default prices are constants
+		panic(fmt.Errorf("pricing.NewStaticPriceSource: %w", err))
+	}
 	return &FeeIndexer{
 		logger: logger.WithField("pkg", "tx_indexer.worker").Logger,
 		worker: worker,
 		db:     db,
+		prices: prices,
+
+		recheckWindow: defaultRecheckWindow,
+		misses:        make(map[uint64]int),
 	}
 }
 
+// SetRecheckWindow sets how long billed txs are checked again on chain, to refund the ones
+// which failed or were reorged after they were billed. Defaults to 24h, 0 disables rechecks.
+func (fi *FeeIndexer) SetRecheckWindow(window time.Duration) {
+	fi.recheckWindow = window
+}
+
+// SetPriceSource sets the source pricing tx volumes for percentage fees, defaults to the fee assets at 1 USD
+func (fi *FeeIndexer) SetPriceSource(prices pricing.PriceSource) {
+	fi.prices = prices
+}
+
 func (fi *FeeIndexer) start(aliveCtx context.Context) error {
 	err := fi.updatePendingTxs()
 	if err != nil {
 		return fmt.Errorf("w.updatePendingTxs: %w", err)
 	}
 
+	// the rechecks and billing retries run apart so that they don't hold back the pending txs
+	if fi.recheckWindow > 0 {
+		go fi.recheckLoop(aliveCtx)
+	}
+	go fi.billingRetryLoop(aliveCtx)
+
 	for {
 		select {
 		case <-aliveCtx.Done():
@@ -74,58 +128,180 @@ func (fi *FeeIndexer) updateTxStatus(ctx context.Context, tx storage.Tx) error {
 	}
 
 	if tx.PluginID != types.PluginVultisigFees_feee && newStatus != nil && *newStatus == rpc.TxOnChainSuccess {
+		err = fi.billTx(ctx, tx)
+		if isUniqueViolation(err) {
+			fi.logger.WithFields(tx.Fields()).Warn("tx already billed")
+			err = nil
+		}
+		if err != nil {
+			// the tx stays successful, so that the lost tx sweep never fails it, only its billing is retried
+			er := fi.markUnbilled(ctx, tx.ID, 1, err)
+			if er != nil {
+				return fmt.Errorf("failed to bill tx: %w", errors.Join(err, er))
+			}
+			fi.logger.WithFields(tx.Fields()).WithError(err).Warn("failed to bill tx, will retry")
+		}
+	}
+	if tx.PluginID == types.PluginVultisigFees_feee {
+		if tx.TxHash == nil {
+			return fmt.Errorf("nil tx hash")
+		}
 		err = fi.db.WithTransaction(ctx, func(ctx context.Context, dbTx pgx.Tx) error {
-			var err error
-
-			//Find plugin
-			pluginInfo, err := fi.db.FindPluginById(ctx, dbTx, tx.PluginID)
-			if err != nil {
+			// the fee plugin sends developer payouts too, the other txs collect fee batches
+			isPayout, err := fi.db.UpdatePayoutStatus(ctx, dbTx, tx.PolicyID, *tx.TxHash, newStatus)
+			if err != nil || isPayout {
 				return err
 			}
+			return fi.db.UpdateBatchStatus(ctx, dbTx, *tx.TxHash, newStatus)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update batch or payout status: %w", err)
+		}
+	}
+	return nil
+}
 
-			var txFee uint64
-			for _, pricing := range pluginInfo.Pricing {
-				if pricing.Type == types.PricingTypePerTx {
-					txFee = pricing.Amount
-					break
-				}
-			}
-			if txFee == 0 {
-				return nil
+// billTx inserts the tx execution fee of a successful tx and clears its unbilled marker.
+// A tx_indexer record carries a single tx execution fee, billing it twice is a unique violation.
+func (fi *FeeIndexer) billTx(ctx context.Context, tx storage.Tx) error {
+	return fi.db.WithTransaction(ctx, func(ctx context.Context, dbTx pgx.Tx) error {
+		err := fi.db.DeleteUnbilledTx(ctx, dbTx, tx.ID)
+		if err != nil {
+			return err
+		}
+
+		//Find plugin
+		pluginInfo, err := fi.db.FindPluginById(ctx, dbTx, tx.PluginID)
+		if err != nil {
+			return err
+		}
+
+		var txPricing *types.Pricing
+		for _, p := range pluginInfo.Pricing {
+			if p.Type == types.PricingTypePerTx {
+				txPricing = &p
+				break
 			}
+		}
+		if txPricing == nil {
+			return nil
+		}
 
-			//Insert fee
-			_, err = fi.db.InsertFee(ctx, dbTx, &types.Fee{
-				PolicyID:       tx.PolicyID,
-				PluginID:       string(tx.PluginID),
-				PublicKey:      tx.FromPublicKey,
-				TxType:         types.TxTypeDebit,
-				Amount:         txFee,
-				FeeType:        types.FeeTxExecFee,
-				UnderlyingType: "tx_indexer_record",
-				UnderlyingID:   tx.ID.String(),
-			})
+		calc, err := pricing.TxFee(
+			ctx,
+			fi.prices,
+			*txPricing,
+			common.Chain(tx.ChainID),
+			tx.TokenID,
+			conv.FromPtr(tx.Amount),
+		)
+		if err != nil {
+			return fmt.Errorf("pricing.TxFee: %w", err)
+		}
+		if calc.Amount == 0 {
+			return nil
+		}
+
+		var metadata []byte
+		if calc.Metric == types.PricingMetricPercentage {
+			metadata, err = json.Marshal(calc)
 			if err != nil {
-				return err
+				return fmt.Errorf("json.Marshal: %w", err)
 			}
+		}
 
-			return nil
+		//Insert fee
+		_, err = fi.db.InsertFee(ctx, dbTx, &types.Fee{
+			PolicyID:       tx.PolicyID,
+			PluginID:       string(tx.PluginID),
+			PublicKey:      tx.FromPublicKey,
+			TxType:         types.TxTypeDebit,
+			Amount:         calc.Amount,
+			Asset:          calc.Asset,
+			FeeType:        types.FeeTxExecFee,
+			Metadata:       metadata,
+			UnderlyingType: "tx_indexer_record",
+			UnderlyingID:   tx.ID.String(),
 		})
-		if err != nil {
-			return fmt.Errorf("failed to insert fee: %w", err)
-		}
+		return err
+	})
+}
+
+// markUnbilled schedules the next billing attempt of a successful tx, backing off exponentially
+func (fi *FeeIndexer) markUnbilled(ctx context.Context, txID uuid.UUID, attempts int, cause error) error {
+	return fi.db.UpsertUnbilledTx(ctx, itypes.UnbilledTx{
+		TxID:          txID,
+		Attempts:      attempts,
+		LastError:     cause.Error(),
+		NextAttemptAt: time.Now().Add(billingRetryBackoff(attempts)),
+	})
+}
+
+func billingRetryBackoff(attempts int) time.Duration {
+	backoff := billingRetryMinBackoff
+	for i := 1; i < attempts && backoff < billingRetryMaxBackoff; i++ {
+		backoff *= 2
 	}
-	if tx.PluginID == types.PluginVultisigFees_feee {
-		if tx.TxHash == nil {
-			return fmt.Errorf("nil tx hash")
+	return min(backoff, billingRetryMaxBackoff)
+}
+
+func (fi *FeeIndexer) billingRetryLoop(aliveCtx context.Context) {
+	for {
+		select {
+		case <-aliveCtx.Done():
+			return
+		case <-time.After(billingRetryInterval):
+			ctx, cancel := context.WithTimeout(aliveCtx, fi.worker.IterationTimeout())
+			err := fi.retryUnbilledTxs(ctx)
+			cancel()
+			if err != nil {
+				fi.logger.Errorf("billing retry error, continue loop: %v", err)
+			}
 		}
-		err = fi.db.WithTransaction(ctx, func(ctx context.Context, dbTx pgx.Tx) error {
-			return fi.db.UpdateBatchStatus(ctx, dbTx, *tx.TxHash, newStatus)
+	}
+}
+
+// retryUnbilledTxs bills again the successful txs whose next attempt is due, the txs keep their on-chain status
+func (fi *FeeIndexer) retryUnbilledTxs(ctx context.Context) error {
+	due, err := fi.db.GetDueUnbilledTxs(ctx, time.Now(), billingRetryBatch)
+	if err != nil {
+		return fmt.Errorf("fi.db.GetDueUnbilledTxs: %w", err)
+	}
+
+	billed := &atomic.Uint64{}
+	eg := &errgroup.Group{}
+	eg.SetLimit(fi.worker.Concurrency())
+	for _, unbilled := range due {
+		eg.Go(func() error {
+			logger := fi.logger.WithField("tx_id", unbilled.TxID).WithField("attempts", unbilled.Attempts)
+			tx, err := fi.worker.TxIndexerRepo().GetTxByID(ctx, unbilled.TxID)
+			if err == nil {
+				err = fi.billTx(ctx, tx)
+			}
+			if isUniqueViolation(err) {
+				// billed meanwhile, only the marker is left
+				err = fi.db.WithTransaction(ctx, func(ctx context.Context, dbTx pgx.Tx) error {
+					return fi.db.DeleteUnbilledTx(ctx, dbTx, unbilled.TxID)
+				})
+			}
+			if err != nil {
+				er := fi.markUnbilled(ctx, unbilled.TxID, unbilled.Attempts+1, err)
+				if er != nil {
+					logger.WithError(er).Error("failed to schedule billing retry")
+				}
+				logger.WithError(err).Warn("failed to bill tx, will retry")
+				return nil
+			}
+			billed.Add(1)
+			return nil
 		})
-		if err != nil {
-			return fmt.Errorf("failed to update batch status: %w", err)
-		}
 	}
+	_ = eg.Wait()
+
+	fi.logger.WithFields(logrus.Fields{
+		"due":    len(due),
+		"billed": billed.Load(),
+	}).Info("unbilled txs retried")
 	return nil
 }
 
@@ -171,5 +347,117 @@ func (fi *FeeIndexer) updatePendingTxs() error {
 	}
 
 	fi.logger.WithField("tx_count", count.Load()).Info("tx statuses updated")
+
 	return nil
 }
+
+func (fi *FeeIndexer) recheckLoop(aliveCtx context.Context) {
+	for {
+		select {
+		case <-aliveCtx.Done():
+			return
+		case <-time.After(recheckInterval):
+			ctx, cancel := context.WithTimeout(aliveCtx, fi.worker.IterationTimeout())
+			err := fi.recheckBilledTxs(ctx)
+			cancel()
+			if err != nil {
+				fi.logger.Errorf("recheck error, continue loop: %v", err)
+			}
+		}
+	}
+}
+
+// recheckBilledTxs refunds the tx execution fees of txs which are no longer successful on chain.
+// The txs are checked with the concurrency of the worker, a tx which can't be checked is retried on the next run.
+func (fi *FeeIndexer) recheckBilledTxs(ctx context.Context) error {
+	billed, err := fi.db.GetUnrefundedTxFees(ctx, time.Now().Add(-fi.recheckWindow))
+	if err != nil {
+		return fmt.Errorf("fi.db.GetUnrefundedTxFees: %w", err)
+	}
+
+	txs := make([]storage.Tx, len(billed))
+	statuses := make([]rpc.TxOnChainStatus, len(billed))
+	eg := &errgroup.Group{}
+	eg.SetLimit(fi.worker.Concurrency())
+	for i, fee := range billed {
+		eg.Go(func() error {
+			tx, err := fi.worker.TxIndexerRepo().GetTxByID(ctx, fee.TxIndexerID)
+			if err != nil {
+				fi.logger.WithField("fee_id", fee.FeeID).WithError(err).Warn("failed to get billed tx")
+				return nil
+			}
+			status, err := fi.worker.RecheckTxStatus(ctx, tx)
+			if err != nil {
+				fi.logger.WithFields(tx.Fields()).WithError(err).Warn("failed to recheck billed tx")
+				return nil
+			}
+			txs[i] = tx
+			statuses[i] = status
+			return nil
+		})
+	}
+	_ = eg.Wait()
+
+	misses := make(map[uint64]int, len(fi.misses))
+	refunded := 0
+	for i, fee := range billed {
+		tx := txs[i]
+		var reason types.RefundReason
+		switch statuses[i] {
+		case rpc.TxOnChainFail:
+			reason = types.RefundReasonTxFailed
+		case rpc.TxOnChainPending:
+			// the tx is missing on chain, it is refunded once the misses show it was reorged
+			misses[fee.FeeID] = fi.misses[fee.FeeID] + 1
+			if misses[fee.FeeID] < reorgMisses {
+				continue
+			}
+			reason = types.RefundReasonTxReorged
+		case "":
+			// not checked, keep the misses until the next run
+			if n, ok := fi.misses[fee.FeeID]; ok {
+				misses[fee.FeeID] = n
+			}
+			continue
+		default:
+			continue
+		}
+
+		err = fi.db.WithTransaction(ctx, func(ctx context.Context, dbTx pgx.Tx) error {
+			_, err := fi.db.RefundFee(ctx, dbTx, types.Refund{
+				DebitFeeID: fee.FeeID,
+				Reason:     reason,
+				TxHash:     conv.FromPtr(tx.TxHash),
+				Network:    common.Chain(tx.ChainID).String(),
+			})
+			return err
+		})
+		if err != nil {
+			fi.logger.WithFields(tx.Fields()).WithField("fee_id", fee.FeeID).WithError(err).Error("failed to refund fee")
+			continue
+		}
+		if reason == types.RefundReasonTxReorged {
+			// the refunded tx is final, it is never billed again even if it gets included again
+			msg := "dropped from chain after a reorg"
+			err = fi.worker.TxIndexerRepo().SetOnChainStatus(ctx, tx.ID, rpc.TxOnChainFail, &msg)
+			if err != nil {
+				fi.logger.WithFields(tx.Fields()).WithError(err).Error("failed to mark reorged tx as failed")
+			}
+		}
+		delete(misses, fee.FeeID)
+		fi.logger.WithFields(tx.Fields()).WithField("fee_id", fee.FeeID).Infof("billed tx refunded: %s", reason)
+		refunded++
+	}
+	fi.misses = misses
+
+	fi.logger.WithFields(logrus.Fields{
+		"checked":  len(billed),
+		"refunded": refunded,
+	}).Info("billed txs rechecked")
+	return nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation
+}
@@ -0,0 +1,16 @@
+package tx_indexer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBillingRetryBackoff(t *testing.T) {
+	require.Equal(t, 5*time.Minute, billingRetryBackoff(1))
+	require.Equal(t, 10*time.Minute, billingRetryBackoff(2))
+	require.Equal(t, 80*time.Minute, billingRetryBackoff(5))
+	require.Equal(t, billingRetryMaxBackoff, billingRetryBackoff(8))
+	require.Equal(t, billingRetryMaxBackoff, billingRetryBackoff(1000))
+}
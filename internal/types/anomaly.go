@@ -0,0 +1,24 @@
+package types
+
+import (
+	"github.com/vultisig/verifier/types"
+)
+
+// SigningActivity counts the sign requests of a plugin over a period
+type SigningActivity struct {
+	// Requests counts the accepted and rejected sign requests
+	Requests   int
+	Rejections int
+	// Resolved counts the accepted txs that succeeded or failed on chain
+	Resolved int
+	Failed   int
+}
+
+// SigningStats compares the recent signing activity of a plugin to its baseline
+type SigningStats struct {
+	PluginID types.PluginID
+	Window   SigningActivity
+	Baseline SigningActivity
+	// NewRecipients counts the accepted txs of the window sent to recipients unseen in the baseline
+	NewRecipients int
+}
@@ -1,15 +1,25 @@
 package types
 
 import (
+	"slices"
 	"time"
 
 	"github.com/vultisig/verifier/types"
 )
 
 type APIKey struct {
-	ID        string         `json:"id"`
-	ApiKey    string         `json:"apiKey"`
-	PluginID  types.PluginID `json:"pluginId"`
-	Status    int64          `json:"status"`
-	ExpiresAt *time.Time     `json:"expires_at"`
+	ID         string         `json:"id"`
+	Prefix     string         `json:"prefix"`
+	Salt       string         `json:"-"`
+	Hash       string         `json:"-"`
+	PluginID   types.PluginID `json:"pluginId"`
+	Status     int64          `json:"status"`
+	Scopes     []string       `json:"scopes"`
+	AllowedIPs []string       `json:"allowedIps"`
+	ExpiresAt  *time.Time     `json:"expires_at"`
+	LastUsedAt *time.Time     `json:"last_used_at"`
+}
+
+func (k APIKey) HasScope(scope string) bool {
+	return slices.Contains(k.Scopes, scope)
 }
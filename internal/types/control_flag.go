@@ -0,0 +1,33 @@
+package types
+
+import (
+	"time"
+
+	psafety "github.com/vultisig/verifier/plugin/safety"
+	"github.com/vultisig/verifier/types"
+)
+
+type ControlFlag struct {
+	Key       string    `json:"key"`
+	Enabled   bool      `json:"enabled"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ControlFlagChange sets control flags and queues them for the plugin servers in SyncTo
+type ControlFlagChange struct {
+	Flags []psafety.ControlFlag
+	// Record is added to the pause history when any flag changes
+	Record *PauseHistoryRecord
+	SyncTo []types.PluginID
+}
+
+// ControlFlagOutboxEntry is a pending push of control flags to a plugin server
+type ControlFlagOutboxEntry struct {
+	ID            int64
+	PluginID      types.PluginID
+	Keys          []string
+	Attempts      int
+	LastError     *string
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+}
@@ -0,0 +1,44 @@
+package types
+
+import "time"
+
+// DunningStage is how far the dunning of an unpaid balance went
+type DunningStage string
+
+const (
+	DunningStageGrace     DunningStage = "grace"     // overdue, not reminded yet
+	DunningStageReminded  DunningStage = "reminded"  // reminders were sent
+	DunningStageSuspended DunningStage = "suspended" // paid plugin policies were deactivated
+)
+
+type DunningEventType string
+
+const (
+	DunningEventReminder    DunningEventType = "reminder"
+	DunningEventSuspended   DunningEventType = "suspended"
+	DunningEventReactivated DunningEventType = "reactivated"
+)
+
+// UnpaidAccount is a vault with an unpaid balance, Unpaid is summed over the fee assets
+type UnpaidAccount struct {
+	PublicKey string
+	Unpaid    uint64
+}
+
+// DunningState tracks a vault from the moment its balance is unpaid until it's paid back
+type DunningState struct {
+	PublicKey      string       `json:"public_key"`
+	Stage          DunningStage `json:"stage"`
+	OverdueSince   time.Time    `json:"overdue_since"`
+	LastReminderAt *time.Time   `json:"last_reminder_at,omitempty"`
+	SuspendedAt    *time.Time   `json:"suspended_at,omitempty"`
+}
+
+// DunningEvent is a reminder, suspension or reactivation, notifications are sent from them
+type DunningEvent struct {
+	ID           uint64           `json:"id"`
+	PublicKey    string           `json:"public_key"`
+	EventType    DunningEventType `json:"event_type"`
+	UnpaidAmount uint64           `json:"unpaid_amount"`
+	CreatedAt    time.Time        `json:"created_at"`
+}
@@ -2,8 +2,14 @@ package types
 
 import (
 	"errors"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/vultisig/recipes/types"
+
+	vtypes "github.com/vultisig/verifier/types"
 )
 
 var (
@@ -16,6 +22,33 @@ type CreditMetadata struct {
 	Network         string `json:"network"`          // Blockchain network (e.g., "ethereum", "polygon")
 }
 
+// BilledTxFee is a tx execution fee with the tx_indexer record it was billed for
+type BilledTxFee struct {
+	FeeID       uint64
+	TxIndexerID uuid.UUID
+}
+
+// UnbilledTx is a successful tx whose fee couldn't be priced yet, with the state of its pricing retries
+type UnbilledTx struct {
+	TxID          uuid.UUID
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+}
+
+// Subscription is the recurring billing of a policy with the periods the policy was active
+type Subscription struct {
+	PolicyID    uuid.UUID
+	PublicKey   string
+	PluginID    string
+	Amount      uint64
+	Asset       vtypes.PricingAsset
+	Frequency   vtypes.PricingFrequency
+	StartDate   time.Time
+	BilledUntil *time.Time // end of the last billed period, nil before the first one
+	Activations []vtypes.PolicyActivation
+}
+
 type FeeAsset struct {
 	Symbol   string `json:"symbol"`
 	Addr     string `json:"addr"`
@@ -23,19 +56,37 @@ type FeeAsset struct {
 	Network  string `json:"network"`
 }
 
-var DefaultFeeAsset = FeeAsset{
-	Symbol:   "USDC",
-	Decimals: 6,
-	Network:  "ethereum",
-	Addr:     "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48",
+// NewFeeAsset converts a registered fee asset to its API representation
+func NewFeeAsset(asset vtypes.Asset) FeeAsset {
+	return FeeAsset{
+		Symbol:   asset.Symbol,
+		Decimals: asset.Decimals,
+		Network:  strings.ToLower(asset.Chain.String()),
+		Addr:     asset.Token,
+	}
 }
 
+// FeeAssetOf returns the API representation of the asset, DefaultFeeAsset for unknown ones
+func FeeAssetOf(id vtypes.PricingAsset) FeeAsset {
+	asset, err := vtypes.GetFeeAsset(id)
+	if err != nil {
+		asset = vtypes.DefaultFeeAsset()
+	}
+	return NewFeeAsset(asset)
+}
+
+var DefaultFeeAsset = NewFeeAsset(vtypes.DefaultFeeAsset())
+
 // TODO: Temporary solution for testing purposes.
 // This will be replaced by integrating the fee policy into every relevant policy.
-var FeeDefaultPolicy = &types.Policy{
-	Rules: []*types.Rule{
-		{
-			Resource: "ethereum.send",
+var FeeDefaultPolicy = NewFeeDefaultPolicy(vtypes.FeeAssets())
+
+// NewFeeDefaultPolicy allows sending any amount of the given assets to the Vultisig treasury, one rule per asset
+func NewFeeDefaultPolicy(assets []vtypes.Asset) *types.Policy {
+	policy := &types.Policy{}
+	for _, asset := range assets {
+		policy.Rules = append(policy.Rules, &types.Rule{
+			Resource: strings.ToLower(asset.Chain.String()) + ".send",
 			Effect:   types.Effect_EFFECT_ALLOW,
 			ParameterConstraints: []*types.ParameterConstraint{
 				{
@@ -43,7 +94,7 @@ var FeeDefaultPolicy = &types.Policy{
 					Constraint: &types.Constraint{
 						Type: types.ConstraintType_CONSTRAINT_TYPE_FIXED,
 						Value: &types.Constraint_FixedValue{
-							FixedValue: "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48",
+							FixedValue: asset.Token,
 						},
 						Required: false,
 					},
@@ -73,6 +124,56 @@ var FeeDefaultPolicy = &types.Policy{
 					},
 				},
 			},
-		},
-	},
+		})
+	}
+	return policy
+}
+
+// NewPayoutPolicy allows sending exactly amount of the asset to the payout address
+func NewPayoutPolicy(asset vtypes.Asset, payoutAddress string, amount uint64) *types.Policy {
+	return &types.Policy{
+		Rules: []*types.Rule{{
+			Resource: strings.ToLower(asset.Chain.String()) + ".send",
+			Effect:   types.Effect_EFFECT_ALLOW,
+			ParameterConstraints: []*types.ParameterConstraint{
+				{
+					ParameterName: "asset",
+					Constraint: &types.Constraint{
+						Type: types.ConstraintType_CONSTRAINT_TYPE_FIXED,
+						Value: &types.Constraint_FixedValue{
+							FixedValue: asset.Token,
+						},
+						Required: false,
+					},
+				},
+				{
+					ParameterName: "from_address",
+					Constraint: &types.Constraint{
+						Type:     types.ConstraintType_CONSTRAINT_TYPE_ANY,
+						Required: true,
+					},
+				},
+				{
+					ParameterName: "amount",
+					Constraint: &types.Constraint{
+						Type: types.ConstraintType_CONSTRAINT_TYPE_FIXED,
+						Value: &types.Constraint_FixedValue{
+							FixedValue: strconv.FormatUint(amount, 10),
+						},
+						Required: true,
+					},
+				},
+				{
+					ParameterName: "to_address",
+					Constraint: &types.Constraint{
+						Type: types.ConstraintType_CONSTRAINT_TYPE_FIXED,
+						Value: &types.Constraint_FixedValue{
+							FixedValue: payoutAddress,
+						},
+						Required: true,
+					},
+				},
+			},
+		}},
+	}
 }
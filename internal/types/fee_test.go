@@ -0,0 +1,114 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	ecommon "github.com/ethereum/go-ethereum/common"
+	etypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+	"github.com/vultisig/recipes/engine"
+	"github.com/vultisig/recipes/sdk/evm/codegen/erc20"
+	"github.com/vultisig/vultisig-go/common"
+
+	vtypes "github.com/vultisig/verifier/types"
+)
+
+const treasury = "0x8E247a480449c84a5fDD25974A8501f3EFa4ABb9"
+
+func erc20TransferTx(t *testing.T, chainID int64, token, to string) []byte {
+	t.Helper()
+	return erc20TransferAmountTx(t, chainID, token, to, 1_000_000)
+}
+
+func erc20TransferAmountTx(t *testing.T, chainID int64, token, to string, amount int64) []byte {
+	t.Helper()
+	tokenAddr := ecommon.HexToAddress(token)
+	payload, err := rlp.EncodeToBytes(struct {
+		ChainID    *big.Int
+		Nonce      uint64
+		GasTipCap  *big.Int
+		GasFeeCap  *big.Int
+		Gas        uint64
+		To         *ecommon.Address `rlp:"nil"`
+		Value      *big.Int
+		Data       []byte
+		AccessList etypes.AccessList
+	}{
+		ChainID:   big.NewInt(chainID),
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(1),
+		Gas:       100_000,
+		To:        &tokenAddr,
+		Value:     big.NewInt(0),
+		Data:      erc20.NewErc20().PackTransfer(ecommon.HexToAddress(to), big.NewInt(amount)),
+	})
+	require.NoError(t, err)
+	return append([]byte{etypes.DynamicFeeTxType}, payload...)
+}
+
+func TestFeeDefaultPolicy(t *testing.T) {
+	require.Len(t, FeeDefaultPolicy.Rules, len(vtypes.FeeAssets()))
+
+	ngn, err := engine.NewEngine()
+	require.NoError(t, err)
+
+	usdt, err := vtypes.GetFeeAsset(vtypes.PricingAssetUSDT)
+	require.NoError(t, err)
+	rule, err := ngn.Evaluate(FeeDefaultPolicy, common.Ethereum, erc20TransferTx(t, 1, usdt.Token, treasury))
+	require.NoError(t, err)
+	// send rules of tokens are evaluated as transfers of the token contract
+	require.Equal(t, usdt.Token, rule.GetTarget().GetAddress())
+
+	usdcArb, err := vtypes.GetFeeAsset(vtypes.PricingAssetUSDCArbitrum)
+	require.NoError(t, err)
+	rule, err = ngn.Evaluate(FeeDefaultPolicy, common.Arbitrum, erc20TransferTx(t, 42161, usdcArb.Token, treasury))
+	require.NoError(t, err)
+	require.Equal(t, "arbitrum.erc20.transfer", rule.Resource)
+	require.Equal(t, usdcArb.Token, rule.GetTarget().GetAddress())
+
+	// the arbitrum token isn't billed on ethereum
+	_, err = ngn.Evaluate(FeeDefaultPolicy, common.Ethereum, erc20TransferTx(t, 1, usdcArb.Token, treasury))
+	require.Error(t, err)
+
+	usdc, err := vtypes.GetFeeAsset(vtypes.PricingAssetUSDC)
+	require.NoError(t, err)
+	_, err = ngn.Evaluate(FeeDefaultPolicy, common.Ethereum,
+		erc20TransferTx(t, 1, usdc.Token, "0x000000000000000000000000000000000000dEaD"))
+	require.Error(t, err)
+}
+
+func TestPayoutPolicy(t *testing.T) {
+	const payoutAddress = "0x1111111111111111111111111111111111111111"
+	ngn, err := engine.NewEngine()
+	require.NoError(t, err)
+
+	usdc, err := vtypes.GetFeeAsset(vtypes.PricingAssetUSDC)
+	require.NoError(t, err)
+	policy := NewPayoutPolicy(usdc, payoutAddress, 2_500_000)
+
+	_, err = ngn.Evaluate(policy, common.Ethereum, erc20TransferAmountTx(t, 1, usdc.Token, payoutAddress, 2_500_000))
+	require.NoError(t, err)
+
+	// another amount, recipient or token isn't paid out
+	_, err = ngn.Evaluate(policy, common.Ethereum, erc20TransferAmountTx(t, 1, usdc.Token, payoutAddress, 2_500_001))
+	require.Error(t, err)
+	_, err = ngn.Evaluate(policy, common.Ethereum, erc20TransferAmountTx(t, 1, usdc.Token, treasury, 2_500_000))
+	require.Error(t, err)
+	usdt, err := vtypes.GetFeeAsset(vtypes.PricingAssetUSDT)
+	require.NoError(t, err)
+	_, err = ngn.Evaluate(policy, common.Ethereum, erc20TransferAmountTx(t, 1, usdt.Token, payoutAddress, 2_500_000))
+	require.Error(t, err)
+}
+
+func TestFeeAssetOf(t *testing.T) {
+	require.Equal(t, FeeAsset{
+		Symbol:   "USDT",
+		Addr:     "0xdAC17F958D2ee523a2206206994597C13D831ec7",
+		Decimals: 6,
+		Network:  "ethereum",
+	}, FeeAssetOf(vtypes.PricingAssetUSDT))
+	require.Equal(t, "arbitrum", FeeAssetOf(vtypes.PricingAssetUSDCArbitrum).Network)
+	require.Equal(t, DefaultFeeAsset, FeeAssetOf("unknown"))
+}
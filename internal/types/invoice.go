@@ -0,0 +1,138 @@
+package types
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	vtypes "github.com/vultisig/verifier/types"
+)
+
+// Invoice is the monthly fee statement of a vault, it is generated once per period and never updated
+type Invoice struct {
+	ID          uuid.UUID `json:"id"`
+	PublicKey   string    `json:"public_key"`
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"` // exclusive
+	InvoiceData
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// InvoiceData is the content of an invoice, stored as a single JSON document
+type InvoiceData struct {
+	LineItems []InvoiceLineItem `json:"line_items"`
+	Credits   []InvoiceCredit   `json:"credits"`
+	Batches   []InvoiceBatch    `json:"batches"`
+	Totals    []InvoiceTotal    `json:"totals"`
+}
+
+// InvoiceLineItem aggregates the debits of a plugin by fee type and asset
+type InvoiceLineItem struct {
+	PluginID string              `json:"plugin_id"`
+	AppName  string              `json:"app_name"`
+	FeeType  string              `json:"fee_type"`
+	Asset    vtypes.PricingAsset `json:"asset"`
+	Count    uint64              `json:"count"`
+	Amount   uint64              `json:"amount"`
+}
+
+// InvoiceCredit is a credit issued to the vault, collections are listed as batches instead
+type InvoiceCredit struct {
+	FeeID     uint64              `json:"fee_id"`
+	FeeType   string              `json:"fee_type"`
+	Reason    string              `json:"reason"`
+	Asset     vtypes.PricingAsset `json:"asset"`
+	Amount    uint64              `json:"amount"`
+	CreatedAt time.Time           `json:"created_at"`
+}
+
+// InvoiceBatch is an on-chain fee collection
+type InvoiceBatch struct {
+	BatchID   int64               `json:"batch_id"`
+	Asset     vtypes.PricingAsset `json:"asset"`
+	Amount    uint64              `json:"amount"`
+	Status    string              `json:"status"`
+	TxHash    string              `json:"tx_hash"`
+	CreatedAt time.Time           `json:"created_at"`
+}
+
+// InvoiceTotal sums an invoice in a single asset
+type InvoiceTotal struct {
+	Asset     vtypes.PricingAsset `json:"asset"`
+	FeeAsset  FeeAsset            `json:"fee_asset"`
+	Charged   uint64              `json:"charged"`
+	Credited  uint64              `json:"credited"`
+	Collected uint64              `json:"collected"` // completed batches only
+}
+
+// IsEmpty reports whether nothing happened in the period
+func (d *InvoiceData) IsEmpty() bool {
+	return len(d.LineItems) == 0 && len(d.Credits) == 0 && len(d.Batches) == 0
+}
+
+// ComputeTotals sets Totals from the line items, credits and batches, ordered by asset
+func (d *InvoiceData) ComputeTotals() {
+	totals := make(map[vtypes.PricingAsset]*InvoiceTotal)
+	get := func(asset vtypes.PricingAsset) *InvoiceTotal {
+		t, ok := totals[asset]
+		if !ok {
+			t = &InvoiceTotal{Asset: asset, FeeAsset: FeeAssetOf(asset)}
+			totals[asset] = t
+		}
+		return t
+	}
+	for _, item := range d.LineItems {
+		get(item.Asset).Charged += item.Amount
+	}
+	for _, credit := range d.Credits {
+		get(credit.Asset).Credited += credit.Amount
+	}
+	for _, batch := range d.Batches {
+		t := get(batch.Asset)
+		if batch.Status == "COMPLETED" {
+			t.Collected += batch.Amount
+		}
+	}
+
+	d.Totals = make([]InvoiceTotal, 0, len(totals))
+	for _, t := range totals {
+		d.Totals = append(d.Totals, *t)
+	}
+	sort.Slice(d.Totals, func(i, j int) bool {
+		return d.Totals[i].Asset < d.Totals[j].Asset
+	})
+}
+
+// InvoicePeriod returns the calendar month (UTC) containing t
+func InvoicePeriod(t time.Time) (time.Time, time.Time) {
+	t = t.UTC()
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 1, 0)
+}
+
+type InvoiceSummary struct {
+	ID          uuid.UUID      `json:"id"`
+	PeriodStart time.Time      `json:"period_start"`
+	PeriodEnd   time.Time      `json:"period_end"`
+	Totals      []InvoiceTotal `json:"totals"`
+	CreatedAt   time.Time      `json:"created_at"`
+}
+
+type InvoicePaginatedList struct {
+	Invoices   []InvoiceSummary `json:"invoices"`
+	TotalCount uint32           `json:"total_count"`
+}
+
+func ToInvoiceSummaries(invoices []Invoice) []InvoiceSummary {
+	result := make([]InvoiceSummary, len(invoices))
+	for i, inv := range invoices {
+		result[i] = InvoiceSummary{
+			ID:          inv.ID,
+			PeriodStart: inv.PeriodStart,
+			PeriodEnd:   inv.PeriodEnd,
+			Totals:      inv.Totals,
+			CreatedAt:   inv.CreatedAt,
+		}
+	}
+	return result
+}
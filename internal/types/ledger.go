@@ -0,0 +1,83 @@
+package types
+
+import (
+	"time"
+
+	vtypes "github.com/vultisig/verifier/types"
+)
+
+// LedgerAccountType is the kind of owner of a ledger account
+type LedgerAccountType string
+
+const (
+	LedgerAccountUser      LedgerAccountType = "user"      // a vault, identified by its public key
+	LedgerAccountDeveloper LedgerAccountType = "developer" // the payout account of a plugin, identified by the plugin ID
+	LedgerAccountTreasury  LedgerAccountType = "treasury"
+)
+
+// Treasury accounts
+const (
+	TreasuryRevenue     = "revenue"     // fees of the verifier itself, credits granted by it
+	TreasuryCollections = "collections" // fees collected on chain
+	TreasuryPayouts     = "payouts"     // developer payouts sent on chain
+)
+
+// LedgerBalance is the balance of a ledger account in an asset, postings are positive on debit
+type LedgerBalance struct {
+	AccountType LedgerAccountType   `json:"account_type"`
+	AccountID   string              `json:"account_id"`
+	Asset       vtypes.PricingAsset `json:"asset"`
+	Balance     int64               `json:"balance"`
+}
+
+// BatchCollection is a fee batch with its collection as recorded by the ledger and the tx indexer
+type BatchCollection struct {
+	BatchID    uint64
+	Asset      vtypes.PricingAsset
+	TotalValue uint64
+	Status     string
+	TxHash     string
+	CreatedAt  time.Time
+	// LedgerCollected is the sum of the postings of the batch to the collections account
+	LedgerCollected int64
+	// OnChainStatus and OnChainAmount are nil when the tx indexer doesn't know the collection tx
+	OnChainStatus *string
+	OnChainAmount *string
+}
+
+type DiscrepancyKind string
+
+const (
+	DiscrepancyMissingOnChainTx DiscrepancyKind = "missing_onchain_tx"
+	DiscrepancyStatusMismatch   DiscrepancyKind = "status_mismatch"
+	DiscrepancyAmountMismatch   DiscrepancyKind = "amount_mismatch"
+	DiscrepancyTotalMismatch    DiscrepancyKind = "total_mismatch"
+	DiscrepancyUnbalancedEntry  DiscrepancyKind = "unbalanced_entry"
+	DiscrepancyUnpostedFee      DiscrepancyKind = "unposted_fee"
+)
+
+type ReconciliationDiscrepancy struct {
+	Kind        DiscrepancyKind     `json:"kind"`
+	Asset       vtypes.PricingAsset `json:"asset,omitempty"`
+	BatchID     uint64              `json:"batch_id,omitempty"`
+	TxHash      string              `json:"tx_hash,omitempty"`
+	ReferenceID uint64              `json:"reference_id,omitempty"` // ledger entry or fee ID
+	Expected    string              `json:"expected,omitempty"`
+	Actual      string              `json:"actual,omitempty"`
+}
+
+// ReconciliationTotal compares, in an asset, the collections account with the batches collected on chain.
+// InFlight is the ledger amount of the batches whose collection tx isn't final yet.
+type ReconciliationTotal struct {
+	Asset            vtypes.PricingAsset `json:"asset"`
+	LedgerCollected  int64               `json:"ledger_collected"`
+	InFlight         int64               `json:"in_flight"`
+	OnChainCollected int64               `json:"onchain_collected"`
+}
+
+type ReconciliationReport struct {
+	ID            uint64                      `json:"id"`
+	CreatedAt     time.Time                   `json:"created_at"`
+	Totals        []ReconciliationTotal       `json:"totals"`
+	Discrepancies []ReconciliationDiscrepancy `json:"discrepancies"`
+}
@@ -0,0 +1,47 @@
+package types
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	vtypes "github.com/vultisig/verifier/types"
+)
+
+type PayoutStatus string
+
+const (
+	PayoutPending   PayoutStatus = "PENDING"
+	PayoutSigning   PayoutStatus = "SIGNING" // a keysign was requested, the tx indexer tracks the payout txs
+	PayoutCompleted PayoutStatus = "COMPLETED"
+	PayoutFailed    PayoutStatus = "FAILED"
+)
+
+// PayableFee is a collected fee of a plugin that wasn't paid out yet, refunds have a negative amount
+type PayableFee struct {
+	FeeID         uint64
+	PluginID      vtypes.PluginID
+	PayoutAddress string // empty when the plugin has none
+	Asset         vtypes.PricingAsset
+	Amount        int64
+}
+
+// DeveloperPayout sends a plugin developer the fees collected for the plugin in an asset until PeriodEnd,
+// minus the platform share
+type DeveloperPayout struct {
+	ID            uint64              `json:"id"`
+	PluginID      vtypes.PluginID     `json:"plugin_id"`
+	PayoutAddress string              `json:"payout_address"`
+	Asset         vtypes.PricingAsset `json:"asset"`
+	PeriodEnd     time.Time           `json:"period_end"`
+	GrossAmount   uint64              `json:"gross_amount"`
+	PlatformShare uint64              `json:"platform_share"`
+	Amount        uint64              `json:"amount"`
+	Status        PayoutStatus        `json:"status"`
+	// KeysignPolicyID is the policy ID of the payout txs in the tx indexer
+	KeysignPolicyID uuid.UUID `json:"keysign_policy_id"`
+	TxHash          *string   `json:"tx_hash,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+	FeeIDs          []uint64  `json:"-"`
+}
@@ -28,6 +28,8 @@ type Plugin struct {
 	AvgRating      float64         `json:"avg_rating"`
 	Installations  int             `json:"installations"`
 	PayoutAddress  string          `json:"payout_address,omitempty"`
+	// Availability is the last health probe of the plugin server, nil until it was probed
+	Availability *PluginAvailability `json:"availability,omitempty"`
 }
 
 type FAQItem struct {
@@ -0,0 +1,34 @@
+package types
+
+import (
+	"time"
+
+	"github.com/vultisig/verifier/types"
+)
+
+// PluginHealth is the result of a probe of the /healthz endpoint of a plugin server
+type PluginHealth struct {
+	PluginID            types.PluginID
+	Available           bool
+	StatusCode          *int
+	LatencyMs           int
+	LastError           *string
+	ConsecutiveFailures int
+	CheckedAt           time.Time
+	LastAvailableAt     *time.Time
+}
+
+// PluginAvailability is the public view of the health of a plugin server
+type PluginAvailability struct {
+	Available       bool       `json:"available"`
+	CheckedAt       time.Time  `json:"checked_at"`
+	LastAvailableAt *time.Time `json:"last_available_at,omitempty"`
+}
+
+func (h PluginHealth) Availability() *PluginAvailability {
+	return &PluginAvailability{
+		Available:       h.Available,
+		CheckedAt:       h.CheckedAt,
+		LastAvailableAt: h.LastAvailableAt,
+	}
+}
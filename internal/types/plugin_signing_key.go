@@ -0,0 +1,25 @@
+package types
+
+import (
+	"time"
+
+	"github.com/vultisig/verifier/types"
+)
+
+// PluginSigningKey is an ed25519 key a plugin server signs its requests to the verifier with
+type PluginSigningKey struct {
+	KeyID     string
+	PluginID  types.PluginID
+	PublicKey string // hex encoded
+	CreatedAt time.Time
+	ExpiresAt *time.Time
+	RevokedAt *time.Time
+}
+
+// Active reports whether requests signed with the key are accepted at now
+func (k PluginSigningKey) Active(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || k.ExpiresAt.After(now)
+}
@@ -1,6 +1,9 @@
 package types
 
 import (
+	"encoding/json"
+	"time"
+
 	"github.com/google/uuid"
 	ptypes "github.com/vultisig/verifier/types"
 )
@@ -12,6 +15,8 @@ const (
 	NotSynced PolicySyncStatus = iota
 	Synced    PolicySyncStatus = iota + 1
 	Failed    PolicySyncStatus = iota + 2
+	// DeadLettered changes were rejected by the plugin server or ran out of attempts, they are not retried
+	DeadLettered PolicySyncStatus = iota + 2
 )
 
 const (
@@ -20,12 +25,48 @@ const (
 	RemovePolicy
 )
 
+func (s PolicySyncStatus) String() string {
+	switch s {
+	case NotSynced:
+		return "pending"
+	case Synced:
+		return "synced"
+	case Failed:
+		return "failed"
+	case DeadLettered:
+		return "dead_lettered"
+	default:
+		return "unknown"
+	}
+}
+
+func (t PolicySyncType) String() string {
+	switch t {
+	case AddPolicy:
+		return "add"
+	case UpdatePolicy:
+		return "update"
+	case RemovePolicy:
+		return "remove"
+	default:
+		return "unknown"
+	}
+}
+
+// PluginPolicySync is a policy change to deliver to the plugin server, the changes of a policy are
+// delivered in the order they were written. The ID is sent as the delivery ID so the plugin server
+// can ignore a change it already applied.
 type PluginPolicySync struct {
-	ID         uuid.UUID        `json:"id" validate:"required"`
-	PolicyID   uuid.UUID        `json:"policy_id" validate:"required"`
-	PluginID   ptypes.PluginID  `json:"plugin_id" validate:"required"`
-	Signature  string           `json:"signature" validate:"required"`
-	SyncType   PolicySyncType   `json:"sync_type" validate:"required"`
-	Status     PolicySyncStatus `json:"status" validate:"required"`
-	FailReason string           `json:"fail_reason"` // when synced is false, this field contains the reason for the failure
+	ID            uuid.UUID        `json:"id" validate:"required"`
+	PolicyID      uuid.UUID        `json:"policy_id" validate:"required"`
+	PluginID      ptypes.PluginID  `json:"plugin_id" validate:"required"`
+	Signature     string           `json:"signature" validate:"required"`
+	SyncType      PolicySyncType   `json:"sync_type" validate:"required"`
+	Status        PolicySyncStatus `json:"status" validate:"required"`
+	FailReason    string           `json:"fail_reason"` // when synced is false, this field contains the reason for the failure
+	Payload       json.RawMessage  `json:"-"`
+	Attempts      int              `json:"attempts"`
+	NextAttemptAt time.Time        `json:"next_attempt_at"`
+	SyncedAt      *time.Time       `json:"synced_at,omitempty"`
+	CreatedAt     time.Time        `json:"created_at"`
 }
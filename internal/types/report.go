@@ -13,6 +13,30 @@ var (
 	ErrReportCooldown = errors.New("report cooldown active")
 )
 
+type ReportStatus string
+
+const (
+	ReportPending ReportStatus = "PENDING"
+	ReportValid   ReportStatus = "VALID"
+	// ReportInvalid reports are dismissed and no longer count towards auto-pause
+	ReportInvalid ReportStatus = "INVALID"
+)
+
+func (s ReportStatus) IsValid() bool {
+	switch s {
+	case ReportPending, ReportValid, ReportInvalid:
+		return true
+	}
+	return false
+}
+
+const (
+	PauseActionAutoPaused    = "auto_paused"
+	PauseActionAnomalyPaused = "anomaly_paused"
+	PauseActionManualPaused  = "manual_paused"
+	PauseActionUnpaused      = "unpaused"
+)
+
 type PluginReport struct {
 	PluginID       types.PluginID `json:"plugin_id"`
 	ReporterPubKey string         `json:"reporter_public_key"`
@@ -21,6 +45,10 @@ type PluginReport struct {
 	CreatedAt      time.Time      `json:"created_at"`
 	LastReportedAt time.Time      `json:"last_reported_at"`
 	ReportCount    int            `json:"report_count"`
+	Status         ReportStatus   `json:"status"`
+	ReviewedBy     *string        `json:"reviewed_by,omitempty"`
+	ReviewedAt     *time.Time     `json:"reviewed_at,omitempty"`
+	ReviewNote     *string        `json:"review_note,omitempty"`
 }
 
 type PauseHistoryRecord struct {
@@ -100,7 +100,7 @@ func FromFeesWithStatus(fees []FeeWithStatus, titleMap map[string]string) []Plug
 			PublicKey:       fee.PublicKey,
 			TransactionType: fee.FeeType,
 			Amount:          strconv.FormatUint(fee.Amount, 10),
-			FeeAsset:        DefaultFeeAsset,
+			FeeAsset:        FeeAssetOf(fee.Asset),
 			Status:          fee.Status,
 			CreatedAt:       fee.CreatedAt.UTC(),
 		}
@@ -116,6 +116,7 @@ type FeeHistoryPaginatedList struct {
 // PluginBillingSummaryRow is the raw data from the database query
 type PluginBillingSummaryRow struct {
 	PluginID  string
+	Asset     vtypes.PricingAsset
 	StartDate time.Time
 	TotalFees uint64
 }
@@ -124,8 +125,11 @@ type PluginBillingSummaryRow struct {
 type PricingInfo struct {
 	Type      string  // once, recurring, per-tx
 	Amount    uint64  // amount in smallest unit
-	Asset     string  // usdc
+	Asset     string  // usdc, usdt, usdc-arbitrum
 	Frequency *string // daily, weekly, biweekly, monthly (nil for non-recurring)
+	Metric    string  // fixed, percentage (amount in basis points)
+	MinAmount *uint64 // percentage caps in smallest unit
+	MaxAmount *uint64
 }
 
 // PluginBillingSummary is the response DTO for plugin billing info
@@ -135,7 +139,15 @@ type PluginBillingSummary struct {
 	Pricing     string          `json:"pricing"` // Formatted: "0.50 USDC one-time + 0.01 USDC per transaction"
 	StartDate   time.Time       `json:"start_date"`
 	NextPayment *time.Time      `json:"next_payment"` // nil for non-recurring
-	TotalFees   string          `json:"total_fees"`
+	TotalFees   string          `json:"total_fees"`   // In the default asset (usdc), Totals has every asset
+	Totals      []AssetTotal    `json:"totals"`
+}
+
+// AssetTotal is the amount billed in a single asset, in its smallest unit
+type AssetTotal struct {
+	Asset    vtypes.PricingAsset `json:"asset"`
+	FeeAsset FeeAsset            `json:"fee_asset"`
+	Amount   string              `json:"amount"`
 }
 
 // PluginBillingSummaryList is the response for the billing summary endpoint
@@ -2,6 +2,13 @@ package types
 
 import "time"
 
+// VaultTokenDevice is the client a refresh token was issued to
+type VaultTokenDevice struct {
+	DeviceName string `json:"device_name"`
+	UserAgent  string `json:"user_agent"`
+	IPAddress  string `json:"ip_address"`
+}
+
 // VaultToken represents a token stored in the database
 type VaultToken struct {
 	ID         string     `json:"id"`
@@ -12,6 +19,10 @@ type VaultToken struct {
 	UpdatedAt  time.Time  `json:"updated_at"`
 	LastUsedAt time.Time  `json:"last_used_at"`
 	RevokedAt  *time.Time `json:"revoked_at"`
+	// FamilyID is shared by the tokens rotated from the same login
+	FamilyID  string     `json:"family_id"`
+	RotatedAt *time.Time `json:"-"`
+	VaultTokenDevice
 }
 
 func (t *VaultToken) IsRevoked() bool {
@@ -20,9 +31,18 @@ func (t *VaultToken) IsRevoked() bool {
 		t.RevokedAt.Before(time.Now())
 }
 
+// IsRotated reports whether the refresh token was already exchanged for the next one of its family
+func (t *VaultToken) IsRotated() bool {
+	return t.RotatedAt != nil
+}
+
 // VaultTokenCreate represents the data needed to create a new vault token
 type VaultTokenCreate struct {
 	PublicKey string    `json:"public_key"`
 	TokenID   string    `json:"token_id"`
 	ExpiresAt time.Time `json:"expires_at"`
+	// FamilyID and ParentTokenID are set when the token is rotated from a previous one
+	FamilyID      string `json:"family_id"`
+	ParentTokenID string `json:"parent_token_id"`
+	VaultTokenDevice
 }
@@ -7,11 +7,12 @@ import (
 	"net/http"
 
 	"github.com/vultisig/verifier/plugin/libhttp"
+	"github.com/vultisig/verifier/plugin/reqsign"
 	"github.com/vultisig/verifier/types"
 )
 
 func NewVerifierEmitter(url, token string) Emitter {
-	return newApiEmitter[string](
+	e := newApiEmitter[string](
 		http.MethodPost,
 		url+"/plugin-signer/sign",
 		map[string]string{
@@ -19,12 +20,30 @@ func NewVerifierEmitter(url, token string) Emitter {
 			"Content-Type":  "application/json",
 		},
 	)
+	e.presignEndpoint = url + "/plugin-signer/presign"
+	return e
+}
+
+// NewSignedVerifierEmitter signs the requests with a key the plugin registered in the portal instead of sending its API key
+func NewSignedVerifierEmitter(url string, signer *reqsign.Signer) Emitter {
+	e := newApiEmitter[string](
+		http.MethodPost,
+		url+"/plugin-signer/sign",
+		map[string]string{
+			"Content-Type": "application/json",
+		},
+	)
+	e.presignEndpoint = url + "/plugin-signer/presign"
+	e.signer = signer
+	return e
 }
 
 type apiEmitter[T comparable] struct {
-	method   string
-	endpoint string
-	headers  map[string]string
+	method          string
+	endpoint        string
+	presignEndpoint string
+	headers         map[string]string
+	signer          *reqsign.Signer
 }
 
 // T is response type from the HTTP API call
@@ -37,7 +56,22 @@ func newApiEmitter[T comparable](method, endpoint string, headers map[string]str
 }
 
 func (e *apiEmitter[T]) Sign(ctx context.Context, req types.PluginKeysignRequest) error {
-	_, err := libhttp.Call[T](ctx, e.method, e.endpoint, e.headers, req, nil)
+	_, err := libhttp.CallSigned[T](ctx, e.signer, e.method, e.endpoint, e.headers, req, nil)
+	if err != nil {
+		var httpErr *libhttp.HTTPError
+		if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusLocked {
+			return ErrPluginPaused
+		}
+		return fmt.Errorf("failed to make API call: %w", err)
+	}
+	return nil
+}
+
+func (e *apiEmitter[T]) Presign(ctx context.Context, req types.PresignRequest) error {
+	if e.presignEndpoint == "" {
+		return errors.New("presign endpoint is not configured")
+	}
+	_, err := libhttp.CallSigned[T](ctx, e.signer, e.method, e.presignEndpoint, e.headers, req, nil)
 	if err != nil {
 		var httpErr *libhttp.HTTPError
 		if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusLocked {
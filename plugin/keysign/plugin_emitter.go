@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/hibiken/asynq"
+	"github.com/vultisig/verifier/plugin/tasks"
 	"github.com/vultisig/verifier/types"
 )
 
@@ -43,3 +44,24 @@ func (e *PluginEmitter) Sign(ctx context.Context, req types.PluginKeysignRequest
 	}
 	return nil
 }
+
+// Presign enqueues the presign task on the plugin worker, which handles it with vault.ManagementService.HandlePresignDKLS
+func (e *PluginEmitter) Presign(ctx context.Context, req types.PresignRequest) error {
+	buf, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	_, err = e.client.EnqueueContext(
+		ctx,
+		asynq.NewTask(tasks.TypePresignDKLS, buf),
+		asynq.MaxRetry(0),
+		asynq.Timeout(5*time.Minute),
+		asynq.Retention(10*time.Minute),
+		asynq.Queue(e.queue),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+	return nil
+}
@@ -6,13 +6,15 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"net/http"
 	"strings"
-	"time"
 
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"github.com/vultisig/mobile-tss-lib/tss"
+	"github.com/vultisig/verifier/plugin/metrics"
 	"github.com/vultisig/verifier/types"
+	"github.com/vultisig/vultisig-go/common"
 	"github.com/vultisig/vultisig-go/relay"
 )
 
@@ -26,11 +28,19 @@ type Emitter interface {
 	Sign(ctx context.Context, req types.PluginKeysignRequest) error
 }
 
+// PresignEmitter is implemented by emitters able to join presign sessions
+type PresignEmitter interface {
+	Presign(ctx context.Context, req types.PresignRequest) error
+}
+
 type Signer struct {
 	logger          *logrus.Logger
 	relay           *relay.Client
 	emitters        []Emitter
 	partiesPrefixes []string
+	wait            WaitConfig
+	metrics         metrics.SignerMetrics
+	httpClient      *http.Client
 }
 
 func NewSigner(
@@ -49,27 +59,49 @@ func NewSigner(
 		relay:           relay,
 		emitters:        emitters,
 		partiesPrefixes: partiesPrefixes,
+		wait:            DefaultWaitConfig(),
+		metrics:         metrics.NewNilSignerMetrics(),
+		httpClient:      &http.Client{},
+	}
+}
+
+// SetWaitConfig changes how the Signer waits on the relay, unset fields keep their defaults
+func (s *Signer) SetWaitConfig(cfg WaitConfig) {
+	s.wait = cfg.withDefaults()
+}
+
+// SetMetrics sets the metrics collector for relay waits
+func (s *Signer) SetMetrics(m metrics.SignerMetrics) {
+	if m == nil {
+		m = metrics.NewNilSignerMetrics()
 	}
+	s.metrics = m
 }
 
 func (s *Signer) genIDs(req types.PluginKeysignRequest) (types.PluginKeysignRequest, error) {
 	// single place to generate, to avoid misusage/empty in plugin implementation
 
-	if req.SessionID != "" {
-		return types.PluginKeysignRequest{}, errors.New("SessionID must be empty")
+	sessionID, hexEncryptionKey, err := newSession(req.SessionID, req.HexEncryptionKey)
+	if err != nil {
+		return types.PluginKeysignRequest{}, err
 	}
-	req.SessionID = uuid.New().String()
+	req.SessionID = sessionID
+	req.HexEncryptionKey = hexEncryptionKey
+	return req, nil
+}
 
-	if req.HexEncryptionKey != "" {
-		return types.PluginKeysignRequest{}, errors.New("HexEncryptionKey must be empty")
+func newSession(sessionID, hexEncryptionKey string) (string, string, error) {
+	if sessionID != "" {
+		return "", "", errors.New("SessionID must be empty")
+	}
+	if hexEncryptionKey != "" {
+		return "", "", errors.New("HexEncryptionKey must be empty")
 	}
 	rnd, err := uuid.New().MarshalBinary()
 	if err != nil {
-		return types.PluginKeysignRequest{}, fmt.Errorf("failed to marshal UUID: %w", err)
+		return "", "", fmt.Errorf("failed to marshal UUID: %w", err)
 	}
-	req.HexEncryptionKey = hex.EncodeToString(rnd)
-
-	return req, nil
+	return uuid.New().String(), hex.EncodeToString(rnd), nil
 }
 
 func (s *Signer) Sign(
@@ -103,55 +135,92 @@ func (s *Signer) Sign(
 	return res, nil
 }
 
+// Presign runs a presign session with every emitter and returns once all parties stored their presignatures.
+// The presignature IDs are then listed from the plugin's own PresignStore.
+func (s *Signer) Presign(ctx context.Context, req types.PresignRequest) error {
+	sessionID, hexEncryptionKey, err := newSession(req.SessionID, req.HexEncryptionKey)
+	if err != nil {
+		return fmt.Errorf("failed to generate IDs: %w", err)
+	}
+	req.SessionID = sessionID
+	req.HexEncryptionKey = hexEncryptionKey
+
+	for _, emitter := range s.emitters {
+		presignEmitter, ok := emitter.(PresignEmitter)
+		if !ok {
+			return fmt.Errorf("emitter %T doesn't support presign", emitter)
+		}
+		err := presignEmitter.Presign(ctx, req)
+		if err != nil {
+			if errors.Is(err, ErrPluginPaused) {
+				return ErrPluginPaused
+			}
+			return fmt.Errorf("failed to presign with emitter: %w", err)
+		}
+	}
+
+	partyIDs, err := s.waitPartiesAndStart(ctx, req.SessionID, s.partiesPrefixes)
+	if err != nil {
+		return fmt.Errorf("failed to wait for parties and start: %w", err)
+	}
+
+	err = s.waitSession(ctx, req.SessionID, phaseComplete, partyIDs, s.wait.CompleteTimeout,
+		func(state types.RelaySessionEvent) (bool, error) {
+			return common.IsSubset(partyIDs, state.Completed), nil
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to wait for parties to complete presign: %w", err)
+	}
+	return nil
+}
+
 func (s *Signer) waitResult(
 	ctx context.Context,
 	sessionID string,
 	partyIDs []string,
 	req types.PluginKeysignRequest,
 ) (map[string]tss.KeysignResponse, error) {
-	for {
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		case <-time.After(time.Second):
-			ok, err := s.relay.CheckCompletedParties(sessionID, partyIDs)
-			if err != nil {
-				return nil, fmt.Errorf("failed to check completed parties: %w", err)
-			}
-			if !ok {
-				s.logger.WithFields(logrus.Fields{
-					"sessionID": sessionID,
-					"partyIDs":  partyIDs,
-				}).Info("Waiting for parties to complete sign")
-				continue
+	err := s.waitSession(ctx, sessionID, phaseComplete, partyIDs, s.wait.CompleteTimeout,
+		func(state types.RelaySessionEvent) (bool, error) {
+			if common.IsSubset(partyIDs, state.Completed) {
+				return true, nil
 			}
+			s.logger.WithFields(logrus.Fields{
+				"sessionID": sessionID,
+				"partyIDs":  partyIDs,
+			}).Info("Waiting for parties to complete sign")
+			return false, nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
 
-			sigs := make(map[string]tss.KeysignResponse, len(req.Messages))
-			for _, msg := range req.Messages {
-				md5Hash := md5.Sum([]byte(msg.Message))
-				messageID := hex.EncodeToString(md5Hash[:])
-
-				sig, completeErr := s.relay.CheckKeysignComplete(sessionID, messageID)
-				if completeErr != nil {
-					s.logger.WithFields(logrus.Fields{
-						"sessionID": sessionID,
-						"messageID": messageID,
-						"partyIDs":  partyIDs,
-					}).WithError(completeErr).Info("continue polling: CheckKeysignComplete")
-					continue
-				}
-				if sig == nil {
-					return nil, fmt.Errorf(
-						"unexpected empty sig: messageID: %s, sessionID: %s",
-						messageID,
-						sessionID,
-					)
-				}
-				sigs[msg.Hash] = *sig
-			}
-			return sigs, nil
+	sigs := make(map[string]tss.KeysignResponse, len(req.Messages))
+	for _, msg := range req.Messages {
+		md5Hash := md5.Sum([]byte(msg.Message))
+		messageID := hex.EncodeToString(md5Hash[:])
+
+		sig, completeErr := s.relay.CheckKeysignComplete(sessionID, messageID)
+		if completeErr != nil {
+			s.logger.WithFields(logrus.Fields{
+				"sessionID": sessionID,
+				"messageID": messageID,
+				"partyIDs":  partyIDs,
+			}).WithError(completeErr).Info("continue polling: CheckKeysignComplete")
+			continue
+		}
+		if sig == nil {
+			return nil, fmt.Errorf(
+				"unexpected empty sig: messageID: %s, sessionID: %s",
+				messageID,
+				sessionID,
+			)
 		}
+		sigs[msg.Hash] = *sig
 	}
+	return sigs, nil
 }
 
 func (s *Signer) waitPartiesAndStart(
@@ -159,47 +228,44 @@ func (s *Signer) waitPartiesAndStart(
 	sessionID string,
 	partiesPrefixes []string,
 ) ([]string, error) {
-	for {
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		case <-time.After(time.Second):
-			partiesJoined, err := s.relay.GetSession(sessionID)
-			if err != nil {
-				return nil, fmt.Errorf("failed to get session: %w", err)
-			}
-
-			partiesIDs := filterIDsByPrefixes(partiesJoined, partiesPrefixes)
+	var partiesIDs []string
+	err := s.waitSession(ctx, sessionID, phaseParties, nil, s.wait.PartiesTimeout,
+		func(state types.RelaySessionEvent) (bool, error) {
+			partiesIDs = filterIDsByPrefixes(state.Parties, partiesPrefixes)
 			if len(partiesIDs) < len(partiesPrefixes) {
 				s.logger.WithFields(logrus.Fields{
 					"sessionID":       sessionID,
 					"partiesJoined":   partiesIDs,
 					"partiesPrefixes": partiesPrefixes,
 				}).Info("Waiting for more parties to join")
-				continue
+				return false, nil
 			}
 			if len(partiesIDs) > len(partiesPrefixes) {
-				return nil, fmt.Errorf(
+				return false, fmt.Errorf(
 					"too many parties joined: [%s], expected prefixes: [%s],"+
 						" it may be caused by a bug in calling code",
 					strings.Join(partiesIDs, ","),
 					strings.Join(partiesPrefixes, ","),
 				)
 			}
+			return true, nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
 
-			s.logger.WithFields(logrus.Fields{
-				"sessionID":       sessionID,
-				"partiesJoined":   partiesIDs,
-				"partiesPrefixes": partiesPrefixes,
-			}).Info("all expected parties joined")
-
-			err = s.relay.StartSession(sessionID, partiesIDs)
-			if err != nil {
-				return nil, fmt.Errorf("failed to start session: %w", err)
-			}
-			return partiesIDs, nil
-		}
+	s.logger.WithFields(logrus.Fields{
+		"sessionID":       sessionID,
+		"partiesJoined":   partiesIDs,
+		"partiesPrefixes": partiesPrefixes,
+	}).Info("all expected parties joined")
+
+	err = s.relay.StartSession(sessionID, partiesIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start session: %w", err)
 	}
+	return partiesIDs, nil
 }
 
 func filterIDsByPrefixes(fullIDs, prefixes []string) []string {
@@ -0,0 +1,295 @@
+package keysign
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vultisig/verifier/types"
+)
+
+type WaitMode string
+
+const (
+	// WaitModePoll polls the relay, backing off between requests
+	WaitModePoll WaitMode = "poll"
+	// WaitModeEvents subscribes to the relay session events, falling back to polling
+	// when the relay doesn't serve them or the stream breaks
+	WaitModeEvents WaitMode = "events"
+)
+
+const (
+	phaseParties  = "parties"
+	phaseComplete = "complete"
+
+	outcomeOK      = "ok"
+	outcomeTimeout = "timeout"
+	outcomeError   = "error"
+)
+
+// WaitConfig controls how the Signer waits on the relay for parties to join and complete
+type WaitConfig struct {
+	Mode WaitMode
+	// RelayURL is the relay base URL, required by WaitModeEvents. When set, polling reads
+	// the completed parties directly instead of the blocking relay.Client.CheckCompletedParties.
+	RelayURL          string
+	PollInterval      time.Duration
+	MaxPollInterval   time.Duration
+	BackoffMultiplier float64
+	// PartiesTimeout and CompleteTimeout bound each wait of a session, zero leaves it to the caller context
+	PartiesTimeout  time.Duration
+	CompleteTimeout time.Duration
+}
+
+// DefaultWaitConfig polls every second, the behaviour of the Signer before wait modes existed
+func DefaultWaitConfig() WaitConfig {
+	return WaitConfig{
+		Mode:              WaitModePoll,
+		PollInterval:      time.Second,
+		MaxPollInterval:   time.Second,
+		BackoffMultiplier: 1,
+	}
+}
+
+func (c WaitConfig) withDefaults() WaitConfig {
+	def := DefaultWaitConfig()
+	if c.Mode == "" {
+		c.Mode = def.Mode
+	}
+	if c.PollInterval <= 0 {
+		c.PollInterval = def.PollInterval
+	}
+	if c.MaxPollInterval < c.PollInterval {
+		c.MaxPollInterval = c.PollInterval
+	}
+	if c.BackoffMultiplier < 1 {
+		c.BackoffMultiplier = def.BackoffMultiplier
+	}
+	return c
+}
+
+func (c WaitConfig) nextInterval(interval time.Duration) time.Duration {
+	next := time.Duration(float64(interval) * c.BackoffMultiplier)
+	if next > c.MaxPollInterval {
+		return c.MaxPollInterval
+	}
+	return next
+}
+
+// sessionCondition reports whether the wait is over, an error aborts it
+type sessionCondition func(state types.RelaySessionEvent) (bool, error)
+
+// waitSession blocks until cond is met for the session, within the phase timeout.
+// partyIDs are the started parties, used to poll completion on relays without a direct read.
+func (s *Signer) waitSession(
+	ctx context.Context,
+	sessionID string,
+	phase string,
+	partyIDs []string,
+	timeout time.Duration,
+	cond sessionCondition,
+) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	mode := s.wait.Mode
+	var err error
+	if mode == WaitModeEvents {
+		var done bool
+		done, err = s.waitEvents(ctx, sessionID, cond)
+		if !done && err == nil && ctx.Err() == nil {
+			mode = WaitModePoll
+		}
+	}
+	if mode == WaitModePoll {
+		err = s.waitPoll(ctx, sessionID, phase, partyIDs, cond)
+	}
+
+	outcome := outcomeOK
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		outcome = outcomeTimeout
+	case err != nil:
+		outcome = outcomeError
+	}
+	s.metrics.RecordWait(phase, string(mode), outcome, time.Since(start).Seconds())
+	if err != nil {
+		return fmt.Errorf("failed to wait for %s: %w", phase, err)
+	}
+	return nil
+}
+
+// waitEvents consumes the relay session events until cond is met. It returns false without error
+// when events aren't available, so the caller can fall back to polling. The stream is closed on return.
+func (s *Signer) waitEvents(ctx context.Context, sessionID string, cond sessionCondition) (bool, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	events, err := s.subscribe(ctx, sessionID)
+	if err != nil {
+		s.logger.WithError(err).WithField("sessionID", sessionID).Warn("relay events unavailable, polling")
+		return false, nil
+	}
+	for state := range events {
+		done, err := cond(state)
+		if err != nil {
+			return false, err
+		}
+		if done {
+			return true, nil
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	s.logger.WithField("sessionID", sessionID).Warn("relay events stream ended, polling")
+	return false, nil
+}
+
+// subscribe opens the server-sent events stream of the session, the channel is closed when the stream ends.
+// The stream is closed once ctx is done, the caller must cancel ctx when it stops reading.
+func (s *Signer) subscribe(ctx context.Context, sessionID string) (<-chan types.RelaySessionEvent, error) {
+	if s.wait.RelayURL == "" {
+		return nil, errors.New("relay URL is not configured")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.wait.RelayURL+"/events/"+sessionID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("unexpected events response: %s", resp.Status)
+	}
+
+	// unblocks the scanner when the subscriber is gone
+	stop := context.AfterFunc(ctx, func() {
+		_ = resp.Body.Close()
+	})
+
+	events := make(chan types.RelaySessionEvent)
+	go func() {
+		defer close(events)
+		defer func() {
+			stop()
+			_ = resp.Body.Close()
+		}()
+		scanner := bufio.NewScanner(resp.Body)
+		var data strings.Builder
+		for scanner.Scan() {
+			line := scanner.Text()
+			if strings.HasPrefix(line, "data:") {
+				data.WriteString(strings.TrimSpace(strings.TrimPrefix(line, "data:")))
+				continue
+			}
+			if line != "" || data.Len() == 0 {
+				continue
+			}
+			var state types.RelaySessionEvent
+			err := json.Unmarshal([]byte(data.String()), &state)
+			data.Reset()
+			if err != nil {
+				s.logger.WithError(err).Warn("failed to decode relay event")
+				continue
+			}
+			select {
+			case events <- state:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events, nil
+}
+
+func (s *Signer) waitPoll(ctx context.Context, sessionID, phase string, partyIDs []string, cond sessionCondition) error {
+	interval := s.wait.PollInterval
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+			state, err := s.pollState(ctx, sessionID, phase, partyIDs)
+			if err != nil {
+				return err
+			}
+			done, err := cond(state)
+			if err != nil {
+				return err
+			}
+			if done {
+				return nil
+			}
+			s.logger.WithFields(logrus.Fields{
+				"sessionID": sessionID,
+				"phase":     phase,
+			}).Debug("waiting on relay")
+			interval = s.wait.nextInterval(interval)
+		}
+	}
+}
+
+func (s *Signer) pollState(ctx context.Context, sessionID, phase string, partyIDs []string) (types.RelaySessionEvent, error) {
+	var state types.RelaySessionEvent
+	if phase == phaseParties {
+		parties, err := s.relay.GetSession(sessionID)
+		if err != nil {
+			return state, fmt.Errorf("failed to get session: %w", err)
+		}
+		state.Parties = parties
+		return state, nil
+	}
+
+	if s.wait.RelayURL == "" {
+		ok, err := s.relay.CheckCompletedParties(sessionID, partyIDs)
+		if err != nil {
+			return state, fmt.Errorf("failed to check completed parties: %w", err)
+		}
+		if ok {
+			state.Completed = partyIDs
+		}
+		return state, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.wait.RelayURL+"/complete/"+sessionID, nil)
+	if err != nil {
+		return state, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return state, fmt.Errorf("failed to get completed parties: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return state, fmt.Errorf("failed to get completed parties: %s", resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return state, fmt.Errorf("failed to read completed parties: %w", err)
+	}
+	// the relay answers with an empty body until a party completes
+	if len(body) == 0 {
+		return state, nil
+	}
+	if err := json.Unmarshal(body, &state.Completed); err != nil {
+		return state, fmt.Errorf("failed to decode completed parties: %w", err)
+	}
+	return state, nil
+}
@@ -0,0 +1,189 @@
+package keysign
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"github.com/vultisig/vultisig-go/common"
+	vgrelay "github.com/vultisig/vultisig-go/relay"
+
+	"github.com/vultisig/verifier/internal/relay"
+	"github.com/vultisig/verifier/types"
+)
+
+type recordedWait struct {
+	phase, mode, outcome string
+}
+
+type recordingMetrics struct {
+	mu    sync.Mutex
+	waits []recordedWait
+}
+
+func (m *recordingMetrics) RecordWait(phase, mode, outcome string, _ float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.waits = append(m.waits, recordedWait{phase: phase, mode: mode, outcome: outcome})
+}
+
+func newTestSigner(t *testing.T, handler http.Handler, mode WaitMode) (*Signer, *recordingMetrics, *vgrelay.Client) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client := vgrelay.NewRelayClient(server.URL)
+	signer := NewSigner(logrus.New(), client, nil, []string{"verifier", "plugin"})
+	signer.SetWaitConfig(WaitConfig{
+		Mode:         mode,
+		RelayURL:     server.URL,
+		PollInterval: 10 * time.Millisecond,
+	})
+	m := &recordingMetrics{}
+	signer.SetMetrics(m)
+	return signer, m, client
+}
+
+// joinAndComplete registers then completes the parties on the relay, like the signing parties would
+func joinAndComplete(client *vgrelay.Client, sessionID string, parties []string) <-chan error {
+	done := make(chan error, 1)
+	go func() {
+		defer close(done)
+		time.Sleep(50 * time.Millisecond)
+		for _, party := range parties {
+			if err := client.RegisterSession(sessionID, party); err != nil {
+				done <- err
+				return
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+		for _, party := range parties {
+			if err := client.CompleteSession(sessionID, party); err != nil {
+				done <- err
+				return
+			}
+		}
+	}()
+	return done
+}
+
+// waitSigned runs both waits of Signer.Sign
+func waitSigned(ctx context.Context, signer *Signer, sessionID string) ([]string, error) {
+	partyIDs, err := signer.waitPartiesAndStart(ctx, sessionID, signer.partiesPrefixes)
+	if err != nil {
+		return nil, err
+	}
+	err = signer.waitSession(ctx, sessionID, phaseComplete, partyIDs, 0,
+		func(state types.RelaySessionEvent) (bool, error) {
+			return common.IsSubset(partyIDs, state.Completed), nil
+		},
+	)
+	return partyIDs, err
+}
+
+func TestSigner_WaitEvents(t *testing.T) {
+	signer, m, client := newTestSigner(t, relay.NewServer(logrus.New(), 0).Handler(), WaitModeEvents)
+	parties := []string{"verifier-1", "plugin-1"}
+	done := joinAndComplete(client, "events-session", parties)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	partyIDs, err := waitSigned(ctx, signer, "events-session")
+	require.NoError(t, err)
+	require.NoError(t, <-done)
+	require.ElementsMatch(t, parties, partyIDs)
+	require.Equal(t, []recordedWait{
+		{phase: phaseParties, mode: string(WaitModeEvents), outcome: outcomeOK},
+		{phase: phaseComplete, mode: string(WaitModeEvents), outcome: outcomeOK},
+	}, m.waits)
+}
+
+func TestSigner_WaitEventsClosesStream(t *testing.T) {
+	closed := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("data: {\"parties\":[\"verifier-1\"]}\n\n"))
+		w.(http.Flusher).Flush()
+		// the relay keeps the stream open, only the subscriber leaving ends it
+		<-r.Context().Done()
+		close(closed)
+	})
+	signer, _, _ := newTestSigner(t, handler, WaitModeEvents)
+
+	done, err := signer.waitEvents(context.Background(), "open-session", func(state types.RelaySessionEvent) (bool, error) {
+		return len(state.Parties) > 0, nil
+	})
+	require.NoError(t, err)
+	require.True(t, done)
+
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("events stream still open after the wait")
+	}
+}
+
+func TestSigner_WaitEventsFallbackToPoll(t *testing.T) {
+	relayHandler := relay.NewServer(logrus.New(), 0).Handler()
+	// a relay without the events endpoint
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/events/") {
+			http.NotFound(w, r)
+			return
+		}
+		relayHandler.ServeHTTP(w, r)
+	})
+	signer, m, client := newTestSigner(t, handler, WaitModeEvents)
+	done := joinAndComplete(client, "poll-session", []string{"verifier-1", "plugin-1"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	partyIDs, err := waitSigned(ctx, signer, "poll-session")
+	require.NoError(t, err)
+	require.NoError(t, <-done)
+	require.Len(t, partyIDs, 2)
+	require.Equal(t, []recordedWait{
+		{phase: phaseParties, mode: string(WaitModePoll), outcome: outcomeOK},
+		{phase: phaseComplete, mode: string(WaitModePoll), outcome: outcomeOK},
+	}, m.waits)
+}
+
+func TestSigner_WaitTimeout(t *testing.T) {
+	signer, m, _ := newTestSigner(t, relay.NewServer(logrus.New(), 0).Handler(), WaitModeEvents)
+	signer.wait.PartiesTimeout = 100 * time.Millisecond
+
+	_, err := signer.waitPartiesAndStart(context.Background(), "empty-session", signer.partiesPrefixes)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Equal(t, []recordedWait{
+		{phase: phaseParties, mode: string(WaitModeEvents), outcome: outcomeTimeout},
+	}, m.waits)
+}
+
+func TestWaitConfig_NextInterval(t *testing.T) {
+	cfg := WaitConfig{
+		PollInterval:      100 * time.Millisecond,
+		MaxPollInterval:   time.Second,
+		BackoffMultiplier: 2,
+	}.withDefaults()
+	require.Equal(t, WaitModePoll, cfg.Mode)
+
+	interval := cfg.PollInterval
+	var got []time.Duration
+	for i := 0; i < 5; i++ {
+		interval = cfg.nextInterval(interval)
+		got = append(got, interval)
+	}
+	require.Equal(t, []time.Duration{
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second,
+		time.Second,
+	}, got)
+}
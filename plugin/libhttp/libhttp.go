@@ -9,6 +9,8 @@ import (
 	"net/http"
 	stdurl "net/url"
 	"time"
+
+	"github.com/vultisig/verifier/plugin/reqsign"
 )
 
 const maxErrorBodySize = 1024
@@ -28,6 +30,18 @@ func Call[T any](
 	headers map[string]string,
 	body interface{},
 	query map[string]string,
+) (T, error) {
+	return CallSigned[T](ctx, nil, method, url, headers, body, query)
+}
+
+// CallSigned is Call with the request signed by signer, a nil signer sends it unsigned
+func CallSigned[T any](
+	ctx context.Context,
+	signer *reqsign.Signer,
+	method, url string,
+	headers map[string]string,
+	body interface{},
+	query map[string]string,
 ) (T, error) {
 	b, err := json.Marshal(body)
 	if err != nil {
@@ -51,6 +65,9 @@ func Call[T any](
 	for k, v := range headers {
 		req.Header.Set(k, v)
 	}
+	if signer != nil {
+		signer.Sign(req, b)
+	}
 
 	client := &http.Client{
 		Timeout: 10 * time.Second,
@@ -2,11 +2,15 @@ package libhttp
 
 import (
 	"context"
+	"crypto/ed25519"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
+
+	"github.com/vultisig/verifier/plugin/reqsign"
 )
 
 type respStruct struct {
@@ -50,3 +54,41 @@ func TestCall_JSONStruct(t *testing.T) {
 		t.Fatalf("unexpected: %#v", got)
 	}
 }
+
+func TestCallSigned(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey error: %v", err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := reqsign.ReadBody(r)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, err = reqsign.Verify(r, body, reqsign.StaticKeys(pub), time.Now(), reqsign.DefaultWindow)
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte("signed"))
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	got, err := CallSigned[string](ctx, reqsign.NewSigner(priv), http.MethodPost, srv.URL+"/sign", nil, respStruct{N: 1}, map[string]string{"a": "b"})
+	if err != nil {
+		t.Fatalf("CallSigned error: %v", err)
+	}
+	if got != "signed" {
+		t.Fatalf("want %q, got %q", "signed", got)
+	}
+
+	_, err = Call[string](ctx, http.MethodPost, srv.URL+"/sign", nil, respStruct{N: 1}, nil)
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("want unauthorized, got %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// SignerMetrics interface for collecting keysign.Signer relay wait metrics
+type SignerMetrics interface {
+	// RecordWait records the time spent waiting on the relay for a session phase.
+	// mode is how the wait ended up being served (events or poll), outcome is ok, timeout or error.
+	RecordWait(phase, mode, outcome string, duration float64)
+}
+
+// NilSignerMetrics is a no-op implementation for when metrics are disabled
+type NilSignerMetrics struct{}
+
+// NewNilSignerMetrics creates a no-op metrics implementation
+func NewNilSignerMetrics() SignerMetrics {
+	return &NilSignerMetrics{}
+}
+
+func (n *NilSignerMetrics) RecordWait(phase, mode, outcome string, duration float64) {}
+
+type signerMetrics struct {
+	waitDuration *prometheus.HistogramVec
+}
+
+// NewSignerMetrics creates the Prometheus implementation and registers it in the registry
+func NewSignerMetrics(registry Registry) SignerMetrics {
+	m := &signerMetrics{
+		waitDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: "plugin",
+				Subsystem: "signer",
+				Name:      "wait_duration_seconds",
+				Help:      "Time spent waiting on the relay by session phase, wait mode and outcome",
+				Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2, 5, 10, 30, 60, 180},
+			},
+			[]string{"phase", "mode", "outcome"},
+		),
+	}
+	registry.MustRegister(m.waitDuration)
+	return m
+}
+
+func (m *signerMetrics) RecordWait(phase, mode, outcome string, duration float64) {
+	m.waitDuration.WithLabelValues(phase, mode, outcome).Observe(duration)
+}
@@ -44,6 +44,14 @@ func (r *Redis) Set(ctx context.Context, key string, value string, expiry time.D
 	}
 	return r.client.Set(ctx, key, value, expiry).Err()
 }
+
+// SetNX sets key unless it is already set, it reports whether key was set
+func (r *Redis) SetNX(ctx context.Context, key string, value string, expiry time.Duration) (bool, error) {
+	if err := contexthelper.CheckCancellation(ctx); err != nil {
+		return false, err
+	}
+	return r.client.SetNX(ctx, key, value, expiry).Result()
+}
 func (r *Redis) Expire(ctx context.Context, key string, expiry time.Duration) error {
 	if err := contexthelper.CheckCancellation(ctx); err != nil {
 		return err
@@ -0,0 +1,50 @@
+package reqsign
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const memorySweepInterval = time.Minute
+
+// MemoryCache is a ReplayCache kept in the process, for receivers running a single instance
+type MemoryCache struct {
+	mu        sync.Mutex
+	expires   map[string]time.Time
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{
+		expires: make(map[string]time.Time),
+		now:     time.Now,
+	}
+}
+
+func (m *MemoryCache) SetNX(_ context.Context, key, _ string, expiry time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.sweep(now)
+
+	if expires, ok := m.expires[key]; ok && now.Before(expires) {
+		return false, nil
+	}
+	m.expires[key] = now.Add(expiry)
+	return true, nil
+}
+
+func (m *MemoryCache) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < memorySweepInterval {
+		return
+	}
+	m.lastSweep = now
+	for key, expires := range m.expires {
+		if !now.Before(expires) {
+			delete(m.expires, key)
+		}
+	}
+}
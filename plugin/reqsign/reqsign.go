@@ -0,0 +1,204 @@
+// Package reqsign signs the requests between the verifier and the plugin servers with ed25519 keys.
+// A signature covers the method, the host the request is sent to, the path with its query, a timestamp and
+// the SHA-256 of the body, so a signed request can't be replayed to another server. Proxies in front of
+// a receiver must keep the Host header. A request whose timestamp is out of the replay window is rejected,
+// and so is a signature already received within it.
+package reqsign
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	HeaderKeyID     = "X-Vultisig-Key-Id"
+	HeaderTimestamp = "X-Vultisig-Timestamp"
+	HeaderSignature = "X-Vultisig-Signature"
+
+	// DefaultWindow is how far the timestamp of a request may be from the clock of the receiver
+	DefaultWindow = 5 * time.Minute
+
+	// MaxBodySize caps the body read before the signature is checked
+	MaxBodySize = 2 << 20
+)
+
+var (
+	ErrMissingSignature = errors.New("missing request signature")
+	ErrStaleRequest     = errors.New("request timestamp out of the replay window")
+	ErrUnknownKey       = errors.New("unknown signing key")
+	ErrInvalidSignature = errors.New("invalid request signature")
+	ErrReplayedRequest  = errors.New("request signature already received")
+	ErrBodyTooLarge     = errors.New("request body too large")
+)
+
+// KeyID is the ID a public key is registered and looked up by
+func KeyID(pub ed25519.PublicKey) string {
+	sum := sha256.Sum256(pub)
+	return hex.EncodeToString(sum[:8])
+}
+
+// ParsePrivateKey parses a hex encoded ed25519 seed or private key
+func ParsePrivateKey(s string) (ed25519.PrivateKey, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode private key: %w", err)
+	}
+	switch len(b) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(b), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(b), nil
+	default:
+		return nil, fmt.Errorf("invalid private key length: %d", len(b))
+	}
+}
+
+// ParsePublicKey parses a hex encoded ed25519 public key
+func ParsePublicKey(s string) (ed25519.PublicKey, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode public key: %w", err)
+	}
+	if len(b) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid public key length: %d", len(b))
+	}
+	return ed25519.PublicKey(b), nil
+}
+
+func message(method, host, uri, timestamp string, body []byte) []byte {
+	sum := sha256.Sum256(body)
+	return []byte(strings.Join([]string{method, strings.ToLower(host), uri, timestamp, hex.EncodeToString(sum[:])}, "\n"))
+}
+
+type Signer struct {
+	key   ed25519.PrivateKey
+	keyID string
+	now   func() time.Time
+}
+
+func NewSigner(key ed25519.PrivateKey) *Signer {
+	return &Signer{
+		key:   key,
+		keyID: KeyID(key.Public().(ed25519.PublicKey)),
+		now:   time.Now,
+	}
+}
+
+func (s *Signer) KeyID() string {
+	return s.keyID
+}
+
+// Sign sets the signature headers of req, body is the body req is sent with
+func (s *Signer) Sign(req *http.Request, body []byte) {
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	timestamp := strconv.FormatInt(s.now().Unix(), 10)
+	signature := ed25519.Sign(s.key, message(req.Method, host, req.URL.RequestURI(), timestamp, body))
+	req.Header.Set(HeaderKeyID, s.keyID)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, base64.StdEncoding.EncodeToString(signature))
+}
+
+// KeyFunc returns the public key registered under keyID, nil if the key is unknown or no longer valid
+type KeyFunc func(ctx context.Context, keyID string) (ed25519.PublicKey, error)
+
+// StaticKeys looks up a fixed set of keys, a receiver lists both keys while the sender rotates
+func StaticKeys(keys ...ed25519.PublicKey) KeyFunc {
+	byID := make(map[string]ed25519.PublicKey, len(keys))
+	for _, key := range keys {
+		byID[KeyID(key)] = key
+	}
+	return func(_ context.Context, keyID string) (ed25519.PublicKey, error) {
+		return byID[keyID], nil
+	}
+}
+
+// Signed reports whether req carries a signature
+func Signed(req *http.Request) bool {
+	return req.Header.Get(HeaderSignature) != ""
+}
+
+// ReadBody reads the body of req up to MaxBodySize and puts it back for the handlers
+func ReadBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	body, err := io.ReadAll(io.LimitReader(req.Body, MaxBodySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	if len(body) > MaxBodySize {
+		return nil, ErrBodyTooLarge
+	}
+	_ = req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// Verify checks the signature of req against its body and the host it was received on,
+// it returns the ID of the key that signed it
+func Verify(req *http.Request, body []byte, keys KeyFunc, now time.Time, window time.Duration) (string, error) {
+	keyID := req.Header.Get(HeaderKeyID)
+	timestamp := req.Header.Get(HeaderTimestamp)
+	encoded := req.Header.Get(HeaderSignature)
+	if keyID == "" || timestamp == "" || encoded == "" {
+		return "", ErrMissingSignature
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid timestamp", ErrStaleRequest)
+	}
+	skew := now.Sub(time.Unix(unix, 0))
+	if skew > window || skew < -window {
+		return "", ErrStaleRequest
+	}
+
+	key, err := keys(req.Context(), keyID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get signing key: %w", err)
+	}
+	if key == nil {
+		return "", ErrUnknownKey
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || !ed25519.Verify(key, message(req.Method, req.Host, req.URL.RequestURI(), timestamp, body), signature) {
+		return "", ErrInvalidSignature
+	}
+	return keyID, nil
+}
+
+// ReplayCache records the signatures received within the replay window. Receivers running several instances
+// share one in redis, MemoryCache only covers a single instance.
+type ReplayCache interface {
+	// SetNX sets key for expiry unless it is already set, it reports whether key was set
+	SetNX(ctx context.Context, key, value string, expiry time.Duration) (bool, error)
+}
+
+// CheckReplay records the signature of a verified request, it returns ErrReplayedRequest when it was already received.
+// The signature is kept twice the window as the timestamp may be up to a window ahead of the receiver clock.
+func CheckReplay(ctx context.Context, cache ReplayCache, req *http.Request, window time.Duration) error {
+	sum := sha256.Sum256([]byte(req.Header.Get(HeaderKeyID) + "\n" + req.Header.Get(HeaderSignature)))
+	set, err := cache.SetNX(ctx, "reqsign:"+hex.EncodeToString(sum[:]), "1", 2*window)
+	if err != nil {
+		return fmt.Errorf("failed to record request signature: %w", err)
+	}
+	if !set {
+		return ErrReplayedRequest
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package reqsign
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignVerify(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	oldPub, oldKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	newPub, newKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	parsed, err := ParsePrivateKey(hex.EncodeToString(oldKey.Seed()))
+	require.NoError(t, err)
+	require.Equal(t, oldKey, parsed)
+	parsedPub, err := ParsePublicKey("0x" + hex.EncodeToString(oldPub))
+	require.NoError(t, err)
+	require.Equal(t, oldPub, parsedPub)
+	_, err = ParsePublicKey("abcd")
+	require.Error(t, err)
+
+	signed := func(key ed25519.PrivateKey, at time.Time, body string) *http.Request {
+		signer := NewSigner(key)
+		signer.now = func() time.Time { return at }
+		req, err := http.NewRequest(http.MethodPut, "https://plugin.example/plugin/policy?x=1", bytes.NewBufferString(body))
+		require.NoError(t, err)
+		signer.Sign(req, []byte(body))
+
+		received := httptest.NewRequest(http.MethodPut, "https://plugin.example/plugin/policy?x=1", bytes.NewBufferString(body))
+		received.Header = req.Header
+		return received
+	}
+
+	// both keys are accepted while the sender rotates
+	keys := StaticKeys(oldPub, newPub)
+	for _, key := range []ed25519.PrivateKey{oldKey, newKey} {
+		req := signed(key, now, `{"id":"1"}`)
+		require.True(t, Signed(req))
+		body, err := ReadBody(req)
+		require.NoError(t, err)
+		keyID, err := Verify(req, body, keys, now, DefaultWindow)
+		require.NoError(t, err)
+		require.Equal(t, KeyID(key.Public().(ed25519.PublicKey)), keyID)
+		// the handlers still get the body
+		again, err := ReadBody(req)
+		require.NoError(t, err)
+		require.Equal(t, body, again)
+	}
+
+	req := signed(oldKey, now, `{"id":"1"}`)
+	_, err = Verify(req, []byte(`{"id":"2"}`), keys, now, DefaultWindow)
+	require.ErrorIs(t, err, ErrInvalidSignature)
+
+	req = signed(oldKey, now.Add(-DefaultWindow-time.Second), `{}`)
+	_, err = Verify(req, []byte(`{}`), keys, now, DefaultWindow)
+	require.ErrorIs(t, err, ErrStaleRequest)
+
+	req = signed(newKey, now, `{}`)
+	_, err = Verify(req, []byte(`{}`), StaticKeys(oldPub), now, DefaultWindow)
+	require.ErrorIs(t, err, ErrUnknownKey)
+
+	req = signed(oldKey, now, `{}`)
+	req.Method = http.MethodDelete
+	_, err = Verify(req, []byte(`{}`), keys, now, DefaultWindow)
+	require.ErrorIs(t, err, ErrInvalidSignature)
+
+	// a request signed for a plugin server is rejected by the others
+	req = signed(oldKey, now, `{}`)
+	req.Host = "other-plugin.example"
+	_, err = Verify(req, []byte(`{}`), keys, now, DefaultWindow)
+	require.ErrorIs(t, err, ErrInvalidSignature)
+
+	_, err = Verify(httptest.NewRequest(http.MethodGet, "/", nil), nil, keys, now, DefaultWindow)
+	require.ErrorIs(t, err, ErrMissingSignature)
+
+	_, err = ReadBody(httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(make([]byte, MaxBodySize+1))))
+	require.ErrorIs(t, err, ErrBodyTooLarge)
+}
+
+func TestCheckReplay(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	_, key, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	signer := NewSigner(key)
+	signer.now = func() time.Time { return now }
+	cache := NewMemoryCache()
+	cache.now = func() time.Time { return now }
+
+	signed := func(body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/plugin/policy", bytes.NewBufferString(body))
+		signer.Sign(req, []byte(body))
+		return req
+	}
+
+	ctx := context.Background()
+	req := signed(`{"id":"1"}`)
+	require.NoError(t, CheckReplay(ctx, cache, req, DefaultWindow))
+	require.ErrorIs(t, CheckReplay(ctx, cache, req, DefaultWindow), ErrReplayedRequest)
+	require.NoError(t, CheckReplay(ctx, cache, signed(`{"id":"2"}`), DefaultWindow))
+
+	// the signature is forgotten once its timestamp is out of the window
+	now = now.Add(2*DefaultWindow + time.Second)
+	require.NoError(t, CheckReplay(ctx, cache, req, DefaultWindow))
+}
@@ -1,6 +1,123 @@
 package safety
 
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/vultisig/vultisig-go/common"
+)
+
 func KeysignFlagKey(pluginID string) string { return pluginID + "-keysign" }
 func KeygenFlagKey(pluginID string) string  { return pluginID + "-keygen" }
 func GlobalKeysignKey() string              { return "global-keysign" }
 func GlobalKeygenKey() string               { return "global-keygen" }
+
+// ChainKeysignKey, VaultKeysignKey and PolicyKeysignKey halt the keysign of a chain, vault or policy,
+// their keys are prefixed with their scope
+func ChainKeysignKey(chain common.Chain) string {
+	return string(ScopeChain) + ":" + strings.ToLower(chain.String()) + "-keysign"
+}
+
+func VaultKeysignKey(publicKey string) string {
+	return string(ScopeVault) + ":" + publicKey + "-keysign"
+}
+
+func PolicyKeysignKey(policyID uuid.UUID) string {
+	return string(ScopePolicy) + ":" + policyID.String() + "-keysign"
+}
+
+// FlagScope is the level a control flag applies to, from the broadest to the narrowest
+type FlagScope string
+
+const (
+	ScopeGlobal FlagScope = "global"
+	ScopeChain  FlagScope = "chain"
+	ScopePlugin FlagScope = "plugin"
+	ScopeVault  FlagScope = "vault"
+	ScopePolicy FlagScope = "policy"
+)
+
+// ScopedKeysignKey returns the keysign flag key of a scope, id is the chain name, plugin ID,
+// vault public key or policy ID, and is empty for the global scope
+func ScopedKeysignKey(scope FlagScope, id string) (string, error) {
+	if scope != ScopeGlobal && id == "" {
+		return "", fmt.Errorf("%s id is required", scope)
+	}
+	switch scope {
+	case ScopeGlobal:
+		return GlobalKeysignKey(), nil
+	case ScopeChain:
+		chain, err := common.FromString(id)
+		if err != nil {
+			return "", err
+		}
+		return ChainKeysignKey(chain), nil
+	case ScopePlugin:
+		return KeysignFlagKey(id), nil
+	case ScopeVault:
+		return VaultKeysignKey(id), nil
+	case ScopePolicy:
+		policyID, err := uuid.Parse(id)
+		if err != nil {
+			return "", fmt.Errorf("invalid policy id: %w", err)
+		}
+		return PolicyKeysignKey(policyID), nil
+	}
+	return "", fmt.Errorf("unknown scope: %s", scope)
+}
+
+// KeysignScope is what a keysign signs for, any of its levels can halt it
+type KeysignScope struct {
+	PluginID string
+	// Chains are the chains of the keysign messages
+	Chains    []common.Chain
+	PublicKey string
+	PolicyID  uuid.UUID
+}
+
+type ScopedKey struct {
+	Scope FlagScope
+	Key   string
+}
+
+// Keys returns the keysign flag keys of the scope, from the broadest level to the narrowest
+func (s KeysignScope) Keys() []ScopedKey {
+	keys := []ScopedKey{{Scope: ScopeGlobal, Key: GlobalKeysignKey()}}
+	seen := make(map[common.Chain]bool, len(s.Chains))
+	for _, chain := range s.Chains {
+		if seen[chain] {
+			continue
+		}
+		seen[chain] = true
+		keys = append(keys, ScopedKey{Scope: ScopeChain, Key: ChainKeysignKey(chain)})
+	}
+	if s.PluginID != "" {
+		keys = append(keys, ScopedKey{Scope: ScopePlugin, Key: KeysignFlagKey(s.PluginID)})
+	}
+	if s.PublicKey != "" {
+		keys = append(keys, ScopedKey{Scope: ScopeVault, Key: VaultKeysignKey(s.PublicKey)})
+	}
+	if s.PolicyID != uuid.Nil {
+		keys = append(keys, ScopedKey{Scope: ScopePolicy, Key: PolicyKeysignKey(s.PolicyID)})
+	}
+	return keys
+}
+
+// ParseFlagKey splits a control flag key into its scope, the id within the scope and its action
+func ParseFlagKey(key string) (scope FlagScope, id string, action string) {
+	i := strings.LastIndex(key, "-")
+	if i < 0 {
+		return "", "", ""
+	}
+	name, action := key[:i], key[i+1:]
+	if name == string(ScopeGlobal) {
+		return ScopeGlobal, "", action
+	}
+	for _, prefixed := range []FlagScope{ScopeChain, ScopeVault, ScopePolicy} {
+		if id, ok := strings.CutPrefix(name, string(prefixed)+":"); ok {
+			return prefixed, id, action
+		}
+	}
+	return ScopePlugin, name, action
+}
@@ -0,0 +1,48 @@
+package safety
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScopedKeysignKey(t *testing.T) {
+	policyID := uuid.New()
+	for _, tc := range []struct {
+		scope FlagScope
+		id    string
+		key   string
+	}{
+		{ScopeGlobal, "", "global-keysign"},
+		{ScopeChain, "bitcoin-cash", "chain:bitcoin-cash-keysign"},
+		{ScopeChain, "Solana", "chain:solana-keysign"},
+		{ScopePlugin, "vultisig-dca-0000", "vultisig-dca-0000-keysign"},
+		{ScopeVault, "02abcd", "vault:02abcd-keysign"},
+		{ScopePolicy, policyID.String(), "policy:" + policyID.String() + "-keysign"},
+	} {
+		key, err := ScopedKeysignKey(tc.scope, tc.id)
+		require.NoError(t, err)
+		require.Equal(t, tc.key, key)
+
+		scope, id, action := ParseFlagKey(key)
+		require.Equal(t, tc.scope, scope)
+		require.Equal(t, strings.ToLower(tc.id), strings.ToLower(id))
+		require.Equal(t, "keysign", action)
+	}
+
+	_, err := ScopedKeysignKey(ScopeChain, "Narnia")
+	require.Error(t, err)
+	_, err = ScopedKeysignKey(ScopePolicy, "not-a-uuid")
+	require.Error(t, err)
+	_, err = ScopedKeysignKey(ScopeVault, "")
+	require.Error(t, err)
+	_, err = ScopedKeysignKey("galaxy", "id")
+	require.Error(t, err)
+
+	scope, id, action := ParseFlagKey(KeygenFlagKey("vultisig-dca-0000"))
+	require.Equal(t, ScopePlugin, scope)
+	require.Equal(t, "vultisig-dca-0000", id)
+	require.Equal(t, "keygen", action)
+}
@@ -2,6 +2,8 @@ package safety_pg
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/vultisig/verifier/plugin/safety"
@@ -46,9 +48,18 @@ func (r *Repo) GetControlFlags(ctx context.Context, k1, k2 string) (map[string]b
 	return result, nil
 }
 
+// UpsertControlFlags stores the flags and notifies each of them on safety.ControlFlagsChannel
 func (r *Repo) UpsertControlFlags(ctx context.Context, flags []safety.ControlFlag) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
 	for _, flag := range flags {
-		_, err := r.pool.Exec(ctx, `
+		_, err = tx.Exec(ctx, `
 			INSERT INTO control_flags (key, enabled, updated_at)
 			VALUES ($1, $2, NOW())
 			ON CONFLICT (key) DO UPDATE
@@ -57,6 +68,49 @@ func (r *Repo) UpsertControlFlags(ctx context.Context, flags []safety.ControlFla
 		if err != nil {
 			return err
 		}
+
+		payload, err := json.Marshal(flag)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, `SELECT pg_notify($1, $2)`, safety.ControlFlagsChannel, string(payload))
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// Listen calls fn with every control flag notified on safety.ControlFlagsChannel until ctx is done
+func (r *Repo) Listen(ctx context.Context, fn func(safety.ControlFlag)) error {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	_, err = conn.Exec(ctx, "LISTEN "+safety.ControlFlagsChannel)
+	if err != nil {
+		return fmt.Errorf("failed to listen to control flags: %w", err)
+	}
+	defer func() {
+		_, _ = conn.Exec(context.Background(), "UNLISTEN "+safety.ControlFlagsChannel)
+	}()
+
+	for {
+		notification, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to wait for control flags: %w", err)
+		}
+
+		var flag safety.ControlFlag
+		err = json.Unmarshal([]byte(notification.Payload), &flag)
+		if err != nil {
+			continue
+		}
+		fn(flag)
 	}
-	return nil
 }
@@ -2,6 +2,9 @@ package safety
 
 import "context"
 
+// ControlFlagsChannel is the Postgres channel each changed control flag is notified on, as JSON
+const ControlFlagsChannel = "control_flags"
+
 type ControlFlag struct {
 	Key     string `json:"key"`
 	Enabled bool   `json:"enabled"`
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/vultisig/verifier/plugin/safety"
 	"github.com/vultisig/verifier/types"
 )
 
@@ -15,3 +16,8 @@ type PolicyFetcher interface {
 type SafetyManager interface {
 	EnforceKeysign(ctx context.Context, pluginID string) error
 }
+
+// SafetyListener calls fn with every control flag changed until ctx is done, safety_pg.Repo implements it
+type SafetyListener interface {
+	Listen(ctx context.Context, fn func(safety.ControlFlag)) error
+}
@@ -80,6 +80,33 @@ func (r *Repo) GetPending(ctx context.Context) ([]scheduler.Scheduler, error) {
 	return schs, nil
 }
 
+func (r *Repo) GetAll(ctx context.Context) ([]scheduler.Scheduler, error) {
+	rows, err := r.tx.Pool().Query(ctx, `
+		SELECT policy_id, next_execution
+		FROM scheduler
+		ORDER BY next_execution
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query scheduler entries: %w", err)
+	}
+	defer rows.Close()
+
+	var schs []scheduler.Scheduler
+	for rows.Next() {
+		var sch scheduler.Scheduler
+		if err := rows.Scan(&sch.PolicyID, &sch.NextExecution); err != nil {
+			return nil, fmt.Errorf("failed to scan scheduler entry: %w", err)
+		}
+		schs = append(schs, sch)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over scheduler entries: %w", err)
+	}
+
+	return schs, nil
+}
+
 func (r *Repo) SetNext(ctx context.Context, policyID uuid.UUID, next time.Time) error {
 	_, err := r.tx.Try(ctx).Exec(ctx, `
 		UPDATE scheduler
@@ -19,5 +19,6 @@ type Storage interface {
 	Create(ctx context.Context, policyID uuid.UUID, next time.Time) error
 	Delete(ctx context.Context, policyID uuid.UUID) error
 	GetPending(ctx context.Context) ([]Scheduler, error)
+	GetAll(ctx context.Context) ([]Scheduler, error)
 	SetNext(ctx context.Context, policyID uuid.UUID, next time.Time) error
 }
@@ -16,6 +16,9 @@ import (
 	"github.com/vultisig/verifier/types"
 )
 
+// deactivateConcurrency bounds the schedules checked at once when a control flag is disabled
+const deactivateConcurrency = 10
+
 type Worker struct {
 	logger  *logrus.Logger
 	metrics metrics.SchedulerMetrics
@@ -28,6 +31,8 @@ type Worker struct {
 	interval Interval
 	policy   PolicyFetcher
 	safety   SafetyManager
+	// safetyListener notifies the control flags changed on the plugin server
+	safetyListener SafetyListener
 
 	pollInterval     time.Duration
 	iterationTimeout time.Duration
@@ -59,6 +64,12 @@ func NewWorker(
 	}
 }
 
+// SetSafetyListener makes the worker deactivate the policies of a paused plugin as soon as a flag is disabled,
+// instead of on their next execution. Pass the safety_pg.Repo of the plugin server.
+func (w *Worker) SetSafetyListener(listener SafetyListener) {
+	w.safetyListener = listener
+}
+
 func (w *Worker) Run() error {
 	ctx, stop := context.WithCancel(context.Background())
 
@@ -80,6 +91,11 @@ func (w *Worker) start(aliveCtx context.Context) error {
 		return fmt.Errorf("failed to enqueue: %w", err)
 	}
 
+	var safetyEvents <-chan safety.ControlFlag
+	if w.safetyListener != nil {
+		safetyEvents = w.listenSafety(aliveCtx)
+	}
+
 	for {
 		select {
 		case <-aliveCtx.Done():
@@ -90,10 +106,49 @@ func (w *Worker) start(aliveCtx context.Context) error {
 			if er != nil {
 				w.logger.Errorf("processing error, continue loop: %v", er)
 			}
+		case flag, ok := <-safetyEvents:
+			if !ok {
+				// a nil channel is never selected
+				safetyEvents = nil
+				continue
+			}
+			if flag.Enabled {
+				continue
+			}
+			w.logger.WithField("key", flag.Key).Info("control flag disabled, checking schedules")
+			er := w.deactivatePaused()
+			if er != nil {
+				w.logger.Errorf("failed to deactivate paused policies: %v", er)
+			}
 		}
 	}
 }
 
+// listenSafety feeds the control flag changes to the worker loop, listening again after a failure.
+// The channel is closed once ctx is done.
+func (w *Worker) listenSafety(ctx context.Context) <-chan safety.ControlFlag {
+	events := make(chan safety.ControlFlag, 16)
+	go func() {
+		defer close(events)
+		for ctx.Err() == nil {
+			err := w.safetyListener.Listen(ctx, func(flag safety.ControlFlag) {
+				select {
+				case events <- flag:
+				case <-ctx.Done():
+				}
+			})
+			if err != nil {
+				w.logger.Errorf("failed to listen to control flags, retrying: %v", err)
+			}
+			select {
+			case <-ctx.Done():
+			case <-time.After(w.pollInterval):
+			}
+		}
+	}()
+	return events
+}
+
 func (w *Worker) enqueue() error {
 	ctx, cancel := context.WithTimeout(context.Background(), w.iterationTimeout)
 	defer cancel()
@@ -117,29 +172,12 @@ func (w *Worker) enqueue() error {
 				return fmt.Errorf("failed to fetch policy: %w", err)
 			}
 
-			if w.safety != nil {
-				err = w.safety.EnforceKeysign(ctx, string(policy.PluginID))
-				if err != nil {
-					if safety.IsDisabledError(err) {
-						w.logger.WithFields(logrus.Fields{
-							"plugin_id": policy.PluginID,
-							"id":        policy.ID,
-						}).Info("deactivating policy: plugin is paused")
-						policy.Deactivate(types.DeactivationReasonPluginPause)
-						_, err = w.policy.UpdatePluginPolicy(ctx, *policy)
-						if err != nil {
-							return fmt.Errorf("failed to deactivate policy: %w", err)
-						}
-						err = w.repo.Delete(ctx, task.PolicyID)
-						if err != nil {
-							return fmt.Errorf("failed to delete schedule: %w", err)
-						}
-						return nil
-					}
-					w.logger.WithField("plugin_id", policy.PluginID).
-						Errorf("failed to check safety: %v", err)
-					return fmt.Errorf("safety check failed: %w", err)
-				}
+			paused, err := w.deactivateIfPaused(ctx, task, policy)
+			if err != nil {
+				return err
+			}
+			if paused {
+				return nil
 			}
 
 			next, err := w.interval.FromNowWhenNext(*policy)
@@ -193,6 +231,72 @@ func (w *Worker) enqueue() error {
 	return nil
 }
 
+// deactivatePaused deactivates the scheduled policies of paused plugins, whether they are due or not
+func (w *Worker) deactivatePaused() error {
+	if w.safety == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), w.iterationTimeout)
+	defer cancel()
+
+	tasks, err := w.repo.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get schedules: %w", err)
+	}
+
+	var eg errgroup.Group
+	eg.SetLimit(deactivateConcurrency)
+	for _, _task := range tasks {
+		task := _task
+		eg.Go(func() error {
+			policy, err := w.policy.GetPluginPolicy(ctx, task.PolicyID)
+			if err != nil {
+				return fmt.Errorf("failed to fetch policy: %w", err)
+			}
+			_, err = w.deactivateIfPaused(ctx, task, policy)
+			return err
+		})
+	}
+	err = eg.Wait()
+	if err != nil {
+		return fmt.Errorf("failed to process schedules: %w", err)
+	}
+	return nil
+}
+
+// deactivateIfPaused deactivates the policy and deletes its schedule when its plugin is paused
+func (w *Worker) deactivateIfPaused(ctx context.Context, task Scheduler, policy *types.PluginPolicy) (bool, error) {
+	if w.safety == nil {
+		return false, nil
+	}
+
+	err := w.safety.EnforceKeysign(ctx, string(policy.PluginID))
+	if err == nil {
+		return false, nil
+	}
+	if !safety.IsDisabledError(err) {
+		w.logger.WithField("plugin_id", policy.PluginID).
+			Errorf("failed to check safety: %v", err)
+		return false, fmt.Errorf("safety check failed: %w", err)
+	}
+
+	w.logger.WithFields(logrus.Fields{
+		"plugin_id": policy.PluginID,
+		"id":        policy.ID,
+	}).Info("deactivating policy: plugin is paused")
+	policy.Deactivate(types.DeactivationReasonPluginPause)
+	_, err = w.policy.UpdatePluginPolicy(ctx, *policy)
+	if err != nil {
+		return false, fmt.Errorf("failed to deactivate policy: %w", err)
+	}
+	err = w.repo.Delete(ctx, task.PolicyID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete schedule: %w", err)
+	}
+	return true, nil
+}
+
 func (w *Worker) collectMetrics(tasks []Scheduler) {
 	if w.metrics == nil {
 		return
@@ -0,0 +1,127 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vultisig/verifier/plugin/safety"
+	"github.com/vultisig/verifier/plugin/safety/safety_pg"
+	"github.com/vultisig/verifier/plugin/storage"
+	"github.com/vultisig/verifier/types"
+)
+
+var _ SafetyListener = (*safety_pg.Repo)(nil)
+
+type fakeStorage struct {
+	mu        sync.Mutex
+	schedules []Scheduler
+	deleted   []uuid.UUID
+}
+
+func (s *fakeStorage) Tx() storage.Tx { return nil }
+
+func (s *fakeStorage) GetByPolicy(_ context.Context, policyID uuid.UUID) (Scheduler, error) {
+	return Scheduler{PolicyID: policyID}, nil
+}
+
+func (s *fakeStorage) Create(context.Context, uuid.UUID, time.Time) error { return nil }
+
+func (s *fakeStorage) Delete(_ context.Context, policyID uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deleted = append(s.deleted, policyID)
+	return nil
+}
+
+// GetPending returns nothing, so that only the control flag events deactivate the policies
+func (s *fakeStorage) GetPending(context.Context) ([]Scheduler, error) { return nil, nil }
+
+func (s *fakeStorage) GetAll(context.Context) ([]Scheduler, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Scheduler(nil), s.schedules...), nil
+}
+
+func (s *fakeStorage) SetNext(context.Context, uuid.UUID, time.Time) error { return nil }
+
+func (s *fakeStorage) deletedIDs() []uuid.UUID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]uuid.UUID(nil), s.deleted...)
+}
+
+type fakePolicies struct {
+	mu      sync.Mutex
+	updated []types.PluginPolicy
+}
+
+func (p *fakePolicies) GetPluginPolicy(_ context.Context, id uuid.UUID) (*types.PluginPolicy, error) {
+	return &types.PluginPolicy{ID: id, PluginID: types.PluginVultisigDCA_0000, Active: true}, nil
+}
+
+func (p *fakePolicies) UpdatePluginPolicy(_ context.Context, policy types.PluginPolicy) (*types.PluginPolicy, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.updated = append(p.updated, policy)
+	return &policy, nil
+}
+
+type pausedSafety struct{}
+
+func (pausedSafety) EnforceKeysign(context.Context, string) error {
+	return safety.ErrPluginDisabled
+}
+
+// flakyListener notifies a disabled flag then loses its connection, every time it is called
+type flakyListener struct {
+	calls atomic.Int32
+}
+
+func (l *flakyListener) Listen(_ context.Context, fn func(safety.ControlFlag)) error {
+	l.calls.Add(1)
+	fn(safety.ControlFlag{Key: "plugin-paused", Enabled: false})
+	return errors.New("connection lost")
+}
+
+func TestWorker_SafetyEvents(t *testing.T) {
+	policyID := uuid.New()
+	repo := &fakeStorage{schedules: []Scheduler{{PolicyID: policyID, NextExecution: time.Now().Add(time.Hour)}}}
+	policies := &fakePolicies{}
+	listener := &flakyListener{}
+
+	w := NewWorker(logrus.New(), nil, "task", "queue", repo, nil, policies, nil, pausedSafety{})
+	w.pollInterval = 10 * time.Millisecond
+	w.SetSafetyListener(listener)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- w.start(ctx)
+	}()
+
+	// the policy is deactivated on the event, although it isn't due, and the listener is called again after a failure
+	require.Eventually(t, func() bool {
+		return len(repo.deletedIDs()) > 0 && listener.calls.Load() >= 2
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, policyID, repo.deletedIDs()[0])
+	policies.mu.Lock()
+	require.False(t, policies.updated[0].Active)
+	require.Equal(t, types.DeactivationReasonPluginPause, *policies.updated[0].DeactivationReason)
+	policies.mu.Unlock()
+
+	cancel()
+	select {
+	case err := <-stopped:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("worker didn't stop")
+	}
+}
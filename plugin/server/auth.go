@@ -1,25 +1,77 @@
 package server
 
 import (
+	"crypto/ed25519"
 	"crypto/subtle"
+	"errors"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
+
+	"github.com/vultisig/verifier/plugin/reqsign"
 )
 
 type Auth struct {
-	token []byte
+	token        []byte
+	verifierKeys reqsign.KeyFunc
+	replay       reqsign.ReplayCache
+	now          func() time.Time
 }
 
 func NewAuth(token string) *Auth {
 	return &Auth{
-		token: []byte(token),
+		token:  []byte(token),
+		replay: reqsign.NewMemoryCache(),
+		now:    time.Now,
 	}
 }
 
+// SetReplayCache shares the signatures already received between the instances of the plugin server,
+// pass its redis when it runs more than one instance
+func (a *Auth) SetReplayCache(cache reqsign.ReplayCache) {
+	a.replay = cache
+}
+
+// SetVerifierKeys makes the middleware accept the requests signed by one of the verifier keys.
+// List the current and the next key while the verifier rotates its signing key.
+func (a *Auth) SetVerifierKeys(keys ...ed25519.PublicKey) {
+	a.verifierKeys = reqsign.StaticKeys(keys...)
+}
+
+// Middleware accepts the requests signed by the verifier, or the requests with the bearer token when a token is set
 func (a *Auth) Middleware(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
+		if a.verifierKeys != nil && reqsign.Signed(c.Request()) {
+			body, err := reqsign.ReadBody(c.Request())
+			if errors.Is(err, reqsign.ErrBodyTooLarge) {
+				return echo.NewHTTPError(http.StatusRequestEntityTooLarge, "request body too large")
+			}
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "failed to read request body")
+			}
+			_, err = reqsign.Verify(c.Request(), body, a.verifierKeys, a.now(), reqsign.DefaultWindow)
+			if err != nil {
+				if errors.Is(err, reqsign.ErrStaleRequest) {
+					return echo.NewHTTPError(http.StatusUnauthorized, "request expired")
+				}
+				return echo.NewHTTPError(http.StatusUnauthorized, "invalid request signature")
+			}
+			err = reqsign.CheckReplay(c.Request().Context(), a.replay, c.Request(), reqsign.DefaultWindow)
+			if err != nil {
+				if errors.Is(err, reqsign.ErrReplayedRequest) {
+					return echo.NewHTTPError(http.StatusUnauthorized, "request already received")
+				}
+				return echo.NewHTTPError(http.StatusInternalServerError, "failed to check request replay")
+			}
+			return next(c)
+		}
+
+		if len(a.token) == 0 {
+			return echo.NewHTTPError(http.StatusUnauthorized, "missing request signature")
+		}
+
 		authHeader := c.Request().Header.Get(echo.HeaderAuthorization)
 		if authHeader == "" {
 			return echo.NewHTTPError(http.StatusUnauthorized, "missing authorization header")
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"net/http"
 	"time"
 
 	"github.com/labstack/echo/v4"
@@ -64,3 +65,34 @@ func (s *Server) VerifierAuthMiddleware(next echo.HandlerFunc) echo.HandlerFunc
 	}
 	return s.authMiddleware(next)
 }
+
+// DeliveryIDHeader identifies a policy sync sent by the verifier, a retried delivery carries the same ID
+const DeliveryIDHeader = "X-Delivery-ID"
+
+// deliveryRetention is how long an applied delivery is remembered, it outlasts the verifier retries
+const deliveryRetention = 24 * time.Hour
+
+// DeliveryDedupMiddleware answers a policy sync that was already applied without applying it again
+func (s *Server) DeliveryDedupMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		deliveryID := c.Request().Header.Get(DeliveryIDHeader)
+		if deliveryID == "" || s.redis == nil {
+			return next(c)
+		}
+
+		ctx := c.Request().Context()
+		key := "policy-sync:" + deliveryID
+		applied, err := s.redis.Get(ctx, key)
+		if err == nil && applied != "" {
+			return c.JSON(http.StatusOK, map[string]string{"delivery_id": deliveryID})
+		}
+
+		err = next(c)
+		if err == nil && c.Response().Status == http.StatusOK {
+			if err := s.redis.Set(ctx, key, deliveryID, deliveryRetention); err != nil {
+				s.logger.WithError(err).WithField("delivery_id", deliveryID).Warn("failed to remember policy sync delivery")
+			}
+		}
+		return err
+	}
+}
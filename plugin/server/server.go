@@ -123,11 +123,11 @@ func (s *Server) GetRouter() *echo.Echo {
 	vlt.DELETE("/:pluginId/:publicKeyECDSA", s.handleDeleteVault, s.VerifierAuthMiddleware)
 
 	plg := e.Group("/plugin")
-	plg.POST("/policy", s.handleCreatePluginPolicy, s.VerifierAuthMiddleware)
-	plg.PUT("/policy", s.handleUpdatePluginPolicyById, s.VerifierAuthMiddleware)
+	plg.POST("/policy", s.handleCreatePluginPolicy, s.VerifierAuthMiddleware, s.DeliveryDedupMiddleware)
+	plg.PUT("/policy", s.handleUpdatePluginPolicyById, s.VerifierAuthMiddleware, s.DeliveryDedupMiddleware)
 	plg.GET("/recipe-specification", s.handleGetRecipeSpecification)
 	plg.POST("/recipe-specification/suggest", s.handleGetRecipeSpecificationSuggest)
-	plg.DELETE("/policy/:policyId", s.handleDeletePluginPolicyById, s.VerifierAuthMiddleware)
+	plg.DELETE("/policy/:policyId", s.handleDeletePluginPolicyById, s.VerifierAuthMiddleware, s.DeliveryDedupMiddleware)
 	plg.PUT("/safety", s.handleSyncSafety, s.VerifierAuthMiddleware)
 
 	if handler, ok := s.spec.(plugin.BuildTxHandler); ok {
@@ -13,8 +13,20 @@ const (
 	TypePluginTransaction  = "plugin:transaction"
 	TypeKeyGenerationDKLS  = "key:generationDKLS"
 	TypeKeySignDKLS        = "key:signDKLS"
+	TypePresignDKLS        = "key:presignDKLS"
 	TypeReshareDKLS        = "key:reshareDKLS"
 	TypePolicyDeactivate   = "policy:deactivate"
+	TypeVaultUninstall     = "vault:uninstall"
+	TypeMonthlyInvoices    = "fee:monthlyInvoices"
+	TypeSubscriptionRefund = "fee:subscriptionRefund"
+	TypeFeeDunning         = "fee:dunning"
+	TypeLedgerReconcile    = "fee:reconcile"
+	TypeDeveloperPayouts   = "fee:developerPayouts"
+	TypeAnomalyDetection   = "safety:anomalyDetection"
+	TypeControlFlagOutbox  = "safety:controlFlagOutbox"
+	TypePolicySync         = "policy:sync"
+	TypePluginHealthCheck  = "plugin:healthCheck"
+	TypeKeysignResultPurge = "key:purgeKeysignResults"
 )
 
 func GetTaskResult(inspector *asynq.Inspector, taskID string) ([]byte, error) {
@@ -15,6 +15,17 @@ type Config struct {
 	MarkLostAfter    time.Duration     `mapstructure:"mark_lost_after" json:"mark_lost_after,omitempty"`
 	Concurrency      int               `mapstructure:"concurrency" json:"concurrency,omitempty"`
 	Metrics          MetricsConfig     `mapstructure:"metrics" json:"metrics,omitempty"`
+	// FeePrices are USD prices of tokens, used for percentage fees on top of the fee assets
+	FeePrices []FeePriceConfig `mapstructure:"fee_prices" json:"fee_prices,omitempty"`
+	// RefundRecheckWindow is how long billed txs are checked again for failures and reorgs, 0 disables it
+	RefundRecheckWindow time.Duration `mapstructure:"refund_recheck_window" json:"refund_recheck_window,omitempty"`
+}
+
+type FeePriceConfig struct {
+	Chain    string `mapstructure:"chain" json:"chain"`
+	Token    string `mapstructure:"token" json:"token"` // empty for the native token
+	Decimals uint8  `mapstructure:"decimals" json:"decimals"`
+	USD      string `mapstructure:"usd" json:"usd"`
 }
 
 type DatabaseConfig struct {
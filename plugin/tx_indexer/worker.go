@@ -200,6 +200,47 @@ func (w *Worker) UpdateTxStatus(ctx context.Context, tx storage.Tx) (*rpc.TxOnCh
 	return &result.Status, nil
 }
 
+// RecheckTxStatus queries the status of a tx that already left the pending state,
+// to catch late failures and reorgs. The stored status is updated when the tx failed,
+// a tx missing on chain is only reported, so that it doesn't go back to the pending txs.
+func (w *Worker) RecheckTxStatus(ctx context.Context, tx storage.Tx) (rpc.TxOnChainStatus, error) {
+	if tx.TxHash == nil {
+		return "", errors.New("unexpected tx.TxHash == nil, tx_id=" + tx.ID.String())
+	}
+	if tx.StatusOnChain == nil {
+		return "", errors.New("unexpected tx.StatusOnChain == nil, tx_id=" + tx.ID.String())
+	}
+
+	chain := common.Chain(tx.ChainID)
+	client, ok := w.clients[chain]
+	if !ok {
+		return *tx.StatusOnChain, nil
+	}
+
+	result, err := client.GetTxStatus(ctx, *tx.TxHash)
+	if err != nil {
+		w.metrics.RecordRPCError(chain)
+		return "", fmt.Errorf("client.GetTxStatus: %w", err)
+	}
+	if result.Status == *tx.StatusOnChain || result.Status == rpc.TxOnChainPending {
+		return result.Status, nil
+	}
+
+	var errorMsg *string
+	if result.Status == rpc.TxOnChainFail && result.ErrorMessage != "" {
+		errorMsg = &result.ErrorMessage
+	}
+	err = w.repo.SetOnChainStatus(ctx, tx.ID, result.Status, errorMsg)
+	if err != nil {
+		w.metrics.RecordProcessingError(chain, "set_status")
+		return "", fmt.Errorf("w.repo.SetOnChainStatus: %w", err)
+	}
+
+	w.metrics.RecordTransactionStatus(chain, string(result.Status))
+	w.logger.WithFields(tx.Fields()).Warnf("status changed on recheck, oldStatus=%s, newStatus=%s", *tx.StatusOnChain, result.Status)
+	return result.Status, nil
+}
+
 func (w *Worker) updatePendingTxs() error {
 	ctx, cancel := context.WithTimeout(context.Background(), w.iterationTimeout)
 	defer cancel()
@@ -86,9 +86,12 @@ func (s *Seeder) SeedDatabase(ctx context.Context) error {
 
 		apiKey := fmt.Sprintf("integration-test-apikey-%s", plugin.ID)
 		_, err = tx.Exec(ctx, `
-			INSERT INTO plugin_apikey (id, plugin_id, apikey, created_at, expires_at, status)
-			VALUES (gen_random_uuid(), $1, $2, NOW(), NULL, 1)
-			ON CONFLICT DO NOTHING
+			INSERT INTO plugin_apikey (id, plugin_id, key_prefix, key_salt, key_hash, scopes, created_at, expires_at, status)
+			SELECT gen_random_uuid(), $1, LEFT($2::text, 12), salt,
+				encode(sha256(decode(salt, 'hex') || convert_to($2::text, 'UTF8')), 'hex'),
+				ARRAY['sign', 'fees:read', 'fees:collect'], NOW(), NULL, 1
+			FROM (SELECT md5(random()::text || $2::text) AS salt) AS salts
+			WHERE NOT EXISTS (SELECT 1 FROM plugin_apikey WHERE plugin_id = $1)
 		`, plugin.ID, apiKey)
 		if err != nil {
 			tx.Rollback(ctx)
@@ -206,12 +206,9 @@ func TestApiKeyLimitAfterExpiry(t *testing.T) {
 	require.NoError(t, err)
 
 	_, err = pool.Exec(ctx, `
-		INSERT INTO plugin_apikey (plugin_id, apikey, status, expires_at)
-		VALUES ($1, 'expired-key-1', 1, NOW() - INTERVAL '1 hour'),
-		       ($1, 'expired-key-2', 1, NOW() - INTERVAL '1 hour'),
-		       ($1, 'expired-key-3', 1, NOW() - INTERVAL '1 hour'),
-		       ($1, 'expired-key-4', 1, NOW() - INTERVAL '1 hour'),
-		       ($1, 'expired-key-5', 1, NOW() - INTERVAL '1 hour')
+		INSERT INTO plugin_apikey (plugin_id, key_prefix, key_salt, key_hash, scopes, status, expires_at)
+		SELECT $1, 'expired-key', 'salt-' || n, 'hash-' || n, ARRAY['sign'], 1, NOW() - INTERVAL '1 hour'
+		FROM generate_series(1, 5) AS n
 	`, testPluginID)
 	require.NoError(t, err)
 
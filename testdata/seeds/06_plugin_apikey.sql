@@ -1,26 +1,18 @@
-INSERT INTO plugin_apikey (id, plugin_id, apikey, created_at, expires_at, status) VALUES
-(
+INSERT INTO plugin_apikey (id, plugin_id, key_prefix, key_salt, key_hash, scopes, created_at, expires_at, status)
+SELECT
  gen_random_uuid(),
- 'vultisig-payroll-0000',
- 'localhost-apikey',
+ keys.plugin_id,
+ LEFT(keys.apikey, 12),
+ salts.salt,
+ encode(sha256(decode(salts.salt, 'hex') || convert_to(keys.apikey, 'UTF8')), 'hex'),
+ ARRAY['sign', 'fees:read', 'fees:collect'],
  now(),
  null,
  1
-),
-(
-    gen_random_uuid(),
-    'vultisig-copytrader-0000',
-    'localhost-apikey-copytrading',
-    now(),
-    null,
-    1
-),
-(
- gen_random_uuid(),
- 'vultisig-fees-feee',
- 'localhost-fee-apikey',
- now(),
- null,
- 1
-) on conflict do nothing;
-
+FROM (VALUES
+ ('vultisig-payroll-0000', 'localhost-apikey'),
+ ('vultisig-copytrader-0000', 'localhost-apikey-copytrading'),
+ ('vultisig-fees-feee', 'localhost-fee-apikey')
+) AS keys(plugin_id, apikey)
+CROSS JOIN LATERAL (SELECT md5(random()::text || keys.apikey) AS salt) AS salts
+WHERE NOT EXISTS (SELECT 1 FROM plugin_apikey WHERE plugin_apikey.plugin_id = keys.plugin_id);
@@ -0,0 +1,85 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	vgcommon "github.com/vultisig/vultisig-go/common"
+)
+
+// Asset describes a token fees can be billed and collected in
+type Asset struct {
+	ID       PricingAsset
+	Chain    vgcommon.Chain
+	Token    string // token contract address on Chain
+	Symbol   string
+	Decimals uint8
+}
+
+// feeAssets are the assets plugins can price in, the fee policy allows a treasury transfer for each of them.
+// Adding an asset also needs a value in the pricing_asset enum.
+var feeAssets = map[PricingAsset]Asset{
+	PricingAssetUSDC: {
+		ID:       PricingAssetUSDC,
+		Chain:    vgcommon.Ethereum,
+		Token:    "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48",
+		Symbol:   "USDC",
+		Decimals: 6,
+	},
+	PricingAssetUSDT: {
+		ID:       PricingAssetUSDT,
+		Chain:    vgcommon.Ethereum,
+		Token:    "0xdAC17F958D2ee523a2206206994597C13D831ec7",
+		Symbol:   "USDT",
+		Decimals: 6,
+	},
+	PricingAssetUSDCArbitrum: {
+		ID:       PricingAssetUSDCArbitrum,
+		Chain:    vgcommon.Arbitrum,
+		Token:    "0xaf88d065e77c8cC2239327C5EDb3A432268e5831",
+		Symbol:   "USDC",
+		Decimals: 6,
+	},
+}
+
+// DefaultFeeAsset is the asset of fees and pricings that don't set one
+func DefaultFeeAsset() Asset {
+	return feeAssets[PricingAssetUSDC]
+}
+
+// GetFeeAsset returns the registered asset with the given ID
+func GetFeeAsset(id PricingAsset) (Asset, error) {
+	asset, ok := feeAssets[id]
+	if !ok {
+		return Asset{}, fmt.Errorf("unsupported fee asset: %s", id)
+	}
+	return asset, nil
+}
+
+// FeeAssets returns every registered asset, sorted by ID
+func FeeAssets() []Asset {
+	assets := make([]Asset, 0, len(feeAssets))
+	for _, asset := range feeAssets {
+		assets = append(assets, asset)
+	}
+	sort.Slice(assets, func(i, j int) bool {
+		return assets[i].ID < assets[j].ID
+	})
+	return assets
+}
+
+// FindFeeAsset returns the registered asset of the token on the chain
+func FindFeeAsset(chain vgcommon.Chain, token string) (Asset, error) {
+	for _, asset := range feeAssets {
+		if asset.Chain == chain && strings.EqualFold(asset.Token, token) {
+			return asset, nil
+		}
+	}
+	return Asset{}, fmt.Errorf("unsupported fee asset: %s on %s", token, chain.String())
+}
+
+func (a PricingAsset) IsValid() bool {
+	_, ok := feeAssets[a]
+	return ok
+}
@@ -2,6 +2,7 @@ package types
 
 import (
 	"encoding/json"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
@@ -21,8 +22,43 @@ const (
 	FeeTxExecFee           = "transaction_execution_fee"
 	FeeTypeBatch           = "batch"
 	FeeTypeBatchFailed     = "batch_failed"
+	FeeTypeRefund          = "refund"
 )
 
+var ErrFeeNotRefundable = errors.New("fee is not refundable")
+
+// RefundReason is the reason code of a refund
+type RefundReason string
+
+const (
+	RefundReasonTxFailed        RefundReason = "tx_failed"        // billed tx failed on chain after it was marked successful
+	RefundReasonTxReorged       RefundReason = "tx_reorged"       // billed tx is no longer on chain
+	RefundReasonNoExecutions    RefundReason = "no_executions"    // subscription period without a successful execution
+	RefundReasonServiceIssue    RefundReason = "service_issue"    // the plugin or the verifier misbehaved
+	RefundReasonDuplicateCharge RefundReason = "duplicate_charge" // the same service was billed twice
+	RefundReasonGoodwill        RefundReason = "goodwill"
+)
+
+func (r RefundReason) IsValid() bool {
+	switch r {
+	case RefundReasonTxFailed, RefundReasonTxReorged, RefundReasonNoExecutions,
+		RefundReasonServiceIssue, RefundReasonDuplicateCharge, RefundReasonGoodwill:
+		return true
+	}
+	return false
+}
+
+// Refund credits back a debit fee, a debit is refunded at most once
+type Refund struct {
+	DebitFeeID uint64
+	Amount     uint64 // 0 refunds the whole debit
+	Reason     RefundReason
+	Note       string
+	TxHash     string
+	Network    string
+	IssuedBy   string // public key of the admin, empty for automatic refunds
+}
+
 // FeeStatus represents the collection status of a fee
 type FeeStatus string
 
@@ -31,23 +67,36 @@ const (
 	FeeStatusProcessing FeeStatus = "PROCESSING" // Fee in batch with status BATCHED or SIGNED
 	FeeStatusCollected  FeeStatus = "COLLECTED"  // Fee in batch with status COMPLETED
 	FeeStatusFailed     FeeStatus = "FAILED"     // Fee in batch with status FAILED
+	FeeStatusRefunded   FeeStatus = "REFUNDED"   // Fee credited back by a refund
 )
 
 type CreditMetadata struct {
-	DebitFeeID uint64 `json:"debit_fee_id"` // ID of the debit transaction
-	TxHash     string `json:"tx_hash"`      // Transaction hash in blockchain
-	Network    string `json:"network"`      // Blockchain network (e.g., "ethereum", "polygon")
+	DebitFeeID uint64       `json:"debit_fee_id"`        // ID of the debit transaction
+	TxHash     string       `json:"tx_hash"`             // Transaction hash in blockchain
+	Network    string       `json:"network"`             // Blockchain network (e.g., "ethereum", "polygon")
+	Reason     RefundReason `json:"reason,omitempty"`    // Set on refunds
+	Note       string       `json:"note,omitempty"`      // Free text explaining a refund
+	IssuedBy   string       `json:"issued_by,omitempty"` // Admin that issued a manual refund
+}
+
+// AssetBalance is the balance of a user in a single fee asset
+type AssetBalance struct {
+	Asset        PricingAsset `json:"asset"`
+	Balance      int64        `json:"balance"` // Current balance (can be negative)
+	UnpaidAmount int64        `json:"unpaid_amount"`
 }
 
 // UserFeeStatus represents the fee status and balance for a user
 type UserFeeStatus struct {
-	PublicKey      string        `json:"public_key"`
-	Balance        int64         `json:"balance"` // Current balance (can be negative)
-	UnpaidAmount   int64         `json:"unpaid_amount"`
-	IsTrialActive  bool          `json:"is_trial_active"`
-	TrialRemaining time.Duration `json:"trial_remaining"`
-	Message        string        `json:"message"`
-	Fees           []*Fee        `json:"fees"`
+	PublicKey string `json:"public_key"`
+	// Balance and UnpaidAmount are in the default asset (usdc), Balances has every asset the user was billed in
+	Balance        int64          `json:"balance"` // Current balance (can be negative)
+	UnpaidAmount   int64          `json:"unpaid_amount"`
+	Balances       []AssetBalance `json:"balances"`
+	IsTrialActive  bool           `json:"is_trial_active"`
+	TrialRemaining time.Duration  `json:"trial_remaining"`
+	Message        string         `json:"message"`
+	Fees           []*Fee         `json:"fees"`
 }
 
 type Fee struct {
@@ -56,10 +105,21 @@ type Fee struct {
 	PluginID       string          `json:"plugin_id"`  // The plugin ID that generated this fee
 	PublicKey      string          `json:"public_key"` // The public key "account" connected to the fee
 	TxType         TxType          `json:"transaction_type"`
-	Amount         uint64          `json:"amount"` // The amount of the fee in the smallest unit, e.g., "1000000" for 0.01 VULTI
+	Amount         uint64          `json:"amount"` // The amount of the fee in the smallest unit of Asset, e.g., "1000000" for 1 USDC
+	Asset          PricingAsset    `json:"asset"`
 	CreatedAt      time.Time       `json:"created_at"`
 	FeeType        string          `json:"fee_type"`
 	Metadata       json.RawMessage `json:"metadata"`
 	UnderlyingType string          `json:"underlying_type"`
 	UnderlyingID   string          `json:"underlying_id"`
 }
+
+// HasUnpaid reports whether the user owes fees in any asset
+func (s *UserFeeStatus) HasUnpaid() bool {
+	for _, b := range s.Balances {
+		if b.UnpaidAmount > 0 {
+			return true
+		}
+	}
+	return s.UnpaidAmount > 0
+}
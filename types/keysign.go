@@ -29,12 +29,22 @@ type KeysignRequest struct {
 }
 
 type KeysignMessage struct {
-	TxIndexerID  string         `json:"tx_indexer_id"` // Tx indexer uuid
-	RawMessage   string         `json:"raw_message"`   // Raw message, used to decode the transaction
-	Message      string         `json:"message"`
-	Hash         string         `json:"hash"`
-	HashFunction HashFunction   `json:"hash_function"`
-	Chain        vgcommon.Chain `json:"chain"`
+	TxIndexerID     string          `json:"tx_indexer_id"` // Tx indexer uuid
+	RawMessage      string          `json:"raw_message"`   // Raw message, used to decode the transaction
+	Message         string          `json:"message"`
+	Hash            string          `json:"hash"`
+	HashFunction    HashFunction    `json:"hash_function"`
+	Chain           vgcommon.Chain  `json:"chain"`
+	SignatureScheme SignatureScheme `json:"signature_scheme,omitempty"` // empty means the chain default
+	PresignID       string          `json:"presign_id,omitempty"`       // presignature to finish the signature with, ecdsa only
+}
+
+// GetSignatureScheme returns the scheme the message has to be signed with, falling back to the chain default
+func (m KeysignMessage) GetSignatureScheme() SignatureScheme {
+	if m.SignatureScheme == "" {
+		return DefaultSignatureScheme(m.Chain)
+	}
+	return m.SignatureScheme
 }
 
 // IsValid checks if the keysign request is valid
@@ -50,6 +60,12 @@ func (r KeysignRequest) IsValid() error {
 		if err != nil {
 			return errors.New("message is not base64 encoded")
 		}
+		if err := m.GetSignatureScheme().ValidateForChain(m.Chain); err != nil {
+			return fmt.Errorf("invalid signature scheme: %w", err)
+		}
+		if m.PresignID != "" && m.GetSignatureScheme() != SignatureSchemeECDSA {
+			return errors.New("presignatures are only supported for ecdsa messages")
+		}
 	}
 	if r.SessionID == "" {
 		return errors.New("invalid session")
@@ -61,6 +77,15 @@ func (r KeysignRequest) IsValid() error {
 	return nil
 }
 
+// Chains returns the chains of the keysign messages
+func (r KeysignRequest) Chains() []vgcommon.Chain {
+	chains := make([]vgcommon.Chain, 0, len(r.Messages))
+	for _, m := range r.Messages {
+		chains = append(chains, m.Chain)
+	}
+	return chains
+}
+
 type PluginKeysignRequest struct {
 	KeysignRequest
 	Transaction     string `json:"transactions"`
@@ -0,0 +1,34 @@
+package types
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vultisig/mobile-tss-lib/tss"
+)
+
+type KeysignResultStatus string
+
+const (
+	KeysignResultQueued    KeysignResultStatus = "queued"
+	KeysignResultRunning   KeysignResultStatus = "running"
+	KeysignResultFailed    KeysignResultStatus = "failed"
+	KeysignResultCompleted KeysignResultStatus = "completed"
+)
+
+// KeysignResult tracks a plugin keysign by its tx indexer ID, beyond the asynq task retention.
+// Data is the encrypted signatures and is only set once completed.
+type KeysignResult struct {
+	TxIndexerID   uuid.UUID                      `json:"tx_indexer_id"`
+	SessionID     string                         `json:"session_id"`
+	TaskID        string                         `json:"task_id"`
+	PluginID      string                         `json:"plugin_id"`
+	PublicKey     string                         `json:"public_key"`
+	Status        KeysignResultStatus            `json:"status"`
+	FailureReason string                         `json:"failure_reason,omitempty"`
+	Signatures    map[string]tss.KeysignResponse `json:"signatures,omitempty"`
+	Data          []byte                         `json:"-"`
+	CreatedAt     time.Time                      `json:"created_at"`
+	UpdatedAt     time.Time                      `json:"updated_at"`
+	ExpiresAt     time.Time                      `json:"expires_at"`
+}
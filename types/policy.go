@@ -3,6 +3,8 @@ package types
 import (
 	"encoding/base64"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -26,6 +28,64 @@ type BillingPolicy struct {
 	StartDate time.Time         `json:"start_date"`                 // Number of a month, e.g., "1" for the first month. Only allow 1 for now
 	Amount    uint64            `json:"amount" validate:"required"` // Amount in the smallest unit, e.g., "1000000" for 0.01 VULTI
 	Asset     string            `json:"asset"`                      // The asset that the fee is denominated in, e.g., "usdc"
+	// Metric, MinAmount and MaxAmount are signed in the description of the fee policy, see parseFeeTerms.
+	// The asset may be signed there too, it is taken from the plugin pricing otherwise.
+	Metric    PricingMetric `json:"metric,omitempty"`
+	MinAmount *uint64       `json:"min_amount,omitempty"`
+	MaxAmount *uint64       `json:"max_amount,omitempty"`
+}
+
+// Fee policy recipes only sign the type, frequency and amount of a fee. The other terms of a fee are signed
+// in the description of its fee policy, e.g. "metric=percentage;min_amount=10000;max_amount=5000000;asset=usdc".
+const (
+	feeTermMetric    = "metric"
+	feeTermMinAmount = "min_amount"
+	feeTermMaxAmount = "max_amount"
+	feeTermAsset     = "asset"
+)
+
+type feeTerms struct {
+	metric    PricingMetric
+	minAmount *uint64
+	maxAmount *uint64
+	asset     string
+}
+
+// parseFeeTerms reads the signed terms of a fee policy, a description without a metric is free text and the fee is fixed
+func parseFeeTerms(description string) (feeTerms, error) {
+	if !strings.Contains(description, feeTermMetric+"=") {
+		return feeTerms{metric: PricingMetricFixed}, nil
+	}
+
+	var terms feeTerms
+	for _, term := range strings.Split(description, ";") {
+		key, value, ok := strings.Cut(strings.TrimSpace(term), "=")
+		if !ok {
+			return feeTerms{}, fmt.Errorf("invalid fee term: %q", term)
+		}
+		switch key {
+		case feeTermMetric:
+			terms.metric = PricingMetric(value)
+		case feeTermAsset:
+			terms.asset = value
+		case feeTermMinAmount, feeTermMaxAmount:
+			amount, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				return feeTerms{}, fmt.Errorf("invalid fee term %s: %w", key, err)
+			}
+			if key == feeTermMinAmount {
+				terms.minAmount = &amount
+			} else {
+				terms.maxAmount = &amount
+			}
+		default:
+			return feeTerms{}, fmt.Errorf("unknown fee term: %s", key)
+		}
+	}
+	if terms.metric != PricingMetricFixed && terms.metric != PricingMetricPercentage {
+		return feeTerms{}, fmt.Errorf("unsupported fee metric: %q", terms.metric)
+	}
+	return terms, nil
 }
 
 // DeactivationReason values for policy deactivation context
@@ -34,6 +94,9 @@ const (
 	DeactivationReasonPluginPause = "plugin_pause" // safety pause auto-disable
 	DeactivationReasonExpiry      = "expiry"       // expiry/TTL
 	DeactivationReasonCompleted   = "completed"    // no more executions
+	DeactivationReasonUnpaidFees  = "unpaid_fees"  // dunning suspension, reactivated once fees are paid
+	// DeactivationReasonPluginRejected is set when the plugin server never accepted the policy
+	DeactivationReasonPluginRejected = "plugin_rejected"
 )
 
 // This type should be used externally when creating or updating a plugin policy. It keeps the protobuf encoded billing recipe as a string which is used to verify a signature.
@@ -50,6 +113,12 @@ type PluginPolicy struct {
 	DeactivationReason *string         `json:"deactivation_reason,omitempty"` // nil when active; 'user', 'plugin_pause', 'expiry', 'completed'
 }
 
+// PolicyActivation is a period a policy was active, DeactivatedAt is nil while it still is
+type PolicyActivation struct {
+	ActivatedAt   time.Time
+	DeactivatedAt *time.Time
+}
+
 func (p *PluginPolicy) Deactivate(reason string) {
 	p.Active = false
 	p.DeactivationReason = &reason
@@ -79,8 +148,8 @@ func (p *PluginPolicy) GetRecipe() (*rtypes.Policy, error) {
 }
 
 // This is used to populate the Billing field of a PluginPolicy from the Recipe field. It does not validate this information against the plugin pricing.
+// The asset is only set when the fee policy signs it, otherwise it is taken from the plugin pricing the billing matches.
 func (p *PluginPolicy) ParseBillingFromRecipe() error {
-
 	p.Billing = []BillingPolicy{}
 
 	recipe, err := p.GetRecipe()
@@ -133,13 +202,21 @@ func (p *PluginPolicy) ParseBillingFromRecipe() error {
 			}
 		}
 
+		terms, err := parseFeeTerms(feePolicy.Description)
+		if err != nil {
+			return fmt.Errorf("failed to parse fee policy terms: %w", err)
+		}
+
 		p.Billing = append(p.Billing, BillingPolicy{
 			ID:        id,
 			Type:      feeType,
 			Frequency: pricingFrequency,
 			StartDate: feePolicy.StartDate.AsTime(),
 			Amount:    uint64(feePolicy.Amount),
-			Asset:     "usdc", // Multiple currencies not currently supported in fee policy recipes or elsewhere so for now we can hard code it, and later, extract from the protobuf encoded fee policies
+			Asset:     terms.asset,
+			Metric:    terms.metric,
+			MinAmount: terms.minAmount,
+			MaxAmount: terms.maxAmount,
 		})
 	}
 	return nil
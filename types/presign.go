@@ -0,0 +1,66 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	vgcommon "github.com/vultisig/vultisig-go/common"
+)
+
+const (
+	// MaxPresignsPerRequest bounds the number of presignatures generated in a single session
+	MaxPresignsPerRequest = 16
+	// MaxPresignPoolSize bounds the unused presignatures a plugin can keep per vault and derive path
+	MaxPresignPoolSize = 64
+)
+
+// ErrPresignPoolFull is returned when saving a presignature would exceed MaxPresignPoolSize
+var ErrPresignPoolFull = errors.New("presignature pool is full")
+
+// PresignRequest asks the parties to generate DKLS ECDSA presignatures for a vault ahead of keysign.
+// Presignatures are bound to the derive path of Chain, so they can only sign messages of chains
+// sharing that path.
+type PresignRequest struct {
+	PublicKey        string         `json:"public_key"` // public key ecdsa, used to identify the backup file
+	PluginID         string         `json:"plugin_id"`
+	SessionID        string         `json:"session"`
+	HexEncryptionKey string         `json:"hex_encryption_key"`
+	Parties          []string       `json:"parties"`
+	Chain            vgcommon.Chain `json:"chain"`
+	Count            int            `json:"count"`
+}
+
+func (r PresignRequest) IsValid() error {
+	if r.PublicKey == "" {
+		return fmt.Errorf("public_key is required")
+	}
+	if r.PluginID == "" {
+		return fmt.Errorf("plugin_id is required")
+	}
+	if r.SessionID == "" {
+		return fmt.Errorf("session is required")
+	}
+	if r.HexEncryptionKey == "" {
+		return fmt.Errorf("hex_encryption_key is required")
+	}
+	if DefaultSignatureScheme(r.Chain) != SignatureSchemeECDSA {
+		return fmt.Errorf("presignatures are only supported for ecdsa chains, got %s", r.Chain.String())
+	}
+	if r.Count < 1 || r.Count > MaxPresignsPerRequest {
+		return fmt.Errorf("count must be between 1 and %d", MaxPresignsPerRequest)
+	}
+	return nil
+}
+
+// Presign is a party's share of a presignature. Data is encrypted with the vault encryption secret
+// and is dropped once the presignature is consumed.
+type Presign struct {
+	ID         string     `json:"id"` // hex encoded DKLS presign session ID, the same for every party
+	PublicKey  string     `json:"public_key"`
+	PluginID   string     `json:"plugin_id"`
+	DerivePath string     `json:"derive_path"`
+	Data       []byte     `json:"-"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ConsumedAt *time.Time `json:"consumed_at,omitempty"`
+}
@@ -1,6 +1,7 @@
 package types
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -27,12 +28,19 @@ type PricingMetric string
 
 const (
 	PricingMetricFixed PricingMetric = "fixed"
+	// PricingMetricPercentage charges a share of the transaction volume, the amount is in basis points
+	PricingMetricPercentage PricingMetric = "percentage"
 )
 
+// MaxBasisPoints is 100% of the transaction volume
+const MaxBasisPoints = 10_000
+
 type PricingAsset string
 
 const (
-	PricingAssetUSDC PricingAsset = "usdc"
+	PricingAssetUSDC         PricingAsset = "usdc"
+	PricingAssetUSDT         PricingAsset = "usdt"
+	PricingAssetUSDCArbitrum PricingAsset = "usdc-arbitrum"
 )
 
 type Pricing struct {
@@ -45,13 +53,43 @@ type Pricing struct {
 	CreatedAt time.Time         `json:"created_at" validate:"required"`
 	UpdatedAt time.Time         `json:"updated_at" validate:"required"`
 	PluginID  PluginID          `json:"plugin_id" validate:"required"`
+	// MinAmount and MaxAmount cap percentage fees, in the smallest unit of Asset
+	MinAmount *uint64 `json:"min_amount,omitempty"`
+	MaxAmount *uint64 `json:"max_amount,omitempty"`
 }
 type PricingCreateDataDto struct {
 	Type      PricingType       `json:"type" validate:"required"`
 	Frequency *PricingFrequency `json:"frequency,omitempty" validate:"omitempty"`
 	Amount    uint64            `json:"amount" validate:"gte=0"`
+	Asset     PricingAsset      `json:"asset,omitempty"` // defaults to usdc
 	Metric    PricingMetric     `json:"metric" validate:"required"`
+	MinAmount *uint64           `json:"min_amount,omitempty"`
+	MaxAmount *uint64           `json:"max_amount,omitempty"`
+}
+
+// Validate checks the metric is consistent with the pricing type, amount and caps
+func (p PricingCreateDataDto) Validate() error {
+	switch p.Metric {
+	case PricingMetricFixed:
+		if p.MinAmount != nil || p.MaxAmount != nil {
+			return fmt.Errorf("min and max amounts are only supported by %s pricing", PricingMetricPercentage)
+		}
+	case PricingMetricPercentage:
+		if p.Type != PricingTypePerTx {
+			return fmt.Errorf("%s pricing must be %s", PricingMetricPercentage, PricingTypePerTx)
+		}
+		if p.Amount == 0 || p.Amount > MaxBasisPoints {
+			return fmt.Errorf("%s pricing amount must be between 1 and %d basis points", PricingMetricPercentage, MaxBasisPoints)
+		}
+		if p.MinAmount != nil && p.MaxAmount != nil && *p.MinAmount > *p.MaxAmount {
+			return fmt.Errorf("min amount %d is above max amount %d", *p.MinAmount, *p.MaxAmount)
+		}
+	default:
+		return fmt.Errorf("unsupported pricing metric: %s", p.Metric)
+	}
+	return nil
 }
+
 type PricingCreateDto struct {
 	PricingCreateDataDto
 	PluginID PluginID `json:"plugin_id" validate:"required"`
@@ -0,0 +1,8 @@
+package types
+
+// RelaySessionEvent is the state of a relay session, streamed as server-sent events by relays
+// serving GET /events/:session every time parties join or complete
+type RelaySessionEvent struct {
+	Parties   []string `json:"parties"`
+	Completed []string `json:"completed"`
+}
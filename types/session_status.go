@@ -0,0 +1,41 @@
+package types
+
+import "time"
+
+type SessionPhase string
+
+const (
+	// SessionPhaseQueued is a requested session the worker hasn't started yet
+	SessionPhaseQueued            SessionPhase = "queued"
+	SessionPhaseRegistered        SessionPhase = "registered"
+	SessionPhaseWaitingForParties SessionPhase = "waiting_for_parties"
+	SessionPhaseRound             SessionPhase = "round"
+	SessionPhaseFinalizing        SessionPhase = "finalizing"
+	SessionPhaseSaved             SessionPhase = "saved"
+	SessionPhaseFailed            SessionPhase = "failed"
+)
+
+const (
+	SessionOperationKeygen  = "keygen"
+	SessionOperationReshare = "reshare"
+)
+
+// SessionStatus is the latest known phase of a keygen or reshare session run by the worker.
+// Round counts the protocol messages applied so far for the key being generated (ecdsa or eddsa).
+type SessionStatus struct {
+	SessionID string       `json:"session_id"`
+	Operation string       `json:"operation"`
+	PublicKey string       `json:"public_key,omitempty"`
+	PluginID  string       `json:"plugin_id,omitempty"`
+	Phase     SessionPhase `json:"phase"`
+	Key       string       `json:"key,omitempty"`
+	Round     int          `json:"round,omitempty"`
+	Parties   []string     `json:"parties,omitempty"`
+	Error     string       `json:"error,omitempty"`
+	StartedAt time.Time    `json:"started_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+func (s SessionStatus) IsFinal() bool {
+	return s.Phase == SessionPhaseSaved || s.Phase == SessionPhaseFailed
+}
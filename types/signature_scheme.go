@@ -0,0 +1,53 @@
+package types
+
+import (
+	"fmt"
+
+	vgcommon "github.com/vultisig/vultisig-go/common"
+)
+
+// SignatureScheme is the signature algorithm a KeysignMessage has to be signed with
+type SignatureScheme string
+
+const (
+	// SignatureSchemeECDSA is ECDSA over secp256k1, the default for non-EdDSA chains
+	SignatureSchemeECDSA SignatureScheme = "ecdsa"
+	// SignatureSchemeEdDSA is Ed25519, the default for EdDSA chains
+	SignatureSchemeEdDSA SignatureScheme = "eddsa"
+	// SignatureSchemeSchnorr is BIP-340 Schnorr over secp256k1, used by Taproot key-path spends.
+	// The DKLS signer can't produce it, keysign requests spending taproot inputs are rejected.
+	SignatureSchemeSchnorr SignatureScheme = "schnorr"
+)
+
+// DefaultSignatureScheme is the scheme used for a chain when a message doesn't set one
+func DefaultSignatureScheme(chain vgcommon.Chain) SignatureScheme {
+	if chain.IsEdDSA() {
+		return SignatureSchemeEdDSA
+	}
+	return SignatureSchemeECDSA
+}
+
+func (s SignatureScheme) IsEdDSA() bool {
+	return s == SignatureSchemeEdDSA
+}
+
+// ValidateForChain checks the chain is able to verify signatures of the scheme
+func (s SignatureScheme) ValidateForChain(chain vgcommon.Chain) error {
+	switch s {
+	case SignatureSchemeECDSA:
+		if chain.IsEdDSA() {
+			return fmt.Errorf("chain %s doesn't support ecdsa signatures", chain.String())
+		}
+	case SignatureSchemeEdDSA:
+		if !chain.IsEdDSA() {
+			return fmt.Errorf("chain %s doesn't support eddsa signatures", chain.String())
+		}
+	case SignatureSchemeSchnorr:
+		if chain != vgcommon.Bitcoin {
+			return fmt.Errorf("schnorr signatures are only supported for Bitcoin taproot inputs, got chain %s", chain.String())
+		}
+	default:
+		return fmt.Errorf("unknown signature scheme: %q", s)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+// VaultUninstallRequest asks the worker to destroy the verifier's key share of a vault for a plugin
+type VaultUninstallRequest struct {
+	PublicKey   string    `json:"public_key"` // public key ecdsa
+	PluginID    string    `json:"plugin_id"`
+	RequestedAt time.Time `json:"requested_at"`
+}
+
+func (req *VaultUninstallRequest) IsValid() error {
+	if req.PublicKey == "" {
+		return fmt.Errorf("public_key is required")
+	}
+	if req.PluginID == "" {
+		return fmt.Errorf("plugin_id is required")
+	}
+	return nil
+}
+
+// VaultDeletionReceipt is returned to the vault owner once the key share has been destroyed.
+// Signature is an Ed25519 signature by SignerPublicKey over the JSON encoding of the receipt
+// with the Signature field left empty.
+type VaultDeletionReceipt struct {
+	PublicKey       string    `json:"public_key"`
+	PluginID        string    `json:"plugin_id"`
+	VaultFile       string    `json:"vault_file"`
+	VersionsDeleted int       `json:"versions_deleted"`
+	CancelledTasks  int       `json:"cancelled_tasks"`
+	PresignsDeleted int64     `json:"presigns_deleted"`
+	RequestedAt     time.Time `json:"requested_at"`
+	DeletedAt       time.Time `json:"deleted_at"`
+	SignerPublicKey string    `json:"signer_public_key"`
+	Signature       string    `json:"signature,omitempty"`
+}
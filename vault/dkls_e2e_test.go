@@ -0,0 +1,331 @@
+package vault
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"math/big"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"github.com/vultisig/mobile-tss-lib/tss"
+	vgcommon "github.com/vultisig/vultisig-go/common"
+	vgrelay "github.com/vultisig/vultisig-go/relay"
+	vgtypes "github.com/vultisig/vultisig-go/types"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/vultisig/verifier/internal/relay"
+	"github.com/vultisig/verifier/plugin/keysign"
+	vtypes "github.com/vultisig/verifier/types"
+	"github.com/vultisig/verifier/vault_config"
+)
+
+const e2ePluginID = "vultisig-dca-0000"
+
+type e2eParty struct {
+	partyID  string
+	service  *DKLSTssService
+	presigns *memoryPresignStore
+}
+
+type memoryPresignStore struct {
+	mu       sync.Mutex
+	presigns map[string]*vtypes.Presign
+}
+
+func (m *memoryPresignStore) SavePresign(_ context.Context, presign vtypes.Presign) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.presigns[presign.ID] = &presign
+	return nil
+}
+
+func (m *memoryPresignStore) ConsumePresign(_ context.Context, publicKey, pluginID, id string) (*vtypes.Presign, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	presign, ok := m.presigns[id]
+	if !ok || presign.ConsumedAt != nil || presign.PublicKey != publicKey || presign.PluginID != pluginID {
+		return nil, nil
+	}
+	consumed := *presign
+	now := time.Now()
+	presign.ConsumedAt = &now
+	presign.Data = nil
+	return &consumed, nil
+}
+
+func (m *memoryPresignStore) ListPresignIDs(_ context.Context, publicKey, pluginID, derivePath string, limit int) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var ids []string
+	for id, presign := range m.presigns {
+		if presign.ConsumedAt == nil && presign.PublicKey == publicKey && presign.PluginID == pluginID &&
+			presign.DerivePath == derivePath && len(ids) < limit {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (m *memoryPresignStore) CountPresigns(ctx context.Context, publicKey, pluginID, derivePath string) (int, error) {
+	ids, err := m.ListPresignIDs(ctx, publicKey, pluginID, derivePath, len(m.presigns))
+	return len(ids), err
+}
+
+func (m *memoryPresignStore) DeletePresigns(_ context.Context, publicKey, pluginID string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var deleted int64
+	for id, presign := range m.presigns {
+		if presign.PublicKey == publicKey && presign.PluginID == pluginID {
+			delete(m.presigns, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func newE2EParty(t *testing.T, relayURL, partyID string, doSetupMsg bool) e2eParty {
+	storage, err := NewLocalVaultStorage(LocalVaultStorageConfig{VaultFilePath: t.TempDir()})
+	require.NoError(t, err)
+
+	var cfg vault_config.Config
+	cfg.Relay.Server = relayURL
+	cfg.EncryptionSecret = "e2e-secret"
+	cfg.DoSetupMsg = doSetupMsg
+
+	service, err := NewDKLSTssService(cfg, storage, nil)
+	require.NoError(t, err)
+	presigns := &memoryPresignStore{presigns: map[string]*vtypes.Presign{}}
+	service.SetPresignStore(presigns)
+	return e2eParty{partyID: partyID, service: service, presigns: presigns}
+}
+
+// keysignEmitter plays the role of the verifier and plugin workers: it joins the keysign with its party
+type keysignEmitter struct {
+	party   e2eParty
+	results chan error
+}
+
+func (e *keysignEmitter) Sign(_ context.Context, req vtypes.PluginKeysignRequest) error {
+	go func() {
+		_, err := e.party.service.ProcessDKLSKeysign(req.KeysignRequest)
+		e.results <- err
+	}()
+	return nil
+}
+
+func (e *keysignEmitter) Presign(_ context.Context, req vtypes.PresignRequest) error {
+	go func() {
+		_, err := e.party.service.ProcessDKLSPresign(req)
+		e.results <- err
+	}()
+	return nil
+}
+
+// initiateKeygen does what the Vultisig app does for a new vault: waits for the parties,
+// uploads the keygen setup message and starts the session
+func initiateKeygen(ctx context.Context, client *vgrelay.Client, sessionID, hexEncryptionKey string) error {
+	var parties []string
+	for len(parties) < 2 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		time.Sleep(100 * time.Millisecond)
+		joined, err := client.GetSession(sessionID)
+		if err != nil {
+			return err
+		}
+		parties = joined
+	}
+	setupMsg, err := NewMPCWrapperImp(false).KeygenSetupMsgNew(2, nil, fmtIdsSlice(parties))
+	if err != nil {
+		return err
+	}
+	payload, err := vgcommon.EncryptGCM(base64.StdEncoding.EncodeToString(setupMsg), hexEncryptionKey)
+	if err != nil {
+		return err
+	}
+	if err := client.UploadSetupMessage(sessionID, "", payload); err != nil {
+		return err
+	}
+	return client.StartSession(sessionID, parties)
+}
+
+func TestDKLS_KeygenAndKeysign(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping DKLS end-to-end test in short mode")
+	}
+
+	relayServer := httptest.NewServer(relay.NewServer(logrus.New(), 0).Handler())
+	defer relayServer.Close()
+	relayClient := vgrelay.NewRelayClient(relayServer.URL)
+
+	verifier := newE2EParty(t, relayServer.URL, "verifier-"+uuid.NewString()[:8], false)
+	plugin := newE2EParty(t, relayServer.URL, "plugin-"+uuid.NewString()[:8], true)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+
+	// keygen
+	sessionID := uuid.NewString()
+	encryptionKey := sha256.Sum256([]byte(sessionID))
+	hexEncryptionKey := hex.EncodeToString(encryptionKey[:])
+
+	publicKeys := make([][2]string, 2)
+	eg, egCtx := errgroup.WithContext(ctx)
+	for i, party := range []e2eParty{verifier, plugin} {
+		eg.Go(func() error {
+			ecdsaPubKey, eddsaPubKey, err := party.service.ProcessDKLSKeygen(vgtypes.VaultCreateRequest{
+				Name:             "e2e",
+				SessionID:        sessionID,
+				HexEncryptionKey: hexEncryptionKey,
+				LocalPartyId:     party.partyID,
+				PluginID:         e2ePluginID,
+			})
+			publicKeys[i] = [2]string{ecdsaPubKey, eddsaPubKey}
+			return err
+		})
+	}
+	eg.Go(func() error {
+		return initiateKeygen(egCtx, relayClient, sessionID, hexEncryptionKey)
+	})
+	require.NoError(t, eg.Wait())
+	require.NotEmpty(t, publicKeys[0][0])
+	require.Equal(t, publicKeys[0], publicKeys[1], "both parties must end up with the same vault")
+
+	// keysign, orchestrated the way plugins do it
+	results := make(chan error, 2)
+	signer := keysign.NewSigner(
+		logrus.New(),
+		relayClient,
+		[]keysign.Emitter{
+			&keysignEmitter{party: verifier, results: results},
+			&keysignEmitter{party: plugin, results: results},
+		},
+		[]string{"verifier", "plugin"},
+	)
+	signer.SetWaitConfig(keysign.WaitConfig{
+		Mode:     keysign.WaitModeEvents,
+		RelayURL: relayServer.URL,
+	})
+
+	hash := sha256.Sum256([]byte("vultisig e2e"))
+	msg := vtypes.KeysignMessage{
+		Message: base64.StdEncoding.EncodeToString(hash[:]),
+		Hash:    hex.EncodeToString(hash[:]),
+		Chain:   vgcommon.Ethereum,
+	}
+	eddsaMsg := vtypes.KeysignMessage{
+		Message: base64.StdEncoding.EncodeToString([]byte("vultisig e2e eddsa")),
+		Hash:    "eddsa",
+		Chain:   vgcommon.Solana,
+	}
+	sigs, err := signer.Sign(ctx, vtypes.PluginKeysignRequest{
+		KeysignRequest: vtypes.KeysignRequest{
+			PublicKey: publicKeys[0][0],
+			PluginID:  e2ePluginID,
+			Messages:  []vtypes.KeysignMessage{msg, eddsaMsg},
+		},
+	})
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		require.NoError(t, <-results)
+	}
+
+	sig, ok := sigs[msg.Hash]
+	require.True(t, ok)
+	verifyECDSA(t, verifier.service, publicKeys[0][0], msg, sig)
+
+	sig, ok = sigs[eddsaMsg.Hash]
+	require.True(t, ok)
+	verifyEdDSA(t, publicKeys[0][1], eddsaMsg, sig)
+
+	// presign ahead of time, then finish a keysign with one of the presignatures
+	require.NoError(t, signer.Presign(ctx, vtypes.PresignRequest{
+		PublicKey: publicKeys[0][0],
+		PluginID:  e2ePluginID,
+		Chain:     vgcommon.Ethereum,
+		Count:     2,
+	}))
+	for i := 0; i < 2; i++ {
+		require.NoError(t, <-results)
+	}
+	derivePath := vgcommon.Ethereum.GetDerivePath()
+	presignIDs, err := plugin.presigns.ListPresignIDs(ctx, publicKeys[0][0], e2ePluginID, derivePath, 10)
+	require.NoError(t, err)
+	require.Len(t, presignIDs, 2)
+	verifierIDs, err := verifier.presigns.ListPresignIDs(ctx, publicKeys[0][0], e2ePluginID, derivePath, 10)
+	require.NoError(t, err)
+	require.ElementsMatch(t, presignIDs, verifierIDs, "parties must agree on the presignature IDs")
+
+	presignHash := sha256.Sum256([]byte("vultisig e2e presign"))
+	presignMsg := vtypes.KeysignMessage{
+		Message:   base64.StdEncoding.EncodeToString(presignHash[:]),
+		Hash:      hex.EncodeToString(presignHash[:]),
+		Chain:     vgcommon.Ethereum,
+		PresignID: presignIDs[0],
+	}
+	sigs, err = signer.Sign(ctx, vtypes.PluginKeysignRequest{
+		KeysignRequest: vtypes.KeysignRequest{
+			PublicKey: publicKeys[0][0],
+			PluginID:  e2ePluginID,
+			Messages:  []vtypes.KeysignMessage{presignMsg},
+		},
+	})
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		require.NoError(t, <-results)
+	}
+	sig, ok = sigs[presignMsg.Hash]
+	require.True(t, ok)
+	verifyECDSA(t, verifier.service, publicKeys[0][0], presignMsg, sig)
+
+	// a consumed presignature is never handed out again
+	_, err = verifier.service.consumePresign(publicKeys[0][0], e2ePluginID, presignIDs[0], derivePath)
+	require.ErrorIs(t, err, ErrPresignUnavailable)
+	count, err := verifier.presigns.CountPresigns(ctx, publicKeys[0][0], e2ePluginID, derivePath)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+}
+
+func verifyEdDSA(t *testing.T, publicKey string, msg vtypes.KeysignMessage, sig tss.KeysignResponse) {
+	publicKeyBytes, err := hex.DecodeString(publicKey)
+	require.NoError(t, err)
+	r, err := hex.DecodeString(sig.R)
+	require.NoError(t, err)
+	s, err := hex.DecodeString(sig.S)
+	require.NoError(t, err)
+	message, err := base64.StdEncoding.DecodeString(msg.Message)
+	require.NoError(t, err)
+	require.True(t, ed25519.Verify(publicKeyBytes, message, append(r, s...)), "signature must verify against the vault key")
+}
+
+func verifyECDSA(t *testing.T, service *DKLSTssService, publicKey string, msg vtypes.KeysignMessage, sig tss.KeysignResponse) {
+	vault, err := service.GetExistingVault(vgcommon.GetVaultBackupFilename(publicKey, e2ePluginID), service.cfg.EncryptionSecret)
+	require.NoError(t, err)
+
+	childPublicKey, err := tss.GetDerivedPubKey(publicKey, vault.HexChainCode, msg.Chain.GetDerivePath(), false)
+	require.NoError(t, err)
+	childPublicKeyBytes, err := hex.DecodeString(childPublicKey)
+	require.NoError(t, err)
+	parsed, err := secp256k1.ParsePubKey(childPublicKeyBytes)
+	require.NoError(t, err)
+
+	r, ok := new(big.Int).SetString(sig.R, 16)
+	require.True(t, ok)
+	s, ok := new(big.Int).SetString(sig.S, 16)
+	require.True(t, ok)
+	hash, err := base64.StdEncoding.DecodeString(msg.Message)
+	require.NoError(t, err)
+	require.True(t, ecdsa.Verify(parsed.ToECDSA(), hash, r, s), "signature must verify against the derived key")
+}
@@ -37,6 +37,9 @@ type DKLSTssService struct {
 	storage                        Storage
 	queueClient                    *asynq.Client
 	processedInitiateDeviceMessage *atomic.Bool
+	sessionReporter                SessionReporter
+	session                        *sessionTracker
+	presignStore                   PresignStore
 }
 
 func NewDKLSTssService(cfg vault_config.Config,
@@ -49,14 +52,32 @@ func NewDKLSTssService(cfg vault_config.Config,
 		localStateAccessor:             NewLocalStateAccessorImp(nil),
 		queueClient:                    queueClient,
 		processedInitiateDeviceMessage: &atomic.Bool{},
+		sessionReporter:                &NoOpSessionReporter{},
 	}, nil
 }
 
+// SetSessionReporter publishes keygen and reshare phase transitions to the given reporter
+func (t *DKLSTssService) SetSessionReporter(reporter SessionReporter) {
+	if reporter == nil {
+		reporter = &NoOpSessionReporter{}
+	}
+	t.sessionReporter = reporter
+}
+
 func (t *DKLSTssService) GetMPCKeygenWrapper(isEdDSA bool) *MPCWrapperImp {
 	return NewMPCWrapperImp(isEdDSA)
 }
 
 func (t *DKLSTssService) ProcessDKLSKeygen(req vgtypes.VaultCreateRequest) (string, string, error) {
+	t.session = newSessionTracker(t.sessionReporter, vtypes.SessionOperationKeygen, req.SessionID, "", req.PluginID)
+	publicKeyECDSA, publicKeyEdDSA, err := t.processDKLSKeygen(req)
+	if err != nil {
+		return "", "", t.session.fail(err)
+	}
+	return publicKeyECDSA, publicKeyEdDSA, nil
+}
+
+func (t *DKLSTssService) processDKLSKeygen(req vgtypes.VaultCreateRequest) (string, string, error) {
 	serverURL := t.cfg.Relay.Server
 	relayClient := vgrelay.NewRelayClient(serverURL)
 
@@ -64,14 +85,17 @@ func (t *DKLSTssService) ProcessDKLSKeygen(req vgtypes.VaultCreateRequest) (stri
 	if err := relayClient.RegisterSession(req.SessionID, req.LocalPartyId); err != nil {
 		return "", "", fmt.Errorf("failed to register session: %w", err)
 	}
+	t.session.phase(vtypes.SessionPhaseRegistered)
 	// wait longer for keygen start
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
 	defer cancel()
 
+	t.session.phase(vtypes.SessionPhaseWaitingForParties)
 	partiesJoined, err := relayClient.WaitForSessionStart(ctx, req.SessionID)
 	if err != nil {
 		return "", "", fmt.Errorf("failed to wait for session start: %w", err)
 	}
+	t.session.parties(partiesJoined)
 	t.logger.WithFields(logrus.Fields{
 		"sessionID":      req.SessionID,
 		"parties_joined": partiesJoined,
@@ -82,12 +106,14 @@ func (t *DKLSTssService) ProcessDKLSKeygen(req vgtypes.VaultCreateRequest) (stri
 	if err != nil {
 		return "", "", fmt.Errorf("failed to keygen ECDSA: %w", err)
 	}
+	t.session.publicKey(publicKeyECDSA)
 	time.Sleep(500 * time.Millisecond)
 	// create EdDSA key
 	publicKeyEdDSA, _, err := t.keygenWithRetry(req.SessionID, req.HexEncryptionKey, req.LocalPartyId, true, partiesJoined)
 	if err != nil {
 		return "", "", fmt.Errorf("failed to keygen EdDSA: %w", err)
 	}
+	t.session.phase(vtypes.SessionPhaseFinalizing)
 
 	if err := relayClient.CompleteSession(req.SessionID, req.LocalPartyId); err != nil {
 		t.logger.WithFields(logrus.Fields{
@@ -108,6 +134,7 @@ func (t *DKLSTssService) ProcessDKLSKeygen(req vgtypes.VaultCreateRequest) (stri
 	if err != nil {
 		return "", "", fmt.Errorf("failed to backup vault: %w", err)
 	}
+	t.session.phase(vtypes.SessionPhaseSaved)
 	return publicKeyECDSA, publicKeyEdDSA, nil
 }
 
@@ -242,6 +269,7 @@ func (t *DKLSTssService) keygen(sessionID string,
 		"keygen_committee": keygenCommittee,
 		"attempt":          attempt,
 	}).Info("Keygen")
+	t.session.key(isEdDSA)
 	relayClient := vgrelay.NewRelayClient(t.cfg.Relay.Server)
 	mpcKeygenWrapper := t.GetMPCKeygenWrapper(isEdDSA)
 	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
@@ -319,6 +347,7 @@ func (t *DKLSTssService) processKeygenInbound(handle Handle,
 	mpcKeygenWrapper := t.GetMPCKeygenWrapper(isEdDSA)
 	relayClient := vgrelay.NewRelayClient(t.cfg.Relay.Server)
 	start := time.Now()
+	round := 0
 	for {
 		if time.Since(start) > (time.Minute * 4) { // 4 minute timeout
 			t.logger.Error("keygen timeout")
@@ -351,6 +380,8 @@ func (t *DKLSTssService) processKeygenInbound(handle Handle,
 				continue
 			}
 			messageCache.Store(cacheKey, struct{}{})
+			round++
+			t.session.round(round)
 			if err := relayClient.DeleteMessageFromServer(sessionID, localPartyID, message.Hash, ""); err != nil {
 				t.logger.Error("fail to delete message", "error", err)
 			}
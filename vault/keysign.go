@@ -8,6 +8,7 @@ import (
 	"crypto/md5"
 	"encoding/base64"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"math/big"
 	"strings"
@@ -42,13 +43,40 @@ func (t *DKLSTssService) GetExistingVault(vaultFileName, password string) (*vaul
 	return vault, nil
 }
 
+var ErrUnsupportedSignatureScheme = errors.New("signature scheme is not supported by the signer")
+
+// SupportsSignatureScheme reports whether the MPC backend can produce signatures of the scheme.
+// BIP-340 Schnorr needs a secp256k1 Schnorr protocol, which go-wrappers doesn't provide yet.
+func SupportsSignatureScheme(scheme types.SignatureScheme) bool {
+	return scheme == types.SignatureSchemeECDSA || scheme == types.SignatureSchemeEdDSA
+}
+
+func validateSignatureSchemes(messages []types.KeysignMessage) error {
+	for _, msg := range messages {
+		scheme := msg.GetSignatureScheme()
+		if err := scheme.ValidateForChain(msg.Chain); err != nil {
+			return err
+		}
+		if !SupportsSignatureScheme(scheme) {
+			return fmt.Errorf("%s: %w", scheme, ErrUnsupportedSignatureScheme)
+		}
+	}
+	return nil
+}
+
 func (t *DKLSTssService) ProcessDKLSKeysign(req types.KeysignRequest) (map[string]tss.KeysignResponse, error) {
 	result := map[string]tss.KeysignResponse{}
+	if err := validateSignatureSchemes(req.Messages); err != nil {
+		return nil, fmt.Errorf("invalid keysign messages: %w", err)
+	}
 	vaultFileName := common.GetVaultBackupFilename(req.PublicKey, req.PluginID)
 	vault, err := t.GetExistingVault(vaultFileName, t.cfg.EncryptionSecret)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get vault: %w", err)
 	}
+	if err := checkTombstone(t.storage, req.PublicKey, req.PluginID, vault); err != nil {
+		return nil, err
+	}
 	localStateAccessor := NewLocalStateAccessorImp(vault)
 	t.localStateAccessor = localStateAccessor
 	localPartyID := localStateAccessor.Vault.LocalPartyId
@@ -73,22 +101,36 @@ func (t *DKLSTssService) ProcessDKLSKeysign(req types.KeysignRequest) (map[strin
 	for _, msg := range req.Messages {
 		var publicKey string
 
-		if msg.Chain.IsEdDSA() {
+		isEdDSA := msg.GetSignatureScheme().IsEdDSA()
+		if isEdDSA {
 			publicKey = localStateAccessor.Vault.PublicKeyEddsa
 		} else {
 			publicKey = localStateAccessor.Vault.PublicKeyEcdsa
 		}
 
+		var presign *Handle
+		if msg.PresignID != "" {
+			presignHandle, err := t.consumePresign(req.PublicKey, req.PluginID, msg.PresignID, msg.Chain.GetDerivePath())
+			if err != nil {
+				return result, fmt.Errorf("failed to load presign: %w", err)
+			}
+			presign = &presignHandle
+		}
+
 		sig, err := t.keysignWithRetry(
 			req.SessionID,
 			req.HexEncryptionKey,
 			publicKey,
-			msg.Chain.IsEdDSA(),
+			isEdDSA,
 			msg.Message,
 			msg.Chain.GetDerivePath(),
 			localPartyID,
 			partiesJoined,
+			presign,
 		)
+		if presign != nil {
+			t.freePresign(*presign)
+		}
 		if err != nil {
 			return result, fmt.Errorf("failed to keysign: %w", err)
 		}
@@ -116,6 +158,7 @@ func (t *DKLSTssService) keysign(sessionID string,
 	derivePath string,
 	localPartyID string,
 	keysignCommittee []string,
+	presign *Handle,
 	attempt int) (*tss.KeysignResponse, error) {
 	if publicKey == "" {
 		return nil, fmt.Errorf("public key is empty")
@@ -141,6 +184,7 @@ func (t *DKLSTssService) keysign(sessionID string,
 		"derive_path":       derivePath,
 		"local_party_id":    localPartyID,
 		"keysign_committee": keysignCommittee,
+		"presign":           presign != nil,
 		"attempt":           attempt,
 	}).Info("Keysign")
 
@@ -180,14 +224,26 @@ func (t *DKLSTssService) keysign(sessionID string,
 			return nil, fmt.Errorf("failed to decode message: %w", e)
 		}
 
-		msg, e := mpcWrapper.SignSetupMsgNew(
-			id,
-			fmtDerivePath(derivePath),
-			hashToSign,
-			fmtIdsSlice(keysignCommittee),
-		)
-		if e != nil {
-			return nil, fmt.Errorf("failed to create SignSetupMsgNew: %w", e)
+		var msg []byte
+		if presign != nil {
+			presignSessionID, e := mpcWrapper.PresignSessionID(*presign)
+			if e != nil {
+				return nil, fmt.Errorf("failed to get presign session ID: %w", e)
+			}
+			msg, e = mpcWrapper.FinishSetupMsgNew(presignSessionID, hashToSign, fmtIdsSlice(keysignCommittee))
+			if e != nil {
+				return nil, fmt.Errorf("failed to create FinishSetupMsgNew: %w", e)
+			}
+		} else {
+			msg, e = mpcWrapper.SignSetupMsgNew(
+				id,
+				fmtDerivePath(derivePath),
+				hashToSign,
+				fmtIdsSlice(keysignCommittee),
+			)
+			if e != nil {
+				return nil, fmt.Errorf("failed to create SignSetupMsgNew: %w", e)
+			}
 		}
 
 		payload, e := common.EncryptGCM(base64.StdEncoding.EncodeToString(msg), hexEncryptionKey)
@@ -226,7 +282,11 @@ func (t *DKLSTssService) keysign(sessionID string,
 		return nil, fmt.Errorf("setupHashToSign is not equal to the reqHashToSign, stop keysign")
 	}
 
-	sessionHandle, err := mpcWrapper.SignSessionFromSetup(setupMsg, []byte(localPartyID), keyshareHandle)
+	shareOrPresign := keyshareHandle
+	if presign != nil {
+		shareOrPresign = *presign
+	}
+	sessionHandle, err := mpcWrapper.SignSessionFromSetup(setupMsg, []byte(localPartyID), shareOrPresign)
 	if err != nil {
 		return nil, fmt.Errorf("failed to SignSessionFromSetup: %w", err)
 	}
@@ -345,8 +405,14 @@ func (t *DKLSTssService) keysignWithRetry(sessionID string,
 	message string,
 	derivePath string,
 	localPartyID string,
-	keysignCommittee []string) (*tss.KeysignResponse, error) {
-	for i := 0; i < 3; i++ {
+	keysignCommittee []string,
+	presign *Handle) (*tss.KeysignResponse, error) {
+	attempts := 3
+	if presign != nil {
+		// a presignature is single use, a failed finish can't be retried with it
+		attempts = 1
+	}
+	for i := 0; i < attempts; i++ {
 		keysignResult, err := t.keysign(sessionID,
 			hexEncryptionKey,
 			publicKey,
@@ -354,7 +420,9 @@ func (t *DKLSTssService) keysignWithRetry(sessionID string,
 			message,
 			derivePath,
 			localPartyID,
-			keysignCommittee, i)
+			keysignCommittee,
+			presign,
+			i)
 		if err != nil {
 			t.logger.WithFields(logrus.Fields{
 				"session_id":        sessionID,
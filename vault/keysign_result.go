@@ -0,0 +1,123 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/vultisig/mobile-tss-lib/tss"
+	vcommon "github.com/vultisig/vultiserver/common"
+
+	vtypes "github.com/vultisig/verifier/types"
+)
+
+// KeysignResultStore persists plugin keysign outcomes by tx indexer ID, so they outlive the asynq task retention
+type KeysignResultStore interface {
+	UpdateKeysignResult(
+		ctx context.Context,
+		txIndexerID uuid.UUID,
+		status vtypes.KeysignResultStatus,
+		failureReason string,
+		data []byte,
+	) error
+}
+
+// NoOpKeysignResultStore is used when keysign results aren't persisted
+type NoOpKeysignResultStore struct{}
+
+func (n *NoOpKeysignResultStore) UpdateKeysignResult(
+	ctx context.Context,
+	txIndexerID uuid.UUID,
+	status vtypes.KeysignResultStatus,
+	failureReason string,
+	data []byte,
+) error {
+	return nil
+}
+
+var _ KeysignResultStore = (*NoOpKeysignResultStore)(nil)
+
+// SetKeysignResultStore persists the state of plugin keysign tasks
+func (s *ManagementService) SetKeysignResultStore(store KeysignResultStore) {
+	if store == nil {
+		store = &NoOpKeysignResultStore{}
+	}
+	s.keysignResults = store
+}
+
+// keysignResultIDs lists the tx indexer IDs of a keysign request, only plugin requests carry them
+func keysignResultIDs(req vtypes.KeysignRequest) []uuid.UUID {
+	var ids []uuid.UUID
+	for _, msg := range req.Messages {
+		if msg.TxIndexerID == "" {
+			continue
+		}
+		id, err := uuid.Parse(msg.TxIndexerID)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// reportKeysignResult records the keysign state, failing to do so doesn't fail the keysign
+func (s *ManagementService) reportKeysignResult(
+	ctx context.Context,
+	req vtypes.KeysignRequest,
+	status vtypes.KeysignResultStatus,
+	failureReason string,
+	signatures map[string]tss.KeysignResponse,
+) {
+	ids := keysignResultIDs(req)
+	if len(ids) == 0 {
+		return
+	}
+
+	var data []byte
+	if signatures != nil {
+		var err error
+		data, err = encryptKeysignResult(s.cfg.EncryptionSecret, signatures)
+		if err != nil {
+			s.logger.WithError(err).WithField("session", req.SessionID).Error("failed to encrypt keysign result")
+			status, failureReason = vtypes.KeysignResultFailed, "failed to store signatures"
+		}
+	}
+	for _, id := range ids {
+		err := s.keysignResults.UpdateKeysignResult(ctx, id, status, failureReason, data)
+		if err != nil {
+			s.logger.WithError(err).WithFields(logrus.Fields{
+				"session":       req.SessionID,
+				"tx_indexer_id": id,
+				"status":        status,
+			}).Error("failed to update keysign result")
+		}
+	}
+}
+
+func encryptKeysignResult(secret string, signatures map[string]tss.KeysignResponse) ([]byte, error) {
+	buf, err := json.Marshal(signatures)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal signatures: %w", err)
+	}
+	encrypted, err := vcommon.EncryptVault(secret, buf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt signatures: %w", err)
+	}
+	return encrypted, nil
+}
+
+// DecryptKeysignResult reads back the signatures persisted by the worker
+func DecryptKeysignResult(secret string, data []byte) (map[string]tss.KeysignResponse, error) {
+	buf, err := vcommon.DecryptVault(secret, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt signatures: %w", err)
+	}
+	var signatures map[string]tss.KeysignResponse
+	if err := json.Unmarshal(buf, &signatures); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal signatures: %w", err)
+	}
+	return signatures, nil
+}
@@ -0,0 +1,97 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+	"github.com/vultisig/mobile-tss-lib/tss"
+
+	psafety "github.com/vultisig/verifier/plugin/safety"
+	"github.com/vultisig/verifier/plugin/tasks"
+	vtypes "github.com/vultisig/verifier/types"
+	"github.com/vultisig/verifier/vault_config"
+)
+
+type keysignResultUpdate struct {
+	id     uuid.UUID
+	status vtypes.KeysignResultStatus
+	reason string
+	data   []byte
+}
+
+type memoryKeysignResultStore struct {
+	updates []keysignResultUpdate
+}
+
+func (m *memoryKeysignResultStore) UpdateKeysignResult(
+	_ context.Context,
+	txIndexerID uuid.UUID,
+	status vtypes.KeysignResultStatus,
+	failureReason string,
+	data []byte,
+) error {
+	m.updates = append(m.updates, keysignResultUpdate{id: txIndexerID, status: status, reason: failureReason, data: data})
+	return nil
+}
+
+type pausedSafetyManager struct{}
+
+func (p *pausedSafetyManager) EnforceKeygen(context.Context, string) error { return nil }
+
+func (p *pausedSafetyManager) EnforceKeysign(context.Context, string) error {
+	return errors.New("plugin is paused")
+}
+
+func (p *pausedSafetyManager) EnforceKeysignScope(context.Context, psafety.KeysignScope) error {
+	return errors.New("plugin is paused")
+}
+
+func TestKeysignResult_EncryptDecrypt(t *testing.T) {
+	signatures := map[string]tss.KeysignResponse{
+		"hash": {Msg: "msg", R: "r", S: "s", RecoveryID: "01"},
+	}
+	data, err := encryptKeysignResult("secret", signatures)
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "msg")
+
+	decrypted, err := DecryptKeysignResult("secret", data)
+	require.NoError(t, err)
+	require.Equal(t, signatures, decrypted)
+
+	_, err = DecryptKeysignResult("other", data)
+	require.Error(t, err)
+}
+
+func TestHandleKeySignDKLS_ReportsFailure(t *testing.T) {
+	cfg := vault_config.Config{EncryptionSecret: "secret"}
+	service, err := NewManagementService(cfg, nil, nil, nil, &pausedSafetyManager{})
+	require.NoError(t, err)
+	store := &memoryKeysignResultStore{}
+	service.SetKeysignResultStore(store)
+
+	txID := uuid.New()
+	payload, err := json.Marshal(vtypes.KeysignRequest{
+		PublicKey: "pubkey",
+		PluginID:  "plugin",
+		Messages: []vtypes.KeysignMessage{
+			{TxIndexerID: txID.String()},
+			{}, // only the first message of a plugin request carries the tx indexer ID
+		},
+	})
+	require.NoError(t, err)
+
+	err = service.HandleKeySignDKLS(context.Background(), asynq.NewTask(tasks.TypeKeySignDKLS, payload))
+	require.ErrorIs(t, err, asynq.SkipRetry)
+	require.Len(t, store.updates, 2)
+	require.Equal(t, txID, store.updates[0].id)
+	require.Equal(t, vtypes.KeysignResultRunning, store.updates[0].status)
+	require.Equal(t, txID, store.updates[1].id)
+	require.Equal(t, vtypes.KeysignResultFailed, store.updates[1].status)
+	require.Contains(t, store.updates[1].reason, "plugin is paused")
+	require.Nil(t, store.updates[1].data)
+}
@@ -0,0 +1,289 @@
+package vault
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	vcommon "github.com/vultisig/vultiserver/common"
+	vgrelay "github.com/vultisig/vultisig-go/relay"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/vultisig/verifier/types"
+	"github.com/vultisig/vultisig-go/common"
+)
+
+var (
+	ErrPresignDisabled    = errors.New("presignatures are not enabled")
+	ErrPresignUnavailable = errors.New("presignature is not available")
+)
+
+// PresignStore keeps a party's presignature shares per vault.
+// A presignature must never finish more than one signature, reusing it leaks the key share,
+// so ConsumePresign has to hand every presignature out at most once, even to concurrent callers.
+type PresignStore interface {
+	// SavePresign returns types.ErrPresignPoolFull when the pool of the presignature is full
+	SavePresign(ctx context.Context, presign types.Presign) error
+	// ConsumePresign marks the presignature as used and returns it, nil if it doesn't exist or is already used
+	ConsumePresign(ctx context.Context, publicKey, pluginID, id string) (*types.Presign, error)
+	ListPresignIDs(ctx context.Context, publicKey, pluginID, derivePath string, limit int) ([]string, error)
+	CountPresigns(ctx context.Context, publicKey, pluginID, derivePath string) (int, error)
+	DeletePresigns(ctx context.Context, publicKey, pluginID string) (int64, error)
+}
+
+// SetPresignStore enables presign sessions and keysign with presignatures
+func (t *DKLSTssService) SetPresignStore(store PresignStore) {
+	t.presignStore = store
+}
+
+// presignMessageID identifies the i-th presignature of a session on the relay,
+// every party derives the same ID without exchanging it
+func presignMessageID(i int) string {
+	md5Hash := md5.Sum([]byte(fmt.Sprintf("presign-%d", i)))
+	return hex.EncodeToString(md5Hash[:])
+}
+
+// ProcessDKLSPresign joins a presign session and stores the generated presignatures, returning their IDs
+func (t *DKLSTssService) ProcessDKLSPresign(req types.PresignRequest) ([]string, error) {
+	if t.presignStore == nil {
+		return nil, ErrPresignDisabled
+	}
+	if err := req.IsValid(); err != nil {
+		return nil, fmt.Errorf("invalid presign request: %w", err)
+	}
+	vaultFileName := common.GetVaultBackupFilename(req.PublicKey, req.PluginID)
+	vault, err := t.GetExistingVault(vaultFileName, t.cfg.EncryptionSecret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vault: %w", err)
+	}
+	if err := checkTombstone(t.storage, req.PublicKey, req.PluginID, vault); err != nil {
+		return nil, err
+	}
+	localStateAccessor := NewLocalStateAccessorImp(vault)
+	t.localStateAccessor = localStateAccessor
+	localPartyID := vault.LocalPartyId
+	relayClient := vgrelay.NewRelayClient(t.cfg.Relay.Server)
+	if err := relayClient.RegisterSession(req.SessionID, localPartyID); err != nil {
+		return nil, fmt.Errorf("failed to start session: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute+3*time.Second)
+	defer cancel()
+
+	partiesJoined, err := relayClient.WaitForSessionStart(ctx, req.SessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wait for session start: %w", err)
+	}
+	t.logger.WithFields(logrus.Fields{
+		"session":        req.SessionID,
+		"parties_joined": partiesJoined,
+		"count":          req.Count,
+	}).Info("Presign session started")
+
+	derivePath := req.Chain.GetDerivePath()
+	ids := make([]string, 0, req.Count)
+	for i := 0; i < req.Count; i++ {
+		presign, err := t.presign(
+			req.SessionID,
+			req.HexEncryptionKey,
+			vault.PublicKeyEcdsa,
+			derivePath,
+			localPartyID,
+			partiesJoined,
+			presignMessageID(i),
+		)
+		if err != nil {
+			return ids, fmt.Errorf("failed to presign: %w", err)
+		}
+		presign.PublicKey = req.PublicKey
+		presign.PluginID = req.PluginID
+		if err := t.presignStore.SavePresign(ctx, *presign); err != nil {
+			return ids, fmt.Errorf("failed to save presign: %w", err)
+		}
+		ids = append(ids, presign.ID)
+	}
+
+	if err := relayClient.CompleteSession(req.SessionID, localPartyID); err != nil {
+		t.logger.WithFields(logrus.Fields{
+			"session": req.SessionID,
+			"error":   err,
+		}).Error("Failed to complete session")
+	}
+	return ids, nil
+}
+
+func (t *DKLSTssService) presign(sessionID string,
+	hexEncryptionKey string,
+	publicKey string,
+	derivePath string,
+	localPartyID string,
+	committee []string,
+	messageID string) (*types.Presign, error) {
+	relayClient := vgrelay.NewRelayClient(t.cfg.Relay.Server)
+	mpcWrapper := t.GetMPCKeygenWrapper(false)
+
+	keyshare, err := t.localStateAccessor.GetLocalState(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get keyshare: %w", err)
+	}
+	keyshareBytes, err := base64.StdEncoding.DecodeString(keyshare)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode keyshare: %w", err)
+	}
+	keyshareHandle, err := mpcWrapper.KeyshareFromBytes(keyshareBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create keyshare from bytes: %w", err)
+	}
+	defer func() {
+		if err := mpcWrapper.KeyshareFree(keyshareHandle); err != nil {
+			t.logger.Error("failed to free keyshare", "error", err)
+		}
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	var encryptedEncodedSetupMsg string
+	if t.cfg.DoSetupMsg {
+		id, err := mpcWrapper.KeyshareKeyID(keyshareHandle)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get keyshare key ID: %w", err)
+		}
+		// no message hash makes the sign session stop at the presignature
+		msg, err := mpcWrapper.SignSetupMsgNew(id, fmtDerivePath(derivePath), nil, fmtIdsSlice(committee))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create SignSetupMsgNew: %w", err)
+		}
+		payload, err := common.EncryptGCM(base64.StdEncoding.EncodeToString(msg), hexEncryptionKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt setup message: %w", err)
+		}
+		if err := relayClient.UploadSetupMessage(sessionID, messageID, payload); err != nil {
+			return nil, fmt.Errorf("failed to relayClient.UploadSetupMessage: %w", err)
+		}
+		encryptedEncodedSetupMsg = payload
+	} else {
+		msg, err := relayClient.WaitForSetupMessage(ctx, sessionID, messageID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to relayClient.WaitForSetupMessage: %w", err)
+		}
+		encryptedEncodedSetupMsg = msg
+	}
+
+	setupMsg, err := t.decodeDecryptMessage(encryptedEncodedSetupMsg, hexEncryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decodeDecryptMessage: %w", err)
+	}
+	// a setup message carrying a hash would produce a full signature of a message nobody validated
+	setupHashToSign, err := mpcWrapper.DecodeMessage(setupMsg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mpcWrapper.DecodeMessage: %w", err)
+	}
+	if len(setupHashToSign) != 0 {
+		return nil, fmt.Errorf("presign setup message carries a message to sign, stop presign")
+	}
+
+	sessionHandle, err := mpcWrapper.SignSessionFromSetup(setupMsg, []byte(localPartyID), keyshareHandle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to SignSessionFromSetup: %w", err)
+	}
+	defer func() {
+		if err := mpcWrapper.SignSessionFree(sessionHandle); err != nil {
+			t.logger.Error("failed to free presign session", "error", err)
+		}
+	}()
+
+	eg := &errgroup.Group{}
+	eg.Go(func() error {
+		if er := t.processKeysignOutbound(sessionHandle, sessionID, hexEncryptionKey, committee, localPartyID, messageID, false); er != nil {
+			t.logger.Error("failed to processKeysignOutbound: ", "error", er)
+		}
+		if er := t.processKeysignInbound(sessionHandle, sessionID, hexEncryptionKey, localPartyID, false, messageID, committee); er != nil {
+			return fmt.Errorf("failed to processKeysignInbound: %w", er)
+		}
+		return nil
+	})
+	if err := eg.Wait(); err != nil {
+		return nil, fmt.Errorf("failed to process presign: %w", err)
+	}
+
+	presignBytes, err := mpcWrapper.SignSessionFinish(sessionHandle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to SignSessionFinish: %w", err)
+	}
+	presignHandle, err := mpcWrapper.PresignFromBytes(presignBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to PresignFromBytes: %w", err)
+	}
+	defer t.freePresign(presignHandle)
+	presignSessionID, err := mpcWrapper.PresignSessionID(presignHandle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to PresignSessionID: %w", err)
+	}
+	encrypted, err := vcommon.EncryptVault(t.cfg.EncryptionSecret, presignBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt presign: %w", err)
+	}
+
+	t.logger.WithFields(logrus.Fields{
+		"session_id":  sessionID,
+		"derive_path": derivePath,
+	}).Info("presign finished successfully")
+	return &types.Presign{
+		ID:         hex.EncodeToString(presignSessionID),
+		DerivePath: derivePath,
+		Data:       encrypted,
+		CreatedAt:  time.Now().UTC(),
+	}, nil
+}
+
+// consumePresign takes the presignature out of the store and loads it. It is burnt even if the keysign fails later.
+// The caller frees the returned handle with freePresign.
+func (t *DKLSTssService) consumePresign(publicKey, pluginID, presignID, derivePath string) (Handle, error) {
+	if t.presignStore == nil {
+		return 0, ErrPresignDisabled
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	presign, err := t.presignStore.ConsumePresign(ctx, publicKey, pluginID, presignID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to consume presign: %w", err)
+	}
+	if presign == nil {
+		return 0, fmt.Errorf("%s: %w", presignID, ErrPresignUnavailable)
+	}
+	if presign.DerivePath != derivePath {
+		return 0, fmt.Errorf("presign %s was generated for derive path %s, not %s", presignID, presign.DerivePath, derivePath)
+	}
+
+	presignBytes, err := vcommon.DecryptVault(t.cfg.EncryptionSecret, presign.Data)
+	if err != nil {
+		return 0, fmt.Errorf("failed to decrypt presign: %w", err)
+	}
+	mpcWrapper := t.GetMPCKeygenWrapper(false)
+	presignHandle, err := mpcWrapper.PresignFromBytes(presignBytes)
+	if err != nil {
+		return 0, fmt.Errorf("failed to PresignFromBytes: %w", err)
+	}
+	sessionID, err := mpcWrapper.PresignSessionID(presignHandle)
+	if err != nil {
+		t.freePresign(presignHandle)
+		return 0, fmt.Errorf("failed to PresignSessionID: %w", err)
+	}
+	if hex.EncodeToString(sessionID) != presignID {
+		t.freePresign(presignHandle)
+		return 0, fmt.Errorf("presign %s doesn't match the stored data", presignID)
+	}
+	return presignHandle, nil
+}
+
+// freePresign releases a loaded presignature, the sign session may already have taken it so a failure is only logged
+func (t *DKLSTssService) freePresign(presign Handle) {
+	if err := t.GetMPCKeygenWrapper(false).PresignFree(presign); err != nil {
+		t.logger.WithError(err).Debug("failed to free presign")
+	}
+}
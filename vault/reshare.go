@@ -16,9 +16,20 @@ import (
 	"github.com/vultisig/vultiserver/relay"
 	vgrelay "github.com/vultisig/vultisig-go/relay"
 	"google.golang.org/protobuf/types/known/timestamppb"
+
+	vtypes "github.com/vultisig/verifier/types"
 )
 
 func (t *DKLSTssService) ProcessReshare(vault *vaultType.Vault,
+	sessionID string,
+	hexEncryptionKey string,
+	email string,
+	pluginId string) error {
+	t.session = newSessionTracker(t.sessionReporter, vtypes.SessionOperationReshare, sessionID, vault.PublicKeyEcdsa, pluginId)
+	return t.session.fail(t.processReshare(vault, sessionID, hexEncryptionKey, email, pluginId))
+}
+
+func (t *DKLSTssService) processReshare(vault *vaultType.Vault,
 	sessionID string,
 	hexEncryptionKey string,
 	email string,
@@ -41,13 +52,16 @@ func (t *DKLSTssService) ProcessReshare(vault *vaultType.Vault,
 	if err := client.RegisterSession(sessionID, vault.LocalPartyId); err != nil {
 		return fmt.Errorf("failed to register session: %w", err)
 	}
+	t.session.phase(vtypes.SessionPhaseRegistered)
 	// wait longer for keygen start
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
 	defer cancel()
+	t.session.phase(vtypes.SessionPhaseWaitingForParties)
 	partiesJoined, err := client.WaitForSessionStart(ctx, sessionID)
 	if err != nil {
 		return fmt.Errorf("failed to wait for session start: %w", err)
 	}
+	t.session.parties(partiesJoined)
 	t.logger.WithFields(logrus.Fields{
 		"session":        sessionID,
 		"parties_joined": partiesJoined,
@@ -61,11 +75,13 @@ func (t *DKLSTssService) ProcessReshare(vault *vaultType.Vault,
 	if err != nil {
 		return fmt.Errorf("failed to reshare ECDSA: %w", err)
 	}
+	t.session.publicKey(ecdsaPubkey)
 	t.logger.Infof("start reshare eddsa")
 	eddsaPubkey, _, err := t.reshareWithRetry(vault, sessionID, hexEncryptionKey, partiesJoined, vault.PublicKeyEddsa, true)
 	if err != nil {
 		return fmt.Errorf("failed to reshare EDDSA: %w", err)
 	}
+	t.session.phase(vtypes.SessionPhaseFinalizing)
 	if err := client.CompleteSession(sessionID, localPartyID); err != nil {
 		t.logger.WithFields(logrus.Fields{
 			"session": sessionID,
@@ -116,7 +132,11 @@ func (t *DKLSTssService) ProcessReshare(vault *vaultType.Vault,
 		LibType:       keygenType.LibType_LIB_TYPE_DKLS,
 		ResharePrefix: "",
 	}
-	return t.SaveVaultToStorage(newVault, email, pluginId)
+	if err := t.SaveVaultToStorage(newVault, email, pluginId); err != nil {
+		return err
+	}
+	t.session.phase(vtypes.SessionPhaseSaved)
+	return nil
 }
 func (t *DKLSTssService) reshareWithRetry(vault *vaultType.Vault,
 	sessionID string,
@@ -147,6 +167,7 @@ func (t *DKLSTssService) reshare(vault *vaultType.Vault,
 			"session_id": sessionID,
 			"public_key": publicKey,
 		}).Infof("Reshare attempt %d,", attempt)
+	t.session.key(isEdDSA)
 	mpcWrapper := t.GetMPCKeygenWrapper(isEdDSA)
 	var keyshareHandle Handle
 	if len(publicKey) > 0 {
@@ -266,6 +287,7 @@ func (t *DKLSTssService) processQcInbound(handle Handle,
 	mpcWrapper := t.GetMPCKeygenWrapper(isEdDSA)
 	relayClient := vgrelay.NewRelayClient(t.cfg.Relay.Server)
 	start := time.Now()
+	round := 0
 	for {
 
 		if time.Since(start) > time.Minute*4 {
@@ -306,6 +328,8 @@ func (t *DKLSTssService) processQcInbound(handle Handle,
 				continue
 			}
 			messageCache.Store(cacheKey, true)
+			round++
+			t.session.round(round)
 			t.logger.Infof("apply inbound message to dkls: %s, from: %s, %d", message.Hash, message.From, message.SequenceNo)
 			if err := relayClient.DeleteMessageFromServer(sessionID, localPartyID, message.Hash, ""); err != nil {
 				t.logger.Error("fail to delete message", "error", err)
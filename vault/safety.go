@@ -1,14 +1,32 @@
 package vault
 
-import "context"
+import (
+	"context"
+
+	psafety "github.com/vultisig/verifier/plugin/safety"
+)
 
 // SafetyManager defines the interface for safety enforcement during keygen and keysign operations.
-// Implementations can check control flags to block operations globally or per-plugin.
+// Implementations can check control flags to block operations globally, per-plugin, or per chain, vault and policy.
 type SafetyManager interface {
 	EnforceKeygen(ctx context.Context, pluginID string) error
 	EnforceKeysign(ctx context.Context, pluginID string) error
 }
 
+// KeysignScopeEnforcer is implemented by the safety managers checking the chain, vault and policy flags of a keysign
+// besides the plugin ones. The keysign of a manager without it is only checked with EnforceKeysign.
+type KeysignScopeEnforcer interface {
+	EnforceKeysignScope(ctx context.Context, scope psafety.KeysignScope) error
+}
+
+// enforceKeysignScope checks the whole scope when the manager supports it, the plugin otherwise
+func enforceKeysignScope(ctx context.Context, m SafetyManager, scope psafety.KeysignScope) error {
+	if enforcer, ok := m.(KeysignScopeEnforcer); ok {
+		return enforcer.EnforceKeysignScope(ctx, scope)
+	}
+	return m.EnforceKeysign(ctx, scope.PluginID)
+}
+
 // NoOpSafetyManager is a no-op implementation of SafetyManager.
 // Use this when safety checks are not required (e.g., in plugins).
 type NoOpSafetyManager struct{}
@@ -20,3 +38,12 @@ func (n *NoOpSafetyManager) EnforceKeygen(ctx context.Context, pluginID string)
 func (n *NoOpSafetyManager) EnforceKeysign(ctx context.Context, pluginID string) error {
 	return nil
 }
+
+func (n *NoOpSafetyManager) EnforceKeysignScope(ctx context.Context, scope psafety.KeysignScope) error {
+	return nil
+}
+
+var (
+	_ SafetyManager        = (*NoOpSafetyManager)(nil)
+	_ KeysignScopeEnforcer = (*NoOpSafetyManager)(nil)
+)
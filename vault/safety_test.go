@@ -0,0 +1,33 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	psafety "github.com/vultisig/verifier/plugin/safety"
+)
+
+// pluginSafetyManager only implements SafetyManager, like the managers written before scopes existed
+type pluginSafetyManager struct {
+	paused string
+}
+
+func (p *pluginSafetyManager) EnforceKeygen(context.Context, string) error { return nil }
+
+func (p *pluginSafetyManager) EnforceKeysign(_ context.Context, pluginID string) error {
+	if pluginID == p.paused {
+		return errors.New("plugin paused")
+	}
+	return nil
+}
+
+func TestEnforceKeysignScope_Fallback(t *testing.T) {
+	ctx := context.Background()
+	m := &pluginSafetyManager{paused: "paused-plugin"}
+
+	require.Error(t, enforceKeysignScope(ctx, m, psafety.KeysignScope{PluginID: "paused-plugin"}))
+	require.NoError(t, enforceKeysignScope(ctx, m, psafety.KeysignScope{PluginID: "other-plugin"}))
+}
@@ -2,11 +2,13 @@ package vault
 
 import (
 	"context"
+	"crypto/ed25519"
 	"encoding/json"
 	"fmt"
 	"plugin"
 
 	"github.com/google/uuid"
+	psafety "github.com/vultisig/verifier/plugin/safety"
 	"github.com/vultisig/verifier/plugin/tx_indexer"
 	"github.com/vultisig/verifier/vault_config"
 
@@ -38,10 +40,15 @@ type ManagementService struct {
 	cfg              vault_config.Config
 	logger           *logrus.Logger
 	queueClient      *asynq.Client
+	inspector        *asynq.Inspector
 	plugin           plugin.Plugin
 	vaultStorage     Storage
 	txIndexerService *tx_indexer.Service
 	safetyMgm        SafetyManager
+	sessionReporter  SessionReporter
+	presignStore     PresignStore
+	keysignResults   KeysignResultStore
+	receiptKey       ed25519.PrivateKey
 }
 
 // NewManagementService creates a new instance of the ManagementService
@@ -66,9 +73,29 @@ func NewManagementService(
 		vaultStorage:     storage,
 		txIndexerService: txIndexerService,
 		safetyMgm:        safetyMgm,
+		sessionReporter:  &NoOpSessionReporter{},
+		keysignResults:   &NoOpKeysignResultStore{},
 	}, nil
 }
 
+// SetInspector enables cancellation of queued keysign tasks when a vault share is uninstalled
+func (s *ManagementService) SetInspector(inspector *asynq.Inspector) {
+	s.inspector = inspector
+}
+
+// SetSessionReporter publishes the phase transitions of keygen and reshare sessions
+func (s *ManagementService) SetSessionReporter(reporter SessionReporter) {
+	if reporter == nil {
+		reporter = &NoOpSessionReporter{}
+	}
+	s.sessionReporter = reporter
+}
+
+// SetPresignStore enables presign tasks and keysign with presignatures
+func (s *ManagementService) SetPresignStore(store PresignStore) {
+	s.presignStore = store
+}
+
 func (s *ManagementService) HandleKeyGenerationDKLS(ctx context.Context, t *asynq.Task) error {
 	if err := contexthelper.CheckCancellation(ctx); err != nil {
 		return err
@@ -95,6 +122,7 @@ func (s *ManagementService) HandleKeyGenerationDKLS(ctx context.Context, t *asyn
 	if err != nil {
 		return fmt.Errorf("NewDKLSTssService failed: %s: %w", err, asynq.SkipRetry)
 	}
+	dklsService.SetSessionReporter(s.sessionReporter)
 	keyECDSA, keyEDDSA, err := dklsService.ProcessDKLSKeygen(req)
 	if err != nil {
 		s.logger.WithError(err).Error("keygen.JoinKeyGeneration failed")
@@ -125,7 +153,7 @@ func (s *ManagementService) HandleKeyGenerationDKLS(ctx context.Context, t *asyn
 	return nil
 }
 
-func (s *ManagementService) HandleKeySignDKLS(ctx context.Context, t *asynq.Task) error {
+func (s *ManagementService) HandleKeySignDKLS(ctx context.Context, t *asynq.Task) (err error) {
 	if err := contexthelper.CheckCancellation(ctx); err != nil {
 		return err
 	}
@@ -134,6 +162,16 @@ func (s *ManagementService) HandleKeySignDKLS(ctx context.Context, t *asynq.Task
 		s.logger.WithError(err).Error("json.Unmarshal failed")
 		return fmt.Errorf("json.Unmarshal failed: %v: %w", err, asynq.SkipRetry)
 	}
+
+	// the result outlives the task, so it's recorded even when the task context is done
+	reportCtx := context.WithoutCancel(ctx)
+	s.reportKeysignResult(reportCtx, req, vtypes.KeysignResultRunning, "", nil)
+	defer func() {
+		// no-op once completed, e.g. when only the tx indexer update fails
+		if err != nil {
+			s.reportKeysignResult(reportCtx, req, vtypes.KeysignResultFailed, err.Error(), nil)
+		}
+	}()
 	s.logger.WithFields(logrus.Fields{
 		"PublicKey": req.PublicKey,
 		"session":   req.SessionID,
@@ -142,7 +180,12 @@ func (s *ManagementService) HandleKeySignDKLS(ctx context.Context, t *asynq.Task
 		"PolicyID":  req.PolicyID,
 	}).Info("joining keysign")
 
-	if err := s.safetyMgm.EnforceKeysign(ctx, req.PluginID); err != nil {
+	if err := enforceKeysignScope(ctx, s.safetyMgm, psafety.KeysignScope{
+		PluginID:  req.PluginID,
+		Chains:    req.Chains(),
+		PublicKey: req.PublicKey,
+		PolicyID:  req.PolicyID,
+	}); err != nil {
 		return fmt.Errorf("EnforceKeysign failed: %v: %w", err, asynq.SkipRetry)
 	}
 
@@ -150,6 +193,7 @@ func (s *ManagementService) HandleKeySignDKLS(ctx context.Context, t *asynq.Task
 	if err != nil {
 		return fmt.Errorf("NewDKLSTssService failed: %s: %w", err, asynq.SkipRetry)
 	}
+	dklsService.SetPresignStore(s.presignStore)
 
 	signatures, err := dklsService.ProcessDKLSKeysign(req)
 	if err != nil {
@@ -160,6 +204,7 @@ func (s *ManagementService) HandleKeySignDKLS(ctx context.Context, t *asynq.Task
 	s.logger.WithFields(logrus.Fields{
 		"Signatures": signatures,
 	}).Info("localPartyID sign completed")
+	s.reportKeysignResult(reportCtx, req, vtypes.KeysignResultCompleted, "", signatures)
 
 	resultBytes, err := json.Marshal(signatures)
 	if err != nil {
@@ -198,6 +243,55 @@ func (s *ManagementService) HandleKeySignDKLS(ctx context.Context, t *asynq.Task
 	return nil
 }
 
+func (s *ManagementService) HandlePresignDKLS(ctx context.Context, t *asynq.Task) error {
+	if err := contexthelper.CheckCancellation(ctx); err != nil {
+		return err
+	}
+	var req vtypes.PresignRequest
+	if err := json.Unmarshal(t.Payload(), &req); err != nil {
+		return fmt.Errorf("json.Unmarshal failed: %v: %w", err, asynq.SkipRetry)
+	}
+	s.logger.WithFields(logrus.Fields{
+		"PublicKey": req.PublicKey,
+		"session":   req.SessionID,
+		"PluginID":  req.PluginID,
+		"Chain":     req.Chain.String(),
+		"Count":     req.Count,
+	}).Info("joining presign")
+
+	if err := req.IsValid(); err != nil {
+		return fmt.Errorf("invalid presign request: %s: %w", err, asynq.SkipRetry)
+	}
+	if err := enforceKeysignScope(ctx, s.safetyMgm, psafety.KeysignScope{
+		PluginID:  req.PluginID,
+		Chains:    []vcommon.Chain{req.Chain},
+		PublicKey: req.PublicKey,
+	}); err != nil {
+		return fmt.Errorf("EnforceKeysign failed: %v: %w", err, asynq.SkipRetry)
+	}
+
+	dklsService, err := NewDKLSTssService(s.cfg, s.vaultStorage, s.queueClient)
+	if err != nil {
+		return fmt.Errorf("NewDKLSTssService failed: %s: %w", err, asynq.SkipRetry)
+	}
+	dklsService.SetPresignStore(s.presignStore)
+
+	ids, err := dklsService.ProcessDKLSPresign(req)
+	if err != nil {
+		s.logger.WithError(err).Error("join presign failed")
+		return fmt.Errorf("join presign failed: %v: %w", err, asynq.SkipRetry)
+	}
+
+	resultBytes, err := json.Marshal(ids)
+	if err != nil {
+		return fmt.Errorf("json.Marshal failed: %v: %w", err, asynq.SkipRetry)
+	}
+	if _, err := t.ResultWriter().Write(resultBytes); err != nil {
+		return fmt.Errorf("t.ResultWriter.Write failed: %v: %w", err, asynq.SkipRetry)
+	}
+	return nil
+}
+
 func (s *ManagementService) HandleReshareDKLS(ctx context.Context, t *asynq.Task) error {
 	if err := contexthelper.CheckCancellation(ctx); err != nil {
 		return err
@@ -244,6 +338,7 @@ func (s *ManagementService) HandleReshareDKLS(ctx context.Context, t *asynq.Task
 		s.logger.WithError(err).Error("NewDKLSTssService failed")
 		return fmt.Errorf("NewDKLSTssService failed: %v: %w", err, asynq.SkipRetry)
 	}
+	service.SetSessionReporter(s.sessionReporter)
 
 	if err := service.ProcessReshare(vault, req.SessionID, req.HexEncryptionKey, req.Email, req.PluginID); err != nil {
 		s.logger.WithError(err).Error("reshare failed")
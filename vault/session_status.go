@@ -0,0 +1,120 @@
+package vault
+
+import (
+	"context"
+	"time"
+
+	vtypes "github.com/vultisig/verifier/types"
+)
+
+// SessionReporter receives phase transitions of keygen and reshare sessions.
+// Implementations must not block the protocol for long, reports are best effort.
+type SessionReporter interface {
+	ReportSessionStatus(ctx context.Context, status vtypes.SessionStatus)
+}
+
+// NoOpSessionReporter is used when no reporter is configured
+type NoOpSessionReporter struct{}
+
+func (n *NoOpSessionReporter) ReportSessionStatus(ctx context.Context, status vtypes.SessionStatus) {}
+
+var _ SessionReporter = (*NoOpSessionReporter)(nil)
+
+// sessionRoundReportInterval spaces the reports of round progress, which changes with every inbound message
+const sessionRoundReportInterval = time.Second
+
+// sessionTracker keeps the current status of one session and pushes the changes to the reporter,
+// phase changes and the first round of each key right away, the next rounds at most once per sessionRoundReportInterval.
+// A nil tracker ignores all updates.
+type sessionTracker struct {
+	reporter   SessionReporter
+	status     vtypes.SessionStatus
+	lastReport time.Time
+	// keyRoundReported is set once the first round of the current key was reported
+	keyRoundReported bool
+	now              func() time.Time
+}
+
+func newSessionTracker(reporter SessionReporter, operation, sessionID, publicKey, pluginID string) *sessionTracker {
+	if reporter == nil {
+		reporter = &NoOpSessionReporter{}
+	}
+	return &sessionTracker{
+		reporter: reporter,
+		status: vtypes.SessionStatus{
+			SessionID: sessionID,
+			Operation: operation,
+			PublicKey: publicKey,
+			PluginID:  pluginID,
+			StartedAt: time.Now().UTC(),
+		},
+		now: time.Now,
+	}
+}
+
+func (s *sessionTracker) phase(phase vtypes.SessionPhase) {
+	if s == nil {
+		return
+	}
+	s.status.Phase = phase
+	s.report()
+}
+
+func (s *sessionTracker) parties(parties []string) {
+	if s == nil {
+		return
+	}
+	s.status.Parties = parties
+}
+
+func (s *sessionTracker) publicKey(publicKey string) {
+	if s == nil {
+		return
+	}
+	s.status.PublicKey = publicKey
+}
+
+func (s *sessionTracker) key(isEdDSA bool) {
+	if s == nil {
+		return
+	}
+	s.status.Key = "ecdsa"
+	if isEdDSA {
+		s.status.Key = "eddsa"
+	}
+	s.status.Round = 0
+	s.keyRoundReported = false
+}
+
+func (s *sessionTracker) round(round int) {
+	if s == nil {
+		return
+	}
+	phase := s.status.Phase
+	s.status.Phase = vtypes.SessionPhaseRound
+	s.status.Round = round
+	if phase == vtypes.SessionPhaseRound && s.keyRoundReported && s.now().Sub(s.lastReport) < sessionRoundReportInterval {
+		return
+	}
+	s.keyRoundReported = true
+	s.report()
+}
+
+// fail records the error and returns it unchanged, so it can wrap return statements
+func (s *sessionTracker) fail(err error) error {
+	if s == nil || err == nil {
+		return err
+	}
+	s.status.Phase = vtypes.SessionPhaseFailed
+	s.status.Error = err.Error()
+	s.report()
+	return err
+}
+
+func (s *sessionTracker) report() {
+	s.lastReport = s.now()
+	s.status.UpdatedAt = s.lastReport.UTC()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	s.reporter.ReportSessionStatus(ctx, s.status)
+}
@@ -0,0 +1,54 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	vtypes "github.com/vultisig/verifier/types"
+)
+
+type recordingSessionReporter struct {
+	reports []vtypes.SessionStatus
+}
+
+func (r *recordingSessionReporter) ReportSessionStatus(_ context.Context, status vtypes.SessionStatus) {
+	r.reports = append(r.reports, status)
+}
+
+func TestSessionTracker_ThrottlesRounds(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	reporter := &recordingSessionReporter{}
+	tracker := newSessionTracker(reporter, vtypes.SessionOperationKeygen, "session", "", "plugin")
+	tracker.now = func() time.Time { return now }
+
+	tracker.phase(vtypes.SessionPhaseRegistered)
+	// the first round is reported, the next ones only once the interval passed
+	for round := 1; round <= 10; round++ {
+		tracker.round(round)
+	}
+	require.Len(t, reporter.reports, 2)
+	require.Equal(t, 1, reporter.reports[1].Round)
+
+	now = now.Add(sessionRoundReportInterval)
+	tracker.round(11)
+	require.Len(t, reporter.reports, 3)
+	require.Equal(t, 11, reporter.reports[2].Round)
+
+	// the first round of the next key isn't held back
+	tracker.key(true)
+	tracker.round(1)
+	require.Len(t, reporter.reports, 4)
+	require.Equal(t, "eddsa", reporter.reports[3].Key)
+	require.Equal(t, 1, reporter.reports[3].Round)
+
+	// phase changes are never held back
+	tracker.round(12)
+	require.Error(t, tracker.fail(errors.New("relay closed")))
+	require.Len(t, reporter.reports, 5)
+	require.Equal(t, vtypes.SessionPhaseFailed, reporter.reports[4].Phase)
+	require.Equal(t, 12, reporter.reports[4].Round)
+}
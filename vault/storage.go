@@ -26,6 +26,13 @@ type Storage interface {
 	DeleteFile(fileName string) error
 }
 
+// Purger is implemented by storages that can remove every stored copy of a file,
+// including previous object versions kept by a versioned bucket. It is used when
+// a key share must be unrecoverable rather than just hidden behind a delete marker.
+type Purger interface {
+	PurgeFile(fileName string) (int, error)
+}
+
 type BlockStorageImp struct {
 	cfg      vault_config.BlockStorage
 	session  *session.Session
@@ -34,6 +41,7 @@ type BlockStorageImp struct {
 }
 
 var _ Storage = (*BlockStorageImp)(nil)
+var _ Purger = (*BlockStorageImp)(nil)
 
 func NewBlockStorageImp(cfg vault_config.BlockStorage) (*BlockStorageImp, error) {
 	sess, err := session.NewSession(&aws.Config{
@@ -139,7 +147,69 @@ func (bs *BlockStorageImp) DeleteFile(fileName string) error {
 	return nil
 }
 
+// PurgeFile deletes every version and delete marker of the given object.
+// It returns the number of removed versions; buckets without versioning report a single version.
+func (bs *BlockStorageImp) PurgeFile(fileName string) (int, error) {
+	var versions []*s3.ObjectIdentifier
+	err := bs.s3Client.ListObjectVersionsPages(&s3.ListObjectVersionsInput{
+		Bucket: aws.String(bs.cfg.Bucket),
+		Prefix: aws.String(fileName),
+	}, func(page *s3.ListObjectVersionsOutput, _ bool) bool {
+		for _, v := range page.Versions {
+			if aws.StringValue(v.Key) == fileName {
+				versions = append(versions, &s3.ObjectIdentifier{Key: v.Key, VersionId: v.VersionId})
+			}
+		}
+		for _, m := range page.DeleteMarkers {
+			if aws.StringValue(m.Key) == fileName {
+				versions = append(versions, &s3.ObjectIdentifier{Key: m.Key, VersionId: m.VersionId})
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list object versions: %w", err)
+	}
+
+	deleted := 0
+	// DeleteObjects accepts at most 1000 keys per request
+	for start := 0; start < len(versions); start += 1000 {
+		end := min(start+1000, len(versions))
+		output, err := bs.s3Client.DeleteObjects(&s3.DeleteObjectsInput{
+			Bucket: aws.String(bs.cfg.Bucket),
+			Delete: &s3.Delete{
+				Objects: versions[start:end],
+				Quiet:   aws.Bool(true),
+			},
+		})
+		if err != nil {
+			return deleted, fmt.Errorf("failed to delete object versions: %w", err)
+		}
+		if len(output.Errors) > 0 {
+			return deleted, fmt.Errorf("failed to delete version %s of %s: %s",
+				aws.StringValue(output.Errors[0].VersionId), fileName, aws.StringValue(output.Errors[0].Message))
+		}
+		deleted += end - start
+	}
+
+	// Unversioned buckets may not report any versions, make sure the current object is gone too
+	exist, err := bs.FileExist(fileName)
+	if err != nil {
+		return deleted, err
+	}
+	if exist {
+		if err := bs.DeleteFile(fileName); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+
+	bs.logger.Infof("purge file %s success, %d versions removed", fileName, deleted)
+	return deleted, nil
+}
+
 var _ Storage = (*LocalVaultStorage)(nil)
+var _ Purger = (*LocalVaultStorage)(nil)
 
 type LocalVaultStorageConfig struct {
 	VaultFilePath string `mapstructure:"vault_file_path" json:"vault_file_path"`
@@ -192,3 +262,23 @@ func (lvs *LocalVaultStorage) DeleteFile(fileName string) error {
 	}
 	return nil
 }
+
+// PurgeFile overwrites the file with zeros before removing it, so the share
+// does not linger in the filesystem's free blocks.
+func (lvs *LocalVaultStorage) PurgeFile(fileName string) (int, error) {
+	filePathName := filepath.Join(lvs.cfg.VaultFilePath, fileName)
+	info, err := os.Stat(filePathName)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("os.Stat failed: %w", err)
+	}
+	if err := os.WriteFile(filePathName, make([]byte, info.Size()), 0o666); err != nil {
+		return 0, fmt.Errorf("os.WriteFile failed: %w", err)
+	}
+	if err := os.Remove(filePathName); err != nil {
+		return 0, fmt.Errorf("os.Remove failed: %w", err)
+	}
+	return 1, nil
+}
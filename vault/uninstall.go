@@ -0,0 +1,308 @@
+package vault
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/sirupsen/logrus"
+	vaultType "github.com/vultisig/commondata/go/vultisig/vault/v1"
+	"github.com/vultisig/vultiserver/contexthelper"
+	vcommon "github.com/vultisig/vultisig-go/common"
+
+	"github.com/vultisig/verifier/plugin/tasks"
+	vtypes "github.com/vultisig/verifier/types"
+)
+
+const tombstoneSuffix = ".tombstone"
+
+var ErrVaultDestroyed = errors.New("vault share has been destroyed")
+
+// Tombstone marks a vault share as destroyed. Any share for the same vault and plugin
+// created before DeletedAt must not be used again, even if a copy of it shows up later.
+type Tombstone struct {
+	PublicKey string    `json:"public_key"`
+	PluginID  string    `json:"plugin_id"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+func GetTombstoneFilename(publicKey, pluginID string) string {
+	return vcommon.GetVaultBackupFilename(publicKey, pluginID) + tombstoneSuffix
+}
+
+// ReadTombstone returns nil if the vault share has never been destroyed
+func ReadTombstone(storage Storage, publicKey, pluginID string) (*Tombstone, error) {
+	fileName := GetTombstoneFilename(publicKey, pluginID)
+	exist, err := storage.Exist(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check tombstone: %w", err)
+	}
+	if !exist {
+		return nil, nil
+	}
+	content, err := storage.GetVault(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tombstone: %w", err)
+	}
+	var tombstone Tombstone
+	if err := json.Unmarshal(content, &tombstone); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tombstone: %w", err)
+	}
+	return &tombstone, nil
+}
+
+func writeTombstone(storage Storage, tombstone Tombstone) error {
+	content, err := json.Marshal(tombstone)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tombstone: %w", err)
+	}
+	return storage.SaveVault(GetTombstoneFilename(tombstone.PublicKey, tombstone.PluginID), content)
+}
+
+// checkTombstone rejects vault shares created before the latest uninstall of the same vault and plugin
+func checkTombstone(storage Storage, publicKey, pluginID string, vault *vaultType.Vault) error {
+	tombstone, err := ReadTombstone(storage, publicKey, pluginID)
+	if err != nil {
+		return err
+	}
+	if tombstone == nil {
+		return nil
+	}
+	if vault.GetCreatedAt() != nil && vault.GetCreatedAt().AsTime().After(tombstone.DeletedAt) {
+		return nil
+	}
+	return fmt.Errorf("vault %s, plugin %s: %w", publicKey, pluginID, ErrVaultDestroyed)
+}
+
+// purgeVaultFile removes every stored copy of the vault file, falling back to a plain delete
+// for storages that don't keep history
+func purgeVaultFile(storage Storage, fileName string) (int, error) {
+	if purger, ok := storage.(Purger); ok {
+		return purger.PurgeFile(fileName)
+	}
+	exist, err := storage.Exist(fileName)
+	if err != nil {
+		return 0, err
+	}
+	if !exist {
+		return 0, nil
+	}
+	if err := storage.DeleteFile(fileName); err != nil && !os.IsNotExist(err) {
+		return 0, err
+	}
+	return 1, nil
+}
+
+// SetDeletionReceiptKey sets the Ed25519 key the deletion receipts are signed with,
+// its public key is published by the api so anyone can verify a receipt. Uninstall fails without it.
+func (s *ManagementService) SetDeletionReceiptKey(key ed25519.PrivateKey) {
+	s.receiptKey = key
+}
+
+func deletionReceiptPayload(receipt vtypes.VaultDeletionReceipt) ([]byte, error) {
+	receipt.Signature = ""
+	return json.Marshal(receipt)
+}
+
+func SignDeletionReceipt(key ed25519.PrivateKey, receipt *vtypes.VaultDeletionReceipt) error {
+	receipt.SignerPublicKey = hex.EncodeToString(key.Public().(ed25519.PublicKey))
+	payload, err := deletionReceiptPayload(*receipt)
+	if err != nil {
+		return fmt.Errorf("failed to marshal receipt: %w", err)
+	}
+	receipt.Signature = hex.EncodeToString(ed25519.Sign(key, payload))
+	return nil
+}
+
+// VerifyDeletionReceipt checks the receipt is signed by the published receipt key
+func VerifyDeletionReceipt(receipt vtypes.VaultDeletionReceipt, signer ed25519.PublicKey) error {
+	pubKey, err := hex.DecodeString(receipt.SignerPublicKey)
+	if err != nil || len(pubKey) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid signer public key")
+	}
+	if !signer.Equal(ed25519.PublicKey(pubKey)) {
+		return fmt.Errorf("receipt is not signed by the deletion receipt key")
+	}
+	sig, err := hex.DecodeString(receipt.Signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature encoding: %w", err)
+	}
+	payload, err := deletionReceiptPayload(receipt)
+	if err != nil {
+		return fmt.Errorf("failed to marshal receipt: %w", err)
+	}
+	if !ed25519.Verify(pubKey, payload, sig) {
+		return fmt.Errorf("invalid receipt signature")
+	}
+	return nil
+}
+
+// cancelPendingKeysign removes queued keysign and presign tasks for the vault and plugin, and cancels the running ones
+func (s *ManagementService) cancelPendingKeysign(publicKey, pluginID string) (int, error) {
+	if s.inspector == nil {
+		return 0, nil
+	}
+
+	matches := func(info *asynq.TaskInfo) bool {
+		if info.Type != tasks.TypeKeySignDKLS && info.Type != tasks.TypePresignDKLS {
+			return false
+		}
+		// keysign and presign payloads identify the vault the same way
+		var req struct {
+			PublicKey string `json:"public_key"`
+			PluginID  string `json:"plugin_id"`
+		}
+		if err := json.Unmarshal(info.Payload, &req); err != nil {
+			return false
+		}
+		return req.PublicKey == publicKey && req.PluginID == pluginID
+	}
+
+	listers := []func(string, ...asynq.ListOption) ([]*asynq.TaskInfo, error){
+		s.inspector.ListPendingTasks,
+		s.inspector.ListScheduledTasks,
+		s.inspector.ListRetryTasks,
+	}
+	var toDelete []string
+	for _, list := range listers {
+		found, err := listMatchingTasks(list, matches)
+		if err != nil {
+			return 0, err
+		}
+		toDelete = append(toDelete, found...)
+	}
+	toCancel, err := listMatchingTasks(s.inspector.ListActiveTasks, matches)
+	if err != nil {
+		return 0, err
+	}
+
+	cancelled := 0
+	for _, id := range toDelete {
+		if err := s.inspector.DeleteTask(tasks.QUEUE_NAME, id); err != nil && !errors.Is(err, asynq.ErrTaskNotFound) {
+			return cancelled, fmt.Errorf("failed to delete task %s: %w", id, err)
+		}
+		cancelled++
+	}
+	for _, id := range toCancel {
+		if err := s.inspector.CancelProcessing(id); err != nil {
+			return cancelled, fmt.Errorf("failed to cancel task %s: %w", id, err)
+		}
+		cancelled++
+	}
+	return cancelled, nil
+}
+
+func listMatchingTasks(
+	list func(string, ...asynq.ListOption) ([]*asynq.TaskInfo, error),
+	matches func(*asynq.TaskInfo) bool,
+) ([]string, error) {
+	const pageSize = 100
+	var ids []string
+	for page := 1; ; page++ {
+		infos, err := list(tasks.QUEUE_NAME, asynq.Page(page), asynq.PageSize(pageSize))
+		if err != nil {
+			if errors.Is(err, asynq.ErrQueueNotFound) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("failed to list tasks: %w", err)
+		}
+		for _, info := range infos {
+			if matches(info) {
+				ids = append(ids, info.ID)
+			}
+		}
+		if len(infos) < pageSize {
+			return ids, nil
+		}
+	}
+}
+
+// HandleVaultUninstall destroys the verifier's key share of a vault for a plugin.
+// It writes a tombstone first so no concurrent keysign can pick the share up, cancels queued keysign tasks,
+// drops its presignatures, removes every stored version of the share and returns a signed deletion receipt as the task result.
+func (s *ManagementService) HandleVaultUninstall(ctx context.Context, t *asynq.Task) error {
+	if err := contexthelper.CheckCancellation(ctx); err != nil {
+		return err
+	}
+	var req vtypes.VaultUninstallRequest
+	if err := json.Unmarshal(t.Payload(), &req); err != nil {
+		return fmt.Errorf("json.Unmarshal failed: %v: %w", err, asynq.SkipRetry)
+	}
+	if err := req.IsValid(); err != nil {
+		return fmt.Errorf("invalid uninstall request: %s: %w", err, asynq.SkipRetry)
+	}
+	if s.receiptKey == nil {
+		return fmt.Errorf("deletion receipt key is not configured: %w", asynq.SkipRetry)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"public_key": req.PublicKey,
+		"plugin_id":  req.PluginID,
+	}).Info("uninstalling vault share")
+
+	deletedAt := time.Now().UTC()
+	err := writeTombstone(s.vaultStorage, Tombstone{
+		PublicKey: req.PublicKey,
+		PluginID:  req.PluginID,
+		DeletedAt: deletedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write tombstone: %w", err)
+	}
+
+	cancelled, err := s.cancelPendingKeysign(req.PublicKey, req.PluginID)
+	if err != nil {
+		return fmt.Errorf("failed to cancel pending keysign tasks: %w", err)
+	}
+
+	var presigns int64
+	if s.presignStore != nil {
+		presigns, err = s.presignStore.DeletePresigns(ctx, req.PublicKey, req.PluginID)
+		if err != nil {
+			return fmt.Errorf("failed to delete presignatures: %w", err)
+		}
+	}
+
+	fileName := vcommon.GetVaultBackupFilename(req.PublicKey, req.PluginID)
+	versions, err := purgeVaultFile(s.vaultStorage, fileName)
+	if err != nil {
+		return fmt.Errorf("failed to purge vault share: %w", err)
+	}
+
+	receipt := vtypes.VaultDeletionReceipt{
+		PublicKey:       req.PublicKey,
+		PluginID:        req.PluginID,
+		VaultFile:       fileName,
+		VersionsDeleted: versions,
+		CancelledTasks:  cancelled,
+		PresignsDeleted: presigns,
+		RequestedAt:     req.RequestedAt,
+		DeletedAt:       deletedAt,
+	}
+	if err := SignDeletionReceipt(s.receiptKey, &receipt); err != nil {
+		return fmt.Errorf("failed to sign receipt: %v: %w", err, asynq.SkipRetry)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"public_key":       req.PublicKey,
+		"plugin_id":        req.PluginID,
+		"versions_deleted": versions,
+		"cancelled_tasks":  cancelled,
+		"presigns_deleted": presigns,
+	}).Info("vault share destroyed")
+
+	resultBytes, err := json.Marshal(receipt)
+	if err != nil {
+		return fmt.Errorf("json.Marshal failed: %v: %w", err, asynq.SkipRetry)
+	}
+	if _, err := t.ResultWriter().Write(resultBytes); err != nil {
+		return fmt.Errorf("t.ResultWriter.Write failed: %v: %w", err, asynq.SkipRetry)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package vault
+
+import (
+	"crypto/ed25519"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	vaultType "github.com/vultisig/commondata/go/vultisig/vault/v1"
+	vcommon "github.com/vultisig/vultisig-go/common"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	vtypes "github.com/vultisig/verifier/types"
+)
+
+func TestDeletionReceipt_SignVerify(t *testing.T) {
+	receipt := vtypes.VaultDeletionReceipt{
+		PublicKey:       "pubkey",
+		PluginID:        "plugin",
+		VaultFile:       "plugin-pubkey.vult",
+		VersionsDeleted: 2,
+		DeletedAt:       time.Now().UTC(),
+	}
+	pub, key, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	require.NoError(t, SignDeletionReceipt(key, &receipt))
+	require.NoError(t, VerifyDeletionReceipt(receipt, pub))
+
+	tampered := receipt
+	tampered.PluginID = "other"
+	require.Error(t, VerifyDeletionReceipt(tampered, pub))
+
+	_, otherKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	forged := receipt
+	require.NoError(t, SignDeletionReceipt(otherKey, &forged))
+	require.Error(t, VerifyDeletionReceipt(forged, pub), "receipt signed by another key must be rejected")
+}
+
+func TestTombstone_RejectsOlderShares(t *testing.T) {
+	storage, err := NewLocalVaultStorage(LocalVaultStorageConfig{VaultFilePath: t.TempDir()})
+	require.NoError(t, err)
+
+	fileName := vcommon.GetVaultBackupFilename("pubkey", "plugin")
+	require.NoError(t, storage.SaveVault(fileName, []byte("share")))
+
+	oldVault := &vaultType.Vault{CreatedAt: timestamppb.New(time.Now().Add(-time.Hour))}
+	require.NoError(t, checkTombstone(storage, "pubkey", "plugin", oldVault))
+
+	require.NoError(t, writeTombstone(storage, Tombstone{
+		PublicKey: "pubkey",
+		PluginID:  "plugin",
+		DeletedAt: time.Now().UTC(),
+	}))
+	versions, err := purgeVaultFile(storage, fileName)
+	require.NoError(t, err)
+	require.Equal(t, 1, versions)
+
+	exist, err := storage.Exist(fileName)
+	require.NoError(t, err)
+	require.False(t, exist)
+
+	require.ErrorIs(t, checkTombstone(storage, "pubkey", "plugin", oldVault), ErrVaultDestroyed)
+
+	newVault := &vaultType.Vault{CreatedAt: timestamppb.New(time.Now().Add(time.Minute))}
+	require.NoError(t, checkTombstone(storage, "pubkey", "plugin", newVault))
+}
@@ -48,6 +48,12 @@ type MPCKeyshareWrapper interface {
 	KeyshareFree(share Handle) error
 	KeyshareChainCode(share Handle) ([]byte, error)
 }
+type MPCPresignWrapper interface {
+	FinishSetupMsgNew(presignSessionID []byte, messageHash []byte, ids []byte) ([]byte, error)
+	PresignFromBytes(buf []byte) (Handle, error)
+	PresignToBytes(presign Handle) ([]byte, error)
+	PresignSessionID(presign Handle) ([]byte, error)
+}
 type MPCSetupWrapper interface {
 	DecodeKeyID(setup []byte) ([]byte, error)
 	DecodeSessionID(setup []byte) ([]byte, error)
@@ -60,6 +66,7 @@ var _ MPCKeysignWrapper = &MPCWrapperImp{}
 var _ MPCKeyshareWrapper = &MPCWrapperImp{}
 var _ MPCSetupWrapper = &MPCWrapperImp{}
 var _ MPCQcWrapper = &MPCWrapperImp{}
+var _ MPCPresignWrapper = &MPCWrapperImp{}
 
 type MPCWrapperImp struct {
 	isEdDSA bool
@@ -317,3 +324,38 @@ func (w *MPCWrapperImp) DecodePartyName(setup []byte, index int) ([]byte, error)
 	}
 	return session.DklsDecodePartyName(setup, index)
 }
+
+func (w *MPCWrapperImp) FinishSetupMsgNew(presignSessionID []byte, messageHash []byte, ids []byte) ([]byte, error) {
+	if w.isEdDSA {
+		return nil, errors.New("presign is not supported for EdDSA")
+	}
+	return session.DklsFinishSetupMsgNew(presignSessionID, messageHash, ids)
+}
+func (w *MPCWrapperImp) PresignFromBytes(buf []byte) (Handle, error) {
+	if w.isEdDSA {
+		return Handle(0), errors.New("presign is not supported for EdDSA")
+	}
+	h, err := session.DklsPresignFromBytes(buf)
+	return Handle(h), err
+}
+func (w *MPCWrapperImp) PresignToBytes(presign Handle) ([]byte, error) {
+	if w.isEdDSA {
+		return nil, errors.New("presign is not supported for EdDSA")
+	}
+	return session.DklsPresignToBytes(session.Handle(presign))
+}
+func (w *MPCWrapperImp) PresignSessionID(presign Handle) ([]byte, error) {
+	if w.isEdDSA {
+		return nil, errors.New("presign is not supported for EdDSA")
+	}
+	return session.DklsPresignSessionID(session.Handle(presign))
+}
+
+// PresignFree releases a presign handle. The library has no presign specific free,
+// its handle table frees a handle whatever the object behind it.
+func (w *MPCWrapperImp) PresignFree(presign Handle) error {
+	if w.isEdDSA {
+		return errors.New("presign is not supported for EdDSA")
+	}
+	return session.DklsKeyshareFree(session.Handle(presign))
+}